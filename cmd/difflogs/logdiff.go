@@ -0,0 +1,254 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/build/internal/diff"
+	"golang.org/x/build/internal/logparser"
+)
+
+// A unit is the part of a log about one test, or about one package
+// apart from its tests, in one section of the build.
+type unit struct {
+	section string // section of the build, such as a cmd/dist test name
+	pkg     string // package, if known
+	test    string // top-level test, if any
+	lines   []string
+}
+
+// key returns the key that aligns u with the unit for the same
+// section, package and test in the other log.
+func (u *unit) key() string {
+	return u.section + "\x00" + u.pkg + "\x00" + u.test
+}
+
+// String returns a description of u, such as "go_test:net net.TestDial".
+func (u *unit) String() string {
+	var parts []string
+	if u.section != "" {
+		parts = append(parts, u.section+":")
+	}
+	name := u.pkg
+	if u.test != "" {
+		if name != "" {
+			name += "."
+		}
+		name += u.test
+	}
+	if name != "" {
+		parts = append(parts, name)
+	}
+	if len(parts) == 0 {
+		return "(build)"
+	}
+	return strings.Join(parts, " ")
+}
+
+var (
+	// sectionRE matches the lines that start a section of the build:
+	// the cmd/dist test headers and the buildlet and bootstrap commands.
+	sectionRE = regexp.MustCompile(`^(?:##### (\S.*)|:: Running (\S+)|(Building .*))$`)
+
+	// testRE matches the lines of go test that start or end a test.
+	testRE = regexp.MustCompile(`^\s*(?:=== (?:RUN|CONT|PAUSE|NAME)|--- (?:FAIL|PASS|SKIP):)\s+([^\s/]+)`)
+
+	// pkgRE matches the result line of go test for a package.
+	pkgRE = regexp.MustCompile(`^(?:ok  |FAIL|\?   )\t(\S+)`)
+)
+
+// splitLog splits the log into units, in the order that they first
+// appear. The lines of a unit are normalized, and the lines of a
+// unit that appears more than once, such as a test run with -count,
+// are all in the first one.
+func splitLog(log string) []*unit {
+	var (
+		units   []*unit
+		byKey   = make(map[string]*unit)
+		section string
+		pending []*unit // units of the package whose result hasn't been seen yet
+		cur     *unit
+	)
+	// flush files the pending units under pkg.
+	flush := func(pkg string) {
+		for _, u := range pending {
+			u.pkg = pkg
+			if u0 := byKey[u.key()]; u0 != nil {
+				u0.lines = append(u0.lines, u.lines...)
+				continue
+			}
+			byKey[u.key()] = u
+			units = append(units, u)
+		}
+		pending, cur = nil, nil
+	}
+	start := func(test string) {
+		cur = &unit{section: section, test: test}
+		pending = append(pending, cur)
+	}
+
+	log = strings.ReplaceAll(log, "\r", "")
+	for _, line := range strings.Split(strings.TrimSuffix(log, "\n"), "\n") {
+		if m := sectionRE.FindStringSubmatch(line); m != nil {
+			flush("")
+			section = m[1] + m[2] + m[3]
+			continue
+		}
+		if m := testRE.FindStringSubmatch(line); m != nil {
+			if cur == nil || cur.test != m[1] {
+				start(m[1])
+			}
+		} else if m := pkgRE.FindStringSubmatch(line); m != nil {
+			// The result line belongs to the package, not its last test.
+			if cur == nil || cur.test != "" {
+				start("")
+			}
+			cur.lines = append(cur.lines, normalize(line))
+			flush(m[1])
+			continue
+		} else if cur == nil {
+			start("")
+		}
+		cur.lines = append(cur.lines, normalize(line))
+	}
+	flush("")
+	return units
+}
+
+// normalizers replace the parts of log lines that vary from run to
+// run without meaning anything, such as times and addresses.
+var normalizers = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Dates and times, such as 2024-01-02T15:04:05.999Z and 2024/01/02 15:04:05.
+	{regexp.MustCompile(`\d{4}[-/]\d\d[-/]\d\d[T ]\d\d:\d\d:\d\d(?:\.\d+)?(?:Z|[+-]\d\d:?\d\d)?`), "<time>"},
+	{regexp.MustCompile(`\b\d\d:\d\d:\d\d(?:\.\d+)?\b`), "<time>"},
+	// Durations, such as (0.12s) in test results and 1.234s in package results.
+	{regexp.MustCompile(`\b\d+(?:\.\d+)?(?:ns|µs|us|ms|s|m|h)\b`), "<dur>"},
+	// Addresses, stack frame offsets and other hexadecimal numbers.
+	{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`), "0x<hex>"},
+	// Goroutine IDs.
+	{regexp.MustCompile(`\bgoroutine \d+\b`), "goroutine <n>"},
+	// Temporary directories and files, such as /tmp/TestFoo1234/001
+	// or C:\Users\gopher\AppData\Local\Temp\go-build1234.
+	{regexp.MustCompile(`(?:/tmp|\\Temp|/var/folders/[^/\s]+/[^/\s]+/T)[/\\][^\s:'"]*`), "<tmp>"},
+	// Ports on the loopback interface.
+	{regexp.MustCompile(`\b(127\.0\.0\.1|\[::1\]|localhost):\d+\b`), "$1:<port>"},
+}
+
+// normalize returns line with the parts that vary from run to run replaced.
+func normalize(line string) string {
+	for _, n := range normalizers {
+		line = n.re.ReplaceAllString(line, n.repl)
+	}
+	return strings.TrimRight(line, " \t")
+}
+
+// diffLogs returns the meaningful differences between the logs old and
+// new, named oldName and newName, or nil if there are none. It starts
+// with the failures that are only in one of the logs. Then it aligns
+// the logs by section, package and test, and shows the units that are
+// only in one of them and the differences between the units in both,
+// after normalizing times, addresses and the like.
+func diffLogs(oldName, old, newName, new string) []byte {
+	var out bytes.Buffer
+	oldFails, newFails := failures(old), failures(new)
+	for _, f := range oldFails {
+		if !hasFailure(newFails, f) {
+			fmt.Fprintf(&out, "failure only in %s: %s\n", oldName, f)
+		}
+	}
+	for _, f := range newFails {
+		if !hasFailure(oldFails, f) {
+			fmt.Fprintf(&out, "failure only in %s: %s\n", newName, f)
+		}
+	}
+	if out.Len() > 0 {
+		fmt.Fprintf(&out, "\n")
+	}
+
+	oldUnits, newUnits := splitLog(old), splitLog(new)
+	newByKey := make(map[string]*unit)
+	for _, u := range newUnits {
+		newByKey[u.key()] = u
+	}
+	oldKeys := make(map[string]bool)
+
+	only := func(u *unit, name string) {
+		fmt.Fprintf(&out, "=== %s: only in %s\n", u, name)
+		for _, line := range u.lines {
+			fmt.Fprintf(&out, "%s\n", line)
+		}
+		fmt.Fprintf(&out, "\n")
+	}
+	for _, u := range oldUnits {
+		oldKeys[u.key()] = true
+		nu := newByKey[u.key()]
+		if nu == nil {
+			only(u, oldName)
+			continue
+		}
+		d := diff.Diff(oldName, text(u.lines), newName, text(nu.lines))
+		if d == nil {
+			continue
+		}
+		fmt.Fprintf(&out, "=== %s\n", u)
+		// Drop the diff header, which only names the logs.
+		for i := 0; i < 3; i++ {
+			_, d, _ = bytes.Cut(d, []byte("\n"))
+		}
+		out.Write(d)
+		fmt.Fprintf(&out, "\n")
+	}
+	for _, u := range newUnits {
+		if !oldKeys[u.key()] {
+			only(u, newName)
+		}
+	}
+	if out.Len() == 0 {
+		return nil
+	}
+	return out.Bytes()
+}
+
+// failures returns the failures in log. Unlike logparser.ParseLog,
+// it returns no failures for a log that doesn't have any, such as
+// the log of a passing build.
+func failures(log string) []*logparser.Fail {
+	fails := logparser.ParseLog(log)
+	if len(fails) == 1 {
+		// ParseLog describes a log without failures by its last line.
+		f := fails[0]
+		if f.Mode == "" && f.Pkg == "" && f.Test == "" && f.Function == "" && !strings.HasPrefix(f.Message, "build failed") {
+			return nil
+		}
+	}
+	return fails
+}
+
+// hasFailure reports whether fails has a failure with the signature of f.
+func hasFailure(fails []*logparser.Fail, f *logparser.Fail) bool {
+	for _, f1 := range fails {
+		if f1.Signature() == f.Signature() {
+			return true
+		}
+	}
+	return false
+}
+
+// text returns the text of lines.
+func text(lines []string) []byte {
+	var b bytes.Buffer
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const passLog = `linux-amd64 at 0123456789abcdef0123456789abcdef01234567
+
+##### Testing packages.
+ok  	net	12.345s
+ok  	os	1.234s
+ok  	strings	0.456s
+`
+
+const failLog = `linux-amd64 at 0123456789abcdef0123456789abcdef01234567
+
+##### Testing packages.
+--- FAIL: TestDial (0.10s)
+    dial_test.go:120: dial tcp 127.0.0.1:40123: connection refused
+FAIL
+FAIL	net	10.001s
+ok  	os	2.001s
+--- FAIL: TestNewFile (0.00s)
+    file_test.go:10: open /tmp/TestNewFile123/001/x: permission denied
+FAIL
+FAIL	os/exec	0.500s
+`
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		line, want string
+	}{
+		{"ok  \tnet\t12.345s", "ok  \tnet\t<dur>"},
+		{"--- FAIL: TestDial (0.10s)", "--- FAIL: TestDial (<dur>)"},
+		{"2024/01/02 15:04:05 started", "<time> started"},
+		{"at 2024-01-02T15:04:05.123Z:", "at <time>:"},
+		{"goroutine 17 [running]:", "goroutine <n> [running]:"},
+		{"\tnet/dial.go:120 +0x1f5", "\tnet/dial.go:120 +0x<hex>"},
+		{"dial tcp 127.0.0.1:40123: refused", "dial tcp 127.0.0.1:<port>: refused"},
+		{"open /tmp/TestX123/001/f: denied", "open <tmp>: denied"},
+		{"TestFoo2 fails 3 times   ", "TestFoo2 fails 3 times"},
+	}
+	for _, tt := range tests {
+		if got := normalize(tt.line); got != tt.want {
+			t.Errorf("normalize(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSplitLog(t *testing.T) {
+	var got []string
+	for _, u := range splitLog(failLog) {
+		got = append(got, u.String())
+	}
+	want := []string{
+		"(build)",
+		"Testing packages.: net.TestDial",
+		"Testing packages.: net",
+		"Testing packages.: os",
+		"Testing packages.: os/exec.TestNewFile",
+		"Testing packages.: os/exec",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("splitLog units:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffLogs(t *testing.T) {
+	if d := diffLogs("a", failLog, "b", strings.ReplaceAll(failLog, "40123", "50321")); d != nil {
+		t.Errorf("logs that differ only in ports have differences:\n%s", d)
+	}
+
+	d := string(diffLogs("pass", passLog, "fail", failLog))
+	for _, want := range []string{
+		"failure only in fail: net.TestDial at dial_test.go:120: dial tcp 127.0.0.1:40123: connection refused\n",
+		"failure only in fail: os/exec.TestNewFile at file_test.go:10: open /tmp/TestNewFile123/001/x: permission denied\n",
+		"=== Testing packages.: net\n",
+		"-ok  \tnet\t<dur>\n+FAIL\tnet\t<dur>\n",
+		"=== Testing packages.: strings: only in pass\n",
+		"=== Testing packages.: net.TestDial: only in fail\n",
+		"=== Testing packages.: os/exec: only in fail\n",
+	} {
+		if !strings.Contains(d, want) {
+			t.Errorf("diff doesn't contain %q:\n%s", want, d)
+		}
+	}
+	if strings.Contains(d, "=== Testing packages.: os\n") {
+		t.Errorf("diff shows os, which differs only in time:\n%s", d)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command difflogs compares two build logs, such as the logs of a
+// failing and a passing run of the same builder or of the same commit.
+//
+//	difflogs [flags] old new
+//
+// difflogs aligns the logs by build section, package and test, and
+// prints only their meaningful differences: the failures that are
+// only in one of the logs, the tests and packages that are only in one
+// of them, and the differences in the output of the others. Before
+// comparing lines, it replaces the parts that vary from run to run,
+// such as times, durations, addresses, goroutine IDs, temporary
+// directories and loopback ports.
+//
+// Each log can be a file, an http or https URL, or the path of a log
+// saved by fetchlogs (golang.org/x/build/cmd/fetchlogs) relative to its
+// rev/ directory, as greplogs prints it. A greplogs Markdown link of
+// the form [path](url) is also accepted, and uses the saved log if
+// there is one.
+//
+// The exit status is 0 if the logs have no meaningful differences,
+// 1 if they do, and 2 if there is an error.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/build/internal/xdg"
+)
+
+var flagDir = flag.String("dir", filepath.Join(xdg.CacheDir(), "fetchlogs"), "`directory` of the logs saved by fetchlogs")
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: difflogs [flags] old new\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetPrefix("difflogs: ")
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 2 {
+		usage()
+	}
+
+	oldName, old, err := readLog(flag.Arg(0))
+	if err != nil {
+		log.Print(err)
+		os.Exit(2)
+	}
+	newName, new, err := readLog(flag.Arg(1))
+	if err != nil {
+		log.Print(err)
+		os.Exit(2)
+	}
+	if oldName == newName {
+		oldName, newName = "old", "new"
+	}
+	d := diffLogs(oldName, old, newName, new)
+	if d == nil {
+		return
+	}
+	os.Stdout.Write(d)
+	os.Exit(1)
+}
+
+// mdLinkRE matches a Markdown link, as printed by greplogs.
+var mdLinkRE = regexp.MustCompile(`^(?:- \[[ x]\] (?:\(.*\) )?)?\[(.*)\]\((.*)\):?$`)
+
+// readLog reads the log named by arg, which is a file, a URL,
+// a greplogs path or a greplogs Markdown link. It returns a short
+// name for the log and its text.
+func readLog(arg string) (name, text string, err error) {
+	if m := mdLinkRE.FindStringSubmatch(arg); m != nil {
+		if file := savedLog(m[1]); file != "" {
+			return readFile(m[1], file)
+		}
+		arg = m[2]
+	}
+	if strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://") {
+		return fetchLog(arg)
+	}
+	if _, err := os.Stat(arg); err == nil {
+		return readFile(arg, arg)
+	}
+	if file := savedLog(arg); file != "" {
+		return readFile(arg, file)
+	}
+	return "", "", fmt.Errorf("%s: no such file or saved log", arg)
+}
+
+// savedLog returns the file of the log saved by fetchlogs
+// at path in its rev/ directory, or "" if there is none.
+func savedLog(path string) string {
+	file := filepath.Join(*flagDir, "rev", filepath.FromSlash(path))
+	if fi, err := os.Stat(file); err != nil || fi.IsDir() {
+		return ""
+	}
+	return file
+}
+
+func readFile(name, file string) (string, string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", "", err
+	}
+	return name, string(data), nil
+}
+
+// fetchLog fetches the log at rawURL.
+func fetchLog(rawURL string) (name, text string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Host == "logs.chromium.org" && u.RawQuery == "" {
+		// LUCI log viewer pages are HTML unless asked for the raw log.
+		u.RawQuery = "format=raw"
+	}
+	resp, err := http.Get(u.String())
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("GET %s: %v", u, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("GET %s: %v", u, err)
+	}
+	return rawURL, string(data), nil
+}
@@ -14,10 +14,26 @@
 //
 //	rev/<ISO 8601 commit date>-<git revision>/<builder>
 //
+// Fetchlogs also downloads the logs of the failed LUCI builds of the
+// same repos and branches from Buildbucket, unless the -luci flag is
+// false, and links them into the same revision directories, named by
+// their LUCI builders. It does so even if the dashboard can't be
+// reached, or if the -dashboard flag is empty, and if LUCI can't be
+// reached, it skips its logs. The log of LUCI build ID is saved as
+// log/luci-ID, and the outputs of its failed tests from ResultDB as
+// log/luci-ID.N. The file rev/<dir>/.<builder>.luci.json records the
+// build and the failed tests whose outputs those are.
+//
 // Fetchlogs will reuse existing log files and revision symlinks, so
 // it only has to download logs that are new since the last time it
 // was run.
 //
+// Fetchlogs also maintains a trigram index of the logs and the
+// metadata of their builds in the logindex file, which greplogs uses
+// to read only the logs that can match a query, and to filter them by
+// builder, revision and date without walking the rev/ directory. The
+// -index=false flag turns this off.
+//
 // This makes failures easily searchable with standard tools. For
 // example, to list the revisions and builders with a particular
 // failure, use:
@@ -42,13 +58,14 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/build/internal/xdg"
 	"golang.org/x/build/maintner"
 	"golang.org/x/build/maintner/godata"
 	"golang.org/x/build/repos"
 	"golang.org/x/build/types"
 )
 
-var defaultDir = filepath.Join(xdgCacheDir(), "fetchlogs")
+var defaultDir = filepath.Join(xdg.CacheDir(), "fetchlogs")
 
 var (
 	flagN         = flag.Int("n", 300, "limit to most recent `N` commits per repo")
@@ -56,7 +73,9 @@ var (
 	flagDir       = flag.String("dir", defaultDir, "`directory` to save logs to")
 	flagRepo      = flag.String("repo", "go", `comma-separated list of repos to fetch logs for, or "all" for all known repos`)
 	flagBranch    = flag.String("branch", "", `comma-separated list of Go repo branches to fetch logs for; default branch if empty`)
-	flagDashboard = flag.String("dashboard", "https://build.golang.org", `the dashboard root url`)
+	flagDashboard = flag.String("dashboard", "https://build.golang.org", `the dashboard root url, or "" to fetch only the logs of LUCI builds`)
+	flagIndex     = flag.Bool("index", true, "update the index of the logs used by greplogs")
+	flagLUCI      = flag.Bool("luci", true, "also fetch the logs of failed LUCI builds")
 )
 
 func main() {
@@ -72,7 +91,7 @@ func main() {
 	// If the top-level directory is the default XDG cache
 	// directory, make sure it exists.
 	if *flagDir == defaultDir {
-		if err := xdgCreateDir(*flagDir); err != nil {
+		if err := xdg.CreateDir(*flagDir); err != nil {
 			log.Fatal(err)
 		}
 	}
@@ -88,132 +107,159 @@ func main() {
 	fetcher := newFetcher(*flagPar)
 	wg := sync.WaitGroup{}
 
-	// Fetch dashboard pages.
-	for _, repo := range parseRepoFlag() {
-		for _, branch := range strings.Split(*flagBranch, ",") {
-			project := repo.GoGerritProject
-			haveCommits := 0
-			for page := 0; haveCommits < *flagN; page++ {
-				dashURL := fmt.Sprintf("%s/?mode=json&page=%d", *flagDashboard, page)
-				if project != "go" {
-					dashURL += "&repo=" + url.QueryEscape(repo.ImportPath)
+	// Fetch dashboard pages. The dashboard being unavailable
+	// doesn't keep the logs of LUCI from being fetched.
+	rs := parseRepoFlag()
+	branches := strings.Split(*flagBranch, ",")
+	if *flagDashboard != "" {
+		for _, repo := range rs {
+			for _, branch := range branches {
+				if err := fetchDashboard(fetcher, &wg, repo, branch); err != nil {
+					log.Printf("skipping the dashboard logs of %s: %v", repo.GoGerritProject, err)
 				}
-				if branch != "" {
-					dashURL += "&branch=" + url.QueryEscape(branch)
-				}
-				index, err := fetcher.get(dashURL)
+			}
+		}
+	}
+
+	// Fetch the logs of LUCI builds.
+	// LUCI being unavailable doesn't keep the logs of the
+	// dashboard from being indexed.
+	if *flagLUCI {
+		luci := newLUCIClient()
+		for _, lb := range luciBranches(rs, branches) {
+			err := fetchLUCI(context.Background(), luci, fetcher, &wg, lb.repo, lb.branch, lb.goBranch)
+			if err != nil {
+				log.Printf("skipping the LUCI builds of %s on %s: %v", lb.repo, lb.branch, err)
+			}
+		}
+	}
+
+	wg.Wait()
+
+	if *flagIndex {
+		if err := updateIndex(); err != nil {
+			log.Fatal("error updating index: ", err)
+		}
+	}
+}
+
+// fetchDashboard fetches the logs of the failed builds of the most
+// recent *flagN commits of repo on the dashboard, testing with Go
+// branch branch, or the default branch if it is empty. It adds the
+// downloads to wg.
+func fetchDashboard(fetcher *fetcher, wg *sync.WaitGroup, repo *repos.Repo, branch string) error {
+	project := repo.GoGerritProject
+	haveCommits := 0
+	for page := 0; haveCommits < *flagN; page++ {
+		dashURL := fmt.Sprintf("%s/?mode=json&page=%d", *flagDashboard, page)
+		if project != "go" {
+			dashURL += "&repo=" + url.QueryEscape(repo.ImportPath)
+		}
+		if branch != "" {
+			dashURL += "&branch=" + url.QueryEscape(branch)
+		}
+		index, err := fetcher.get(dashURL)
+		if err != nil {
+			return err
+		}
+
+		var status types.BuildStatus
+		err = json.NewDecoder(index).Decode(&status)
+		index.Close()
+		if err != nil {
+			return fmt.Errorf("error unmarshalling result: %v", err)
+		}
+
+		if len(status.Revisions) == 0 {
+			// We asked for a page of revisions and received a valid reply with none.
+			// Assume that there are no more beyond this.
+			break
+		}
+
+		for _, rev := range status.Revisions {
+			if haveCommits >= *flagN {
+				break
+			}
+			if rev.Repo != project {
+				// The results for the "go" repo (fetched without the "&repo" query
+				// parameter) empirically include some subrepo results for release
+				// branches.
+				//
+				// Those aren't really relevant to the "go" repo — and they should be
+				// included when we fetch the subrepo explicitly anyway — so filter
+				// them out here.
+				continue
+			}
+			haveCommits++
+
+			// Create a revision directory. This way we
+			// have a record of commits with no failures.
+			date, err := parseRevDate(rev.Date)
+			if err != nil {
+				return fmt.Errorf("malformed revision date: %v", err)
+			}
+			var goDate time.Time
+			if rev.GoRevision != "" {
+				goDate, err = goCommitTime(rev.GoRevision)
 				if err != nil {
+					return err
+				}
+			}
+			revDir, revDirDepth := revToDir(rev.Revision, date, rev.GoRevision, goDate)
+			ensureDir(revDir)
+
+			if rev.GoRevision != "" {
+				// In October 2021 we started creating a separate subdirectory for
+				// each Go repo commit. (Previously, we overwrote the link for each
+				// subrepo commit when downloading a new Go commit.) Remove the
+				// previous links, if any, so that greplogs won't double-count them.
+				prevRevDir, _ := revToDir(rev.Revision, date, "", time.Time{})
+				if err := os.RemoveAll(prevRevDir); err != nil {
 					log.Fatal(err)
 				}
+			}
 
-				var status types.BuildStatus
-				if err = json.NewDecoder(index).Decode(&status); err != nil {
-					log.Fatal("error unmarshalling result: ", err)
-				}
-				index.Close()
+			// Save revision metadata.
+			buf := bytes.Buffer{}
+			enc := json.NewEncoder(&buf)
+			if err = enc.Encode(rev); err != nil {
+				log.Fatal(err)
+			}
+			if err = writeFileAtomic(filepath.Join(revDir, ".rev.json"), &buf); err != nil {
+				log.Fatal("error saving revision metadata: ", err)
+			}
 
-				if len(status.Revisions) == 0 {
-					// We asked for a page of revisions and received a valid reply with none.
-					// Assume that there are no more beyond this.
-					break
-				}
+			// Save builders list so Results list can be
+			// interpreted.
+			if err = enc.Encode(status.Builders); err != nil {
+				log.Fatal(err)
+			}
+			if err = writeFileAtomic(filepath.Join(revDir, ".builders.json"), &buf); err != nil {
+				log.Fatal("error saving builders metadata: ", err)
+			}
 
-				for _, rev := range status.Revisions {
-					if haveCommits >= *flagN {
-						break
-					}
-					if rev.Repo != project {
-						// The results for the "go" repo (fetched without the "&repo" query
-						// parameter) empirically include some subrepo results for release
-						// branches.
-						//
-						// Those aren't really relevant to the "go" repo — and they should be
-						// included when we fetch the subrepo explicitly anyway — so filter
-						// them out here.
-						continue
-					}
-					haveCommits++
+			// Fetch revision logs.
+			for i, res := range rev.Results {
+				if res == "" || res == "ok" {
+					continue
+				}
 
-					// Create a revision directory. This way we
-					// have a record of commits with no failures.
-					date, err := parseRevDate(rev.Date)
+				wg.Add(1)
+				go func(builder, logURL string) {
+					defer wg.Done()
+					logPath := filepath.Join("log", filepath.Base(logURL))
+					err := fetcher.getFile(logURL, logPath)
 					if err != nil {
-						log.Fatal("malformed revision date: ", err)
-					}
-					var goDate time.Time
-					if rev.GoRevision != "" {
-						commit, err := goProject(useCached).GitCommit(rev.GoRevision)
-						if err != nil {
-							// A rare race is possible here: if a commit is added to the Go repo
-							// after the initial maintner load, and a dashboard test run completes
-							// for that commit before we're done fetching logs, the maintner data
-							// might not include that commit. To rule out that possibility, refresh
-							// the local maintner data before bailing out.
-							commit, err = goProject(forceRefresh).GitCommit(rev.GoRevision)
-							if err != nil {
-								log.Fatal("invalid GoRevision: ", err)
-							}
-						}
-						goDate = commit.CommitTime
+						log.Fatal("error fetching log: ", err)
 					}
-					revDir, revDirDepth := revToDir(rev.Revision, date, rev.GoRevision, goDate)
-					ensureDir(revDir)
-
-					if rev.GoRevision != "" {
-						// In October 2021 we started creating a separate subdirectory for
-						// each Go repo commit. (Previously, we overwrote the link for each
-						// subrepo commit when downloading a new Go commit.) Remove the
-						// previous links, if any, so that greplogs won't double-count them.
-						prevRevDir, _ := revToDir(rev.Revision, date, "", time.Time{})
-						if err := os.RemoveAll(prevRevDir); err != nil {
-							log.Fatal(err)
-						}
+					if err := linkLog(revDir, revDirDepth, builder, logPath); err != nil {
+						log.Fatal("error linking log: ", err)
 					}
-
-					// Save revision metadata.
-					buf := bytes.Buffer{}
-					enc := json.NewEncoder(&buf)
-					if err = enc.Encode(rev); err != nil {
-						log.Fatal(err)
-					}
-					if err = writeFileAtomic(filepath.Join(revDir, ".rev.json"), &buf); err != nil {
-						log.Fatal("error saving revision metadata: ", err)
-					}
-
-					// Save builders list so Results list can be
-					// interpreted.
-					if err = enc.Encode(status.Builders); err != nil {
-						log.Fatal(err)
-					}
-					if err = writeFileAtomic(filepath.Join(revDir, ".builders.json"), &buf); err != nil {
-						log.Fatal("error saving builders metadata: ", err)
-					}
-
-					// Fetch revision logs.
-					for i, res := range rev.Results {
-						if res == "" || res == "ok" {
-							continue
-						}
-
-						wg.Add(1)
-						go func(builder, logURL string) {
-							defer wg.Done()
-							logPath := filepath.Join("log", filepath.Base(logURL))
-							err := fetcher.getFile(logURL, logPath)
-							if err != nil {
-								log.Fatal("error fetching log: ", err)
-							}
-							if err := linkLog(revDir, revDirDepth, builder, logPath); err != nil {
-								log.Fatal("error linking log: ", err)
-							}
-						}(status.Builders[i], res)
-					}
-				}
+				}(status.Builders[i], res)
 			}
 		}
 	}
-
-	wg.Wait()
+	return nil
 }
 
 func parseRepoFlag() (rs []*repos.Repo) {
@@ -260,7 +306,7 @@ func parseRepoFlag() (rs []*repos.Repo) {
 			// right away (in which case we can't hide any substantial latency) or not
 			// at all (in which case we shouldn't bother churning memory and disk
 			// pages to load it).
-			_ = goProject(useCached)
+			goProject(useCached)
 		}()
 	}
 
@@ -373,17 +419,13 @@ func getGoProject(ctx context.Context) (*maintner.GerritProject, error) {
 	return gp, nil
 }
 
-func goProject(policy refreshPolicy) *maintner.GerritProject {
+func goProject(policy refreshPolicy) (*maintner.GerritProject, error) {
 	goProjectMu.Lock()
 	defer goProjectMu.Unlock()
 	if policy == forceRefresh || (cachedGoProject == nil && goProjectErr == nil) {
 		cachedGoProject, goProjectErr = getGoProject(context.Background())
 	}
-
-	if goProjectErr != nil {
-		log.Fatal(goProjectErr)
-	}
-	return cachedGoProject
+	return cachedGoProject, goProjectErr
 }
 
 type refreshPolicy int8
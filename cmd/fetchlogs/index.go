@@ -0,0 +1,149 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/build/internal/logindex"
+	"golang.org/x/build/types"
+)
+
+// updateIndex adds the logs in the log/ directory that aren't in the
+// index yet to it, and records the metadata of the logs in the rev/
+// directory, so that greplogs can find the logs that match a query
+// without reading all of them.
+func updateIndex() error {
+	ix, err := logindex.Open(logindex.FileName)
+	if err != nil {
+		return err
+	}
+
+	// Find the trigrams of the new logs in parallel,
+	// but add them one at a time.
+	entries, err := os.ReadDir("log")
+	if err != nil {
+		return err
+	}
+	type result struct {
+		name string
+		ts   logindex.Trigrams
+		err  error
+	}
+	names := make(chan string)
+	results := make(chan result, *flagPar)
+	var wg sync.WaitGroup
+	for i := 0; i < *flagPar; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				ts, err := logindex.FileTrigrams(filepath.Join("log", name))
+				results <- result{name, ts, err}
+			}
+		}()
+	}
+	go func() {
+		for _, e := range entries {
+			name := e.Name()
+			if !e.Type().IsRegular() || strings.HasSuffix(name, ".tmp") || ix.Has(name) {
+				continue
+			}
+			names <- name
+		}
+		close(names)
+		wg.Wait()
+		close(results)
+	}()
+	added := 0
+	for r := range results {
+		if r.err != nil && err == nil {
+			err = r.err
+		}
+		if r.err == nil {
+			ix.Add(r.name, r.ts)
+			added++
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	docs, err := revDocs()
+	if err != nil {
+		return err
+	}
+	ix.SetDocs(docs)
+	if err := ix.Write(logindex.FileName); err != nil {
+		return err
+	}
+	fmt.Printf("indexed %d new logs, %d in all\n", added, ix.Len())
+	return nil
+}
+
+// revDocs returns the metadata of the logs linked from the revision
+// directories in rev/.
+func revDocs() ([]*logindex.Doc, error) {
+	revs, err := os.ReadDir("rev")
+	if err != nil {
+		return nil, err
+	}
+	var docs []*logindex.Doc
+	for _, rev := range revs {
+		if !rev.IsDir() {
+			continue
+		}
+		revDir := filepath.Join("rev", rev.Name())
+		// The directory name starts with the later of
+		// the commit dates of the revision and the Go revision,
+		// which is what greplogs filters by.
+		const layout = "2006-01-02T15:04:05"
+		if len(rev.Name()) < len(layout) {
+			continue
+		}
+		date, err := time.Parse(layout, rev.Name()[:len(layout)])
+		if err != nil {
+			continue
+		}
+		var meta types.BuildRevision
+		if data, err := os.ReadFile(filepath.Join(revDir, ".rev.json")); err == nil {
+			if err := json.Unmarshal(data, &meta); err != nil {
+				return nil, fmt.Errorf("%s: %v", revDir, err)
+			}
+		}
+		links, err := os.ReadDir(revDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range links {
+			if strings.HasPrefix(l.Name(), ".") || l.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			path := filepath.Join(revDir, l.Name())
+			target, err := os.Readlink(path)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, &logindex.Doc{
+				Path:       path,
+				Log:        filepath.Base(target),
+				Builder:    l.Name(),
+				Repo:       meta.Repo,
+				Branch:     meta.Branch,
+				Revision:   meta.Revision,
+				GoBranch:   meta.GoBranch,
+				GoRevision: meta.GoRevision,
+				Date:       date,
+			})
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,494 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	bbpb "go.chromium.org/luci/buildbucket/proto"
+	"go.chromium.org/luci/common/api/gitiles"
+	gitpb "go.chromium.org/luci/common/proto/git"
+	gpb "go.chromium.org/luci/common/proto/gitiles"
+	"go.chromium.org/luci/grpc/prpc"
+	rdbpb "go.chromium.org/luci/resultdb/proto/v1"
+	"golang.org/x/build/repos"
+	"golang.org/x/build/types"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const resultDBHost = "results.api.cr.dev"
+const crBuildBucketHost = "cr-buildbucket.appspot.com"
+const gitilesHost = "go.googlesource.com"
+
+// luciClient is a client of the LUCI services that run the Go builders.
+type luciClient struct {
+	gitiles  gpb.GitilesClient
+	builds   bbpb.BuildsClient
+	builders bbpb.BuildersClient
+	resultDB rdbpb.ResultDBClient
+}
+
+func newLUCIClient() *luciClient {
+	c := new(http.Client)
+	gitilesClient, err := gitiles.NewRESTClient(c, gitilesHost, false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return &luciClient{
+		gitiles:  gitilesClient,
+		builds:   bbpb.NewBuildsClient(&prpc.Client{C: c, Host: crBuildBucketHost}),
+		builders: bbpb.NewBuildersClient(&prpc.Client{C: c, Host: crBuildBucketHost}),
+		resultDB: rdbpb.NewResultDBClient(&prpc.Client{C: c, Host: resultDBHost}),
+	}
+}
+
+// A luciBuild is the record of a failed LUCI build that fetchlogs
+// saves next to the link to its log, in rev/<dir>/.<builder>.luci.json.
+type luciBuild struct {
+	ID       int64
+	URL      string // build page
+	Status   string
+	LogURL   string // log of the build, linked as rev/<dir>/<builder>
+	Failures []*luciFailure
+}
+
+// A luciFailure is a failed test of a luciBuild.
+type luciFailure struct {
+	TestID string
+	Status string
+	URL    string // ResultDB artifact with the output of the test
+	Log    string // path of the saved output, as log/luci-<build ID>.<n>
+}
+
+// A luciBranch is a branch of a repo to fetch the logs of LUCI builds
+// of, and the Go branch they test with.
+type luciBranch struct{ repo, branch, goBranch string }
+
+// luciBranches returns the branches of rs to fetch the logs of LUCI
+// builds for, testing with the Go branches goBranches, as given by the
+// -repo and -branch flags. An empty Go branch is the default, master.
+// Other repos than go are tested at master against each Go branch,
+// like the post-submit builders whose go_branch property is that branch.
+func luciBranches(rs []*repos.Repo, goBranches []string) []luciBranch {
+	var lbs []luciBranch
+	for _, r := range rs {
+		for _, goBranch := range goBranches {
+			goBranch = strings.TrimSpace(goBranch)
+			if goBranch == "" {
+				goBranch = "master"
+			}
+			branch := goBranch
+			if r.GoGerritProject != "go" {
+				branch = "master"
+			}
+			lbs = append(lbs, luciBranch{r.GoGerritProject, branch, goBranch})
+		}
+	}
+	return lbs
+}
+
+// fetchLUCI fetches the logs of the failed LUCI builds of the most
+// recent *flagN commits on branch of repo, testing with Go branch
+// goBranch, and links them into the revision directories like the logs
+// of the dashboard. It adds the downloads to wg, which log their errors
+// rather than stopping fetchlogs, like the error fetchLUCI returns.
+func fetchLUCI(ctx context.Context, c *luciClient, f *fetcher, wg *sync.WaitGroup, repo, branch, goBranch string) error {
+	commits, err := c.listCommits(ctx, repo, branch, *flagN)
+	if err != nil {
+		return fmt.Errorf("listing commits: %v", err)
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+	byHash := make(map[string]*gitpb.Commit)
+	since := time.Now()
+	for _, commit := range commits {
+		byHash[commit.GetId()] = commit
+		if t := commit.GetCommitter().GetTime().AsTime(); t.Before(since) {
+			since = t
+		}
+	}
+	builders, err := c.listBuilders(ctx, repo, goBranch)
+	if err != nil {
+		return fmt.Errorf("listing LUCI builders: %v", err)
+	}
+
+	// Find the failed builds of the commits. If a builder failed
+	// more than once on a commit, keep the build that ended last.
+	type key struct{ builder, commit, goCommit string }
+	var mu sync.Mutex
+	latest := make(map[key]*bbpb.Build)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(*flagPar)
+	for _, builder := range builders {
+		builder := builder
+		g.Go(func() error {
+			builds, err := c.failedBuilds(gctx, builder, since)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, b := range builds {
+				commit, goCommit := buildSources(repo, b)
+				if byHash[commit] == nil {
+					continue
+				}
+				k := key{builder, commit, goCommit}
+				if b0 := latest[k]; b0 == nil || b0.GetEndTime().AsTime().Before(b.GetEndTime().AsTime()) {
+					latest[k] = b
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("searching LUCI builds: %v", err)
+	}
+
+	for k, b := range latest {
+		commit := byHash[k.commit]
+		date := commit.GetCommitter().GetTime().AsTime()
+		var goDate time.Time
+		if k.goCommit != "" {
+			var err error
+			goDate, err = goCommitTime(k.goCommit)
+			if err != nil {
+				log.Printf("skipping LUCI build %d: %v", b.GetId(), err)
+				continue
+			}
+		}
+		revDir, revDirDepth := revToDir(k.commit, date, k.goCommit, goDate)
+		ensureDir(revDir)
+
+		// Save revision metadata, unless the dashboard has.
+		revPath := filepath.Join(revDir, ".rev.json")
+		if _, err := os.Stat(revPath); os.IsNotExist(err) {
+			rev := types.BuildRevision{
+				Repo:       repo,
+				Revision:   k.commit,
+				GoRevision: k.goCommit,
+				Date:       date.Format(time.RFC3339),
+				Branch:     branch,
+				Author:     fmt.Sprintf("%s <%s>", commit.GetAuthor().GetName(), commit.GetAuthor().GetEmail()),
+				Desc:       commit.GetMessage(),
+			}
+			if repo != "go" {
+				rev.GoBranch = goBranch
+			}
+			buf := bytes.Buffer{}
+			if err := json.NewEncoder(&buf).Encode(rev); err != nil {
+				return err
+			}
+			if err := writeFileAtomic(revPath, &buf); err != nil {
+				return fmt.Errorf("saving revision metadata: %v", err)
+			}
+		}
+
+		wg.Add(1)
+		go func(builder string, b *bbpb.Build) {
+			defer wg.Done()
+			if err := fetchLUCIBuild(ctx, c, f, revDir, revDirDepth, builder, b); err != nil {
+				log.Printf("error fetching LUCI build %d: %v", b.GetId(), err)
+			}
+		}(k.builder, b)
+	}
+	return nil
+}
+
+// fetchLUCIBuild downloads the log and the outputs of the failed tests
+// of the build b and links the log into revDir.
+func fetchLUCIBuild(ctx context.Context, c *luciClient, f *fetcher, revDir string, revDirDepth int, builder string, b *bbpb.Build) error {
+	lb := &luciBuild{
+		ID:     b.GetId(),
+		URL:    fmt.Sprintf("https://ci.chromium.org/b/%d", b.GetId()),
+		Status: b.GetStatus().String(),
+		LogURL: buildLogURL(b),
+	}
+	if lb.LogURL == "" {
+		fmt.Printf("no log url: %s\n", lb.URL)
+		return nil
+	}
+	<-f.tokens // obey the job limit of the downloads
+	failures, err := c.failedTests(ctx, b.GetInfra().GetResultdb().GetInvocation())
+	f.tokens <- struct{}{}
+	if err != nil {
+		return err
+	}
+	for i, fail := range failures {
+		fail.Log = filepath.Join("log", fmt.Sprintf("luci-%d.%d", lb.ID, i))
+		if err := f.getFile(fail.URL, fail.Log); err != nil {
+			return err
+		}
+	}
+	lb.Failures = failures
+
+	logPath := filepath.Join("log", fmt.Sprintf("luci-%d", lb.ID))
+	if err := f.getFile(lb.LogURL+"?format=raw", logPath); err != nil {
+		return err
+	}
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(lb); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(revDir, "."+builder+".luci.json"), &buf); err != nil {
+		return err
+	}
+	return linkLog(revDir, revDirDepth, builder, logPath)
+}
+
+// goCommitTime returns the commit time of the Go commit rev.
+func goCommitTime(rev string) (time.Time, error) {
+	gp, err := goProject(useCached)
+	if err != nil {
+		return time.Time{}, err
+	}
+	commit, err := gp.GitCommit(rev)
+	if err != nil {
+		// A rare race is possible here: if a commit is added to the Go repo
+		// after the initial maintner load, and a test run completes
+		// for that commit before we're done fetching logs, the maintner data
+		// might not include that commit. To rule out that possibility, refresh
+		// the local maintner data before bailing out.
+		gp, err = goProject(forceRefresh)
+		if err != nil {
+			return time.Time{}, err
+		}
+		commit, err = gp.GitCommit(rev)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid GoRevision: %v", err)
+		}
+	}
+	return commit.CommitTime, nil
+}
+
+// listCommits returns the n most recent commits on branch of repo.
+func (c *luciClient) listCommits(ctx context.Context, repo, branch string, n int) ([]*gitpb.Commit, error) {
+	var commits []*gitpb.Commit
+	var pageToken string
+nextPage:
+	resp, err := c.gitiles.Log(ctx, &gpb.LogRequest{
+		Project:    repo,
+		Committish: "refs/heads/" + branch,
+		PageSize:   int32(min(n-len(commits), 1000)),
+		PageToken:  pageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	commits = append(commits, resp.GetLog()...)
+	if len(commits) < n && resp.GetNextPageToken() != "" {
+		pageToken = resp.GetNextPageToken()
+		goto nextPage
+	}
+	if len(commits) > n {
+		commits = commits[:n]
+	}
+	return commits, nil
+}
+
+// listBuilders returns the names of the post-submit builders
+// for repo and goBranch.
+func (c *luciClient) listBuilders(ctx context.Context, repo, goBranch string) ([]string, error) {
+	var builders []string
+	var pageToken string
+nextPage:
+	resp, err := c.builders.ListBuilders(ctx, &bbpb.ListBuildersRequest{
+		Project:   "golang",
+		Bucket:    "ci",
+		PageSize:  1000,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range resp.GetBuilders() {
+		var p struct {
+			Repo     string `json:"project,omitempty"`
+			GoBranch string `json:"go_branch,omitempty"`
+		}
+		if err := json.Unmarshal([]byte(b.GetConfig().GetProperties()), &p); err != nil {
+			return nil, err
+		}
+		if p.Repo == repo && p.GoBranch == goBranch {
+			builders = append(builders, b.GetId().GetBuilder())
+		}
+	}
+	if resp.GetNextPageToken() != "" {
+		pageToken = resp.GetNextPageToken()
+		goto nextPage
+	}
+	slices.Sort(builders)
+	return builders, nil
+}
+
+// failedBuilds returns the failed builds of builder created since then.
+func (c *luciClient) failedBuilds(ctx context.Context, builder string, since time.Time) ([]*bbpb.Build, error) {
+	pred := &bbpb.BuildPredicate{
+		Builder:    &bbpb.BuilderID{Project: "golang", Bucket: "ci", Builder: builder},
+		Status:     bbpb.Status_FAILURE,
+		CreateTime: &bbpb.TimeRange{StartTime: timestamppb.New(since)},
+	}
+	mask, err := fieldmaskpb.New((*bbpb.Build)(nil), "id", "builder", "output", "status", "steps", "infra", "end_time")
+	if err != nil {
+		return nil, err
+	}
+	var builds []*bbpb.Build
+	var pageToken string
+nextPage:
+	resp, err := c.builds.SearchBuilds(ctx, &bbpb.SearchBuildsRequest{
+		Predicate: pred,
+		Mask:      &bbpb.BuildMask{Fields: mask},
+		PageSize:  1000,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	builds = append(builds, resp.GetBuilds()...)
+	if resp.GetNextPageToken() != "" {
+		pageToken = resp.GetNextPageToken()
+		goto nextPage
+	}
+	return builds, nil
+}
+
+// failedTests returns the failed tests of the ResultDB invocation
+// that have output, sorted by test ID.
+func (c *luciClient) failedTests(ctx context.Context, invocation string) ([]*luciFailure, error) {
+	if invocation == "" {
+		return nil, nil
+	}
+	var results []*rdbpb.TestResult
+	var pageToken string
+nextPage:
+	resp, err := c.resultDB.QueryTestResults(ctx, &rdbpb.QueryTestResultsRequest{
+		Invocations: []string{invocation},
+		Predicate:   &rdbpb.TestResultPredicate{Expectancy: rdbpb.TestResultPredicate_VARIANTS_WITH_UNEXPECTED_RESULTS},
+		PageSize:    1000,
+		PageToken:   pageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, resp.GetTestResults()...)
+	if resp.GetNextPageToken() != "" {
+		pageToken = resp.GetNextPageToken()
+		goto nextPage
+	}
+
+	var failures []*luciFailure
+	for _, rr := range results {
+		artifacts, err := c.testArtifacts(ctx, invocation, rr.GetTestId())
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range artifacts {
+			if a.GetArtifactId() == "output" {
+				failures = append(failures, &luciFailure{
+					TestID: rr.GetTestId(),
+					Status: rr.GetStatus().String(),
+					URL:    a.GetFetchUrl(),
+				})
+			}
+		}
+	}
+	slices.SortFunc(failures, func(f1, f2 *luciFailure) int {
+		return strings.Compare(f1.TestID, f2.TestID)
+	})
+	return failures, nil
+}
+
+// testArtifacts returns the artifacts of the unexpected results
+// of the test testID in the ResultDB invocation.
+func (c *luciClient) testArtifacts(ctx context.Context, invocation, testID string) ([]*rdbpb.Artifact, error) {
+	var artifacts []*rdbpb.Artifact
+	var pageToken string
+nextPage:
+	resp, err := c.resultDB.QueryArtifacts(ctx, &rdbpb.QueryArtifactsRequest{
+		Invocations: []string{invocation},
+		Predicate: &rdbpb.ArtifactPredicate{
+			TestResultPredicate: &rdbpb.TestResultPredicate{
+				TestIdRegexp: regexp.QuoteMeta(testID),
+				Expectancy:   rdbpb.TestResultPredicate_VARIANTS_WITH_UNEXPECTED_RESULTS,
+			},
+		},
+		PageSize:  1000,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	artifacts = append(artifacts, resp.GetArtifacts()...)
+	if resp.GetNextPageToken() != "" {
+		pageToken = resp.GetNextPageToken()
+		goto nextPage
+	}
+	return artifacts, nil
+}
+
+// buildSources returns the commit of repo and the Go commit
+// that the build b tested. goCommit is empty if repo is "go".
+func buildSources(repo string, b *bbpb.Build) (commit, goCommit string) {
+	prop := b.GetOutput().GetProperties().GetFields()
+	for _, s := range prop["sources"].GetListValue().GetValues() {
+		x := s.GetStructValue().GetFields()["gitilesCommit"].GetStructValue().GetFields()
+		c := x["id"].GetStringValue()
+		switch x["project"].GetStringValue() {
+		case repo:
+			commit = c
+		case "go":
+			goCommit = c
+		}
+	}
+	return commit, goCommit
+}
+
+// buildLogURL returns the URL of the log of the failed build b:
+// the combined output of its tests, or, for a build failure,
+// the output of the failed step or of the build.
+func buildLogURL(b *bbpb.Build) string {
+	links := b.GetOutput().GetProperties().GetFields()["failure"].GetStructValue().GetFields()["links"].GetListValue().GetValues()
+	for _, l := range links {
+		m := l.GetStructValue().GetFields()
+		if strings.Contains(m["name"].GetStringValue(), "(combined output)") {
+			return m["url"].GetStringValue()
+		}
+	}
+	steps := b.GetSteps()
+	for i := len(steps) - 1; i >= 0; i-- {
+		s := steps[i]
+		if s.GetStatus() != bbpb.Status_FAILURE {
+			continue
+		}
+		for _, l := range s.GetLogs() {
+			if l.GetName() == "stderr" || l.GetName() == "output" {
+				return l.GetViewUrl()
+			}
+		}
+	}
+	for _, l := range b.GetOutput().GetLogs() {
+		if l.GetName() == "stderr" {
+			return l.GetViewUrl()
+		}
+	}
+	return ""
+}
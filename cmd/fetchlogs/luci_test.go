@@ -0,0 +1,365 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	bbpb "go.chromium.org/luci/buildbucket/proto"
+	gitpb "go.chromium.org/luci/common/proto/git"
+	gpb "go.chromium.org/luci/common/proto/gitiles"
+	rdbpb "go.chromium.org/luci/resultdb/proto/v1"
+	"golang.org/x/build/repos"
+	"golang.org/x/build/types"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testBuild returns a build with the given output properties.
+func testBuild(t *testing.T, id int64, props map[string]any) *bbpb.Build {
+	t.Helper()
+	s, err := structpb.NewStruct(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &bbpb.Build{
+		Id:     id,
+		Status: bbpb.Status_FAILURE,
+		Output: &bbpb.Build_Output{Properties: s},
+	}
+}
+
+func sources(commits ...string) map[string]any {
+	var srcs []any
+	for i := 0; i < len(commits); i += 2 {
+		srcs = append(srcs, map[string]any{
+			"gitilesCommit": map[string]any{"project": commits[i], "id": commits[i+1]},
+		})
+	}
+	return map[string]any{"sources": srcs}
+}
+
+func TestBuildSources(t *testing.T) {
+	tests := []struct {
+		repo                     string
+		props                    map[string]any
+		wantCommit, wantGoCommit string
+	}{
+		{"go", sources("go", "aaaa"), "aaaa", ""},
+		{"tools", sources("tools", "bbbb", "go", "aaaa"), "bbbb", "aaaa"},
+		{"tools", sources("go", "aaaa"), "", "aaaa"},
+		{"go", map[string]any{}, "", ""},
+	}
+	for _, tt := range tests {
+		commit, goCommit := buildSources(tt.repo, testBuild(t, 1, tt.props))
+		if commit != tt.wantCommit || goCommit != tt.wantGoCommit {
+			t.Errorf("buildSources(%q, %v) = %q, %q; want %q, %q", tt.repo, tt.props, commit, goCommit, tt.wantCommit, tt.wantGoCommit)
+		}
+	}
+}
+
+func TestBuildLogURL(t *testing.T) {
+	combined := testBuild(t, 1, map[string]any{
+		"failure": map[string]any{"links": []any{
+			map[string]any{"name": "net.TestDial", "url": "https://logs.example/test"},
+			map[string]any{"name": "go test (combined output)", "url": "https://logs.example/combined"},
+		}},
+	})
+	failedStep := testBuild(t, 2, nil)
+	failedStep.Steps = []*bbpb.Step{
+		{Name: "build", Status: bbpb.Status_FAILURE, Logs: []*bbpb.Log{{Name: "stderr", ViewUrl: "https://logs.example/build"}}},
+		{Name: "test", Status: bbpb.Status_SUCCESS, Logs: []*bbpb.Log{{Name: "stderr", ViewUrl: "https://logs.example/test"}}},
+	}
+	buildStderr := testBuild(t, 3, nil)
+	buildStderr.Output.Logs = []*bbpb.Log{{Name: "stderr", ViewUrl: "https://logs.example/stderr"}}
+
+	tests := []struct {
+		b    *bbpb.Build
+		want string
+	}{
+		{combined, "https://logs.example/combined"},
+		{failedStep, "https://logs.example/build"},
+		{buildStderr, "https://logs.example/stderr"},
+		{testBuild(t, 4, nil), ""},
+	}
+	for _, tt := range tests {
+		if got := buildLogURL(tt.b); got != tt.want {
+			t.Errorf("buildLogURL(build %d) = %q; want %q", tt.b.GetId(), got, tt.want)
+		}
+	}
+}
+
+// Fake LUCI services. They embed the interfaces they fake,
+// so calling any other method panics.
+type (
+	fakeGitiles struct {
+		gpb.GitilesClient
+		commits []*gitpb.Commit
+	}
+	fakeBuilders struct {
+		bbpb.BuildersClient
+		builders []*bbpb.BuilderItem
+	}
+	fakeBuilds struct {
+		bbpb.BuildsClient
+		builds map[string][]*bbpb.Build // by builder
+	}
+	fakeResultDB struct {
+		rdbpb.ResultDBClient
+		results   []*rdbpb.TestResult
+		artifacts map[string][]*rdbpb.Artifact // by test ID
+	}
+)
+
+func (c *fakeGitiles) Log(ctx context.Context, req *gpb.LogRequest, opts ...grpc.CallOption) (*gpb.LogResponse, error) {
+	if req.Committish != "refs/heads/master" {
+		return nil, fmt.Errorf("unexpected committish %q", req.Committish)
+	}
+	return &gpb.LogResponse{Log: c.commits}, nil
+}
+
+func (c *fakeBuilders) ListBuilders(ctx context.Context, req *bbpb.ListBuildersRequest, opts ...grpc.CallOption) (*bbpb.ListBuildersResponse, error) {
+	return &bbpb.ListBuildersResponse{Builders: c.builders}, nil
+}
+
+func (c *fakeBuilds) SearchBuilds(ctx context.Context, req *bbpb.SearchBuildsRequest, opts ...grpc.CallOption) (*bbpb.SearchBuildsResponse, error) {
+	return &bbpb.SearchBuildsResponse{Builds: c.builds[req.GetPredicate().GetBuilder().GetBuilder()]}, nil
+}
+
+// page returns the page of items starting at the index in pageToken,
+// one item per page, and the token of the next page.
+func page[T any](items []T, pageToken string) ([]T, string) {
+	i, _ := strconv.Atoi(pageToken)
+	if i >= len(items) {
+		return nil, ""
+	}
+	next := ""
+	if i+1 < len(items) {
+		next = strconv.Itoa(i + 1)
+	}
+	return items[i : i+1], next
+}
+
+func (c *fakeResultDB) QueryTestResults(ctx context.Context, req *rdbpb.QueryTestResultsRequest, opts ...grpc.CallOption) (*rdbpb.QueryTestResultsResponse, error) {
+	results, next := page(c.results, req.GetPageToken())
+	return &rdbpb.QueryTestResultsResponse{TestResults: results, NextPageToken: next}, nil
+}
+
+func (c *fakeResultDB) QueryArtifacts(ctx context.Context, req *rdbpb.QueryArtifactsRequest, opts ...grpc.CallOption) (*rdbpb.QueryArtifactsResponse, error) {
+	for id, as := range c.artifacts {
+		if strings.Contains(req.GetPredicate().GetTestResultPredicate().GetTestIdRegexp(), id) {
+			as, next := page(as, req.GetPageToken())
+			return &rdbpb.QueryArtifactsResponse{Artifacts: as, NextPageToken: next}, nil
+		}
+	}
+	return &rdbpb.QueryArtifactsResponse{}, nil
+}
+
+func TestFailedTests(t *testing.T) {
+	c := &luciClient{resultDB: &fakeResultDB{
+		results: []*rdbpb.TestResult{
+			{TestId: "os.TestOpen", Status: rdbpb.TestStatus_FAIL},
+			{TestId: "net.TestDial", Status: rdbpb.TestStatus_CRASH},
+			{TestId: "io.TestCopy", Status: rdbpb.TestStatus_FAIL},
+		},
+		artifacts: map[string][]*rdbpb.Artifact{
+			"TestOpen": {{ArtifactId: "stderr"}, {ArtifactId: "output", FetchUrl: "https://results.example/TestOpen"}},
+			"TestDial": {{ArtifactId: "output", FetchUrl: "https://results.example/TestDial"}},
+		},
+	}}
+	got, err := c.failedTests(context.Background(), "invocations/build-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*luciFailure{
+		{TestID: "net.TestDial", Status: "CRASH", URL: "https://results.example/TestDial"},
+		{TestID: "os.TestOpen", Status: "FAIL", URL: "https://results.example/TestOpen"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("failedTests = %+v; want %+v", got, want)
+	}
+}
+
+func TestLUCIBranches(t *testing.T) {
+	rs := []*repos.Repo{repos.ByGerritProject["go"], repos.ByGerritProject["tools"]}
+	got := luciBranches(rs, []string{"", "release-branch.go1.21"})
+	want := []luciBranch{
+		{"go", "master", "master"},
+		{"go", "release-branch.go1.21", "release-branch.go1.21"},
+		{"tools", "master", "master"},
+		{"tools", "master", "release-branch.go1.21"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("luciBranches = %+v; want %+v", got, want)
+	}
+}
+
+// chdir changes to dir for the rest of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestFetchLUCI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "log of %s\n", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	const (
+		commit1 = "1111111111111111111111111111111111111111"
+		commit2 = "2222222222222222222222222222222222222222"
+	)
+	date := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	gitCommit := func(id string, t time.Time) *gitpb.Commit {
+		return &gitpb.Commit{
+			Id:        id,
+			Author:    &gitpb.Commit_User{Name: "Gopher", Email: "gopher@golang.org"},
+			Committer: &gitpb.Commit_User{Time: timestamppb.New(t)},
+			Message:   "all: fix things\n",
+		}
+	}
+	failed := testBuild(t, 42, map[string]any{
+		"sources": sources("go", commit1)["sources"],
+		"failure": map[string]any{"links": []any{
+			map[string]any{"name": "go test (combined output)", "url": srv.URL + "/combined"},
+		}},
+	})
+	failed.EndTime = timestamppb.New(date.Add(time.Hour))
+	failed.Infra = &bbpb.BuildInfra{Resultdb: &bbpb.BuildInfra_ResultDB{Invocation: "invocations/build-42"}}
+	// An earlier failure of the same builder on the same commit is superseded.
+	earlier := testBuild(t, 41, sources("go", commit1))
+	earlier.EndTime = timestamppb.New(date)
+	// A failure of a commit that isn't among the recent ones is ignored.
+	other := testBuild(t, 40, sources("go", "3333333333333333333333333333333333333333"))
+
+	c := &luciClient{
+		gitiles: &fakeGitiles{commits: []*gitpb.Commit{gitCommit(commit2, date.Add(time.Minute)), gitCommit(commit1, date)}},
+		builders: &fakeBuilders{builders: []*bbpb.BuilderItem{
+			{Id: &bbpb.BuilderID{Builder: "gotip-linux-amd64"}, Config: &bbpb.BuilderConfig{Properties: `{"project":"go","go_branch":"master"}`}},
+			{Id: &bbpb.BuilderID{Builder: "go1.21-linux-amd64"}, Config: &bbpb.BuilderConfig{Properties: `{"project":"go","go_branch":"release-branch.go1.21"}`}},
+		}},
+		builds: &fakeBuilds{builds: map[string][]*bbpb.Build{
+			"gotip-linux-amd64":  {earlier, failed, other},
+			"go1.21-linux-amd64": {testBuild(t, 50, sources("go", commit2))},
+		}},
+		resultDB: &fakeResultDB{
+			results: []*rdbpb.TestResult{{TestId: "net.TestDial", Status: rdbpb.TestStatus_FAIL}},
+			artifacts: map[string][]*rdbpb.Artifact{
+				"TestDial": {{ArtifactId: "output", FetchUrl: srv.URL + "/TestDial"}},
+			},
+		},
+	}
+
+	chdir(t, t.TempDir())
+	ensureDir("log")
+	ensureDir("rev")
+	var wg sync.WaitGroup
+	if err := fetchLUCI(context.Background(), c, newFetcher(1), &wg, "go", "master", "master"); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	revDir, _ := revToDir(commit1, date, "", time.Time{})
+	if revDir != filepath.Join("rev", "2026-10-01T12:00:00-1111111") {
+		t.Fatalf("revision directory is %s", revDir)
+	}
+	entries, err := os.ReadDir("rev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(revDir) {
+		t.Errorf("rev has %v; want only %s", entries, filepath.Base(revDir))
+	}
+
+	// The log of the build is linked under the name of its builder.
+	target, err := os.Readlink(filepath.Join(revDir, "gotip-linux-amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("..", "..", "log", "luci-42"); target != want {
+		t.Errorf("log link points to %s; want %s", target, want)
+	}
+	data, err := os.ReadFile(filepath.Join(revDir, "gotip-linux-amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "log of /combined\n"; got != want {
+		t.Errorf("log = %q; want %q", got, want)
+	}
+	data, err = os.ReadFile(filepath.Join("log", "luci-42.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "log of /TestDial\n"; got != want {
+		t.Errorf("test output = %q; want %q", got, want)
+	}
+
+	// The build and its failed tests are recorded next to the link.
+	data, err = os.ReadFile(filepath.Join(revDir, ".gotip-linux-amd64.luci.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lb luciBuild
+	if err := json.Unmarshal(data, &lb); err != nil {
+		t.Fatal(err)
+	}
+	wantBuild := luciBuild{
+		ID:     42,
+		URL:    "https://ci.chromium.org/b/42",
+		Status: "FAILURE",
+		LogURL: srv.URL + "/combined",
+		Failures: []*luciFailure{{
+			TestID: "net.TestDial",
+			Status: "FAIL",
+			URL:    srv.URL + "/TestDial",
+			Log:    filepath.Join("log", "luci-42.0"),
+		}},
+	}
+	if !reflect.DeepEqual(lb, wantBuild) {
+		t.Errorf(".luci.json = %s; want %+v", data, wantBuild)
+	}
+
+	// So is the revision, as the dashboard would.
+	data, err = os.ReadFile(filepath.Join(revDir, ".rev.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rev types.BuildRevision
+	if err := json.Unmarshal(data, &rev); err != nil {
+		t.Fatal(err)
+	}
+	wantRev := types.BuildRevision{
+		Repo:     "go",
+		Revision: commit1,
+		Date:     date.Format(time.RFC3339),
+		Branch:   "master",
+		Author:   "Gopher <gopher@golang.org>",
+		Desc:     "all: fix things\n",
+	}
+	if !reflect.DeepEqual(rev, wantRev) {
+		t.Errorf(".rev.json = %s; want %+v", data, wantRev)
+	}
+}
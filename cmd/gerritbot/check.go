@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/build/cmd/gerritbot/internal/rules"
+	"golang.org/x/build/gerrit"
+)
+
+const checkUsage = `usage: gerritbot check [flags] [commit]
+       gerritbot check [flags] -cl <number>
+
+Check runs GerritBot's commit message checks against a commit in the
+git repository in the current directory (HEAD by default), or against
+the current patch set of a Gerrit CL. It exits with status 1 if it
+finds possible problems.
+
+Flags:
+`
+
+// runCheck implements the "gerritbot check" command.
+// It returns the exit status.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		repo      = fs.String("repo", "", "repository name, like \"tools\"; by default, derived from the CL or the origin remote")
+		cl        = fs.Int("cl", 0, "check the current patch set of this CL instead of a local commit")
+		gerritURL = fs.String("gerrit", "https://go-review.googlesource.com", "Gerrit server to fetch the CL from")
+		rulesDir  = fs.String("rules-dir", "", "if non-empty, directory of per-repo rule configuration files")
+	)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, checkUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 || *cl != 0 && fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx := context.Background()
+	var msg string
+	var err error
+	if *cl != 0 {
+		var project string
+		project, msg, err = clCommitMessage(ctx, gerrit.NewClient(*gerritURL, gerrit.NoAuth), *cl)
+		if *repo == "" {
+			*repo = project
+		}
+	} else {
+		rev := "HEAD"
+		if fs.NArg() == 1 {
+			rev = fs.Arg(0)
+		}
+		msg, err = localCommitMessage(rev)
+		if err == nil && *repo == "" {
+			*repo, err = localRepoName()
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gerritbot check: %v\n", err)
+		return 1
+	}
+
+	rs := rules.DefaultRuleSet
+	if *rulesDir != "" {
+		rs, err = rules.LoadRuleSet(*rulesDir, *repo)
+		if err != nil {
+			fmt.Fprintf(stderr, "gerritbot check: %v\n", err)
+			return 1
+		}
+	}
+	change, err := rules.ParseCommitMessage(*repo, withFooter(msg))
+	if err != nil {
+		fmt.Fprintf(stderr, "gerritbot check: %v\n", err)
+		return 1
+	}
+	results := rs.Check(change)
+	if len(results) == 0 {
+		fmt.Fprintln(stdout, "No problems found.")
+		return 0
+	}
+	fmt.Fprint(stdout, rules.FormatResults(results))
+	return 1
+}
+
+// clCommitMessage returns the project and the commit message of the
+// current patch set of a CL.
+func clCommitMessage(ctx context.Context, c *gerrit.Client, cl int) (project, msg string, err error) {
+	ci, err := c.GetChange(ctx, strconv.Itoa(cl), gerrit.QueryChangesOpt{Fields: []string{"CURRENT_REVISION", "CURRENT_COMMIT"}})
+	if err != nil {
+		return "", "", fmt.Errorf("fetching CL %d: %v", cl, err)
+	}
+	rev, ok := ci.Revisions[ci.CurrentRevision]
+	if !ok || rev.Commit == nil {
+		return "", "", fmt.Errorf("CL %d has no current commit", cl)
+	}
+	return ci.Project, rev.Commit.Message, nil
+}
+
+// localCommitMessage returns the commit message of rev
+// in the git repository in the current directory.
+func localCommitMessage(rev string) (string, error) {
+	out, err := exec.Command("git", "log", "-1", "--format=%B", rev, "--").Output()
+	if err != nil {
+		return "", fmt.Errorf("reading commit message of %s: %v", rev, execErr(err))
+	}
+	return string(out), nil
+}
+
+// localRepoName returns the repository name of the origin remote
+// of the git repository in the current directory. For example, it
+// returns "tools" for https://go.googlesource.com/tools.
+func localRepoName() (string, error) {
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", fmt.Errorf("finding origin remote (use -repo to set the repository name): %v", execErr(err))
+	}
+	name := path.Base(strings.TrimSuffix(strings.TrimSpace(string(out)), "/"))
+	return strings.TrimSuffix(name, ".git"), nil
+}
+
+// execErr adds the standard error output of a failed command to err.
+func execErr(err error) error {
+	if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(ee.Stderr)))
+	}
+	return err
+}
+
+// footerRE matches a commit message footer line, like rules.ParseCommitMessage.
+var footerRE = regexp.MustCompile(`^[a-zA-Z][^ ]*: `)
+
+// withFooter adds a placeholder footer to msg if it has none.
+// Local commits that were not yet uploaded may lack the Change-Id
+// footer, but rules.ParseCommitMessage requires footers.
+func withFooter(msg string) string {
+	msg = strings.TrimRight(msg, "\n")
+	lines := strings.Split(msg, "\n")
+	last := lines[len(lines)-1]
+	if len(lines) > 2 && footerRE.MatchString(last) {
+		return msg + "\n"
+	}
+	return msg + "\n\nChange-Id: I0000000000000000000000000000000000000000\n"
+}
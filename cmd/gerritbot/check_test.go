@@ -0,0 +1,80 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCheckCL(t *testing.T) {
+	messages := map[string]string{
+		"1": "fmt: improve the formatting\n\nThis changes the formatting of things to be better.\n\nFixes #12345\n\nChange-Id: I0123456789abcdef0123456789abcdef01234567\n",
+		"2": "Fix things.\n\nChange-Id: I0123456789abcdef0123456789abcdef01234567\n",
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := strings.TrimPrefix(r.URL.Path, "/changes/")
+		msg, ok := messages[n]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, ")]}'\n"+`{"project":"go","current_revision":"abc","revisions":{"abc":{"commit":{"message":%q}}}}`, msg)
+	}))
+	defer s.Close()
+
+	check := func(args ...string) (string, int) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		code := runCheck(append([]string{"-gerrit", s.URL}, args...), &stdout, &stderr)
+		return stdout.String() + stderr.String(), code
+	}
+
+	if out, code := check("-cl", "1"); code != 0 || out != "No problems found.\n" {
+		t.Errorf("check of good CL: status %d, output:\n%s", code, out)
+	}
+	out, code := check("-cl", "2")
+	if code != 1 || !strings.Contains(out, "The commit title should not end with a period.") {
+		t.Errorf("check of bad CL: status %d, output:\n%s", code, out)
+	}
+
+	// Rules can be disabled with a configuration file.
+	dir := t.TempDir()
+	cfg := `{"disable": ["title: ends with period"]}`
+	if err := os.WriteFile(filepath.Join(dir, "go.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, code := check("-rules-dir", dir, "-cl", "2"); code != 1 || strings.Contains(out, "period") {
+		t.Errorf("check of bad CL with rules disabled: status %d, output:\n%s", code, out)
+	}
+
+	if out, code := check("-cl", "3"); code != 1 || !strings.Contains(out, "404") {
+		t.Errorf("check of missing CL: status %d, output:\n%s", code, out)
+	}
+	if _, code := check("-cl", "1", "HEAD"); code != 2 {
+		t.Errorf("check with CL and commit: status %d; want 2", code)
+	}
+}
+
+func TestWithFooter(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"fmt: x\n", "fmt: x\n\nChange-Id: I0000000000000000000000000000000000000000\n"},
+		{"fmt: x\n\nBody.\n", "fmt: x\n\nBody.\n\nChange-Id: I0000000000000000000000000000000000000000\n"},
+		{"fmt: x\n\nBody.\n\nChange-Id: I1\n\n", "fmt: x\n\nBody.\n\nChange-Id: I1\n"},
+	}
+	for _, tt := range tests {
+		if got := withFooter(tt.in); got != tt.want {
+			t.Errorf("withFooter(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,10 @@
 
 // The gerritbot binary converts GitHub Pull Requests to Gerrit Changes,
 // updating the PR and Gerrit Change as appropriate.
+//
+// Run as "gerritbot check", it instead runs its commit message checks
+// against a local commit or a Gerrit CL, so contributors can check
+// their changes before uploading them.
 package main
 
 import (
@@ -44,12 +48,17 @@ var (
 	gitcookiesFile  = flag.String("gitcookies-file", "", "if non-empty, write a git http cookiefile to this location using secret manager")
 	dryRun          = flag.Bool("dry-run", false, "print out mutating actions but don’t perform any")
 	singlePR        = flag.String("single-pr", "", "process only this PR, specified in GitHub shortlink format, e.g. golang/go#1")
+	rulesDir        = flag.String("rules-dir", "", "if non-empty, directory of per-repo commit message rule configuration files named <repo>.json; re-read for each check")
+	githubSince     = flag.String("github-comments-since", "", "if non-empty, an RFC 3339 time; GitHub PR comments made before it are not copied to Gerrit. If empty, only comments made after GerritBot starts are copied")
 )
 
 // TODO(amedee): set to this value until the SLO numbers are published
 const secretClientTimeout = 10 * time.Second
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:], os.Stdout, os.Stderr))
+	}
 	https.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
@@ -69,6 +78,12 @@ func main() {
 		log.Fatalf("gerritClient(): %v", err)
 	}
 	b := newBot(ghc, gc)
+	if *githubSince != "" {
+		b.githubCommentsSince, err = time.Parse(time.RFC3339, *githubSince)
+		if err != nil {
+			log.Fatalf("invalid -github-comments-since: %v", err)
+		}
+	}
 
 	ctx := context.Background()
 	b.initCorpus(ctx)
@@ -241,6 +256,13 @@ type bot struct {
 
 	// Cache of Gerrit Account IDs to AccountInfo structs.
 	cachedGerritAccounts map[int]*gerrit.AccountInfo // 1234 -> Detailed Account Info
+
+	// GitHub comments that have been copied to Gerrit, which might not be
+	// reflected in the maintner corpus yet, or that Gerrit rejected.
+	postedGitHubComments map[string]bool // GitHub comment URL -> true
+
+	// GitHub comments made before this time are not copied to Gerrit.
+	githubCommentsSince time.Time
 }
 
 func newBot(githubClient *github.Client, gerritClient *gerrit.Client) *bot {
@@ -250,6 +272,8 @@ func newBot(githubClient *github.Client, gerritClient *gerrit.Client) *bot {
 		importedPRs:          map[string]*maintner.GerritCL{},
 		pendingCLs:           map[string]string{},
 		cachedGerritAccounts: map[int]*gerrit.AccountInfo{},
+		postedGitHubComments: map[string]bool{},
+		githubCommentsSince:  time.Now(),
 	}
 }
 
@@ -324,6 +348,7 @@ func (b *bot) checkPullRequests() {
 			if issue.PullRequest && issue.Closed {
 				// Clean up any reference of closed CLs within pendingCLs.
 				delete(b.pendingCLs, shortLink)
+				b.forgetGitHubComments(githubPRURL(id.Owner, id.Repo, int(issue.Number)))
 				if cl, ok := b.importedPRs[shortLink]; ok {
 					// The CL associated with the PR is still open since it's
 					// present in importedPRs, so abandon it.
@@ -445,6 +470,10 @@ func (b *bot) processPullRequest(ctx context.Context, pr *github.PullRequest) er
 	if err := b.syncGerritCommentsToGitHub(ctx, pr, cl); err != nil {
 		return fmt.Errorf("syncGerritCommentsToGitHub: %v", err)
 	}
+	// Failing to copy comments must not keep new commits from being imported.
+	if err := b.syncGitHubCommentsToGerrit(ctx, pr, cl); err != nil {
+		log.Printf("syncGitHubCommentsToGerrit: %v", err)
+	}
 
 	if cmsg == cl.Commit.Msg && pr.GetDraft() == cl.WorkInProgress() {
 		log.Printf("Change https://go-review.googlesource.com/q/%s is up to date; nothing to do.",
@@ -810,7 +839,15 @@ Please visit Gerrit at %s.
 		if err != nil {
 			return fmt.Errorf("failed to parse commit message for %s: %v", prShortLink(pr), err)
 		}
-		problems := rules.Check(change)
+		rs := rules.DefaultRuleSet
+		if *rulesDir != "" {
+			if rs, err = rules.LoadRuleSet(*rulesDir, repo.GetName()); err != nil {
+				// Don't let a bad configuration stop PR imports.
+				log.Printf("loading commit message rules for %s, using the defaults: %v", repo.GetName(), err)
+				rs = rules.DefaultRuleSet
+			}
+		}
+		problems := rs.Check(change)
 		if len(problems) > 0 {
 			summary := rules.FormatResults(problems)
 			// If needed, summary contains advice for how to edit the commit message.
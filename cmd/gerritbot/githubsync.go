@@ -0,0 +1,288 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v48/github"
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+)
+
+// githubBotLogin is the GitHub account GerritBot posts as.
+// Its comments are never copied to Gerrit.
+const githubBotLogin = "gopherbot"
+
+// Kinds of GitHub PR comments.
+const (
+	githubIssueComment  = "comment"        // a comment on the PR conversation
+	githubReview        = "review"         // the summary of a review
+	githubReviewComment = "review comment" // a comment on a line of the diff
+)
+
+// githubComment is a comment on a GitHub PR.
+type githubComment struct {
+	Kind    string // githubIssueComment, githubReview or githubReviewComment
+	ID      int64
+	Login   string
+	Body    string
+	Created time.Time
+
+	// For review comments:
+	Path      string
+	Line      int32  // 0 if the comment is outdated
+	Side      string // "LEFT" or "RIGHT"
+	CommitID  string // the PR head the comment was made on
+	InReplyTo int64  // ID of the review comment this replies to
+}
+
+// URL returns the URL of c on the PR at prURL.
+// It also identifies c in the comments copied to Gerrit.
+func (c *githubComment) URL(prURL string) string {
+	switch c.Kind {
+	case githubReview:
+		return fmt.Sprintf("%s#pullrequestreview-%d", prURL, c.ID)
+	case githubReviewComment:
+		return fmt.Sprintf("%s#discussion_r%d", prURL, c.ID)
+	}
+	return fmt.Sprintf("%s#issuecomment-%d", prURL, c.ID)
+}
+
+// gerritReview is a review to post on a Gerrit CL to copy a GitHub comment.
+type gerritReview struct {
+	key      string // the URL of the GitHub comment
+	revision string // the revision to post on
+	input    gerrit.ReviewInput
+}
+
+// githubCommentsForPR returns the comments on the PR gi,
+// in the order they were made.
+// The corpus must be locked.
+func githubCommentsForPR(gi *maintner.GitHubIssue) []*githubComment {
+	var comments []*githubComment
+	gi.ForeachComment(func(c *maintner.GitHubComment) error {
+		comments = append(comments, &githubComment{
+			Kind:    githubIssueComment,
+			ID:      c.ID,
+			Login:   githubLogin(c.User),
+			Body:    c.Body,
+			Created: c.Created,
+		})
+		return nil
+	})
+	gi.ForeachReview(func(r *maintner.GitHubReview) error {
+		comments = append(comments, &githubComment{
+			Kind:    githubReview,
+			ID:      r.ID,
+			Login:   githubLogin(r.Actor),
+			Body:    r.Body,
+			Created: r.Created,
+		})
+		return nil
+	})
+	gi.ForeachReviewComment(func(rc *maintner.GitHubReviewComment) error {
+		comments = append(comments, &githubComment{
+			Kind:      githubReviewComment,
+			ID:        rc.ID,
+			Login:     githubLogin(rc.User),
+			Body:      rc.Body,
+			Created:   rc.Created,
+			Path:      rc.Path,
+			Line:      rc.Line,
+			Side:      rc.Side,
+			CommitID:  rc.CommitID,
+			InReplyTo: rc.InReplyTo,
+		})
+		return nil
+	})
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].Created.Before(comments[j].Created) })
+	return comments
+}
+
+// githubLogin returns the login of u, which may be nil.
+func githubLogin(u *maintner.GitHubUser) string {
+	if u == nil {
+		return ""
+	}
+	return u.Login
+}
+
+// patchSetsByGitHubRev returns the patch sets of cl by the PR head
+// commit they were imported from, according to their GitHub-Last-Rev
+// footers.
+func patchSetsByGitHubRev(cl *maintner.GerritCL) map[string]int32 {
+	m := make(map[string]int32)
+	for v := int32(1); v <= cl.Version; v++ {
+		c := cl.CommitAtVersion(v)
+		if c == nil {
+			continue
+		}
+		for _, line := range strings.Split(c.Msg, "\n") {
+			if rev, ok := strings.CutPrefix(line, prefixGitFooterLastRev); ok {
+				m[strings.TrimSpace(rev)] = v
+			}
+		}
+	}
+	return m
+}
+
+// gerritReviewsForGitHubComments returns the reviews that copy the
+// comments on the PR at prURL to its CL, cl. Comments that were
+// already copied, according to cl's messages and comments and the
+// posted set, are skipped, as are comments made by GerritBot and
+// comments relaying Gerrit messages. So are comments made before
+// since or before cl was created, so as not to flood existing CLs
+// with their history.
+//
+// Review comments on a line of a PR head that was imported as a patch
+// set, according to patchSets, are copied as comments on the same
+// line of that patch set. Other review comments are copied as change
+// messages that quote the file and line.
+func gerritReviewsForGitHubComments(prURL string, comments []*githubComment, cl *maintner.GerritCL, patchSets map[string]int32, posted map[string]bool, since time.Time) []gerritReview {
+	if cl.Created.After(since) {
+		since = cl.Created
+	}
+	// Copies include the comment's URL in parentheses.
+	isCopy := func(text, key string) bool { return strings.Contains(text, "("+key+")") }
+	copied := func(key string) bool {
+		if posted[key] {
+			return true
+		}
+		for _, m := range cl.Messages {
+			if isCopy(m.Message, key) {
+				return true
+			}
+		}
+		for _, c := range cl.Comments {
+			if isCopy(c.Message, key) {
+				return true
+			}
+		}
+		return false
+	}
+	// gerritCommentFor returns the ID of the Gerrit comment
+	// that is a copy of the GitHub comment at key.
+	gerritCommentFor := func(key string) string {
+		for _, c := range cl.Comments {
+			if isCopy(c.Message, key) {
+				return c.ID
+			}
+		}
+		return ""
+	}
+
+	var reviews []gerritReview
+	for _, c := range comments {
+		body := strings.TrimSpace(c.Body)
+		if body == "" || c.Login == githubBotLogin || c.Created.Before(since) ||
+			strings.HasPrefix(body, "/comments ") || strings.Contains(body, "Please don’t reply on this GitHub thread.") {
+			continue
+		}
+		key := c.URL(prURL)
+		if copied(key) {
+			continue
+		}
+		header := fmt.Sprintf("GitHub %s from @%s (%s)", c.Kind, c.Login, key)
+		r := gerritReview{key: key, revision: "current"}
+		ps, ok := patchSets[c.CommitID]
+		if c.Kind == githubReviewComment && c.Line > 0 && ok {
+			ci := gerrit.CommentInput{
+				Line:    int(c.Line),
+				Message: header + ":\n\n" + body,
+			}
+			if c.Side == "LEFT" {
+				ci.Side = "PARENT"
+			}
+			if c.InReplyTo != 0 {
+				parent := githubComment{Kind: githubReviewComment, ID: c.InReplyTo}
+				ci.InReplyTo = gerritCommentFor(parent.URL(prURL))
+			}
+			r.revision = strconv.Itoa(int(ps))
+			r.input.Comments = map[string][]gerrit.CommentInput{c.Path: {ci}}
+		} else {
+			if c.Kind == githubReviewComment {
+				header += fmt.Sprintf(" on %s", c.Path)
+				if c.Line > 0 {
+					header += fmt.Sprintf(":%d", c.Line)
+				}
+			}
+			r.input.Message = header + ":\n\n" + body
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews
+}
+
+// syncGitHubCommentsToGerrit copies new comments on pr to its CL, cl.
+// b.RWMutex must be Lock'ed.
+func (b *bot) syncGitHubCommentsToGerrit(ctx context.Context, pr *github.PullRequest, cl *maintner.GerritCL) error {
+	repo := pr.GetBase().GetRepo()
+	gr := b.corpus.GitHub().Repo(repo.GetOwner().GetLogin(), repo.GetName())
+	if gr == nil {
+		return fmt.Errorf("unknown github repo %s/%s", repo.GetOwner().GetLogin(), repo.GetName())
+	}
+	gi := gr.Issue(int32(pr.GetNumber()))
+	if gi == nil {
+		return nil
+	}
+	prURL := githubPRURL(repo.GetOwner().GetLogin(), repo.GetName(), pr.GetNumber())
+	reviews := gerritReviewsForGitHubComments(prURL, githubCommentsForPR(gi), cl, patchSetsByGitHubRev(cl), b.postedGitHubComments, b.githubCommentsSince)
+	changeID := fmt.Sprintf("%s~%d", url.PathEscape(cl.Project.Project()), cl.Number)
+	for _, r := range reviews {
+		if *dryRun {
+			log.Printf("[dry run] would copy %s to https://go-review.googlesource.com/c/%s/+/%d", r.key, cl.Project.Project(), cl.Number)
+			continue
+		}
+		if err := b.gerritClient.SetReview(ctx, changeID, r.revision, r.input); err != nil {
+			log.Printf("copying %s to CL %d: %v", r.key, cl.Number, err)
+			if !isPermanentGerritError(err) {
+				// Try again on the next run.
+				continue
+			}
+			// Gerrit would reject the comment every time, such as
+			// one on a line that it doesn't have, so don't retry it.
+		}
+		// The corpus might not have the copy yet.
+		b.postedGitHubComments[r.key] = true
+	}
+	return nil
+}
+
+// isPermanentGerritError reports whether err is a rejection by Gerrit
+// of the request itself, which would fail the same way if retried.
+func isPermanentGerritError(err error) bool {
+	var he *gerrit.HTTPError
+	if !errors.As(err, &he) {
+		return false
+	}
+	code := he.Res.StatusCode
+	return code >= 400 && code < 500 && code != http.StatusTooManyRequests && code != http.StatusRequestTimeout
+}
+
+// forgetGitHubComments removes the comments on the PR at prURL
+// from b.postedGitHubComments, once the PR is closed.
+// b.RWMutex must be Lock'ed.
+func (b *bot) forgetGitHubComments(prURL string) {
+	for key := range b.postedGitHubComments {
+		if strings.HasPrefix(key, prURL+"#") {
+			delete(b.postedGitHubComments, key)
+		}
+	}
+}
+
+// githubPRURL returns the URL of PR number in owner/repo.
+func githubPRURL(owner, repo string, number int) string {
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d", owner, repo, number)
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/gerrit"
+	"golang.org/x/build/maintner"
+)
+
+func TestGerritReviewsForGitHubComments(t *testing.T) {
+	const prURL = "https://github.com/golang/go/pull/42"
+	since := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	imported := since.Add(time.Hour)
+	now := imported.Add(time.Hour)
+	comments := []*githubComment{
+		{Kind: githubIssueComment, ID: 1, Login: "gopher", Body: "Thanks, PTAL.", Created: now},
+		{Kind: githubIssueComment, ID: 2, Login: "gopher", Body: "Too old.", Created: since.Add(-time.Hour)},
+		{Kind: githubIssueComment, ID: 14, Login: "gopher", Body: "Before the import.", Created: imported.Add(-time.Minute)},
+		{Kind: githubIssueComment, ID: 3, Login: githubBotLogin, Body: "Message from Reviewer:\n\nLGTM", Created: now},
+		{Kind: githubIssueComment, ID: 4, Login: "gopher", Body: "/comments off", Created: now},
+		{Kind: githubIssueComment, ID: 5, Login: "gopher", Body: "Already copied.", Created: now},
+		{Kind: githubIssueComment, ID: 6, Login: "gopher", Body: "Copied, but not in the corpus yet.", Created: now},
+		{Kind: githubReview, ID: 7, Login: "reviewer", Body: "", Created: now},
+		{Kind: githubReview, ID: 8, Login: "reviewer", Body: "Looks good.", Created: now},
+		{Kind: githubReviewComment, ID: 9, Login: "reviewer", Body: "Nit: rename.", Created: now,
+			Path: "src/fmt/print.go", Line: 10, Side: "RIGHT", CommitID: "head2"},
+		{Kind: githubReviewComment, ID: 10, Login: "gopher", Body: "Done.", Created: now,
+			Path: "src/fmt/print.go", Line: 10, Side: "RIGHT", CommitID: "head2", InReplyTo: 11},
+		{Kind: githubReviewComment, ID: 12, Login: "reviewer", Body: "Why remove this?", Created: now,
+			Path: "src/fmt/print.go", Line: 3, Side: "LEFT", CommitID: "head1"},
+		{Kind: githubReviewComment, ID: 13, Login: "reviewer", Body: "On an unknown head.", Created: now,
+			Path: "src/fmt/scan.go", Line: 7, Side: "RIGHT", CommitID: "head0"},
+	}
+	cl := &maintner.GerritCL{
+		Created: imported,
+		Messages: []*maintner.GerritMessage{
+			{Message: "Patch Set 2:\n\nGitHub comment from @gopher (" + prURL + "#issuecomment-5):\n\nAlready copied."},
+		},
+		Comments: []*maintner.GerritComment{
+			{ID: "uuid11", Message: "GitHub review comment from @reviewer (" + prURL + "#discussion_r11):\n\nHm."},
+		},
+	}
+	patchSets := map[string]int32{"head1": 1, "head2": 2}
+	posted := map[string]bool{prURL + "#issuecomment-6": true}
+
+	got := gerritReviewsForGitHubComments(prURL, comments, cl, patchSets, posted, since)
+	want := []gerritReview{
+		{
+			key:      prURL + "#issuecomment-1",
+			revision: "current",
+			input:    gerrit.ReviewInput{Message: "GitHub comment from @gopher (" + prURL + "#issuecomment-1):\n\nThanks, PTAL."},
+		},
+		{
+			key:      prURL + "#pullrequestreview-8",
+			revision: "current",
+			input:    gerrit.ReviewInput{Message: "GitHub review from @reviewer (" + prURL + "#pullrequestreview-8):\n\nLooks good."},
+		},
+		{
+			key:      prURL + "#discussion_r9",
+			revision: "2",
+			input: gerrit.ReviewInput{Comments: map[string][]gerrit.CommentInput{
+				"src/fmt/print.go": {{Line: 10, Message: "GitHub review comment from @reviewer (" + prURL + "#discussion_r9):\n\nNit: rename."}},
+			}},
+		},
+		{
+			key:      prURL + "#discussion_r10",
+			revision: "2",
+			input: gerrit.ReviewInput{Comments: map[string][]gerrit.CommentInput{
+				"src/fmt/print.go": {{Line: 10, InReplyTo: "uuid11", Message: "GitHub review comment from @gopher (" + prURL + "#discussion_r10):\n\nDone."}},
+			}},
+		},
+		{
+			key:      prURL + "#discussion_r12",
+			revision: "1",
+			input: gerrit.ReviewInput{Comments: map[string][]gerrit.CommentInput{
+				"src/fmt/print.go": {{Line: 3, Side: "PARENT", Message: "GitHub review comment from @reviewer (" + prURL + "#discussion_r12):\n\nWhy remove this?"}},
+			}},
+		},
+		{
+			key:      prURL + "#discussion_r13",
+			revision: "current",
+			input:    gerrit.ReviewInput{Message: "GitHub review comment from @reviewer (" + prURL + "#discussion_r13) on src/fmt/scan.go:7:\n\nOn an unknown head."},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(gerritReview{})); diff != "" {
+		t.Errorf("gerritReviewsForGitHubComments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestForgetGitHubComments(t *testing.T) {
+	b := newBot(nil, nil)
+	b.postedGitHubComments = map[string]bool{
+		"https://github.com/golang/go/pull/4#issuecomment-1":  true,
+		"https://github.com/golang/go/pull/4#discussion_r2":   true,
+		"https://github.com/golang/go/pull/42#issuecomment-3": true,
+		"https://github.com/golang/net/pull/4#issuecomment-4": true,
+	}
+	b.forgetGitHubComments(githubPRURL("golang", "go", 4))
+	want := map[string]bool{
+		"https://github.com/golang/go/pull/42#issuecomment-3": true,
+		"https://github.com/golang/net/pull/4#issuecomment-4": true,
+	}
+	if diff := cmp.Diff(want, b.postedGitHubComments); diff != "" {
+		t.Errorf("postedGitHubComments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsPermanentGerritError(t *testing.T) {
+	httpError := func(code int) error {
+		req := httptest.NewRequest("POST", "/changes/go~1/revisions/current/review", nil)
+		return &gerrit.HTTPError{Res: &http.Response{StatusCode: code, Status: http.StatusText(code), Request: req}}
+	}
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{httpError(http.StatusBadRequest), true},
+		{httpError(http.StatusConflict), true},
+		{fmt.Errorf("wrapped: %w", httpError(http.StatusNotFound)), true},
+		{httpError(http.StatusTooManyRequests), false},
+		{httpError(http.StatusInternalServerError), false},
+		{errors.New("connection reset"), false},
+	}
+	for _, tt := range tests {
+		if got := isPermanentGerritError(tt.err); got != tt.want {
+			t.Errorf("isPermanentGerritError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"golang.org/x/exp/slices"
+)
+
+// Config adjusts the rules checked for a repo. It is usually loaded
+// from a JSON file by LoadConfig, for example:
+//
+//	{
+//		"disable": ["body: long lines"],
+//		"rules": [
+//			{
+//				"name": "body: mentions internal host",
+//				"when": "body =~ `corp\\.example\\.com`",
+//				"finding": "Please don't mention internal hosts in the commit message."
+//			}
+//		]
+//	}
+type Config struct {
+	// Disable lists the names of built-in rules to skip.
+	Disable []string `json:"disable"`
+
+	// Rules are additional rules, checked after the built-in ones.
+	Rules []RuleConfig `json:"rules"`
+}
+
+// RuleConfig defines a rule in a Config.
+type RuleConfig struct {
+	// Name is the rule name, which is reported in Result.Name.
+	Name string `json:"name"`
+
+	// Group, if non-empty, places the rule in a group with the other
+	// configured rules of the same Group. At most one rule from a group,
+	// the first to trigger in the order of Rules, reports a finding.
+	Group string `json:"group"`
+
+	// When is the condition under which the rule reports its finding.
+	// See the documentation in expr.go for its syntax.
+	When string `json:"when"`
+
+	// Finding is the finding to report.
+	Finding string `json:"finding"`
+
+	// Note is the auxiliary note to report with the finding.
+	// If empty, the usual advice on editing commit messages is used.
+	Note string `json:"note"`
+}
+
+// A RuleSet is a set of rules that can be checked against changes.
+type RuleSet struct {
+	groups [][]rule
+}
+
+// DefaultRuleSet is the set of built-in rules, as used by Check.
+var DefaultRuleSet = &RuleSet{groups: ruleGroups}
+
+// ParseConfig parses a Config from its JSON encoding.
+func ParseConfig(data []byte) (*Config, error) {
+	cfg := new(Config)
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("rules: parsing config: %v", err)
+	}
+	return cfg, nil
+}
+
+// LoadConfig loads the Config for repo from the file repo+".json" in
+// dir. If there is no such file, it returns an empty Config.
+func LoadConfig(dir, repo string) (*Config, error) {
+	if !regexp.MustCompile(`^[a-zA-Z0-9._-]+$`).MatchString(repo) || repo[0] == '.' {
+		return nil, fmt.Errorf("rules: invalid repo name %q", repo)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, repo+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return new(Config), nil
+	} else if err != nil {
+		return nil, err
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", repo+".json", err)
+	}
+	return cfg, nil
+}
+
+// RuleSet returns the built-in rules adjusted by cfg.
+// It reports an error if cfg disables an unknown rule
+// or has an invalid rule.
+func (cfg *Config) RuleSet() (*RuleSet, error) {
+	known := make(map[string]bool)
+	for _, group := range ruleGroups {
+		for _, r := range group {
+			known[r.name] = true
+		}
+	}
+	for _, name := range cfg.Disable {
+		if !known[name] {
+			return nil, fmt.Errorf("rules: cannot disable unknown rule %q", name)
+		}
+	}
+
+	var rs RuleSet
+	for _, group := range ruleGroups {
+		var g []rule
+		for _, r := range group {
+			if !slices.Contains(cfg.Disable, r.name) {
+				g = append(g, r)
+			}
+		}
+		if len(g) > 0 {
+			rs.groups = append(rs.groups, g)
+		}
+	}
+
+	groupIndex := make(map[string]int) // Group name → index in rs.groups
+	for _, rc := range cfg.Rules {
+		if rc.Name == "" || rc.Finding == "" {
+			return nil, fmt.Errorf("rules: configured rule %q needs a name and a finding", rc.Name)
+		}
+		if known[rc.Name] {
+			return nil, fmt.Errorf("rules: configured rule %q has the name of a built-in rule", rc.Name)
+		}
+		known[rc.Name] = true
+		c, err := parseCond(rc.When)
+		if err != nil {
+			return nil, fmt.Errorf("rules: rule %q: bad condition %q: %v", rc.Name, rc.When, err)
+		}
+		finding, note := rc.Finding, rc.Note
+		if note == "" {
+			note = commitMessageAdvice
+		}
+		r := rule{
+			name: rc.Name,
+			f: func(change Change) (string, string) {
+				if c(change) {
+					return finding, note
+				}
+				return "", ""
+			},
+		}
+		if i, ok := groupIndex[rc.Group]; ok && rc.Group != "" {
+			rs.groups[i] = append(rs.groups[i], r)
+			continue
+		}
+		groupIndex[rc.Group] = len(rs.groups)
+		rs.groups = append(rs.groups, []rule{r})
+	}
+	return &rs, nil
+}
+
+// LoadRuleSet returns the rules for repo, as configured in dir.
+// See LoadConfig.
+func LoadRuleSet(dir, repo string) (*RuleSet, error) {
+	cfg, err := LoadConfig(dir, repo)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.RuleSet()
+}
+
+// Names returns the names of the rules in rs, in the order they are checked.
+func (rs *RuleSet) Names() []string {
+	var names []string
+	for _, group := range rs.groups {
+		for _, r := range group {
+			names = append(names, r.name)
+		}
+	}
+	return names
+}
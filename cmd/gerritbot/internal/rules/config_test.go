@@ -0,0 +1,173 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/exp/slices"
+)
+
+func TestCond(t *testing.T) {
+	change := Change{
+		Repo:  "tools",
+		Title: "gopls: fix a typo",
+		Body:  "Short.\n\nA longer second paragraph line.",
+	}
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{`title =~ "^gopls: "`, true},
+		{`title !~ "^gopls: "`, false},
+		{"body =~ `(?m)^A longer`", true},
+		{`repo == "tools"`, true},
+		{`repo != "tools" || len(title) == 17`, true},
+		{`len(body) < 24`, false},
+		{`lines(body) == 3 && maxline(body) == 31`, true},
+		{`trivial`, true},
+		{`!trivial && len(body) < 24`, false},
+		{`!(trivial && repo == "go")`, true},
+		{`message =~ "typo\n\nShort"`, true},
+		{`maxline("ab\nc") >= 2`, true},
+	}
+	for _, tt := range tests {
+		c, err := parseCond(tt.cond)
+		if err != nil {
+			t.Errorf("parseCond(%q): %v", tt.cond, err)
+			continue
+		}
+		if got := c(change); got != tt.want {
+			t.Errorf("%s = %v; want %v", tt.cond, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{
+		``,
+		`title`,
+		`title =~ body`,
+		`len(body) =~ "x"`,
+		`title < "x"`,
+		`title == 3`,
+		`unknown == "x"`,
+		`len body`,
+		`(trivial`,
+		`trivial trivial`,
+		`title =~ "("`,
+		`title == "unterminated`,
+		`title # "x"`,
+	} {
+		if _, err := parseCond(bad); err == nil {
+			t.Errorf("parseCond(%q) succeeded; want error", bad)
+		}
+	}
+}
+
+func TestConfigRuleSet(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{
+		"disable": ["title: ends with period", "body: long lines"],
+		"rules": [
+			{"name": "title: too long", "group": "title", "when": "len(title) > 30", "finding": "The title is long."},
+			{"name": "title: has caps", "group": "title", "when": "title =~ \"[A-Z]\"", "finding": "The title has capital letters.", "note": "Use lowercase."},
+			{"name": "body: mentions TODO", "when": "body =~ \"TODO\"", "finding": "The body mentions a TODO."}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := cfg.RuleSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := rs.Names()
+	for _, name := range cfg.Disable {
+		if slices.Contains(names, name) {
+			t.Errorf("RuleSet has disabled rule %q", name)
+		}
+	}
+
+	change := Change{
+		Repo:  "go",
+		Title: "fmt: Improve the formatting of things.",
+		Body:  "Do it better. TODO more.\n\nFixes #12345",
+	}
+	var got []string
+	for _, r := range rs.Check(change) {
+		got = append(got, r.Name)
+	}
+	want := []string{
+		"title: no lowercase word after a first colon",
+		// "title: ends with period" is disabled.
+		// Only the first rule in the "title" group reports.
+		"title: too long",
+		"body: mentions TODO",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Check mismatch (-want +got):\n%s", diff)
+	}
+
+	change.Title = "fmt: Improve"
+	got = nil
+	for _, r := range rs.Check(change) {
+		got = append(got, r.Name+": "+r.Note)
+	}
+	want = []string{
+		"title: no lowercase word after a first colon: " + commitMessageAdvice,
+		"title: has caps: Use lowercase.",
+		"body: mentions TODO: " + commitMessageAdvice,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Check mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	for _, cfg := range []string{
+		`{"disable": ["no such rule"]}`,
+		`{"rules": [{"name": "title: ends with period", "when": "trivial", "finding": "x"}]}`,
+		`{"rules": [{"name": "a", "when": "trivial"}]}`,
+		`{"rules": [{"name": "a", "when": "title =~", "finding": "x"}]}`,
+		`{"rules": [{"name": "a", "when": "trivial", "finding": "x"}, {"name": "a", "when": "trivial", "finding": "y"}]}`,
+	} {
+		c, err := ParseConfig([]byte(cfg))
+		if err != nil {
+			t.Errorf("ParseConfig(%s): %v", cfg, err)
+			continue
+		}
+		if _, err := c.RuleSet(); err == nil {
+			t.Errorf("RuleSet for %s succeeded; want error", cfg)
+		}
+	}
+	if _, err := ParseConfig([]byte(`{"rules": 1}`)); err == nil {
+		t.Errorf("ParseConfig of bad JSON succeeded")
+	}
+}
+
+func TestLoadRuleSet(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tools.json"), []byte(`{"disable": ["body: short"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := LoadRuleSet(dir, "tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(rs.Names(), "body: short") {
+		t.Errorf("tools rules include disabled rule")
+	}
+	rs, err = LoadRuleSet(dir, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(DefaultRuleSet.Names(), rs.Names()); diff != "" {
+		t.Errorf("rules for repo without config differ from defaults (-want +got):\n%s", diff)
+	}
+	if _, err := LoadRuleSet(dir, "../etc"); err == nil {
+		t.Errorf("LoadRuleSet with bad repo name succeeded")
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file implements the small expression language used by the
+// "when" conditions of configured rules. For example:
+//
+//	!trivial && len(body) < 24
+//	title =~ `\.$` || body =~ `(?mi)^Signed-off-by: `
+//	repo != "proposal" && maxline(body) > 78
+//
+// The string values are title, body, message (title and body joined
+// by a blank line) and repo. The functions len, lines and maxline
+// return the length in runes, the number of lines, and the length of
+// the longest line of a string. trivial reports whether the change
+// looks like a trivial fix, as the built-in rules use it.
+//
+// Strings can be compared with ==, != and matched against regular
+// expressions with =~ and !~. Integers can be compared with ==, !=,
+// <, <=, > and >=. Conditions combine with &&, || and !, and can be
+// grouped with parentheses. String literals are Go quoted or raw
+// strings.
+
+// A cond is a compiled condition.
+type cond func(change Change) bool
+
+// A value is a compiled string or integer operand.
+type value struct {
+	str func(change Change) string // for strings
+	num func(change Change) int    // for integers
+}
+
+// parseCond compiles the condition expression s.
+func parseCond(s string) (cond, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &condParser{toks: toks}
+	c, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %s", p.toks[p.pos].text)
+	}
+	return c, nil
+}
+
+// token kinds.
+const (
+	tokIdent  = "identifier"
+	tokString = "string"
+	tokNumber = "number"
+	tokOp     = "operator"
+)
+
+type token struct {
+	kind string
+	text string // the string's value, for tokString
+}
+
+// ops are the operators, with longer ones first.
+var ops = []string{"&&", "||", "==", "!=", "=~", "!~", "<=", ">=", "<", ">", "!", "(", ")"}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			return toks, nil
+		}
+		switch c := s[0]; {
+		case c == '"' || c == '`':
+			lit, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("bad string literal at %.10q", s)
+			}
+			v, err := strconv.Unquote(lit)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{tokString, v})
+			s = s[len(lit):]
+		case '0' <= c && c <= '9':
+			i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+			if i < 0 {
+				i = len(s)
+			}
+			toks = append(toks, token{tokNumber, s[:i]})
+			s = s[i:]
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			i := strings.IndexFunc(s, func(r rune) bool {
+				return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			if i < 0 {
+				i = len(s)
+			}
+			toks = append(toks, token{tokIdent, s[:i]})
+			s = s[i:]
+		default:
+			found := false
+			for _, op := range ops {
+				if strings.HasPrefix(s, op) {
+					toks = append(toks, token{tokOp, op})
+					s = s[len(op):]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected %q", s[0])
+			}
+		}
+	}
+}
+
+type condParser struct {
+	toks []token
+	pos  int
+}
+
+// peekOp reports whether the next token is the operator op.
+func (p *condParser) peekOp(op string) bool {
+	return p.pos < len(p.toks) && p.toks[p.pos].kind == tokOp && p.toks[p.pos].text == op
+}
+
+func (p *condParser) next() (token, error) {
+	if p.pos >= len(p.toks) {
+		return token{}, fmt.Errorf("unexpected end of condition")
+	}
+	t := p.toks[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *condParser) or() (cond, error) {
+	x, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("||") {
+		p.pos++
+		y, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		x0 := x
+		x = func(change Change) bool { return x0(change) || y(change) }
+	}
+	return x, nil
+}
+
+func (p *condParser) and() (cond, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("&&") {
+		p.pos++
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		x0 := x
+		x = func(change Change) bool { return x0(change) && y(change) }
+	}
+	return x, nil
+}
+
+func (p *condParser) unary() (cond, error) {
+	switch {
+	case p.peekOp("!"):
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(change Change) bool { return !x(change) }, nil
+	case p.peekOp("("):
+		p.pos++
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekOp(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return x, nil
+	case p.pos < len(p.toks) && p.toks[p.pos] == token{tokIdent, "trivial"}:
+		p.pos++
+		return mightBeTrivial, nil
+	}
+	return p.comparison()
+}
+
+func (p *condParser) comparison() (cond, error) {
+	x, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != tokOp {
+		return nil, fmt.Errorf("expected operator, found %s", op.text)
+	}
+	if op.text == "=~" || op.text == "!~" {
+		if x.str == nil {
+			return nil, fmt.Errorf("%s needs a string on its left", op.text)
+		}
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != tokString {
+			return nil, fmt.Errorf("%s needs a string literal on its right", op.text)
+		}
+		re, err := regexp.Compile(t.text)
+		if err != nil {
+			return nil, err
+		}
+		want := op.text == "=~"
+		return func(change Change) bool { return re.MatchString(x.str(change)) == want }, nil
+	}
+	y, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case x.str != nil && y.str != nil:
+		switch op.text {
+		case "==":
+			return func(change Change) bool { return x.str(change) == y.str(change) }, nil
+		case "!=":
+			return func(change Change) bool { return x.str(change) != y.str(change) }, nil
+		}
+		return nil, fmt.Errorf("cannot compare strings with %s", op.text)
+	case x.num != nil && y.num != nil:
+		var f func(a, b int) bool
+		switch op.text {
+		case "==":
+			f = func(a, b int) bool { return a == b }
+		case "!=":
+			f = func(a, b int) bool { return a != b }
+		case "<":
+			f = func(a, b int) bool { return a < b }
+		case "<=":
+			f = func(a, b int) bool { return a <= b }
+		case ">":
+			f = func(a, b int) bool { return a > b }
+		case ">=":
+			f = func(a, b int) bool { return a >= b }
+		default:
+			return nil, fmt.Errorf("cannot compare integers with %s", op.text)
+		}
+		return func(change Change) bool { return f(x.num(change), y.num(change)) }, nil
+	}
+	return nil, fmt.Errorf("mismatched types in comparison with %s", op.text)
+}
+
+// stringVars are the string values a condition can refer to.
+var stringVars = map[string]func(change Change) string{
+	"title": func(change Change) string { return change.Title },
+	"body":  func(change Change) string { return change.Body },
+	"repo":  func(change Change) string { return change.Repo },
+	"message": func(change Change) string {
+		if change.Body == "" {
+			return change.Title
+		}
+		return change.Title + "\n\n" + change.Body
+	},
+}
+
+// funcs are the functions from a string to an integer a condition can call.
+var funcs = map[string]func(s string) int{
+	"len":   func(s string) int { return len([]rune(s)) },
+	"lines": func(s string) int { return len(splitLines(s)) },
+	"maxline": func(s string) int {
+		longest := 0
+		for _, line := range splitLines(s) {
+			longest = max(longest, len([]rune(line)))
+		}
+		return longest
+	},
+}
+
+func (p *condParser) operand() (value, error) {
+	t, err := p.next()
+	if err != nil {
+		return value{}, err
+	}
+	switch t.kind {
+	case tokString:
+		return value{str: func(Change) string { return t.text }}, nil
+	case tokNumber:
+		n, err := strconv.Atoi(t.text)
+		if err != nil {
+			return value{}, err
+		}
+		return value{num: func(Change) int { return n }}, nil
+	case tokIdent:
+		if v, ok := stringVars[t.text]; ok {
+			return value{str: v}, nil
+		}
+		f, ok := funcs[t.text]
+		if !ok {
+			return value{}, fmt.Errorf("unknown name %s", t.text)
+		}
+		if !p.peekOp("(") {
+			return value{}, fmt.Errorf("%s must be called", t.text)
+		}
+		p.pos++
+		arg, err := p.operand()
+		if err != nil {
+			return value{}, err
+		}
+		if arg.str == nil {
+			return value{}, fmt.Errorf("%s needs a string argument", t.text)
+		}
+		if !p.peekOp(")") {
+			return value{}, fmt.Errorf("missing ) after %s argument", t.text)
+		}
+		p.pos++
+		return value{num: func(change Change) int { return f(arg.str(change)) }}, nil
+	}
+	return value{}, fmt.Errorf("unexpected %s", t.text)
+}
@@ -79,9 +79,14 @@ type Result struct {
 	Note    string
 }
 
-// Check runs the defined rules against one Change.
+// Check runs the built-in rules against one Change.
 func Check(change Change) (results []Result) {
-	for _, group := range ruleGroups {
+	return DefaultRuleSet.Check(change)
+}
+
+// Check runs the rules in rs against one Change.
+func (rs *RuleSet) Check(change Change) (results []Result) {
+	for _, group := range rs.groups {
 		for _, rule := range group {
 			if slices.Contains(rule.skip, change.Repo) || len(rule.only) > 0 && !slices.Contains(rule.only, change.Repo) {
 				continue
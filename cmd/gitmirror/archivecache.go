@@ -0,0 +1,307 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"go.opencensus.io/stats"
+	"golang.org/x/sync/singleflight"
+)
+
+// An archiveFormat is a kind of archive that gitmirror serves.
+type archiveFormat struct {
+	ext         string // URL and cache key suffix
+	gitFormat   string // format for git archive to write
+	contentType string
+	zstd        bool // whether to compress git's output with zstd
+}
+
+var (
+	archiveTgz  = archiveFormat{ext: ".tar.gz", gitFormat: "tgz", contentType: "application/x-compressed"}
+	archiveZstd = archiveFormat{ext: ".tar.zst", gitFormat: "tar", contentType: "application/zstd", zstd: true}
+)
+
+// zstdEncoder compresses zstd archives. Its EncodeAll method may be
+// used concurrently.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// errNotFound is returned for archives of revisions, directories or
+// pathspecs that don't exist.
+var errNotFound = errors.New("unknown revision, path or pathspec")
+
+// archive returns an archive of rev in the given format. If dir is not
+// empty, the archive is of that directory, rooted at it. If there are
+// pathspecs, only the files that match them are included. The hit
+// result reports whether the archive came from the cache.
+//
+// Archives are cached by tree, so a cached archive may carry the commit
+// time and ID of a different revision with the same tree than rev.
+func (r *repo) archive(rev, dir string, pathspecs []string, format archiveFormat) (data []byte, hit bool, err error) {
+	treeish := rev
+	if dir != "" {
+		treeish = rev + ":" + dir
+	}
+	tree, err := r.resolveTree(treeish)
+	if err != nil {
+		return nil, false, err
+	}
+
+	create := func() ([]byte, error) {
+		args := []string{"archive", "--format=" + format.gitFormat, treeish}
+		if len(pathspecs) > 0 {
+			args = append(append(args, "--"), pathspecs...)
+		}
+		data, stderr, err := r.runGitQuiet(args...)
+		if bytes.Contains(stderr, []byte("did not match any files")) {
+			return nil, fmt.Errorf("%w: %s", errNotFound, bytes.TrimSpace(stderr))
+		} else if err != nil {
+			return nil, fmt.Errorf("git archive: %v\n\n%s", err, stderr)
+		}
+		if format.zstd {
+			data = zstdEncoder.EncodeAll(data, nil)
+		}
+		return data, nil
+	}
+	c := r.mirror.archives
+	if c == nil {
+		data, err := create()
+		return data, false, err
+	}
+	key := r.name + "-" + tree
+	if len(pathspecs) > 0 {
+		key += fmt.Sprintf("-%x", sha256.Sum256([]byte(strings.Join(pathspecs, "\x00"))))[:17]
+	}
+	return c.getOrCreate(key+format.ext, create)
+}
+
+// resolveTree returns the hash of the tree that treeish names.
+func (r *repo) resolveTree(treeish string) (string, error) {
+	obj := treeish
+	if strings.Contains(treeish, ":") {
+		// A <rev>:<path> name takes everything after the colon
+		// as the path, so resolve it before peeling it.
+		out, _, err := r.runGitQuiet("rev-parse", "--verify", "--quiet", treeish)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", errNotFound, treeish)
+		}
+		obj = string(bytes.TrimSpace(out))
+	}
+	out, _, err := r.runGitQuiet("rev-parse", "--verify", "--quiet", obj+"^{tree}")
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errNotFound, treeish)
+	}
+	return string(bytes.TrimSpace(out)), nil
+}
+
+// An archiveCache is an on-disk cache of generated archives.
+// Archives are keyed by the hash of the tree they contain, so a tree
+// that several revisions share is only archived once. When the cache
+// grows past its maximum size, the least recently used archives are
+// removed.
+type archiveCache struct {
+	dir      string
+	maxBytes int64
+
+	group singleflight.Group // generates each missing archive once
+
+	mu      sync.Mutex
+	lru     *list.List               // of *archiveEntry, most recently used first
+	entries map[string]*list.Element // by key
+	stats   archiveCacheStats
+}
+
+type archiveEntry struct {
+	key  string
+	size int64
+}
+
+// archiveCacheStats are the statistics of an archiveCache.
+type archiveCacheStats struct {
+	Hits, Misses, Evictions int64
+	Entries                 int
+	Bytes, MaxBytes         int64
+}
+
+// newArchiveCache returns a cache of at most maxBytes of archives in dir,
+// picking up archives left in dir by a previous run.
+func newArchiveCache(dir string, maxBytes int64) (*archiveCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	c := &archiveCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var infos []fs.FileInfo
+	for _, ent := range ents {
+		info, err := ent.Info()
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() || strings.HasPrefix(ent.Name(), ".") {
+			// Left over from an interrupted put.
+			os.Remove(filepath.Join(dir, ent.Name()))
+			continue
+		}
+		infos = append(infos, info)
+	}
+	// Oldest first, so that the newest end up at the front.
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ModTime().Before(infos[j].ModTime()) })
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, info := range infos {
+		c.addLocked(info.Name(), info.Size())
+	}
+	c.evictLocked()
+	return c, nil
+}
+
+// getOrCreate returns the archive with the given key, calling create to
+// generate it if it isn't cached. The hit result reports whether it was.
+func (c *archiveCache) getOrCreate(key string, create func() ([]byte, error)) (data []byte, hit bool, err error) {
+	if data, ok := c.get(key); ok {
+		return data, true, nil
+	}
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		if data, ok := c.get(key); ok {
+			return data, nil
+		}
+		c.mu.Lock()
+		c.stats.Misses++
+		c.mu.Unlock()
+		data, err := create()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.put(key, data); err != nil {
+			log.Printf("archive cache: %v", err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return v.([]byte), false, nil
+}
+
+// get returns the archive with the given key, if it's cached.
+func (c *archiveCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(e)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		log.Printf("archive cache: %v", err)
+		c.remove(key)
+		return nil, false
+	}
+	c.mu.Lock()
+	c.stats.Hits++
+	c.mu.Unlock()
+	return data, true
+}
+
+// put adds an archive to the cache, evicting others as needed.
+func (c *archiveCache) put(key string, data []byte) error {
+	if int64(len(data)) > c.maxBytes {
+		return nil
+	}
+	f, err := os.CreateTemp(c.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(c.dir, key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("writing %s: %v", key, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addLocked(key, int64(len(data)))
+	c.evictLocked()
+	return nil
+}
+
+func (c *archiveCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.removeLocked(e)
+	}
+}
+
+func (c *archiveCache) addLocked(key string, size int64) {
+	if e, ok := c.entries[key]; ok {
+		c.stats.Bytes -= e.Value.(*archiveEntry).size
+		c.lru.Remove(e)
+	}
+	c.entries[key] = c.lru.PushFront(&archiveEntry{key: key, size: size})
+	c.stats.Bytes += size
+}
+
+func (c *archiveCache) removeLocked(e *list.Element) {
+	ent := e.Value.(*archiveEntry)
+	c.lru.Remove(e)
+	delete(c.entries, ent.key)
+	c.stats.Bytes -= ent.size
+}
+
+// evictLocked removes the least recently used archives until the
+// cache is no larger than its maximum size.
+func (c *archiveCache) evictLocked() {
+	for c.stats.Bytes > c.maxBytes {
+		e := c.lru.Back()
+		key := e.Value.(*archiveEntry).key
+		c.removeLocked(e)
+		if err := os.Remove(filepath.Join(c.dir, key)); err != nil && !os.IsNotExist(err) {
+			log.Printf("archive cache: %v", err)
+		}
+		c.stats.Evictions++
+		stats.Record(context.Background(), mArchiveEvictions.M(1))
+	}
+	stats.Record(context.Background(), mArchiveCacheSize.M(c.stats.Bytes))
+}
+
+// snapshot returns the current statistics of the cache.
+func (c *archiveCache) snapshot() archiveCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Entries = len(c.entries)
+	s.MaxBytes = c.maxBytes
+	return s
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newArchiveCache(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := func(s string) func() ([]byte, error) {
+		return func() ([]byte, error) { return []byte(s), nil }
+	}
+	for _, key := range []string{"a", "b"} {
+		if _, hit, err := c.getOrCreate(key, create("1234")); err != nil || hit {
+			t.Fatalf("getOrCreate(%q) = %v, %v; want miss", key, hit, err)
+		}
+	}
+	// Use a, so that b is the least recently used.
+	if data, hit, err := c.getOrCreate("a", nil); err != nil || !hit || string(data) != "1234" {
+		t.Fatalf("getOrCreate(a) = %q, %v, %v; want hit", data, hit, err)
+	}
+	if _, _, err := c.getOrCreate("c", create("1234")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b")); !os.IsNotExist(err) {
+		t.Errorf("b was not evicted: %v", err)
+	}
+	// Archives larger than the cache aren't cached.
+	if _, _, err := c.getOrCreate("big", create(strings.Repeat("x", 11))); err != nil {
+		t.Fatal(err)
+	}
+	want := archiveCacheStats{Hits: 1, Misses: 4, Evictions: 1, Entries: 2, Bytes: 8, MaxBytes: 10}
+	if got := c.snapshot(); got != want {
+		t.Errorf("stats = %+v; want %+v", got, want)
+	}
+
+	// Errors aren't cached.
+	errBad := errors.New("bad")
+	if _, _, err := c.getOrCreate("d", func() ([]byte, error) { return nil, errBad }); err != errBad {
+		t.Errorf("getOrCreate error = %v; want %v", err, errBad)
+	}
+
+	// A new cache picks up the archives left by the old one,
+	// and removes any partial ones.
+	if err := os.WriteFile(filepath.Join(dir, ".tmp-123"), []byte("x"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	c, err = newArchiveCache(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.snapshot(); got.Entries != 2 || got.Bytes != 8 {
+		t.Errorf("reopened cache stats = %+v; want 2 entries of 8 bytes", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".tmp-123")); !os.IsNotExist(err) {
+		t.Errorf("partial archive was not removed: %v", err)
+	}
+	if data, hit, err := c.getOrCreate("c", nil); err != nil || !hit || string(data) != "1234" {
+		t.Errorf("getOrCreate(c) = %q, %v, %v; want hit", data, hit, err)
+	}
+}
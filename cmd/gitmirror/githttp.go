@@ -0,0 +1,144 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/build/internal/envutil"
+)
+
+// uploadPackConfig is the configuration that git upload-pack runs with
+// when serving fetches. Filters allow partial clones, and requests for
+// any object let partial clones fetch missing blobs lazily. Shallow
+// fetches are supported by default.
+var uploadPackConfig = []string{
+	"-c", "uploadpack.allowFilter=true",
+	"-c", "uploadpack.allowAnySHA1InWant=true",
+}
+
+// serveGit serves the repo read-only over git's smart HTTP protocol,
+// so that it can be fetched from at <base URL>/<name>.git.
+//
+// GET /<name>.git/info/refs?service=git-upload-pack
+// POST /<name>.git/git-upload-pack
+func (r *repo) serveGit(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	lw := &loggingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		log.Printf("git-http: %s %s %s %q: status %d, %d bytes in %v",
+			req.RemoteAddr, req.Method, req.URL.Path, req.Header.Get("Git-Protocol"),
+			lw.status, lw.n, time.Since(start).Round(time.Millisecond))
+	}()
+
+	suffix := strings.TrimPrefix(req.URL.Path, "/"+r.name+".git")
+	switch {
+	case suffix == "/info/refs" && (req.Method == "GET" || req.Method == "HEAD"):
+		switch service := req.FormValue("service"); service {
+		case "git-upload-pack":
+			r.serveInfoRefs(lw, req)
+		case "":
+			http.Error(lw, "only the smart HTTP protocol is supported", http.StatusForbidden)
+		default:
+			http.Error(lw, "this mirror is read-only", http.StatusForbidden)
+		}
+	case suffix == "/git-upload-pack" && req.Method == "POST":
+		r.serveUploadPack(lw, req)
+	case suffix == "/git-receive-pack":
+		http.Error(lw, "this mirror is read-only", http.StatusForbidden)
+	default:
+		http.NotFound(lw, req)
+	}
+}
+
+// serveInfoRefs serves the ref advertisement that starts a fetch.
+func (r *repo) serveInfoRefs(w http.ResponseWriter, req *http.Request) {
+	ctx, cancel := context.WithTimeout(req.Context(), time.Minute)
+	defer cancel()
+	w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Protocol v2 clients expect the capability advertisement right
+	// away; older ones expect a service line first.
+	if !strings.Contains(req.Header.Get("Git-Protocol"), "version=2") {
+		io.WriteString(w, pktLine("# service=git-upload-pack\n"))
+		io.WriteString(w, "0000")
+	}
+	cmd := r.uploadPackCmd(req, "--advertise-refs")
+	cmd.Stdout = w
+	if err := runCmdContext(ctx, cmd); err != nil {
+		r.logf("git upload-pack --advertise-refs: %v", err)
+	}
+}
+
+// serveUploadPack serves a request for objects.
+func (r *repo) serveUploadPack(w http.ResponseWriter, req *http.Request) {
+	ctx, cancel := context.WithTimeout(req.Context(), 30*time.Minute)
+	defer cancel()
+	var body io.Reader = req.Body
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer zr.Close()
+		body = zr
+	}
+	w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+	w.Header().Set("Cache-Control", "no-cache")
+	cmd := r.uploadPackCmd(req)
+	cmd.Stdin = body
+	cmd.Stdout = w
+	if err := runCmdContext(ctx, cmd); err != nil {
+		r.logf("git upload-pack: %v", err)
+	}
+}
+
+// uploadPackCmd returns a stateless git upload-pack command for the
+// repo, passing along the protocol version that the client asked for.
+func (r *repo) uploadPackCmd(req *http.Request, args ...string) *exec.Cmd {
+	var cmdArgs []string
+	cmdArgs = append(cmdArgs, uploadPackConfig...)
+	cmdArgs = append(cmdArgs, "upload-pack", "--stateless-rpc")
+	cmdArgs = append(cmdArgs, args...)
+	cmd := exec.Command("git", append(cmdArgs, r.root)...)
+	envutil.SetDir(cmd, r.root)
+	envutil.SetEnv(cmd, "GIT_DIR="+r.root, "HOME="+r.mirror.homeDir)
+	if proto := req.Header.Get("Git-Protocol"); proto != "" {
+		envutil.SetEnv(cmd, "GIT_PROTOCOL="+proto)
+	}
+	return cmd
+}
+
+// pktLine returns s encoded as a git pkt-line.
+func pktLine(s string) string {
+	return fmt.Sprintf("%04x%s", len(s)+4, s)
+}
+
+// A loggingResponseWriter records the status and size of a response.
+type loggingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	n      int64
+}
+
+func (w *loggingResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *loggingResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -5,20 +5,25 @@
 // The gitmirror binary watches the specified Gerrit repositories for
 // new commits and syncs them to mirror repositories.
 //
-// It also serves tarballs over HTTP for the build system.
+// It also serves tarballs over HTTP for the build system, and serves
+// the repositories read-only over git's smart HTTP protocol at
+// /<name>.git, so that they can be fetched from the mirror.
 package main
 
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"html"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path"
 	"path/filepath"
 	"runtime"
 	"sort"
@@ -27,9 +32,14 @@ import (
 	"sync"
 	"time"
 
+	"cloud.google.com/go/compute/metadata"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"golang.org/x/build/cmd/pubsubhelper/pubsubtypes"
 	"golang.org/x/build/gerrit"
 	"golang.org/x/build/internal/envutil"
 	"golang.org/x/build/internal/gitauth"
+	"golang.org/x/build/internal/metrics"
 	"golang.org/x/build/internal/secret"
 	"golang.org/x/build/maintner"
 	"golang.org/x/build/maintner/godata"
@@ -38,13 +48,18 @@ import (
 )
 
 var (
-	flagHTTPAddr     = flag.String("http", "", "If non-empty, the listen address to run an HTTP server on")
-	flagCacheDir     = flag.String("cachedir", "", "git cache directory. If empty a temp directory is made.")
-	flagPollInterval = flag.Duration("poll", 60*time.Second, "Remote repo poll interval")
-	flagMirror       = flag.Bool("mirror", false, "whether to mirror to mirror repos; if disabled, it only runs in HTTP archive server mode")
-	flagMirrorGitHub = flag.Bool("mirror-github", true, "whether to mirror to GitHub when mirroring is enabled")
-	flagMirrorCSR    = flag.Bool("mirror-csr", true, "whether to mirror to Cloud Source Repositories when mirroring is enabled")
-	flagSecretsDir   = flag.String("secretsdir", "", "directory to load secrets from instead of GCP")
+	flagHTTPAddr       = flag.String("http", "", "If non-empty, the listen address to run an HTTP server on")
+	flagCacheDir       = flag.String("cachedir", "", "git cache directory. If empty a temp directory is made.")
+	flagPollInterval   = flag.Duration("poll", 60*time.Second, "Remote repo poll interval")
+	flagMirror         = flag.Bool("mirror", false, "whether to mirror to mirror repos; if disabled, it only runs in HTTP archive server mode")
+	flagMirrorGitHub   = flag.Bool("mirror-github", true, "whether to mirror to GitHub when mirroring is enabled")
+	flagMirrorCSR      = flag.Bool("mirror-csr", true, "whether to mirror to Cloud Source Repositories when mirroring is enabled")
+	flagMirrorExtra    = flag.Bool("mirror-extra", true, "whether to mirror to the extra mirrors configured in x/build/repos when mirroring is enabled")
+	flagMirrorInterval = flag.Duration("mirror-interval", time.Hour, "how often to push the whole repo to each mirror with --mirror, repairing refs changed on the mirror by others; 0 to only push changed refs after the first push")
+	flagPubSub         = flag.String("pubsub", "https://pubsubhelper.golang.org", "base URL of the pubsubhelper to long-poll for Gerrit events; empty to disable")
+	flagServeGit       = flag.Bool("serve-git", true, "whether to serve the repos read-only over git's smart HTTP protocol")
+	flagArchiveCache   = flag.Int64("archive-cache-size", 10<<30, "maximum size in bytes of the on-disk cache of generated archives; 0 to disable")
+	flagSecretsDir     = flag.String("secretsdir", "", "directory to load secrets from instead of GCP")
 )
 
 func main() {
@@ -59,6 +74,19 @@ func main() {
 	http.HandleFunc("/debug/env", handleDebugEnv)
 	http.HandleFunc("/debug/goroutines", handleDebugGoroutines)
 
+	// Both the serving and the mirroring deployments name their
+	// container gitmirror; the pod name tells them apart.
+	gr, err := metrics.GKEResource("gitmirror")
+	if err != nil && metadata.OnGCE() {
+		log.Println("metrics.GKEResource:", err)
+	}
+	if ms, err := metrics.NewService(gr, views); err != nil {
+		log.Println("failed to initialize metrics:", err)
+	} else {
+		http.Handle("/metrics", ms)
+		defer ms.Stop()
+	}
+
 	if err := gitauth.Init(); err != nil {
 		log.Fatalf("gitauth: %v", err)
 	}
@@ -82,8 +110,16 @@ func main() {
 		gerritClient: gerrit.NewClient("https://go-review.googlesource.com", gerrit.NoAuth),
 		mirrorGitHub: *flagMirrorGitHub,
 		mirrorCSR:    *flagMirrorCSR,
+		mirrorExtra:  *flagMirrorExtra,
+		serveGit:     *flagServeGit,
 		timeoutScale: 1,
 	}
+	if *flagArchiveCache > 0 {
+		m.archives, err = newArchiveCache(filepath.Join(cacheDir, ".archive-cache"), *flagArchiveCache)
+		if err != nil {
+			log.Fatalf("creating archive cache: %v", err)
+		}
+	}
 
 	var eg errgroup.Group
 	for _, repo := range repospkg.ByGerritProject {
@@ -93,6 +129,7 @@ func main() {
 
 	http.HandleFunc("/", m.handleRoot)
 	http.HandleFunc("/healthz", m.handleHealth)
+	http.HandleFunc("/debug/archive-cache", m.handleArchiveCache)
 
 	if err := eg.Wait(); err != nil {
 		log.Fatalf("initializing repos: %v", err)
@@ -112,6 +149,10 @@ func main() {
 	}
 	go m.pollGerritAndTickleLoop()
 	go m.subscribeToMaintnerAndTickleLoop()
+	if *flagPubSub != "" {
+		go m.subscribeToPubSubAndTickleLoop(*flagPubSub)
+	}
+	go m.reportMetricsLoop()
 
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt)
@@ -203,6 +244,9 @@ type gitMirror struct {
 	goBase                  string // Base URL/path for Go upstream repos.
 	gerritClient            *gerrit.Client
 	mirrorGitHub, mirrorCSR bool
+	mirrorExtra             bool          // mirror to the repos' ExtraMirrors
+	serveGit                bool          // serve the repos over git's smart HTTP protocol
+	archives                *archiveCache // if non-nil, caches generated archives
 	timeoutScale            int
 }
 
@@ -216,8 +260,12 @@ func (m *gitMirror) addRepo(meta *repospkg.Repo) *repo {
 		changed: make(chan bool, 1),
 		mirror:  m,
 	}
-	m.mux.Handle("/"+name+".tar.gz", r)
+	m.mux.Handle("/"+name+archiveTgz.ext, r)
+	m.mux.Handle("/"+name+archiveZstd.ext, r)
 	m.mux.Handle("/debug/watcher/"+r.name, r)
+	if m.serveGit {
+		m.mux.HandleFunc("/"+name+".git/", r.serveGit)
+	}
 	m.repos[name] = r
 	return r
 }
@@ -237,6 +285,13 @@ func (m *gitMirror) addMirrors() error {
 				return fmt.Errorf("adding CSR remote: %v", err)
 			}
 		}
+		if m.mirrorExtra {
+			for _, mirror := range repo.meta.ExtraMirrors {
+				if err := repo.addRemote(mirror.Name, mirror.URL, mirror.PushOption); err != nil {
+					return fmt.Errorf("adding %s remote: %v", mirror.Name, err)
+				}
+			}
+		}
 	}
 	return nil
 }
@@ -256,12 +311,33 @@ func (m *gitMirror) handleRoot(w http.ResponseWriter, r *http.Request) {
 		names = append(names, name)
 	}
 	sort.Strings(names)
+	now := time.Now()
 	for _, name := range names {
-		fmt.Fprintf(w, "<a href='/debug/watcher/%s'>%s</a> - %s\n", name, name, m.repos[name].statusLine())
+		r := m.repos[name]
+		fmt.Fprintf(w, "<a href='/debug/watcher/%s'>%s</a> - %s\n", name, name, r.statusLine())
+		for _, dest := range r.dests {
+			fmt.Fprintf(w, "    %s\n", html.EscapeString(dest.status(now)))
+		}
+	}
+	if m.archives != nil {
+		st := m.archives.snapshot()
+		fmt.Fprintf(w, "\n<a href='/debug/archive-cache'>archive cache</a> - %d archives, %d of %d bytes\n", st.Entries, st.Bytes, st.MaxBytes)
 	}
 	fmt.Fprint(w, "</pre></body></html>")
 }
 
+// GET /debug/archive-cache
+func (m *gitMirror) handleArchiveCache(w http.ResponseWriter, r *http.Request) {
+	if m.archives == nil {
+		http.Error(w, "archive cache disabled", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	e := json.NewEncoder(w)
+	e.SetIndent("", "\t")
+	e.Encode(m.archives.snapshot())
+}
+
 func (m *gitMirror) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	for _, r := range m.repos {
@@ -320,11 +396,6 @@ func (r *statusRing) foreachDesc(fn func(statusEntry)) {
 	}
 }
 
-type remote struct {
-	name       string // name as configured in the repo.
-	pushOption string // optional extra push option (--push-option).
-}
-
 // repo represents a repository to be watched.
 type repo struct {
 	name    string
@@ -333,7 +404,7 @@ type repo struct {
 	meta    *repospkg.Repo
 	changed chan bool // sent to when a change comes in
 	status  statusRing
-	dests   []remote // destination remotes to mirror to
+	dests   []*remote // destination remotes to mirror to
 	mirror  *gitMirror
 
 	mu        sync.Mutex
@@ -454,7 +525,7 @@ func (r *repo) setStatus(status string) {
 }
 
 func (r *repo) addRemote(name, url, pushOption string) error {
-	r.dests = append(r.dests, remote{
+	r.dests = append(r.dests, &remote{
 		name:       name,
 		pushOption: pushOption,
 	})
@@ -495,18 +566,37 @@ func (r *repo) loop() {
 }
 
 func (r *repo) loopOnce() error {
+	start := time.Now()
 	if err := r.fetch(); err != nil {
 		r.logf("fetch failed: %v", err)
 		r.setErr(err)
 		return err
 	}
+	if len(r.dests) == 0 {
+		r.setErr(nil)
+		r.setStatus("waiting")
+		return nil
+	}
+	refs, err := r.mirroredRefs()
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+	// Push to every remote even if one of them fails,
+	// so that a broken mirror doesn't hold up the others.
+	var firstErr error
 	for _, dest := range r.dests {
-		if err := r.push(dest); err != nil {
-			r.logf("push failed: %v", err)
-			r.setErr(err)
-			return err
+		if err := r.push(dest, refs, start); err != nil {
+			r.logf("push to %s failed: %v", dest.name, err)
+			if firstErr == nil {
+				firstErr = err
+			}
 		}
 	}
+	if firstErr != nil {
+		r.setErr(firstErr)
+		return firstErr
+	}
 	r.setErr(nil)
 	r.setStatus("waiting")
 	return nil
@@ -534,25 +624,44 @@ func (r *repo) fetch() error {
 	return err
 }
 
-// push runs "git push -f --mirror dest" in the repository root.
+// push brings dest up to date with refs, the repo's mirrored refs as
+// of a fetch that started at fetched. The first push to dest runs
+// "git push -f --mirror dest", and so does a push every
+// *flagMirrorInterval; others push only the refs that changed since
+// the last successful push.
 // It tries three times, just in case it failed because of a transient error.
-func (r *repo) push(dest remote) error {
+func (r *repo) push(dest *remote, refs map[string]string, fetched time.Time) error {
+	specs, all := dest.refspecs(refs, time.Now())
+	if !all && len(specs) == 0 {
+		return nil
+	}
+	dest.noteBehind(fetched)
 	err := r.try(3, func(attempt int) error {
-		r.setStatus(fmt.Sprintf("syncing to %v, attempt %d", dest, attempt))
-		args := []string{"push", "-f", "--mirror"}
+		r.setStatus(fmt.Sprintf("syncing to %v, attempt %d", dest.name, attempt))
+		args := []string{"push", "-f"}
+		if all {
+			args = append(args, "--mirror")
+		}
 		if dest.pushOption != "" {
 			args = append(args, "--push-option", dest.pushOption)
 		}
 		args = append(args, dest.name)
+		args = append(args, specs...)
 		if _, stderr, err := r.runGitLogged(args...); err != nil {
 			return fmt.Errorf("%v\n\n%s", err, stderr)
 		}
 		return nil
 	})
+	dest.pushDone(refs, all, err, time.Now())
+	if all {
+		r.recordPush(dest, len(refs), err)
+	} else {
+		r.recordPush(dest, len(specs), err)
+	}
 	if err != nil {
 		r.setStatus("sync to " + dest.name + " failed")
 	} else {
-		r.setStatus("did sync to " + dest.name)
+		r.setStatus(fmt.Sprintf("did sync to %s (%d refs)", dest.name, len(specs)))
 	}
 	return err
 }
@@ -566,8 +675,13 @@ func (r *repo) fetchRevIfNeeded(ctx context.Context, rev string) error {
 	return err
 }
 
-// GET /<name>.tar.gz
+// GET /<name>.tar.gz?rev=<rev>[&path=<dir>][&pathspec=<pathspec>...]
+// GET /<name>.tar.zst?rev=<rev>[&path=<dir>][&pathspec=<pathspec>...]
 // GET /debug/watcher/<name>
+//
+// The path parameter requests an archive of a subdirectory, rooted at
+// that directory, and the pathspec parameters limit the archive to the
+// files that match them, as in git archive.
 func (r *repo) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	if req.Method != "GET" && req.Method != "HEAD" {
 		w.WriteHeader(http.StatusBadRequest)
@@ -578,24 +692,47 @@ func (r *repo) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	rev := req.FormValue("rev")
-	if rev == "" {
+	if rev == "" || strings.HasPrefix(rev, "-") {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	dir := req.FormValue("path")
+	if dir != "" {
+		dir = path.Clean(dir)
+		if path.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, "../") {
+			http.Error(w, "invalid path", http.StatusBadRequest)
+			return
+		}
+	}
+	format := archiveTgz
+	if strings.HasSuffix(req.URL.Path, archiveZstd.ext) {
+		format = archiveZstd
+	}
 	ctx, cancel := context.WithTimeout(req.Context(), 30*time.Second)
 	defer cancel()
 	if err := r.fetchRevIfNeeded(ctx, rev); err != nil {
 		// Try the archive anyway, it might work
 		r.logf("error fetching revision %s: %v", rev, err)
 	}
-	tgz, _, err := r.runGitQuiet("archive", "--format=tgz", rev)
-	if err != nil {
+	data, hit, err := r.archive(rev, dir, req.Form["pathspec"], format)
+	if errors.Is(err, errNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Content-Length", strconv.Itoa(len(tgz)))
-	w.Header().Set("Content-Type", "application/x-compressed")
-	w.Write(tgz)
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	stats.RecordWithTags(context.Background(),
+		[]tag.Mutator{tag.Upsert(kRepo, r.name), tag.Upsert(kCacheResult, result)},
+		mArchiveRequests.M(1))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("X-Archive-Cache", result)
+	w.Write(data)
 }
 
 func (r *repo) serveStatus(w http.ResponseWriter, req *http.Request) {
@@ -603,7 +740,15 @@ func (r *repo) serveStatus(w http.ResponseWriter, req *http.Request) {
 	fmt.Fprintf(w, "<html><head><title>watcher: %s</title><body><h1>watcher status for repo: %q</h1>\n",
 		r.name, r.name)
 	fmt.Fprintf(w, "<pre>\n")
-	nowRound := time.Now().Round(time.Second)
+	now := time.Now()
+	if len(r.dests) > 0 {
+		fmt.Fprintf(w, "mirrors:\n")
+		for _, dest := range r.dests {
+			fmt.Fprintf(w, "  %s\n", html.EscapeString(dest.status(now)))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	nowRound := now.Round(time.Second)
 	r.status.foreachDesc(func(ent statusEntry) {
 		fmt.Fprintf(w, "%v   %-20s %v\n",
 			ent.t.In(time.UTC).Format(time.RFC3339),
@@ -693,6 +838,66 @@ func (m *gitMirror) subscribeToMaintnerAndTickle() error {
 	}
 }
 
+// subscribeToPubSubAndTickleLoop long-polls the pubsubhelper at urlBase
+// for Gerrit events, such as ref updates, and tickles the loop of the
+// event's repo so that it is mirrored right away.
+func (m *gitMirror) subscribeToPubSubAndTickleLoop(urlBase string) {
+	var after time.Time
+	for {
+		newAfter, err := m.waitPubSubEvent(urlBase, after)
+		if err != nil {
+			log.Printf("pubsub loop: %v; retrying in 5 seconds", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		after = newAfter
+	}
+}
+
+// waitPubSubEvent waits for the next pubsubhelper event after the given
+// time, tickles the loop of the repo it is about, if any, and returns
+// the time to wait for the next event after.
+func (m *gitMirror) waitPubSubEvent(urlBase string, after time.Time) (time.Time, error) {
+	var afterStr string
+	if !after.IsZero() {
+		afterStr = after.UTC().Format(time.RFC3339Nano)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", urlBase+"/waitevent?after="+afterStr, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return time.Time{}, errors.New(res.Status)
+	}
+	var evt pubsubtypes.Event
+	if err := json.NewDecoder(res.Body).Decode(&evt); err != nil {
+		return time.Time{}, err
+	}
+	if gr := evt.Gerrit; gr != nil && strings.HasPrefix(gr.URL, "https://go-review.googlesource.com/") {
+		stats.Record(context.Background(), mPubSubEvents.M(1))
+		m.notifyChanged(gr.Project)
+	}
+	return evt.Time.Time(), nil
+}
+
+// reportMetricsLoop periodically records the lag of every mirror.
+func (m *gitMirror) reportMetricsLoop() {
+	for {
+		now := time.Now()
+		for _, r := range m.repos {
+			r.reportMetrics(now)
+		}
+		time.Sleep(time.Minute)
+	}
+}
+
 // gerritMetaMap returns the map from repo name (e.g. "go") to its
 // latest master hash.
 func (m *gitMirror) gerritMetaMap() (map[string]string, error) {
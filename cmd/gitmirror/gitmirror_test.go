@@ -5,6 +5,9 @@
 package main
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,9 +15,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
+	"go4.org/types"
+	"golang.org/x/build/cmd/pubsubhelper/pubsubtypes"
 	"golang.org/x/build/internal/envutil"
 	repospkg "golang.org/x/build/repos"
 )
@@ -64,6 +72,146 @@ func TestArchive(t *testing.T) {
 	tm.get("/build.tar.gz?rev=" + secondRev)
 }
 
+func TestArchiveSubdirAndFormats(t *testing.T) {
+	tm := newTestMirror(t)
+	for name, content := range map[string]string{
+		"go.mod":          "module golang.org/x/build\n",
+		"cmd/a/a.go":      "package a\n",
+		"cmd/a/a_test.go": "package a\n",
+		"cmd/b/b.go":      "package b\n",
+		"internal/x/x.go": "package x\n",
+	} {
+		file := filepath.Join(tm.gerrit.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tm.commit("first commit")
+	rev := strings.TrimSpace(tm.git(tm.gerrit, "rev-parse", "HEAD"))
+	tm.loopOnce()
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/build.tar.gz?rev=" + rev, []string{"README", "cmd/", "cmd/a/", "cmd/a/a.go", "cmd/a/a_test.go", "cmd/b/", "cmd/b/b.go", "go.mod", "internal/", "internal/x/", "internal/x/x.go"}},
+		{"/build.tar.gz?rev=" + rev + "&path=cmd/a", []string{"a.go", "a_test.go"}},
+		{"/build.tar.zst?rev=" + rev + "&path=cmd", []string{"a/", "a/a.go", "a/a_test.go", "b/", "b/b.go"}},
+		{"/build.tar.gz?rev=" + rev + "&pathspec=go.mod&pathspec=cmd/*/*_test.go", []string{"cmd/", "cmd/a/", "cmd/a/a_test.go", "go.mod"}},
+	}
+	for _, tt := range tests {
+		for _, wantCache := range []string{"miss", "hit"} {
+			resp, err := http.Get(tm.server.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("GET %s: status %d", tt.path, resp.StatusCode)
+			}
+			if got := resp.Header.Get("X-Archive-Cache"); got != wantCache {
+				t.Errorf("GET %s: cache %s, want %s", tt.path, got, wantCache)
+			}
+			var got []string
+			for _, hdr := range readArchive(t, resp) {
+				got = append(got, hdr.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GET %s: files %q, want %q", tt.path, got, tt.want)
+			}
+		}
+	}
+
+	// A second revision with the same tree is served from the cache.
+	tm.git(tm.gerrit, "commit", "--allow-empty", "-m", "empty")
+	tm.loopOnce()
+	rev2 := strings.TrimSpace(tm.git(tm.gerrit, "rev-parse", "HEAD"))
+	resp, err := http.Get(tm.server.URL + "/build.tar.gz?rev=" + rev2 + "&path=cmd/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("X-Archive-Cache"); got != "hit" {
+		t.Errorf("archive of the same tree at another revision: cache %s, want hit", got)
+	}
+
+	for _, path := range []string{
+		"/build.tar.gz?rev=" + rev + "&path=nope",
+		"/build.tar.gz?rev=" + rev + "&pathspec=nope",
+		"/build.tar.gz?rev=0000000000000000000000000000000000000000",
+	} {
+		resp, err := http.Get(tm.server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s: status %d, want %d", path, resp.StatusCode, http.StatusNotFound)
+		}
+	}
+	for _, path := range []string{"/build.tar.gz?rev=--output=x", "/build.tar.gz?rev=" + rev + "&path=../x"} {
+		resp, err := http.Get(tm.server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("GET %s: status %d, want %d", path, resp.StatusCode, http.StatusBadRequest)
+		}
+	}
+
+	var st archiveCacheStats
+	if err := json.Unmarshal([]byte(tm.get("/debug/archive-cache")), &st); err != nil {
+		t.Fatal(err)
+	}
+	// The pathspec that matches nothing is a miss that isn't cached.
+	if st.Hits != 5 || st.Misses != 5 || st.Entries != 4 {
+		t.Errorf("cache stats = %+v; want 5 hits, 5 misses and 4 entries", st)
+	}
+}
+
+// readArchive returns the headers of the files in the tar archive in
+// resp's body, decompressing it according to its content type.
+func readArchive(t *testing.T, resp *http.Response) []*tar.Header {
+	t.Helper()
+	defer resp.Body.Close()
+	var r io.Reader
+	switch ct := resp.Header.Get("Content-Type"); ct {
+	case archiveTgz.contentType:
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = zr
+	case archiveZstd.contentType:
+		zr, err := zstd.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	var hdrs []*tar.Header
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		hdrs = append(hdrs, hdr)
+	}
+	return hdrs
+}
+
 func TestMirror(t *testing.T) {
 	tm := newTestMirror(t)
 	for i := 0; i < 2; i++ {
@@ -94,6 +242,230 @@ func TestMirrorInitiallyEmpty(t *testing.T) {
 	}
 }
 
+func TestMirrorChangedRefs(t *testing.T) {
+	tm := newTestMirror(t)
+	tm.commit("first commit")
+	tm.git(tm.gerrit, "branch", "release-branch.go1.21")
+	tm.loopOnce()
+	if got := tm.git(tm.github, "branch", "--list", "release-branch.go1.21"); !strings.Contains(got, "release-branch.go1.21") {
+		t.Fatalf("github is missing release-branch.go1.21; branches: %q", got)
+	}
+
+	// Unchanged refs don't need a push.
+	github := tm.buildRepo.dests[0]
+	refs, err := tm.buildRepo.mirroredRefs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if specs, all := github.refspecs(refs, time.Now()); all || len(specs) != 0 {
+		t.Errorf("refspecs with no changes = %q, %v; want none", specs, all)
+	}
+
+	tm.commit("second commit")
+	tm.git(tm.gerrit, "branch", "-D", "release-branch.go1.21")
+	tm.git(tm.gerrit, "tag", "v0.1.0")
+	if err := tm.buildRepo.fetch(); err != nil {
+		t.Fatal(err)
+	}
+	refs, err = tm.buildRepo.mirroredRefs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := strings.TrimSpace(tm.git(tm.gerrit, "symbolic-ref", "HEAD"))
+	want := []string{"+" + head + ":" + head, "+refs/tags/v0.1.0:refs/tags/v0.1.0", ":refs/heads/release-branch.go1.21"}
+	if specs, all := github.refspecs(refs, time.Now()); all || !reflect.DeepEqual(specs, want) {
+		t.Errorf("refspecs = %q, %v; want %q", specs, all, want)
+	}
+
+	tm.loopOnce()
+	for _, dest := range []explicitRepo{tm.github, tm.csr} {
+		if got := tm.git(dest, "branch", "--list", "release-branch.go1.21"); got != "" {
+			t.Errorf("%s still has deleted branch: %q", dest.gitDir, got)
+		}
+		if got, want := tm.git(dest, "rev-parse", "v0.1.0"), tm.git(tm.gerrit, "rev-parse", "HEAD"); got != want {
+			t.Errorf("%s has tag v0.1.0 at %v, want %v", dest.gitDir, got, want)
+		}
+	}
+}
+
+func TestMirrorRepairsRemote(t *testing.T) {
+	tm := newTestMirror(t)
+	tm.commit("first commit")
+	tm.loopOnce()
+
+	// A branch created on the mirror by others isn't noticed
+	// by the pushes of changed refs, but it is by the next
+	// push of the whole repo.
+	tm.git(tm.github, "branch", "stray")
+	tm.commit("second commit")
+	tm.loopOnce()
+	if got := tm.git(tm.github, "branch", "--list", "stray"); got == "" {
+		t.Fatalf("stray branch was removed before the whole repo was due to be pushed")
+	}
+	github := tm.buildRepo.dests[0]
+	github.mu.Lock()
+	github.lastMirror = time.Now().Add(-*flagMirrorInterval)
+	github.mu.Unlock()
+	tm.loopOnce()
+	if got := tm.git(tm.github, "branch", "--list", "stray"); got != "" {
+		t.Errorf("stray branch is still on the mirror: %q", got)
+	}
+}
+
+func TestMirrorRemoteFailure(t *testing.T) {
+	tm := newTestMirror(t)
+	if err := tm.buildRepo.addRemote("broken", filepath.Join(t.TempDir(), "missing"), ""); err != nil {
+		t.Fatal(err)
+	}
+	tm.commit("first commit")
+	if err := tm.buildRepo.loopOnce(); err == nil {
+		t.Fatal("loopOnce succeeded with a broken remote")
+	}
+	// The other remotes are mirrored regardless.
+	rev := tm.git(tm.gerrit, "rev-parse", "HEAD")
+	if githubRev := tm.git(tm.github, "rev-parse", "HEAD"); rev != githubRev {
+		t.Errorf("github HEAD is %v, want %v", githubRev, rev)
+	}
+
+	now := time.Now()
+	for _, dest := range tm.buildRepo.dests {
+		lag := dest.lag(now)
+		if broken := dest.name == "broken"; broken != (lag > 0) {
+			t.Errorf("%s lag = %v", dest.name, lag)
+		}
+	}
+	body := tm.get("/")
+	for _, substr := range []string{"github: up to date", "broken: behind for", "last error"} {
+		if !strings.Contains(body, substr) {
+			t.Errorf("GET /: want %q in body, got %s", substr, body)
+		}
+	}
+}
+
+func TestAddExtraMirrors(t *testing.T) {
+	tm := newTestMirror(t)
+	tm.buildRepo.dests = nil
+	tm.m.mirrorGitHub, tm.m.mirrorCSR = false, false
+	extra := initBareRepo(t)
+	tm.buildRepo.meta.ExtraMirrors = []repospkg.Mirror{{Name: "extra", URL: extra.gitDir}}
+	if err := tm.m.addMirrors(); err != nil {
+		t.Fatal(err)
+	}
+	tm.commit("first commit")
+	tm.loopOnce()
+	if got, want := tm.git(extra, "rev-parse", "HEAD"), tm.git(tm.gerrit, "rev-parse", "HEAD"); got != want {
+		t.Errorf("extra HEAD is %v, want %v", got, want)
+	}
+
+	tm.buildRepo.dests = nil
+	tm.m.mirrorExtra = false
+	if err := tm.m.addMirrors(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tm.buildRepo.dests) != 0 {
+		t.Errorf("added %d remotes with extra mirrors disabled", len(tm.buildRepo.dests))
+	}
+}
+
+func TestPubSubTickle(t *testing.T) {
+	tm := newTestMirror(t)
+	evtTime := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	var gotAfter string
+	ps := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAfter = r.FormValue("after")
+		json.NewEncoder(w).Encode(pubsubtypes.Event{
+			Time:   types.Time3339(evtTime),
+			Gerrit: &pubsubtypes.GerritEvent{URL: "https://go-review.googlesource.com/123", Project: "build"},
+		})
+	}))
+	defer ps.Close()
+
+	after, err := tm.m.waitPubSubEvent(ps.URL, evtTime.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.Equal(evtTime) {
+		t.Errorf("next after = %v; want %v", after, evtTime)
+	}
+	if want := "2024-05-31T23:00:00Z"; gotAfter != want {
+		t.Errorf("after parameter = %q; want %q", gotAfter, want)
+	}
+	select {
+	case <-tm.buildRepo.changed:
+	default:
+		t.Error("pubsub event didn't tickle the build repo")
+	}
+}
+
+func TestServeGit(t *testing.T) {
+	tm := newTestMirror(t)
+	tm.commit("first commit")
+	tm.commit("second commit")
+	tm.loopOnce()
+	rev := tm.git(tm.gerrit, "rev-parse", "HEAD")
+	url := tm.server.URL + "/build.git"
+
+	for _, version := range []string{"0", "2"} {
+		for _, tt := range []struct {
+			name    string
+			args    []string
+			commits string
+		}{
+			{"full", nil, "2"},
+			{"shallow", []string{"--depth=1"}, "1"},
+			{"partial", []string{"--filter=blob:none"}, "2"},
+		} {
+			t.Run(tt.name+"-v"+version, func(t *testing.T) {
+				dir := filepath.Join(t.TempDir(), "build")
+				args := append([]string{"-c", "protocol.version=" + version, "clone"}, tt.args...)
+				runGit(t, "", append(args, url, dir)...)
+				if got := runGit(t, dir, "rev-parse", "HEAD"); got != rev {
+					t.Errorf("HEAD = %v, want %v", got, rev)
+				}
+				if got := strings.TrimSpace(runGit(t, dir, "rev-list", "--count", "HEAD")); got != tt.commits {
+					t.Errorf("clone has %v commits, want %v", got, tt.commits)
+				}
+				if data, err := os.ReadFile(filepath.Join(dir, "README")); err != nil || string(data) != "second commit" {
+					t.Errorf("README = %q, %v; want %q", data, err, "second commit")
+				}
+			})
+		}
+	}
+
+	// The mirror is read-only.
+	dir := filepath.Join(t.TempDir(), "build")
+	runGit(t, "", "clone", url, dir)
+	cmd := exec.Command("git", "push", "origin", "HEAD:refs/heads/other")
+	envutil.SetDir(cmd, dir)
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("git push to the mirror succeeded:\n%s", out)
+	}
+	for _, path := range []string{"/build.git/info/refs", "/build.git/info/refs?service=git-receive-pack"} {
+		resp, err := http.Get(tm.server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("GET %s: status %d; want %d", path, resp.StatusCode, http.StatusForbidden)
+		}
+	}
+}
+
+// runGit runs git in dir, which may be empty, and returns its output.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	if dir != "" {
+		envutil.SetDir(cmd, dir)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
 type testMirror struct {
 	// Local paths to the copies of the build repo.
 	gerrit, github, csr explicitRepo
@@ -142,38 +514,27 @@ func newTestMirror(t *testing.T) *testMirror {
 			repos:        map[string]*repo{},
 			mirrorGitHub: true,
 			mirrorCSR:    true,
+			mirrorExtra:  true,
+			serveGit:     true,
 			timeoutScale: 0,
 		},
 		t: t,
 	}
+	archives, err := newArchiveCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.m.archives = archives
 	tm.m.mux.HandleFunc("/", tm.m.handleRoot)
+	tm.m.mux.HandleFunc("/debug/archive-cache", tm.m.handleArchiveCache)
 	tm.server = httptest.NewServer(tm.m.mux)
 	t.Cleanup(tm.server.Close)
 
-	initRepo := func(dir string, bare bool) explicitRepo {
-		gitDir, initArgs := filepath.Join(dir, ".git"), []string{"init"}
-		if bare {
-			gitDir, initArgs = dir, append(initArgs, "--bare")
-		}
-		for _, args := range [][]string{
-			initArgs,
-			{"config", "user.name", "Gopher"},
-			{"config", "user.email", "gopher@golang.org"},
-		} {
-			cmd := exec.Command("git", args...)
-			envutil.SetDir(cmd, dir)
-			envutil.SetEnv(cmd, "GIT_DIR="+gitDir)
-			if out, err := cmd.CombinedOutput(); err != nil {
-				t.Fatalf("%s: %v\n%s", strings.Join(cmd.Args, " "), err, out)
-			}
-		}
-		return explicitRepo{gitDir, dir}
-	}
 	// The origin is non-bare so we can commit to it; the destinations are
 	// bare so we can push to them.
-	tm.gerrit = initRepo(gerrit, false)
-	tm.github = initRepo(t.TempDir(), true)
-	tm.csr = initRepo(t.TempDir(), true)
+	tm.gerrit = initRepo(t, gerrit, false)
+	tm.github = initBareRepo(t)
+	tm.csr = initBareRepo(t)
 
 	tm.buildRepo = tm.m.addRepo(&repospkg.Repo{
 		GoGerritProject:    "build",
@@ -195,6 +556,31 @@ func newTestMirror(t *testing.T) *testMirror {
 	return tm
 }
 
+func initRepo(t *testing.T, dir string, bare bool) explicitRepo {
+	gitDir, initArgs := filepath.Join(dir, ".git"), []string{"init"}
+	if bare {
+		gitDir, initArgs = dir, append(initArgs, "--bare")
+	}
+	for _, args := range [][]string{
+		initArgs,
+		{"config", "user.name", "Gopher"},
+		{"config", "user.email", "gopher@golang.org"},
+	} {
+		cmd := exec.Command("git", args...)
+		envutil.SetDir(cmd, dir)
+		envutil.SetEnv(cmd, "GIT_DIR="+gitDir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%s: %v\n%s", strings.Join(cmd.Args, " "), err, out)
+		}
+	}
+	return explicitRepo{gitDir, dir}
+}
+
+// initBareRepo returns a new bare repository to mirror to.
+func initBareRepo(t *testing.T) explicitRepo {
+	return initRepo(t, t.TempDir(), true)
+}
+
 func (tm *testMirror) loopOnce() {
 	tm.t.Helper()
 	if err := tm.buildRepo.loopOnce(); err != nil {
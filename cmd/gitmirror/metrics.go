@@ -0,0 +1,77 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	kRepo   = tag.MustNewKey("go-build/gitmirror/keys/repo")
+	kRemote = tag.MustNewKey("go-build/gitmirror/keys/remote")
+
+	mRemoteLag    = stats.Float64("go-build/gitmirror/remote_lag", "time a mirror has been behind its Gerrit repo", stats.UnitSeconds)
+	mPushErrors   = stats.Int64("go-build/gitmirror/push_errors", "failed pushes to a mirror", stats.UnitDimensionless)
+	mPushedRefs   = stats.Int64("go-build/gitmirror/pushed_refs", "refs pushed to a mirror", stats.UnitDimensionless)
+	mPubSubEvents = stats.Int64("go-build/gitmirror/pubsub_events", "Gerrit events received from pubsubhelper", stats.UnitDimensionless)
+
+	kCacheResult = tag.MustNewKey("go-build/gitmirror/keys/cache_result")
+
+	mArchiveRequests  = stats.Int64("go-build/gitmirror/archive_requests", "archive requests by cache result", stats.UnitDimensionless)
+	mArchiveCacheSize = stats.Int64("go-build/gitmirror/archive_cache_size", "size of the archive cache", stats.UnitBytes)
+	mArchiveEvictions = stats.Int64("go-build/gitmirror/archive_evictions", "archives evicted from the cache", stats.UnitDimensionless)
+)
+
+// views are the metrics that gitmirror exports.
+var views = []*view.View{
+	{
+		Name:        "go-build/gitmirror/remote_lag",
+		Description: "Time a mirror has been behind its Gerrit repo, in seconds",
+		Measure:     mRemoteLag,
+		TagKeys:     []tag.Key{kRepo, kRemote},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        "go-build/gitmirror/push_errors",
+		Description: "Count of failed pushes to a mirror",
+		Measure:     mPushErrors,
+		TagKeys:     []tag.Key{kRepo, kRemote},
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "go-build/gitmirror/pushed_refs",
+		Description: "Count of refs pushed to a mirror",
+		Measure:     mPushedRefs,
+		TagKeys:     []tag.Key{kRepo, kRemote},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        "go-build/gitmirror/pubsub_events",
+		Description: "Count of Gerrit events received from pubsubhelper",
+		Measure:     mPubSubEvents,
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "go-build/gitmirror/archive_requests",
+		Description: "Count of archive requests by cache result",
+		Measure:     mArchiveRequests,
+		TagKeys:     []tag.Key{kRepo, kCacheResult},
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "go-build/gitmirror/archive_cache_size",
+		Description: "Size of the archive cache, in bytes",
+		Measure:     mArchiveCacheSize,
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        "go-build/gitmirror/archive_evictions",
+		Description: "Count of archives evicted from the cache",
+		Measure:     mArchiveEvictions,
+		Aggregation: view.Count(),
+	},
+}
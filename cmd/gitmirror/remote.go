@@ -0,0 +1,164 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+)
+
+// A remote is a mirror that a repo is pushed to.
+type remote struct {
+	name       string // name as configured in the repo.
+	pushOption string // optional extra push option (--push-option).
+
+	mu sync.Mutex
+	// pushed is the hash of each ref as of the last successful push,
+	// or nil before the first one. Only the refs that changed since
+	// are pushed, and the first push mirrors the whole repo.
+	pushed map[string]string
+	// lastMirror is the time of the last successful push of the whole
+	// repo. The whole repo is pushed again every *flagMirrorInterval,
+	// to repair refs that were changed on the remote by others.
+	lastMirror  time.Time
+	behindSince time.Time // when the remote first lacked a pending change; zero if up to date
+	lastPush    time.Time // time of the last successful push
+	lastErr     error
+	lastErrTime time.Time
+}
+
+// refspecs returns the refspecs to push at now to bring the remote up
+// to date with refs, the hashes of the local refs by name. It returns
+// nil if the remote is up to date, and all is true if the whole repo
+// needs to be mirrored, because nothing was pushed yet or because it
+// was last mirrored more than *flagMirrorInterval ago.
+func (d *remote) refspecs(refs map[string]string, now time.Time) (specs []string, all bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pushed == nil || (*flagMirrorInterval > 0 && now.Sub(d.lastMirror) >= *flagMirrorInterval) {
+		return nil, true
+	}
+	for ref, hash := range refs {
+		if d.pushed[ref] != hash {
+			specs = append(specs, "+"+ref+":"+ref)
+		}
+	}
+	for ref := range d.pushed {
+		if _, ok := refs[ref]; !ok {
+			specs = append(specs, ":"+ref)
+		}
+	}
+	sort.Strings(specs)
+	return specs, false
+}
+
+// noteBehind records that the remote lacks changes as of now,
+// unless it was already behind.
+func (d *remote) noteBehind(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.behindSince.IsZero() {
+		d.behindSince = now
+	}
+}
+
+// pushDone records the result of a push of refs that finished at now.
+// all is whether the whole repo was pushed.
+func (d *remote) pushDone(refs map[string]string, all bool, err error, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil {
+		d.lastErr, d.lastErrTime = err, now
+		return
+	}
+	d.pushed = refs
+	if all {
+		d.lastMirror = now
+	}
+	d.behindSince = time.Time{}
+	d.lastPush = now
+	d.lastErr = nil
+}
+
+// lag returns how long the remote has been behind at now.
+func (d *remote) lag(now time.Time) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.behindSince.IsZero() {
+		return 0
+	}
+	return now.Sub(d.behindSince)
+}
+
+// status returns a one-line summary of the remote's state.
+func (d *remote) status(now time.Time) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var b strings.Builder
+	b.WriteString(d.name)
+	switch {
+	case d.lastPush.IsZero() && d.lastErr == nil:
+		b.WriteString(": not pushed yet")
+	case d.behindSince.IsZero():
+		fmt.Fprintf(&b, ": up to date, last push %v ago", now.Sub(d.lastPush).Round(time.Second))
+	default:
+		fmt.Fprintf(&b, ": behind for %v", now.Sub(d.behindSince).Round(time.Second))
+	}
+	if d.lastErr != nil {
+		fmt.Fprintf(&b, "; last error %v ago: %v", now.Sub(d.lastErrTime).Round(time.Second), firstLine(d.lastErr.Error()))
+	}
+	return b.String()
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// mirroredRefs returns the hashes of the refs in the repo that are
+// mirrored: its branches and tags.
+func (r *repo) mirroredRefs() (map[string]string, error) {
+	out, stderr, err := r.runGitQuiet("for-each-ref", "--format=%(objectname) %(refname)", "refs/heads/", "refs/tags/")
+	if err != nil {
+		return nil, fmt.Errorf("listing refs: %v\n\n%s", err, stderr)
+	}
+	refs := make(map[string]string)
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		hash, ref, ok := strings.Cut(sc.Text(), " ")
+		if !ok {
+			return nil, fmt.Errorf("unexpected for-each-ref output %q", sc.Text())
+		}
+		refs[ref] = hash
+	}
+	return refs, nil
+}
+
+// reportMetrics records the lag of each of the repo's remotes.
+func (r *repo) reportMetrics(now time.Time) {
+	for _, dest := range r.dests {
+		stats.RecordWithTags(context.Background(),
+			[]tag.Mutator{tag.Upsert(kRepo, r.name), tag.Upsert(kRemote, dest.name)},
+			mRemoteLag.M(dest.lag(now).Seconds()))
+	}
+}
+
+func (r *repo) recordPush(dest *remote, refs int, err error) {
+	mutators := []tag.Mutator{tag.Upsert(kRepo, r.name), tag.Upsert(kRemote, dest.name)}
+	if err != nil {
+		stats.RecordWithTags(context.Background(), mutators, mPushErrors.M(1))
+		return
+	}
+	stats.RecordWithTags(context.Background(), mutators, mPushedRefs.M(int64(refs)))
+}
@@ -61,15 +61,46 @@ var (
 	gerritTokenFile = flag.String("gerrit-token-file", filepath.Join(os.Getenv("HOME"), "keys", "gerrit-gobot"), `File to load Gerrit token from. File should be of form <git-email>:<token>`)
 
 	onlyRun = flag.String("only-run", "", "if non-empty, the name of a task to run. Mostly for debugging, but tasks (like 'kicktrain') may choose to only run in explicit mode")
+
+	planFile = flag.String("plan", "", "if non-empty, a file to write the plan of actions the tasks would take to, as HTML if it ends in .html and as JSON otherwise; implies -dry-run")
+	planAddr = flag.String("plan-http", "", "if non-empty, the address to serve the plan of the latest dry run on, as HTML at / and as JSON at /plan.json; implies -dry-run")
+
+	routingFile = flag.String("routing-rules", "", "if non-empty, a file of issue routing rules to use instead of the built-in routing.json")
+	corpusDir   = flag.String("corpus-dir", "", "if non-empty, load the corpus from the maintner mutation logs in this directory instead of from maintner.golang.org; for testing routing rules against a recorded corpus with -dry-run")
+
+	// taskIntervals overrides defaultTaskIntervals. It is set by the -task-interval flag.
+	taskIntervals = map[string]time.Duration{}
 )
 
+// defaultTaskIntervals are the minimum intervals between runs of tasks
+// that are too expensive to run on every loop in daemon mode.
+// Tasks not listed run on every loop.
+var defaultTaskIntervals = map[string]time.Duration{
+	"freeze old issues": 24 * time.Hour,
+}
+
 func init() {
+	flag.Func("task-interval", "`name=duration`: run the named task at most once per duration in daemon mode; may be repeated", func(s string) error {
+		name, d, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("want name=duration")
+		}
+		if !slices.ContainsFunc(tasks, func(t task) bool { return t.name == name }) {
+			return fmt.Errorf("unknown task %q", name)
+		}
+		interval, err := time.ParseDuration(d)
+		if err != nil {
+			return err
+		}
+		taskIntervals[name] = interval
+		return nil
+	})
 	flag.Usage = func() {
 		output := flag.CommandLine.Output()
 		fmt.Fprintf(output, "gopherbot runs Go's gopherbot role account on GitHub and Gerrit.\n\n")
 		flag.PrintDefaults()
 		fmt.Fprintln(output, "")
-		fmt.Fprintln(output, "Tasks (can be used for the --only-run flag):")
+		fmt.Fprintln(output, "Tasks (can be used for the --only-run and --task-interval flags):")
 		for _, t := range tasks {
 			fmt.Fprintf(output, "  %q\n", t.name)
 		}
@@ -102,18 +133,12 @@ const (
 )
 
 // GitHub Milestone numbers for the golang/go repo.
+// Milestones set by routing rules are looked up by title instead.
 var (
-	proposal      = milestone{30, "Proposal"}
-	unreleased    = milestone{22, "Unreleased"}
-	unplanned     = milestone{6, "Unplanned"}
-	gccgo         = milestone{23, "Gccgo"}
-	vgo           = milestone{71, "vgo"}
-	vulnUnplanned = milestone{288, "vuln/unplanned"}
+	proposal  = milestone{30, "Proposal"}
+	unplanned = milestone{6, "Unplanned"}
 )
 
-// GitHub Milestone numbers for the golang/vscode-go repo.
-var vscodeUntriaged = milestone{26, "Untriaged"}
-
 type milestone struct {
 	Number int
 	Name   string
@@ -231,6 +256,14 @@ type githubIssue struct {
 
 func main() {
 	flag.Parse()
+	if *planFile != "" || *planAddr != "" {
+		*dryRun = true
+	}
+	var plans *planServer
+	if *planAddr != "" {
+		plans = new(planServer)
+		go func() { log.Fatal(http.ListenAndServe(*planAddr, plans)) }()
+	}
 
 	var sc *secret.Client
 	if metadata.OnGCE() {
@@ -384,16 +417,36 @@ func main() {
 	for n := int32(55359); n <= 55828; n++ {
 		bot.deletedIssues[githubIssue{goRepo, n}] = true
 	}
+	if *routingFile != "" {
+		bot.routing, err = loadRoutingRules(*routingFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 	bot.initCorpus()
 
 	for {
 		t0 := time.Now()
+		if *dryRun {
+			bot.plan = &plan{Start: t0}
+		}
 		taskErrors := bot.doTasks(ctx)
 		for _, err := range taskErrors {
 			log.Print(err)
 		}
 		botDur := time.Since(t0)
 		log.Printf("gopherbot ran in %v", botDur)
+		if bot.plan != nil {
+			bot.plan.End = time.Now()
+			if plans != nil {
+				plans.setPlan(bot.plan)
+			}
+			if *planFile != "" {
+				if err := writePlanFile(bot.plan, *planFile); err != nil {
+					log.Fatal(err)
+				}
+			}
+		}
 		if !*daemon {
 			if len(taskErrors) > 0 {
 				os.Exit(1)
@@ -440,6 +493,15 @@ type gopherbot struct {
 	deletedChanges map[gerritChange]bool
 	deletedIssues  map[githubIssue]bool
 
+	// task is the name of the task being run.
+	task string
+	// lastRun is when each task last ran successfully.
+	lastRun map[string]time.Time
+	// plan, if non-nil, collects the actions skipped in dry-run mode.
+	plan *plan
+	// routing is the issue routing rules, or nil for defaultRoutingRules.
+	routing *routingRules
+
 	releases struct {
 		sync.Mutex
 		lastUpdate time.Time
@@ -448,29 +510,20 @@ type gopherbot struct {
 	}
 }
 
-var tasks = []struct {
+type task struct {
 	name string
 	fn   func(*gopherbot, context.Context) error
-}{
+}
+
+var tasks = []task{
 	// Tasks that are specific to the golang/go repo.
 	{"kicktrain", (*gopherbot).getOffKickTrain},
-	{"label access issues", (*gopherbot).labelAccessIssues},
-	{"label build issues", (*gopherbot).labelBuildIssues},
+	{"route issues", (*gopherbot).routeIssues},
 	{"label compiler/runtime issues", (*gopherbot).labelCompilerRuntimeIssues},
-	{"label mobile issues", (*gopherbot).labelMobileIssues},
-	{"label tools issues", (*gopherbot).labelToolsIssues},
-	{"label website issues", (*gopherbot).labelWebsiteIssues},
-	{"label pkgsite issues", (*gopherbot).labelPkgsiteIssues},
-	{"label proxy.golang.org issues", (*gopherbot).labelProxyIssues},
-	{"label vulncheck or vulndb issues", (*gopherbot).labelVulnIssues},
 	{"label proposals", (*gopherbot).labelProposals},
-	{"handle gopls issues", (*gopherbot).handleGoplsIssues},
-	{"handle telemetry issues", (*gopherbot).handleTelemetryIssues},
 	{"open cherry pick issues", (*gopherbot).openCherryPickIssues},
 	{"close cherry pick issues", (*gopherbot).closeCherryPickIssues},
 	{"close luci-config issues", (*gopherbot).closeLUCIConfigIssues},
-	{"set subrepo milestones", (*gopherbot).setSubrepoMilestones},
-	{"set misc milestones", (*gopherbot).setMiscMilestones},
 	{"apply minor release milestones", (*gopherbot).setMinorMilestones},
 	{"update needs", (*gopherbot).updateNeeds},
 
@@ -485,9 +538,6 @@ var tasks = []struct {
 	{"assign reviewers to CLs", (*gopherbot).assignReviewersToCLs},
 	{"auto-submit CLs", (*gopherbot).autoSubmitCLs},
 
-	// Tasks that are specific to the golang/vscode-go repo.
-	{"set vscode-go milestones", (*gopherbot).setVSCodeGoMilestones},
-
 	{"access", (*gopherbot).whoNeedsAccess},
 	{"cl2issue", (*gopherbot).cl2issue},
 	{"congratulate new contributors", (*gopherbot).congratulateNewContributors},
@@ -510,9 +560,15 @@ func gardenIssues(repo *maintner.GitHubRepo) bool {
 
 func (b *gopherbot) initCorpus() {
 	ctx := context.Background()
-	corpus, err := godata.Get(ctx)
+	var corpus *maintner.Corpus
+	var err error
+	if *corpusDir != "" {
+		corpus, err = loadCorpus(ctx, *corpusDir)
+	} else {
+		corpus, err = godata.Get(ctx)
+	}
 	if err != nil {
-		log.Fatalf("godata.Get: %v", err)
+		log.Fatalf("loading corpus: %v", err)
 	}
 
 	repo := corpus.GitHub().Repo("golang", "go")
@@ -526,20 +582,48 @@ func (b *gopherbot) initCorpus() {
 
 // doTasks performs tasks in sequence. It doesn't stop if
 // if encounters an error, but reports errors at the end.
+// Tasks that ran successfully within their interval are skipped,
+// unless they're selected with -only-run.
 func (b *gopherbot) doTasks(ctx context.Context) []error {
 	var errs []error
 	for _, task := range tasks {
 		if *onlyRun != "" && task.name != *onlyRun {
 			continue
 		}
+		now := time.Now()
+		if *onlyRun == "" && !b.taskDue(task.name, now) {
+			continue
+		}
+		b.task = task.name
 		err := task.fn(b, ctx)
+		b.task = ""
 		if err != nil {
 			errs = append(errs, fmt.Errorf("%s: %v", task.name, err))
+			continue
+		}
+		if b.lastRun == nil {
+			b.lastRun = make(map[string]time.Time)
 		}
+		b.lastRun[task.name] = now
 	}
 	return errs
 }
 
+// taskInterval returns the minimum interval between runs of the named task.
+func taskInterval(name string) time.Duration {
+	if d, ok := taskIntervals[name]; ok {
+		return d
+	}
+	return defaultTaskIntervals[name]
+}
+
+// taskDue reports whether the named task should run at now,
+// according to its interval and when it last ran successfully.
+func (b *gopherbot) taskDue(name string, now time.Time) bool {
+	last, ok := b.lastRun[name]
+	return !ok || now.Sub(last) >= taskInterval(name)
+}
+
 // issuesService represents portions of github.IssuesService that we want to override in tests.
 type issuesService interface {
 	ListLabelsByIssue(ctx context.Context, owner string, repo string, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error)
@@ -562,7 +646,7 @@ func (b *gopherbot) addLabels(ctx context.Context, repoID maintner.GitHubRepoID,
 		toAdd = append(toAdd, label)
 	}
 
-	if *dryRun || len(toAdd) == 0 {
+	if len(toAdd) == 0 || b.skipAction("add labels", issueURL(repoID, gi.Number), strings.Join(toAdd, ", ")) {
 		return nil
 	}
 
@@ -593,7 +677,7 @@ func (b *gopherbot) removeLabels(ctx context.Context, repoID maintner.GitHubRepo
 		removeLabels = true
 	}
 
-	if *dryRun || !removeLabels {
+	if !removeLabels || b.skipAction("remove labels", issueURL(repoID, gi.Number), strings.Join(labels, ", ")) {
 		return nil
 	}
 
@@ -643,7 +727,7 @@ func removeLabelFromIssue(ctx context.Context, repoID maintner.GitHubRepoID, iss
 
 func (b *gopherbot) setMilestone(ctx context.Context, repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue, m milestone) error {
 	printIssue("milestone-"+m.Name, repoID, gi)
-	if *dryRun {
+	if b.skipAction("set milestone", issueURL(repoID, gi.Number), m.Name) {
 		return nil
 	}
 	_, resp, err := b.ghc.Issues.Edit(ctx, repoID.Owner, repoID.Repo, int(gi.Number), &github.IssueRequest{
@@ -700,8 +784,7 @@ func (b *gopherbot) addGitHubComment(ctx context.Context, repo *maintner.GitHubR
 			return nil
 		}
 	}
-	if *dryRun {
-		log.Printf("[dry-run] would add comment to github.com/%s/issues/%d: %v", repo.ID(), issueNum, msg)
+	if b.skipAction("comment", issueURL(repo.ID(), issueNum), msg) {
 		return nil
 	}
 	_, resp, createError := b.ghc.Issues.CreateComment(ctx, repo.ID().Owner, repo.ID().Repo, int(issueNum), &github.IssueComment{
@@ -755,8 +838,7 @@ func (b *gopherbot) createGitHubIssue(ctx context.Context, title, msg string, la
 			return i.GetNumber(), nil
 		}
 	}
-	if *dryRun {
-		log.Printf("[dry-run] would create issue with title %s and labels %v\n%s", title, labels, msg)
+	if b.skipAction("create issue", "https://github.com/golang/go/issues", fmt.Sprintf("title %s and labels %v\n%s", title, labels, msg)) {
 		return 4242, nil
 	}
 	i, _, err := b.ghc.Issues.Create(ctx, "golang", "go", &github.IssueRequest{
@@ -779,12 +861,11 @@ var (
 // closeGitHubIssue closes a GitHub issue.
 // reason specifies why it's being closed. (GitHub's default reason on 2023-06-12 is "completed".)
 func (b *gopherbot) closeGitHubIssue(ctx context.Context, repoID maintner.GitHubRepoID, number int32, reason issueCloseReason) error {
-	if *dryRun {
-		var suffix string
-		if reason != nil {
-			suffix = " as " + *reason
-		}
-		log.Printf("[dry-run] would close go.dev/issue/%v%s", number, suffix)
+	var detail string
+	if reason != nil {
+		detail = "as " + *reason
+	}
+	if b.skipAction("close issue", issueURL(repoID, number), detail) {
 		return nil
 	}
 	_, _, err := b.ghc.Issues.Edit(ctx, repoID.Owner, repoID.Repo, int(number), &github.IssueRequest{
@@ -811,8 +892,7 @@ func (b *gopherbot) addGerritComment(ctx context.Context, changeID, comment stri
 	if b == nil {
 		panic("nil gopherbot")
 	}
-	if *dryRun {
-		log.Printf("[dry-run] would add comment to golang.org/cl/%s: %v", changeID, comment)
+	if b.skipAction("comment", "https://go-review.googlesource.com/q/"+changeID, comment) {
 		return nil
 	}
 	if opts == nil {
@@ -913,10 +993,8 @@ func (b *gopherbot) getOffKickTrain(ctx context.Context) error {
 	fmt.Printf("%d issues:\n", len(matches))
 	for _, m := range matches {
 		fmt.Printf("%-30s - %s\n", m.url, m.title)
-		if !*dryRun {
-			if err := b.setMilestone(ctx, b.gorepo.ID(), m.gi, unplanned); err != nil {
-				return err
-			}
+		if err := b.setMilestone(ctx, b.gorepo.ID(), m.gi, unplanned); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -943,7 +1021,7 @@ func (b *gopherbot) freezeOldIssues(ctx context.Context) error {
 				return nil
 			}
 			printIssue("freeze", repo.ID(), gi)
-			if *dryRun {
+			if b.skipAction("lock issue", issueURL(repo.ID(), gi.Number), "") {
 				return nil
 			}
 			_, err := b.ghc.Issues.Lock(ctx, repo.ID().Owner, repo.ID().Repo, int(gi.Number), nil)
@@ -1022,97 +1100,6 @@ func isGo2Issue(gi *maintner.GitHubIssue) bool {
 	return strings.Contains(gi.Title, "Go 2") || strings.Contains(gi.Title, "go2") || strings.Contains(gi.Title, "Go2")
 }
 
-func (b *gopherbot) setSubrepoMilestones(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !gi.Milestone.IsNone() || gi.HasEvent("demilestoned") || gi.HasEvent("milestoned") {
-			return nil
-		}
-		if !strings.HasPrefix(gi.Title, "x/") {
-			return nil
-		}
-		pkg := gi.Title
-		if colon := strings.IndexByte(pkg, ':'); colon >= 0 {
-			pkg = pkg[:colon]
-		}
-		if sp := strings.IndexByte(pkg, ' '); sp >= 0 {
-			pkg = pkg[:sp]
-		}
-		switch pkg {
-		case "",
-			"x/arch",
-			"x/crypto/chacha20poly1305",
-			"x/crypto/curve25519",
-			"x/crypto/poly1305",
-			"x/net/http2",
-			"x/net/idna",
-			"x/net/lif",
-			"x/net/proxy",
-			"x/net/route",
-			"x/text/unicode/norm",
-			"x/text/width":
-			// These get vendored in. Don't mess with them.
-			return nil
-		case "x/vgo":
-			// Handled by setMiscMilestones
-			return nil
-		}
-		return b.setMilestone(ctx, b.gorepo.ID(), gi, unreleased)
-	})
-}
-
-func (b *gopherbot) setMiscMilestones(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !gi.Milestone.IsNone() || gi.HasEvent("demilestoned") || gi.HasEvent("milestoned") {
-			return nil
-		}
-		if strings.Contains(gi.Title, "gccgo") { // TODO: better gccgo bug report heuristic?
-			return b.setMilestone(ctx, b.gorepo.ID(), gi, gccgo)
-		}
-		if strings.HasPrefix(gi.Title, "x/vgo") {
-			return b.setMilestone(ctx, b.gorepo.ID(), gi, vgo)
-		}
-		if strings.HasPrefix(gi.Title, "x/vuln") {
-			return b.setMilestone(ctx, b.gorepo.ID(), gi, vulnUnplanned)
-		}
-		return nil
-	})
-}
-
-func (b *gopherbot) setVSCodeGoMilestones(ctx context.Context) error {
-	vscode := b.corpus.GitHub().Repo("golang", "vscode-go")
-	if vscode == nil {
-		return nil
-	}
-	return b.foreachIssue(vscode, open, func(gi *maintner.GitHubIssue) error {
-		if !gi.Milestone.IsNone() || gi.HasEvent("demilestoned") || gi.HasEvent("milestoned") {
-			return nil
-		}
-		// Work-around golang/go#40640 by only milestoning new issues.
-		if time.Since(gi.Created) > 24*time.Hour {
-			return nil
-		}
-		return b.setMilestone(ctx, vscode.ID(), gi, vscodeUntriaged)
-	})
-}
-
-func (b *gopherbot) labelAccessIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "access: ") || gi.HasLabel("Access") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "Access")
-	})
-}
-
-func (b *gopherbot) labelBuildIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "x/build") || gi.HasLabel("Builders") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "Builders")
-	})
-}
-
 func (b *gopherbot) labelCompilerRuntimeIssues(ctx context.Context) error {
 	entries, err := getAllCodeOwners(ctx)
 	if err != nil {
@@ -1150,15 +1137,6 @@ func (b *gopherbot) labelCompilerRuntimeIssues(ctx context.Context) error {
 	})
 }
 
-func (b *gopherbot) labelMobileIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "x/mobile") || gi.HasLabel("mobile") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "mobile")
-	})
-}
-
 func (b *gopherbot) labelDocumentationIssues(ctx context.Context) error {
 	const documentation = "Documentation"
 	return b.corpus.GitHub().ForeachRepo(func(repo *maintner.GitHubRepo) error {
@@ -1177,78 +1155,6 @@ func (b *gopherbot) labelDocumentationIssues(ctx context.Context) error {
 	})
 }
 
-func (b *gopherbot) labelToolsIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "x/tools") || gi.HasLabel("Tools") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "Tools")
-	})
-}
-
-func (b *gopherbot) labelWebsiteIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		hasWebsiteTitle := strings.HasPrefix(gi.Title, "x/website:")
-		if !hasWebsiteTitle || gi.HasLabel("website") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "website")
-	})
-}
-
-func (b *gopherbot) labelPkgsiteIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		hasPkgsiteTitle := strings.HasPrefix(gi.Title, "x/pkgsite:")
-		if !hasPkgsiteTitle || gi.HasLabel("pkgsite") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "pkgsite")
-	})
-}
-
-func (b *gopherbot) labelProxyIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		hasProxyTitle := strings.Contains(gi.Title, "proxy.golang.org") || strings.Contains(gi.Title, "sum.golang.org") || strings.Contains(gi.Title, "index.golang.org")
-		if !hasProxyTitle || gi.HasLabel("proxy.golang.org") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "proxy.golang.org")
-	})
-}
-
-func (b *gopherbot) labelVulnIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		hasVulnTitle := strings.HasPrefix(gi.Title, "x/vuln:") || strings.HasPrefix(gi.Title, "x/vuln/") ||
-			strings.HasPrefix(gi.Title, "x/vulndb:") || strings.HasPrefix(gi.Title, "x/vulndb/")
-		if !hasVulnTitle || gi.HasLabel("vulncheck or vulndb") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "vulncheck or vulndb")
-	})
-}
-
-// handleGoplsIssues labels and asks for additional information on gopls issues.
-//
-// This is necessary because gopls issues often require additional information to diagnose,
-// and we don't ask for this information in the Go issue template.
-func (b *gopherbot) handleGoplsIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !isGoplsTitle(gi.Title) || gi.HasLabel("gopls") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "gopls")
-	})
-}
-
-func (b *gopherbot) handleTelemetryIssues(ctx context.Context) error {
-	return b.foreachIssue(b.gorepo, open, func(gi *maintner.GitHubIssue) error {
-		if !strings.HasPrefix(gi.Title, "x/telemetry") || gi.HasLabel("telemetry") || gi.HasEvent("unlabeled") {
-			return nil
-		}
-		return b.addLabel(ctx, b.gorepo.ID(), gi, "telemetry")
-	})
-}
-
 func (b *gopherbot) closeStaleWaitingForInfo(ctx context.Context) error {
 	const waitingForInfo = "WaitingForInfo"
 	now := time.Now()
@@ -1619,8 +1525,7 @@ func (b *gopherbot) unwaitCLs(ctx context.Context) error {
 				if hasReplied {
 					log.Printf("https://go.dev/cl/%d -- remove wait-author; reply from %s", cl.Number, cl.Owner())
 					err := b.onLatestCL(ctx, cl, func() error {
-						if *dryRun {
-							log.Printf("[dry run] would remove hashtag 'wait-author' from CL %d", cl.Number)
+						if b.skipAction("remove hashtag", changeURL(int(cl.Number)), "wait-author") {
 							return nil
 						}
 						_, err := b.gerrit.RemoveHashtags(ctx, fmt.Sprint(cl.Number), "wait-author")
@@ -1649,8 +1554,7 @@ func (b *gopherbot) topicToHashtag(ctx context.Context) error {
 		return err
 	}
 	for _, cl := range waitTopicCLs {
-		if *dryRun {
-			log.Printf("[dry run] would replace 'wait-release' topic with hashtag on CL %d (%.32s…)", cl.ChangeNumber, cl.Subject)
+		if b.skipAction("replace topic with hashtag", changeURL(cl.ChangeNumber), "wait-release") {
 			continue
 		}
 		_, err := b.gerrit.AddHashtags(ctx, cl.ID, "wait-release")
@@ -2237,7 +2141,7 @@ func (b *gopherbot) assignReviewersToCLs(ctx context.Context) error {
 			if len(merged.Primary) == 0 && len(merged.Secondary) == 0 {
 				// No owners found for the change. Add the #no-owners tag.
 				log.Printf("Adding no-owners tag to change %s...", changeURL)
-				if *dryRun {
+				if b.skipAction("add hashtag", changeURL, tagNoOwners) {
 					return nil
 				}
 				if _, err := b.gerrit.AddHashtags(ctx, gc.ID(), tagNoOwners); err != nil {
@@ -2262,8 +2166,7 @@ func (b *gopherbot) assignReviewersToCLs(ctx context.Context) error {
 				log.Printf("Setting review %+v on %s would have no effect, continuing", review, changeURL)
 				return nil
 			}
-			if *dryRun {
-				log.Printf("[dry run] Would set review on %s: %+v", changeURL, review)
+			if b.skipAction("set review", changeURL, fmt.Sprintf("%+v", review)) {
 				return nil
 			}
 			log.Printf("Setting review on %s: %+v", changeURL, review)
@@ -2319,8 +2222,7 @@ func (b *gopherbot) abandonScratchReviews(ctx context.Context) error {
 		if b.deletedChanges[gerritChange{scratchProject.Project(), cl.Number}] || !cl.Meta.Commit.CommitTime.Before(tooOld) {
 			return nil
 		}
-		if *dryRun {
-			log.Printf("[dry-run] would've closed scratch CL https://go.dev/cl/%d ...", cl.Number)
+		if b.skipAction("abandon", changeURL(int(cl.Number)), "Auto-abandoning old scratch review.") {
 			return nil
 		}
 		log.Printf("closing scratch CL https://go.dev/cl/%d ...", cl.Number)
@@ -2565,8 +2467,7 @@ func (b *gopherbot) autoSubmitCLs(ctx context.Context) error {
 				}
 			}
 
-			if *dryRun {
-				log.Printf("[dry-run] would've submitted CL https://golang.org/cl/%d ...", cl.Number)
+			if b.skipAction("submit", changeURL(int(cl.Number)), "") {
 				return nil
 			}
 			log.Printf("submitting CL https://golang.org/cl/%d ...", cl.Number)
@@ -2841,16 +2742,6 @@ func isDocumentationTitle(t string) bool {
 		strings.Contains(t, "docs ")
 }
 
-func isGoplsTitle(t string) bool {
-	// If the prefix doesn't contain "gopls" or "lsp",
-	// then it may not be a gopls issue.
-	i := strings.Index(t, ":")
-	if i > -1 {
-		t = t[:i]
-	}
-	return strings.Contains(t, "gopls") || strings.Contains(t, "lsp")
-}
-
 var lastTask string
 
 func printIssue(task string, repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue) {
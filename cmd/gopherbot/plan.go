@@ -0,0 +1,164 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/build/maintner"
+)
+
+// An action is a change that a task makes, or would make in dry-run mode.
+type action struct {
+	Task   string `json:"task"`
+	Kind   string `json:"kind"`   // what the change is, like "add labels"
+	Target string `json:"target"` // URL of the issue or CL changed
+	Detail string `json:"detail,omitempty"`
+}
+
+// A plan is the list of actions the tasks would take in a dry run.
+type plan struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	mu      sync.Mutex
+	Actions []action `json:"actions"`
+}
+
+func (p *plan) add(a action) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Actions = append(p.Actions, a)
+}
+
+// skipAction reports whether the task should skip taking an action
+// because gopherbot is in dry-run mode. In that case, it logs the
+// action and adds it to b.plan, if any. The target is the URL of the
+// issue or CL the action changes.
+func (b *gopherbot) skipAction(kind, target, detail string) bool {
+	if !*dryRun {
+		return false
+	}
+	if detail != "" {
+		log.Printf("[dry-run] would %s on %s: %s", kind, target, detail)
+	} else {
+		log.Printf("[dry-run] would %s on %s", kind, target)
+	}
+	if b.plan != nil {
+		b.plan.add(action{Task: b.task, Kind: kind, Target: target, Detail: detail})
+	}
+	return true
+}
+
+// issueURL returns the URL of issue number in repo.
+func issueURL(repo maintner.GitHubRepoID, number int32) string {
+	if repo.Owner == "golang" && repo.Repo == "go" {
+		return fmt.Sprintf("https://go.dev/issue/%d", number)
+	}
+	return fmt.Sprintf("https://github.com/%s/issues/%d", repo, number)
+}
+
+// changeURL returns the URL of a Gerrit CL.
+func changeURL(number int) string {
+	return fmt.Sprintf("https://go.dev/cl/%d", number)
+}
+
+// writeJSON writes p to w as JSON.
+func (p *plan) writeJSON(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(p)
+}
+
+// writeHTML writes p to w as an HTML page.
+func (p *plan) writeHTML(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return planTemplate.Execute(w, p)
+}
+
+// writePlanFile writes p to the named file,
+// as HTML if the name ends in ".html" and as JSON otherwise.
+func writePlanFile(p *plan, name string) error {
+	var buf bytes.Buffer
+	var err error
+	if strings.HasSuffix(name, ".html") {
+		err = p.writeHTML(&buf)
+	} else {
+		err = p.writeJSON(&buf)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, buf.Bytes(), 0666)
+}
+
+var planTemplate = template.Must(template.New("plan").Parse(`<!DOCTYPE html>
+<html lang="en">
+<title>gopherbot plan</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { text-align: left; vertical-align: top; padding: 0.2em 0.6em; border-bottom: 1px solid #ddd; }
+td.detail { font-family: monospace; white-space: pre-wrap; }
+</style>
+<h1>gopherbot plan</h1>
+<p>Dry run from {{.Start.UTC.Format "2006-01-02 15:04:05 MST"}}{{if not .End.IsZero}} to {{.End.UTC.Format "15:04:05 MST"}}{{end}}:
+{{len .Actions}} actions. Also available as <a href="plan.json">JSON</a>.</p>
+<table>
+<tr><th>Task</th><th>Action</th><th>Target</th><th>Detail</th></tr>
+{{range .Actions}}<tr><td>{{.Task}}</td><td>{{.Kind}}</td><td><a href="{{.Target}}">{{.Target}}</a></td><td class="detail">{{.Detail}}</td></tr>
+{{end}}</table>
+</html>
+`))
+
+// planServer serves the plan of the latest dry run.
+type planServer struct {
+	mu   sync.Mutex
+	plan *plan // or nil before the first run ends
+}
+
+func (s *planServer) setPlan(p *plan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.plan = p
+}
+
+func (s *planServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	p := s.plan
+	s.mu.Unlock()
+	if p == nil {
+		http.Error(w, "no dry run has finished yet", http.StatusServiceUnavailable)
+		return
+	}
+	var err error
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err = p.writeHTML(w)
+	case "/plan.json":
+		w.Header().Set("Content-Type", "application/json")
+		err = p.writeJSON(w)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("serving plan: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/maintner"
+)
+
+func setDryRun(t *testing.T) {
+	t.Helper()
+	old := *dryRun
+	*dryRun = true
+	t.Cleanup(func() { *dryRun = old })
+}
+
+func TestDryRunPlan(t *testing.T) {
+	setDryRun(t)
+	fis := &fakeIssuesService{}
+	b := &gopherbot{is: fis, plan: &plan{}, task: "label issues"}
+	gi := &maintner.GitHubIssue{Number: 42}
+	repo := maintner.GitHubRepoID{Owner: "golang", Repo: "go"}
+	if err := b.addLabels(context.Background(), repo, gi, []string{"NeedsFix", "Documentation"}); err != nil {
+		t.Fatal(err)
+	}
+	b.task = "close scratch"
+	if err := b.closeGitHubIssue(context.Background(), maintner.GitHubRepoID{Owner: "golang", Repo: "vscode-go"}, 7, notPlanned); err != nil {
+		t.Fatal(err)
+	}
+	if len(fis.labels) != 0 {
+		t.Errorf("labels were added in dry-run mode: %v", fis.labels)
+	}
+
+	want := []action{
+		{Task: "label issues", Kind: "add labels", Target: "https://go.dev/issue/42", Detail: "NeedsFix, Documentation"},
+		{Task: "close scratch", Kind: "close issue", Target: "https://github.com/golang/vscode-go/issues/7", Detail: "as not_planned"},
+	}
+	if diff := cmp.Diff(want, b.plan.Actions); diff != "" {
+		t.Errorf("plan actions mismatch (-want +got):\n%s", diff)
+	}
+
+	s := new(planServer)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+	if w := get("/"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET / before the first run: status %d; want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	s.setPlan(b.plan)
+	w := get("/plan.json")
+	var got plan
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("GET /plan.json: %v\n%s", err, w.Body)
+	}
+	if diff := cmp.Diff(want, got.Actions); diff != "" {
+		t.Errorf("GET /plan.json actions mismatch (-want +got):\n%s", diff)
+	}
+	w = get("/")
+	if body := w.Body.String(); w.Code != http.StatusOK || !strings.Contains(body, `<a href="https://go.dev/issue/42">`) || !strings.Contains(body, "2 actions") {
+		t.Errorf("GET /: status %d, body:\n%s", w.Code, body)
+	}
+	if w := get("/other"); w.Code != http.StatusNotFound {
+		t.Errorf("GET /other: status %d; want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSkipActionNotDryRun(t *testing.T) {
+	b := &gopherbot{plan: &plan{}}
+	if b.skipAction("comment", "https://go.dev/issue/1", "hi") {
+		t.Error("skipAction = true without -dry-run")
+	}
+	if len(b.plan.Actions) != 0 {
+		t.Errorf("plan has actions without -dry-run: %v", b.plan.Actions)
+	}
+}
+
+func TestPlanHTMLEscaping(t *testing.T) {
+	p := &plan{Actions: []action{{Kind: "comment", Target: "https://go.dev/issue/1", Detail: "<script>"}}}
+	var buf bytes.Buffer
+	if err := p.writeHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<script>") {
+		t.Errorf("detail not escaped:\n%s", buf.String())
+	}
+}
+
+func TestTaskIntervalFlag(t *testing.T) {
+	t.Cleanup(func() { taskIntervals = map[string]time.Duration{} })
+	f := flag.CommandLine.Lookup("task-interval").Value
+	if err := f.Set("freeze old issues=1h"); err != nil {
+		t.Fatal(err)
+	}
+	if got := taskInterval("freeze old issues"); got != time.Hour {
+		t.Errorf("interval of freeze old issues = %v; want 1h", got)
+	}
+	for _, bad := range []string{"freeze old issues", "no such task=1h", "freeze old issues=soon"} {
+		if err := f.Set(bad); err == nil {
+			t.Errorf("-task-interval=%q: no error", bad)
+		}
+	}
+}
+
+func TestTaskDue(t *testing.T) {
+	now := time.Now()
+	b := &gopherbot{}
+	if !b.taskDue("freeze old issues", now) {
+		t.Error("task that never ran is not due")
+	}
+	b.lastRun = map[string]time.Time{
+		"freeze old issues":       now.Add(-time.Hour),
+		"abandon scratch reviews": now.Add(-time.Hour),
+	}
+	if b.taskDue("freeze old issues", now) {
+		t.Error("freeze old issues is due an hour after it ran")
+	}
+	if !b.taskDue("freeze old issues", now.Add(24*time.Hour)) {
+		t.Error("freeze old issues is not due a day after it ran")
+	}
+	if !b.taskDue("abandon scratch reviews", now) {
+		t.Error("task without an interval is not due")
+	}
+}
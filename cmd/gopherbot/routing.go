@@ -0,0 +1,445 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/go-github/v48/github"
+	"golang.org/x/build/maintner"
+	"golang.org/x/exp/slices"
+)
+
+// Issue routing rules declare which labels, milestones and assignees
+// new issues get, and which comments gopherbot posts on them, based on
+// the issue's repo, title and author. The "route issues" task applies
+// them. The rules are in routing.json, unless the -routing-rules flag
+// names another file.
+//
+// Like the hand-written tasks, the rules try not to get into an edit
+// war with a human: they don't add labels to issues that had any label
+// removed, set milestones on issues that ever had a milestone, or
+// assign issues that had any assignee.
+
+//go:embed routing.json
+var defaultRoutingRulesJSON []byte
+
+// defaultRoutingRules are the rules in routing.json.
+var defaultRoutingRules = mustParseRoutingRules(defaultRoutingRulesJSON)
+
+// routingRules is the JSON format of a routing rule file.
+type routingRules struct {
+	Rules []*routingRule `json:"rules"`
+}
+
+// A routingRule routes the issues that match all of its conditions.
+// Empty conditions match all issues.
+type routingRule struct {
+	Name string `json:"name"`
+	Note string `json:"note,omitempty"` // why the rule exists, for human readers
+
+	// Repos are the repos, like "golang/go", that the rule applies to.
+	// If empty, the rule applies to golang/go.
+	Repos []string `json:"repos,omitempty"`
+	// The issue title must either start with one of TitlePrefix or
+	// contain one of TitleContains, if either is set, and must match
+	// TitleRegexp, if set.
+	TitlePrefix   []string `json:"title_prefix,omitempty"`
+	TitleContains []string `json:"title_contains,omitempty"`
+	TitleRegexp   string   `json:"title_regexp,omitempty"`
+	// Packages and ExceptPackages match the package paths that the
+	// issue title starts with, like "x/tools/gopls" in
+	// "x/tools/gopls: crash on save". A pattern ending in "/..."
+	// also matches the packages under it. At least one of the title's
+	// packages must match Packages, if set, and none may match
+	// ExceptPackages.
+	Packages       []string `json:"packages,omitempty"`
+	ExceptPackages []string `json:"except_packages,omitempty"`
+	// AuthorAssociation are the author's allowed relationships to the
+	// repo, like "NONE" or "FIRST_TIME_CONTRIBUTOR".
+	AuthorAssociation []string `json:"author_association,omitempty"`
+	// CreatedWithin, if set, is a duration like "24h". Only issues
+	// created within it are routed.
+	CreatedWithin string `json:"created_within,omitempty"`
+
+	Labels    []string `json:"labels,omitempty"`
+	Milestone string   `json:"milestone,omitempty"` // milestone title
+	Assignees []string `json:"assignees,omitempty"` // GitHub logins
+	// Comment is a text/template for a comment to post once on the
+	// issue. It is executed with a routingComment.
+	Comment string `json:"comment,omitempty"`
+
+	titleRE       *regexp.Regexp
+	createdWithin time.Duration
+	comment       *template.Template
+}
+
+// routingComment is the data that routing comment templates are executed with.
+type routingComment struct {
+	Repo   string // like "golang/go"
+	Number int32
+	Title  string
+	Author string // GitHub login
+}
+
+// authorAssociations are the valid values of author_association.
+// See https://docs.github.com/en/graphql/reference/enums#commentauthorassociation.
+var authorAssociations = []string{
+	"COLLABORATOR", "CONTRIBUTOR", "FIRST_TIMER", "FIRST_TIME_CONTRIBUTOR",
+	"MANNEQUIN", "MEMBER", "NONE", "OWNER",
+}
+
+// parseRoutingRules parses and checks a routing rule file.
+func parseRoutingRules(data []byte) (*routingRules, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	rs := new(routingRules)
+	if err := dec.Decode(rs); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for i, r := range rs.Rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("duplicate rule %q", r.Name)
+		}
+		seen[r.Name] = true
+		if err := r.init(); err != nil {
+			return nil, fmt.Errorf("rule %q: %v", r.Name, err)
+		}
+	}
+	return rs, nil
+}
+
+func mustParseRoutingRules(data []byte) *routingRules {
+	rs, err := parseRoutingRules(data)
+	if err != nil {
+		panic(fmt.Sprintf("routing.json: %v", err))
+	}
+	return rs
+}
+
+// loadRoutingRules reads the routing rule file name.
+func loadRoutingRules(name string) (*routingRules, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := parseRoutingRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return rs, nil
+}
+
+// init checks r and prepares its compiled fields.
+func (r *routingRule) init() error {
+	if len(r.Labels) == 0 && r.Milestone == "" && len(r.Assignees) == 0 && r.Comment == "" {
+		return fmt.Errorf("no labels, milestone, assignees or comment")
+	}
+	for _, repo := range r.Repos {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" {
+			return fmt.Errorf("bad repo %q; want owner/repo", repo)
+		}
+	}
+	for _, p := range append(slices.Clip(r.Packages), r.ExceptPackages...) {
+		if p == "" || p == "/..." {
+			return fmt.Errorf("empty package pattern")
+		}
+	}
+	for _, a := range r.AuthorAssociation {
+		if !slices.Contains(authorAssociations, a) {
+			return fmt.Errorf("unknown author association %q", a)
+		}
+	}
+	var err error
+	if r.TitleRegexp != "" {
+		if r.titleRE, err = regexp.Compile(r.TitleRegexp); err != nil {
+			return err
+		}
+	}
+	if r.CreatedWithin != "" {
+		if r.createdWithin, err = time.ParseDuration(r.CreatedWithin); err != nil {
+			return err
+		}
+	}
+	if r.Comment != "" {
+		if r.comment, err = template.New(r.Name).Option("missingkey=error").Parse(r.Comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appliesToRepo reports whether r applies to issues in repo.
+func (r *routingRule) appliesToRepo(repo maintner.GitHubRepoID) bool {
+	if len(r.Repos) == 0 {
+		return repo == maintner.GitHubRepoID{Owner: "golang", Repo: "go"}
+	}
+	return slices.Contains(r.Repos, repo.String())
+}
+
+// match reports whether gi matches r's conditions other than its repo, at now.
+func (r *routingRule) match(gi *maintner.GitHubIssue, now time.Time) bool {
+	if len(r.TitlePrefix) > 0 || len(r.TitleContains) > 0 {
+		ok := slices.ContainsFunc(r.TitlePrefix, func(p string) bool { return strings.HasPrefix(gi.Title, p) }) ||
+			slices.ContainsFunc(r.TitleContains, func(s string) bool { return strings.Contains(gi.Title, s) })
+		if !ok {
+			return false
+		}
+	}
+	if r.titleRE != nil && !r.titleRE.MatchString(gi.Title) {
+		return false
+	}
+	if len(r.Packages) > 0 || len(r.ExceptPackages) > 0 {
+		pkgs := titlePackages(gi.Title)
+		if len(r.Packages) > 0 && !slices.ContainsFunc(pkgs, func(p string) bool { return matchPackage(r.Packages, p) }) {
+			return false
+		}
+		if slices.ContainsFunc(pkgs, func(p string) bool { return matchPackage(r.ExceptPackages, p) }) {
+			return false
+		}
+	}
+	if len(r.AuthorAssociation) > 0 && !slices.Contains(r.AuthorAssociation, gi.AuthorAssociation) {
+		return false
+	}
+	if r.createdWithin > 0 && now.Sub(gi.Created) > r.createdWithin {
+		return false
+	}
+	return true
+}
+
+// titlePackages returns the package paths that an issue title starts
+// with. For example, it returns "cmd/go" and "x/mod/module" for
+// "cmd/go, x/mod/module: bad error". Without a colon, it returns
+// the title's first word.
+func titlePackages(title string) []string {
+	prefix, _, _ := strings.Cut(title, ":")
+	var pkgs []string
+	for _, p := range strings.Split(prefix, ",") {
+		p, _, _ = strings.Cut(strings.TrimSpace(p), " ")
+		if p != "" {
+			pkgs = append(pkgs, p)
+		}
+	}
+	return pkgs
+}
+
+// matchPackage reports whether pkg matches one of patterns.
+func matchPackage(patterns []string, pkg string) bool {
+	for _, pat := range patterns {
+		if dir, ok := strings.CutSuffix(pat, "/..."); ok {
+			if pkg == dir || strings.HasPrefix(pkg, dir+"/") {
+				return true
+			}
+		} else if pkg == pat {
+			return true
+		}
+	}
+	return false
+}
+
+// An issueRoute is what the routing rules would change on an issue.
+type issueRoute struct {
+	Labels    []string
+	Milestone string
+	Assignees []string
+	Comments  []string
+}
+
+func (rt *issueRoute) isZero() bool {
+	return len(rt.Labels) == 0 && rt.Milestone == "" && len(rt.Assignees) == 0 && len(rt.Comments) == 0
+}
+
+// route returns the changes that the rules make to the issue gi in repo, at now.
+// The first matching rule with a milestone decides the milestone.
+// The route has the changes of the other rules even if the comment
+// of some rule can't be made, which route reports in its error.
+func (rs *routingRules) route(repo maintner.GitHubRepoID, gi *maintner.GitHubIssue, now time.Time) (issueRoute, error) {
+	var (
+		rt   issueRoute
+		errs []error
+	)
+	canLabel := !gi.HasEvent("unlabeled")
+	canMilestone := gi.Milestone.IsNone() && !gi.HasEvent("milestoned") && !gi.HasEvent("demilestoned")
+	canAssign := len(gi.Assignees) == 0 && !gi.HasEvent("assigned") && !gi.HasEvent("unassigned")
+	for _, r := range rs.Rules {
+		if !r.appliesToRepo(repo) || !r.match(gi, now) {
+			continue
+		}
+		if canLabel {
+			for _, l := range r.Labels {
+				if !gi.HasLabel(l) && !slices.Contains(rt.Labels, l) {
+					rt.Labels = append(rt.Labels, l)
+				}
+			}
+		}
+		if canMilestone && rt.Milestone == "" {
+			rt.Milestone = r.Milestone
+		}
+		if canAssign {
+			for _, a := range r.Assignees {
+				if !slices.Contains(rt.Assignees, a) {
+					rt.Assignees = append(rt.Assignees, a)
+				}
+			}
+		}
+		if r.comment != nil {
+			var buf bytes.Buffer
+			err := r.comment.Execute(&buf, routingComment{
+				Repo:   repo.String(),
+				Number: gi.Number,
+				Title:  gi.Title,
+				Author: githubLogin(gi.User),
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: %v", r.Name, err))
+			} else if msg := strings.TrimSpace(buf.String()); msg != "" && !hasCommentContaining(gi, msg) {
+				rt.Comments = append(rt.Comments, msg)
+			}
+		}
+	}
+	return rt, errors.Join(errs...)
+}
+
+// githubLogin returns the login of u, which may be nil.
+func githubLogin(u *maintner.GitHubUser) string {
+	if u == nil {
+		return ""
+	}
+	return u.Login
+}
+
+// hasCommentContaining reports whether gi has a comment containing msg.
+func hasCommentContaining(gi *maintner.GitHubIssue, msg string) bool {
+	found := false
+	gi.ForeachComment(func(c *maintner.GitHubComment) error {
+		if strings.Contains(c.Body, msg) {
+			found = true
+			return errStopIteration
+		}
+		return nil
+	})
+	return found
+}
+
+// routingRepos returns the repos in the corpus that rs applies to.
+func (rs *routingRules) routingRepos(corpus *maintner.Corpus) []*maintner.GitHubRepo {
+	var repos []*maintner.GitHubRepo
+	corpus.GitHub().ForeachRepo(func(repo *maintner.GitHubRepo) error {
+		if slices.ContainsFunc(rs.Rules, func(r *routingRule) bool { return r.appliesToRepo(repo.ID()) }) {
+			repos = append(repos, repo)
+		}
+		return nil
+	})
+	return repos
+}
+
+// routeIssues applies the issue routing rules to open issues.
+func (b *gopherbot) routeIssues(ctx context.Context) error {
+	rs := b.routing
+	if rs == nil {
+		rs = defaultRoutingRules
+	}
+	now := time.Now()
+	// One issue or rule failing, such as a rule naming a milestone
+	// that doesn't exist, mustn't keep the others from being routed.
+	var errs []error
+	for _, repo := range rs.routingRepos(b.corpus) {
+		b.foreachIssue(repo, open, func(gi *maintner.GitHubIssue) error {
+			rt, err := rs.route(repo.ID(), gi, now)
+			err = errors.Join(err, b.applyRoute(ctx, repo, gi, rt))
+			if err != nil {
+				err = fmt.Errorf("routing %s#%d: %w", repo.ID(), gi.Number, err)
+				log.Print(err)
+				errs = append(errs, err)
+			}
+			return nil
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// applyRoute makes the changes in rt to the issue gi in repo.
+// It makes as many of them as it can, and returns the errors
+// of the others together.
+func (b *gopherbot) applyRoute(ctx context.Context, repo *maintner.GitHubRepo, gi *maintner.GitHubIssue, rt issueRoute) error {
+	var errs []error
+	if len(rt.Labels) > 0 {
+		if err := b.addLabels(ctx, repo.ID(), gi, rt.Labels); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if rt.Milestone != "" {
+		if m, ok := repoMilestone(repo, rt.Milestone); !ok {
+			errs = append(errs, fmt.Errorf("no milestone %q in %s", rt.Milestone, repo.ID()))
+		} else if err := b.setMilestone(ctx, repo.ID(), gi, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(rt.Assignees) > 0 {
+		if err := b.addAssignees(ctx, repo.ID(), gi, rt.Assignees); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, msg := range rt.Comments {
+		if err := b.addGitHubComment(ctx, repo, gi.Number, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// repoMilestone returns the open milestone in repo with the given title.
+func repoMilestone(repo *maintner.GitHubRepo, title string) (milestone, bool) {
+	var m milestone
+	found := false
+	repo.ForeachMilestone(func(ms *maintner.GitHubMilestone) error {
+		if ms.Title == title && !ms.Closed {
+			m, found = milestone{int(ms.Number), ms.Title}, true
+			return errStopIteration
+		}
+		return nil
+	})
+	return m, found
+}
+
+func (b *gopherbot) addAssignees(ctx context.Context, repoID maintner.GitHubRepoID, gi *maintner.GitHubIssue, logins []string) error {
+	printIssue("assign-"+strings.Join(logins, ","), repoID, gi)
+	if b.skipAction("assign", issueURL(repoID, gi.Number), strings.Join(logins, ", ")) {
+		return nil
+	}
+	_, _, err := b.ghc.Issues.AddAssignees(ctx, repoID.Owner, repoID.Repo, int(gi.Number), logins)
+	if ge, ok := err.(*github.ErrorResponse); ok && ge.Response != nil && ge.Response.StatusCode == http.StatusNotFound {
+		// An issue can become 404 on GitHub due to being deleted or transferred. See go.dev/issue/30182.
+		b.deletedIssues[githubIssue{repoID, gi.Number}] = true
+		return nil
+	}
+	return err
+}
+
+// loadCorpus loads a corpus from the maintner mutation logs in dir,
+// like those that maintnerd writes to its -data-dir.
+func loadCorpus(ctx context.Context, dir string) (*maintner.Corpus, error) {
+	corpus := new(maintner.Corpus)
+	if err := corpus.Initialize(ctx, maintner.NewDiskMutationLogger(dir)); err != nil {
+		return nil, err
+	}
+	return corpus, nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintpb"
+)
+
+var updateRouting = flag.Bool("update-routing", false, "update the routing golden files in testdata/routing")
+
+// routingTestNow is the time the routing tests run at,
+// relative to the issues in testdata/routing/corpus.jsonl.
+var routingTestNow = time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC)
+
+// loadTestCorpus records the maintner mutations in
+// testdata/routing/corpus.jsonl, one JSON-encoded maintpb.Mutation
+// per line, to a mutation log and loads a corpus from it.
+func loadTestCorpus(t *testing.T) *maintner.Corpus {
+	t.Helper()
+	data, err := os.ReadFile("testdata/routing/corpus.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	logger := maintner.NewDiskMutationLogger(dir)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		m := new(maintpb.Mutation)
+		if err := json.Unmarshal(sc.Bytes(), m); err != nil {
+			t.Fatalf("corpus.jsonl:%d: %v", line, err)
+		}
+		if err := logger.Log(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	corpus, err := loadCorpus(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return corpus
+}
+
+// routeCorpus returns a line for each open issue in corpus
+// that rs routes, describing the route.
+func routeCorpus(t *testing.T, corpus *maintner.Corpus, rs *routingRules) string {
+	t.Helper()
+	var buf bytes.Buffer
+	b := &gopherbot{corpus: corpus}
+	for _, repo := range rs.routingRepos(corpus) {
+		err := b.foreachIssue(repo, open, func(gi *maintner.GitHubIssue) error {
+			rt, err := rs.route(repo.ID(), gi, routingTestNow)
+			if err != nil || rt.isZero() {
+				return err
+			}
+			fmt.Fprintf(&buf, "%s#%d:", repo.ID(), gi.Number)
+			if len(rt.Labels) > 0 {
+				fmt.Fprintf(&buf, " labels=%q", rt.Labels)
+			}
+			if rt.Milestone != "" {
+				fmt.Fprintf(&buf, " milestone=%q", rt.Milestone)
+			}
+			if len(rt.Assignees) > 0 {
+				fmt.Fprintf(&buf, " assignees=%q", rt.Assignees)
+			}
+			for _, c := range rt.Comments {
+				fmt.Fprintf(&buf, " comment=%q", c)
+			}
+			buf.WriteString("\n")
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.String()
+}
+
+// TestRoutingRules runs routing rule files against the recorded corpus
+// in testdata/routing and compares the routes with golden files.
+// Run with -update-routing after changing the rules or the corpus.
+func TestRoutingRules(t *testing.T) {
+	corpus := loadTestCorpus(t)
+	tests := []struct {
+		name  string
+		rules *routingRules
+	}{
+		{"default", defaultRoutingRules},
+		{"extra", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := tt.rules
+			if rs == nil {
+				var err error
+				rs, err = loadRoutingRules(filepath.Join("testdata/routing", tt.name+".json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := routeCorpus(t, corpus, rs)
+			golden := filepath.Join("testdata/routing", tt.name+".golden")
+			if *updateRouting {
+				if err := os.WriteFile(golden, []byte(got), 0666); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(want), got); diff != "" {
+				t.Errorf("routes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRouteIssuesPlan(t *testing.T) {
+	setDryRun(t)
+	corpus := loadTestCorpus(t)
+	rs, err := parseRoutingRules([]byte(`{"rules": [
+		{"name": "gccgo", "title_contains": ["gccgo"], "milestone": "Gccgo", "labels": ["gccgo"], "assignees": ["gccgo-owner"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &gopherbot{corpus: corpus, routing: rs, plan: &plan{}, task: "route issues"}
+	if err := b.routeIssues(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []action{
+		{Task: "route issues", Kind: "add labels", Target: "https://go.dev/issue/10", Detail: "gccgo"},
+		{Task: "route issues", Kind: "set milestone", Target: "https://go.dev/issue/10", Detail: "Gccgo"},
+		{Task: "route issues", Kind: "assign", Target: "https://go.dev/issue/10", Detail: "gccgo-owner"},
+	}
+	if diff := cmp.Diff(want, b.plan.Actions); diff != "" {
+		t.Errorf("plan actions mismatch (-want +got):\n%s", diff)
+	}
+
+	// A missing milestone doesn't keep the other changes from being made.
+	b.plan = &plan{}
+	b.routing, err = parseRoutingRules([]byte(`{"rules": [
+		{"name": "missing", "title_prefix": ["x/vuln"], "milestone": "No Such Milestone"},
+		{"name": "gccgo", "title_contains": ["gccgo"], "labels": ["gccgo"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.routeIssues(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `no milestone "No Such Milestone" in golang/go`) {
+		t.Errorf("routeIssues error = %v; want missing milestone", err)
+	}
+	want = []action{
+		{Task: "route issues", Kind: "add labels", Target: "https://go.dev/issue/10", Detail: "gccgo"},
+	}
+	if diff := cmp.Diff(want, b.plan.Actions); diff != "" {
+		t.Errorf("plan actions with a missing milestone mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseRoutingRulesErrors(t *testing.T) {
+	tests := []struct {
+		rules, err string
+	}{
+		{`{"rules": [{"labels": ["x"]}]}`, "no name"},
+		{`{"rules": [{"name": "a", "labels": ["x"]}, {"name": "a", "labels": ["y"]}]}`, `duplicate rule "a"`},
+		{`{"rules": [{"name": "a"}]}`, "no labels, milestone, assignees or comment"},
+		{`{"rules": [{"name": "a", "labels": ["x"], "repos": ["go"]}]}`, "bad repo"},
+		{`{"rules": [{"name": "a", "labels": ["x"], "title_regexp": "("}]}`, "missing closing )"},
+		{`{"rules": [{"name": "a", "labels": ["x"], "created_within": "soon"}]}`, "invalid duration"},
+		{`{"rules": [{"name": "a", "labels": ["x"], "author_association": ["FRIEND"]}]}`, "unknown author association"},
+		{`{"rules": [{"name": "a", "comment": "{{.Nope"}]}`, "unclosed action"},
+		{`{"rules": [{"name": "a", "labels": ["x"], "title_prefixes": ["x/"]}]}`, "unknown field"},
+	}
+	for _, tt := range tests {
+		_, err := parseRoutingRules([]byte(tt.rules))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parseRoutingRules(%s) error = %v; want %q", tt.rules, err, tt.err)
+		}
+	}
+}
+
+func TestTitlePackages(t *testing.T) {
+	tests := []struct {
+		title string
+		want  []string
+	}{
+		{"x/tools/gopls: crash", []string{"x/tools/gopls"}},
+		{"cmd/go, x/mod/module: bad error", []string{"cmd/go", "x/mod/module"}},
+		{"x/vgo hangs", []string{"x/vgo"}},
+		{": nothing", nil},
+	}
+	for _, tt := range tests {
+		if got := titlePackages(tt.title); !cmp.Equal(got, tt.want) {
+			t.Errorf("titlePackages(%q) = %q; want %q", tt.title, got, tt.want)
+		}
+	}
+	if !matchPackage([]string{"x/tools/..."}, "x/tools") || !matchPackage([]string{"x/tools/..."}, "x/tools/gopls") ||
+		matchPackage([]string{"x/tools/..."}, "x/toolsmith") || matchPackage([]string{"x/tools"}, "x/tools/gopls") {
+		t.Error("matchPackage mismatch")
+	}
+}
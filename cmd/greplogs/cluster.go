@@ -0,0 +1,189 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/build/internal/logparser"
+)
+
+// A cluster is a group of failures with the same signature,
+// which are likely to have the same cause.
+type cluster struct {
+	sig       string
+	n         int             // number of failures
+	logs      map[string]bool // logs with the failures
+	builders  map[string]int  // number of failures by builder
+	platforms map[string]int  // number of failures by GOOS/GOARCH
+	issues    map[string]bool // known issues that match the failures
+
+	// The most recent failure.
+	fail   *logparser.Fail
+	latest time.Time
+	path   string // path of its log
+	url    string // URL of its log, if known
+}
+
+// clusters are the clusters of the failures found with -cluster, by signature.
+var clusters = make(map[string]*cluster)
+
+// addFailure adds the failure f, found in the log at path for builder,
+// to its cluster. date is the date of the revision, if known, and
+// issues are the known issues that match f.
+func addFailure(f *logparser.Fail, builder, path, url string, date time.Time, issues []string) {
+	sig := f.Signature()
+	c := clusters[sig]
+	if c == nil {
+		c = &cluster{
+			sig:       sig,
+			logs:      make(map[string]bool),
+			builders:  make(map[string]int),
+			platforms: make(map[string]int),
+			issues:    make(map[string]bool),
+		}
+		clusters[sig] = c
+	}
+	c.n++
+	c.logs[path] = true
+	c.builders[builder]++
+	c.platforms[builderPlatform(builder)]++
+	for _, issue := range issues {
+		c.issues[issue] = true
+	}
+	if c.fail == nil || date.After(c.latest) {
+		c.fail, c.latest, c.path, c.url = f, date, path, url
+	}
+}
+
+// rankClusters returns the clusters, most frequent first.
+// Clusters with the same number of failures are ordered
+// by their most recent failure, latest first.
+func rankClusters() []*cluster {
+	var cs []*cluster
+	for _, c := range clusters {
+		cs = append(cs, c)
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		ci, cj := cs[i], cs[j]
+		if ci.n != cj.n {
+			return ci.n > cj.n
+		}
+		if !ci.latest.Equal(cj.latest) {
+			return ci.latest.After(cj.latest)
+		}
+		return ci.sig < cj.sig
+	})
+	return cs
+}
+
+// goosList are the GOOS values that builderPlatform recognizes.
+var goosList = []string{
+	"aix", "android", "darwin", "dragonfly", "freebsd", "illumos", "ios", "js",
+	"linux", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows",
+}
+
+// builderPlatform returns the GOOS/GOARCH of builder, as found in its
+// name, such as "linux/amd64" for "linux-amd64-longtest" and for
+// "gotip-linux-amd64_c2s16-perf". It returns "unknown" if the name
+// doesn't have one.
+func builderPlatform(builder string) string {
+	parts := strings.Split(builder, "-")
+	for i := 0; i+1 < len(parts); i++ {
+		for _, goos := range goosList {
+			if parts[i] == goos {
+				goarch, _, _ := strings.Cut(parts[i+1], "_")
+				return goos + "/" + goarch
+			}
+		}
+	}
+	return "unknown"
+}
+
+// printClusters prints a report of the clusters cs to w.
+func printClusters(w io.Writer, cs []*cluster) {
+	var uncovered []string
+	for i, c := range cs {
+		f := c.fail
+		fmt.Fprintf(w, "\n## %d. %s\n\n", i+1, f)
+		fmt.Fprintf(w, "- failures: %d, in %d logs\n", c.n, len(c.logs))
+		if !c.latest.IsZero() {
+			fmt.Fprintf(w, "- last seen: %s\n", c.latest.Format(rfc3339DateTime))
+		}
+		fmt.Fprintf(w, "- signature: `%s`\n", c.sig)
+		fmt.Fprintf(w, "- builders: %s\n", distribution(c.builders))
+		fmt.Fprintf(w, "- platforms: %s\n", distribution(c.platforms))
+		if len(c.issues) == 0 {
+			fmt.Fprintf(w, "- known issues: none\n")
+			uncovered = append(uncovered, fmt.Sprint(i+1))
+		} else {
+			var issues []string
+			for issue := range c.issues {
+				issues = append(issues, issue)
+			}
+			sort.Strings(issues)
+			fmt.Fprintf(w, "- known issues: %s\n", strings.Join(issues, ", "))
+		}
+		if *flagMD && c.url != "" {
+			fmt.Fprintf(w, "- latest: [%s](%s)\n", c.path, c.url)
+		} else {
+			fmt.Fprintf(w, "- latest: %s\n", c.path)
+		}
+		if snippet := f.Snippet; snippet != "" {
+			if *flagMD {
+				fmt.Fprintf(w, "\n```\n%s\n```\n", strings.TrimRight(snippet, "\n"))
+			} else {
+				fmt.Fprintf(w, "\n%s\n", strings.TrimRight(snippet, "\n"))
+			}
+		}
+	}
+	if len(uncovered) > 0 {
+		fmt.Fprintf(w, "\n%d of %d clusters are not covered by any known issue: %s\n",
+			len(uncovered), len(cs), strings.Join(uncovered, ", "))
+	}
+}
+
+// distribution returns a description of the counts in m,
+// such as "linux-amd64 (3), windows-386 (1)", largest first.
+func distribution(m map[string]int) string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s (%d)", k, m[k])
+	}
+	return b.String()
+}
+
+// revDate returns the date of the revision of the log at nicePath,
+// from the name of its revision directory, or the zero time if it
+// doesn't have one.
+func revDate(nicePath string) time.Time {
+	match := pathDateRE.FindStringSubmatch(filepath.Base(filepath.Dir(nicePath)))
+	if match == nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(rfc3339DateTime, match[1])
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
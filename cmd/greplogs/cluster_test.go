@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/build/internal/logparser"
+)
+
+func TestBuilderPlatform(t *testing.T) {
+	tests := []struct {
+		builder, want string
+	}{
+		{"linux-amd64", "linux/amd64"},
+		{"linux-amd64-longtest", "linux/amd64"},
+		{"gotip-darwin-arm64_13", "darwin/arm64"},
+		{"x_tools-go1.22-windows-386", "windows/386"},
+		{"misc-compile", "unknown"},
+		{"linux", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := builderPlatform(tt.builder); got != tt.want {
+			t.Errorf("builderPlatform(%q) = %q, want %q", tt.builder, got, tt.want)
+		}
+	}
+}
+
+func TestClusters(t *testing.T) {
+	defer func(old map[string]*cluster) { clusters = old }(clusters)
+	clusters = make(map[string]*cluster)
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	dial := func(port string) *logparser.Fail {
+		return &logparser.Fail{Mode: "test", Pkg: "net", Test: "TestDial", File: "dial_test.go", Line: 120,
+			Message: "dial tcp 127.0.0.1:" + port + ": connection refused"}
+	}
+	panicked := &logparser.Fail{Function: "os.f", Message: "boom"}
+	timeout := &logparser.Fail{Mode: "test", Pkg: "os", Message: "test timed out"}
+
+	addFailure(panicked, "windows-386", "r1/windows-386", "", day(5), nil)
+	addFailure(dial("4321"), "linux-amd64", "r1/linux-amd64", "", day(1), []string{"123"})
+	addFailure(dial("9999"), "gotip-linux-amd64", "r2/gotip-linux-amd64", "", day(2), nil)
+	addFailure(timeout, "linux-386", "r3/linux-386", "", day(3), nil)
+
+	cs := rankClusters()
+	var got []string
+	for _, c := range cs {
+		got = append(got, c.fail.Message)
+	}
+	want := []string{
+		"dial tcp 127.0.0.1:9999: connection refused", // most frequent, latest failure
+		"boom", // most recent
+		"test timed out",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("ranked clusters:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if c := cs[0]; c.n != 2 || len(c.logs) != 2 || !c.issues["123"] || c.platforms["linux/amd64"] != 2 {
+		t.Errorf("dial cluster = %+v", c)
+	}
+
+	var b strings.Builder
+	printClusters(&b, cs)
+	if out := b.String(); !strings.Contains(out, "2 of 3 clusters are not covered by any known issue: 2, 3\n") {
+		t.Errorf("report doesn't list the uncovered clusters:\n%s", out)
+	}
+}
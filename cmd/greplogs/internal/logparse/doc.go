@@ -1,7 +0,0 @@
-// Copyright 2015 The Go Authors. All rights reserved.
-// Use of this source code is governed by a BSD-style
-// license that can be found in the LICENSE file.
-
-// Package logparse contains functions for parsing and analyzing build and test
-// logs produced by all.bash.
-package logparse
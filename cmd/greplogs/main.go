@@ -14,6 +14,18 @@
 // greplogs can search an arbitrary set of files just like grep.
 // Alternatively, the -dashboard flag causes it to search the logs
 // saved locally by fetchlogs (golang.org/x/build/cmd/fetchlogs).
+// If fetchlogs has indexed the logs, greplogs uses the index to read
+// only the logs that can match the -e and -E regexps, and takes the
+// builders, revisions and dates that -omit, -since and -before filter
+// by from the metadata in the index instead of walking the revision
+// directories. The results are the same either way; -index=false
+// searches without the index.
+//
+// The -cluster flag groups the failures in the matching logs by their
+// signature, which leaves out the details that vary from run to run,
+// and prints a report of the groups instead of the failures, most
+// frequent first, with the builders and platforms they happened on
+// and the -known-issue regexps that match them.
 package main
 
 import (
@@ -28,7 +40,9 @@ import (
 	"time"
 
 	"github.com/kballard/go-shellquote"
-	"golang.org/x/build/cmd/greplogs/internal/logparse"
+	"golang.org/x/build/internal/logindex"
+	"golang.org/x/build/internal/logparser"
+	"golang.org/x/build/internal/xdg"
 )
 
 // TODO: If searching dashboard logs, optionally print to builder URLs
@@ -53,6 +67,8 @@ var (
 	flagDetails   = flag.Bool("details", false, "surround Markdown results in a <details> tag")
 	flagFilesOnly = flag.Bool("l", false, "print only names of matching files")
 	flagColor     = flag.String("color", "auto", "highlight output in color: `mode` is never, always, or auto")
+	flagIndex     = flag.Bool("index", true, "with -dashboard, use the fetchlogs index to skip logs that can't match")
+	flagCluster   = flag.Bool("cluster", false, "group the failures by signature and print a report of the groups")
 
 	color         *colorizer
 	since, before timeFlag
@@ -116,6 +132,11 @@ func main() {
 		}
 	}
 
+	if *flagCluster && *flagFilesOnly {
+		fmt.Fprintf(os.Stderr, "-cluster is incompatible with -l and -triage\n")
+		os.Exit(2)
+	}
+
 	status := 1
 	defer func() { os.Exit(status) }()
 
@@ -138,8 +159,11 @@ func main() {
 	// Gather paths.
 	var paths []string
 	var stripDir string
+	skip := func(log string) bool { return false }
+	docs := make(map[string][]*logindex.Doc) // indexed logs by revision directory
 	if *flagDashboard {
-		revDir := filepath.Join(xdgCacheDir(), "fetchlogs", "rev")
+		fetchlogsDir := filepath.Join(xdg.CacheDir(), "fetchlogs")
+		revDir := filepath.Join(fetchlogsDir, "rev")
 		fis, err := os.ReadDir(revDir)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%s: %s\n", revDir, err)
@@ -153,12 +177,52 @@ func main() {
 		}
 		sort.Sort(sort.Reverse(sort.StringSlice(paths)))
 		stripDir = revDir + "/"
+
+		if *flagIndex {
+			ix, err := logindex.Open(filepath.Join(fetchlogsDir, logindex.FileName))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			skip = indexSkip(ix)
+			for _, doc := range ix.Docs() {
+				dir := filepath.Join(fetchlogsDir, filepath.Dir(doc.Path))
+				docs[dir] = append(docs[dir], doc)
+			}
+		}
 	} else {
 		paths = flag.Args()
 	}
 
 	// Process files
+	processFile := func(path string, doc *logindex.Doc) {
+		nicePath := path
+		if stripDir != "" && strings.HasPrefix(path, stripDir) {
+			nicePath = path[len(stripDir):]
+		}
+
+		found, err := process(path, nicePath, doc)
+		if err != nil {
+			status = 2
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		} else if found {
+			numMatching++
+			if status == 1 {
+				status = 0
+			}
+		}
+	}
 	for _, path := range paths {
+		if ds := docs[path]; len(ds) > 0 {
+			// The index has the logs of this revision directory,
+			// so there's no need to walk it.
+			for _, doc := range ds {
+				if !skip(doc.Log) {
+					processFile(filepath.Join(path, filepath.Base(doc.Path)), doc)
+				}
+			}
+			continue
+		}
 		filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				status = 2
@@ -168,32 +232,65 @@ func main() {
 			if info.IsDir() || strings.HasPrefix(filepath.Base(path), ".") {
 				return nil
 			}
-
-			nicePath := path
-			if stripDir != "" && strings.HasPrefix(path, stripDir) {
-				nicePath = path[len(stripDir):]
-			}
-
-			found, err := process(path, nicePath)
-			if err != nil {
-				status = 2
-				fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
-			} else if found {
-				numMatching++
-				if status == 1 {
-					status = 0
-				}
+			if link, err := os.Readlink(path); err == nil && skip(filepath.Base(link)) {
+				return nil
 			}
+			processFile(path, nil)
 			return nil
 		})
 	}
+
+	if *flagCluster {
+		printClusters(os.Stdout, rankClusters())
+	}
+}
+
+// indexSkip returns a function that reports whether the log named log
+// can be skipped because the index ix shows that it doesn't match the
+// -e and -E regexps. Logs that aren't in the index are never skipped.
+func indexSkip(ix *logindex.Index) func(log string) bool {
+	res := append(append([]*regexp.Regexp(nil), fileRegexps...), failRegexps...)
+	if len(res) == 0 {
+		return func(string) bool { return false }
+	}
+	cands := ix.Candidates(res)
+	return func(log string) bool {
+		return ix.Has(log) && !cands[log]
+	}
+}
+
+// docRevs returns the revisions of the build of doc, abbreviated and
+// joined as in the names of the revision directories of fetchlogs.
+func docRevs(doc *logindex.Doc) string {
+	short := func(rev string) string {
+		if len(rev) > 7 {
+			return rev[:7]
+		}
+		return rev
+	}
+	revs := short(doc.Revision)
+	if doc.GoRevision != "" {
+		revs += "-" + short(doc.GoRevision)
+	}
+	return revs
 }
 
 var pathDateRE = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2})-([0-9a-f]+(?:-[0-9a-f]+)?)$`)
 
-func process(path, nicePath string) (found bool, err error) {
+// process searches the log at path, which is printed as nicePath.
+// If doc is non-nil, it is the metadata of the log from the index,
+// which the log is filtered by instead of by its path.
+func process(path, nicePath string, doc *logindex.Doc) (found bool, err error) {
+	if doc != nil && doc.Revision == "" {
+		// The revision directory had no metadata when indexed.
+		doc = nil
+	}
+
 	// If this is from the dashboard, filter by builder and date and get the builder URL.
 	builder := filepath.Base(nicePath)
+	if doc != nil {
+		builder = doc.Builder
+	}
 	for _, b := range brokenBuilders {
 		if builder == b {
 			return false, nil
@@ -204,20 +301,27 @@ func process(path, nicePath string) (found bool, err error) {
 	}
 
 	if !since.Time.IsZero() || !before.Time.IsZero() {
-		revDir := filepath.Dir(nicePath)
-		revDirBase := filepath.Base(revDir)
-		match := pathDateRE.FindStringSubmatch(revDirBase)
-		if len(match) != 3 {
-			// Without a valid log date we can't filter by it.
-			return false, fmt.Errorf("timestamp not found in rev dir name: %q", revDirBase)
+		var revs string
+		var revTime time.Time
+		if doc != nil {
+			revs, revTime = docRevs(doc), doc.Date
+		} else {
+			revDir := filepath.Dir(nicePath)
+			revDirBase := filepath.Base(revDir)
+			match := pathDateRE.FindStringSubmatch(revDirBase)
+			if len(match) != 3 {
+				// Without a valid log date we can't filter by it.
+				return false, fmt.Errorf("timestamp not found in rev dir name: %q", revDirBase)
+			}
+			revs = match[2]
+			revTime, err = time.Parse("2006-01-02T15:04:05", match[1])
+			if err != nil {
+				return false, err
+			}
 		}
-		if omit.AnyMatchString(match[2]) {
+		if omit.AnyMatchString(revs) {
 			return false, nil
 		}
-		revTime, err := time.Parse("2006-01-02T15:04:05", match[1])
-		if err != nil {
-			return false, err
-		}
 		if !since.Time.IsZero() && revTime.Before(since.Time) {
 			return false, nil
 		}
@@ -228,9 +332,18 @@ func process(path, nicePath string) (found bool, err error) {
 
 	// TODO: Get the URL from the rev.json metadata
 	var logURL string
-	if link, err := os.Readlink(path); err == nil {
-		hash := filepath.Base(link)
-		logURL = "https://build.golang.org/log/" + hash
+	var hash string
+	if doc != nil {
+		hash = doc.Log
+	} else if link, err := os.Readlink(path); err == nil {
+		hash = filepath.Base(link)
+	}
+	if hash != "" {
+		if id, ok := strings.CutPrefix(hash, "luci-"); ok {
+			logURL = "https://ci.chromium.org/b/" + id
+		} else {
+			logURL = "https://build.golang.org/log/" + hash
+		}
 	}
 
 	// TODO: Use streaming if possible.
@@ -271,18 +384,14 @@ func process(path, nicePath string) (found bool, err error) {
 	})
 
 	// Extract failures.
-	failures, err := logparse.Extract(string(data), "", "")
-	if err != nil {
-		return false, err
-	}
-
+	failures := logparser.ParseLog(string(data))
 	timer.Stop()
 
 	// Print failures.
 	for _, failure := range failures {
 		var msg []byte
-		if failure.FullMessage != "" {
-			msg = []byte(failure.FullMessage)
+		if failure.Output != "" {
+			msg = []byte(failure.Output)
 		} else {
 			msg = []byte(failure.Message)
 		}
@@ -291,6 +400,11 @@ func process(path, nicePath string) (found bool, err error) {
 			continue
 		}
 
+		if *flagCluster {
+			addFailure(failure, builder, nicePath, logURL, revDate(nicePath), knownIssues.Matches(msg))
+			continue
+		}
+
 		fmt.Printf("%s%s\n", color.color(printPath, colorPath), color.color(":", colorPathColon))
 		if *flagMD {
 			fmt.Printf("```\n")
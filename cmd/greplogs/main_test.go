@@ -0,0 +1,55 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/build/internal/logindex"
+)
+
+func TestProcessDoc(t *testing.T) {
+	defer func(s, b timeFlag, o regexpList, l bool, c *colorizer) {
+		since, before, omit, *flagFilesOnly, color = s, b, o, l, c
+	}(since, before, omit, *flagFilesOnly, color)
+	since = timeFlag{Time: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	omit = nil
+	omit.Set("^bad")
+	*flagFilesOnly = true
+	color = newColorizer(false)
+
+	// The log's path has no date or revision to filter by,
+	// so filtering has to use the index's metadata.
+	path := filepath.Join(t.TempDir(), "linux-amd64")
+	if err := os.WriteFile(path, []byte("--- FAIL: TestDial\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	doc := func(builder, rev string, date time.Time) *logindex.Doc {
+		return &logindex.Doc{Builder: builder, Log: "luci-1", Revision: rev, Date: date}
+	}
+	newer := since.Time.Add(time.Hour)
+	tests := []struct {
+		doc  *logindex.Doc
+		want bool
+	}{
+		{doc("linux-amd64", "1111111111", newer), true},
+		{doc("linux-amd64", "1111111111", since.Time.Add(-time.Hour)), false},
+		{doc("linux-amd64", "bad1111111", newer), false},
+		{doc("bad-builder", "1111111111", newer), false},
+	}
+	for _, tt := range tests {
+		found, err := process(path, "somewhere/linux-amd64", tt.doc)
+		if err != nil {
+			t.Errorf("process(%+v): %v", tt.doc, err)
+			continue
+		}
+		if found != tt.want {
+			t.Errorf("process(%+v) = %v, want %v", tt.doc, found, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	bbpb "go.chromium.org/luci/buildbucket/proto"
+	"rsc.io/github"
+)
+
+// An Archive records the builds, logs and issues that watchflakes
+// fetched from LUCI and GitHub in one run, so that the run can be
+// replayed offline with -replay.
+type Archive struct {
+	Time   time.Time    // start of the run
+	Build  string       // -build flag of the run
+	Boards []*Dashboard // dashboards, with the failures and logs of the failed builds
+
+	// Statuses are the statuses of the results in Boards as read,
+	// indexed like Dashboard.Results, before watchflakes changed
+	// the statuses of broken commits and builders to SKIP.
+	Statuses [][][]bbpb.Status
+
+	Issues []*ArchivedIssue
+}
+
+// An ArchivedIssue is an issue in an Archive.
+type ArchivedIssue struct {
+	*github.Issue
+
+	// Comments are the comments on the issue,
+	// or nil if the run didn't read them.
+	Comments []*github.IssueComment `json:",omitempty"`
+}
+
+var (
+	recording *Archive // archive being recorded, for -record
+	replaying *Archive // archive being replayed, for -replay
+)
+
+// recordStatuses records the statuses of the results in boards
+// in a, which must be done before they are changed to SKIP.
+func (a *Archive) recordStatuses(boards []*Dashboard) {
+	a.Boards = boards
+	a.Statuses = make([][][]bbpb.Status, len(boards))
+	for i, dash := range boards {
+		a.Statuses[i] = make([][]bbpb.Status, len(dash.Results))
+		for j, rs := range dash.Results {
+			a.Statuses[i][j] = make([]bbpb.Status, len(rs))
+			for k, r := range rs {
+				if r != nil {
+					a.Statuses[i][j][k] = r.Status
+				}
+			}
+		}
+	}
+}
+
+// recordIssues records issues in a.
+func (a *Archive) recordIssues(issues []*Issue) {
+	a.Issues = nil
+	for _, issue := range issues {
+		a.Issues = append(a.Issues, &ArchivedIssue{Issue: issue.Issue})
+	}
+}
+
+// recordComments records the comments on the issue numbered num.
+func (a *Archive) recordComments(num int, comments []*github.IssueComment) {
+	for _, ai := range a.Issues {
+		if ai.Number == num {
+			ai.Comments = comments
+		}
+	}
+}
+
+// boards returns the dashboards in a as they were read,
+// with the statuses that they had then.
+func (a *Archive) boards() []*Dashboard {
+	for i, dash := range a.Boards {
+		for j, rs := range dash.Results {
+			for k, r := range rs {
+				if r != nil {
+					r.Status = a.Statuses[i][j][k]
+					r.Top = false
+				}
+			}
+		}
+	}
+	return a.Boards
+}
+
+// failures returns the failed results in boards,
+// in the order that LUCIClient.FindFailures returns them.
+func failures(boards []*Dashboard) []*BuildResult {
+	var res []*BuildResult
+	for _, dash := range boards {
+		for _, rs := range dash.Results {
+			for _, r := range rs {
+				if r != nil && r.Status == bbpb.Status_FAILURE {
+					res = append(res, r)
+				}
+			}
+		}
+	}
+	sortResults(res)
+	return res
+}
+
+// issues returns the issues in a, as readIssues would.
+func (a *Archive) issues() []*Issue {
+	var issues []*Issue
+	for _, ai := range a.Issues {
+		issues = append(issues, &Issue{Issue: ai.Issue, NewBody: true, Stale: true})
+	}
+	return issues
+}
+
+// comments returns the comments on the issue numbered num.
+// An issue whose comments the recorded run didn't read
+// has no comments.
+func (a *Archive) comments(num int) []*github.IssueComment {
+	for _, ai := range a.Issues {
+		if ai.Number == num {
+			return ai.Comments
+		}
+	}
+	return nil
+}
+
+// writeArchive writes a to the named file,
+// compressed with gzip if the name ends in .gz.
+func writeArchive(file string, a *Archive) (err error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	var w io.Writer = f
+	if strings.HasSuffix(file, ".gz") {
+		zw := gzip.NewWriter(f)
+		defer func() {
+			if cerr := zw.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = zw
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(a)
+}
+
+// readArchive reads the archive in the named file,
+// as written by writeArchive.
+func readArchive(file string) (*Archive, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(file, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %v", file, err)
+		}
+		r = zr
+	}
+	a := new(Archive)
+	if err := json.NewDecoder(r).Decode(a); err != nil {
+		return nil, fmt.Errorf("reading %s: %v", file, err)
+	}
+	if len(a.Statuses) != len(a.Boards) {
+		return nil, fmt.Errorf("reading %s: statuses don't match boards", file)
+	}
+	for i, dash := range a.Boards {
+		if len(a.Statuses[i]) != len(dash.Results) {
+			return nil, fmt.Errorf("reading %s: statuses don't match boards", file)
+		}
+		for j, rs := range dash.Results {
+			if len(a.Statuses[i][j]) != len(rs) {
+				return nil, fmt.Errorf("reading %s: statuses don't match boards", file)
+			}
+		}
+	}
+	return a, nil
+}
+
+// writeReport writes the updates that watchflakes would post to
+// issues to w, in place of posting them. The report only depends on
+// the failures and the issues, so that the reports of replaying an
+// archive can be diffed to see the effect of a change.
+func writeReport(w io.Writer, issues []*Issue) {
+	var updated, created []*Issue
+	for _, issue := range issues {
+		if len(issue.Post) == 0 {
+			continue
+		}
+		if issue.Number == 0 {
+			created = append(created, issue)
+		} else {
+			updated = append(updated, issue)
+		}
+	}
+	sort.SliceStable(updated, func(i, j int) bool { return updated[i].Number < updated[j].Number })
+	for _, issue := range updated {
+		fmt.Fprintf(w, "## comment on #%d %s\n\n%s\n", issue.Number, issue.Title, updateText(issue))
+	}
+	for _, issue := range created {
+		fmt.Fprintf(w, "## new issue: %s\n\n%s\n", issue.Title, issue.Body)
+		fmt.Fprintf(w, "## comment on new issue: %s\n\n%s\n", issue.Title, updateText(issue))
+	}
+	fmt.Fprintf(w, "## %d issues updated, %d new issues\n", len(updated), len(created))
+}
+
+// saveRecording writes the archive being recorded, if any.
+func saveRecording() {
+	if recording == nil {
+		return
+	}
+	if err := writeArchive(*recordFile, recording); err != nil {
+		log.Fatalf("writing archive: %v", err)
+	}
+}
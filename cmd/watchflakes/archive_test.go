@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	bbpb "go.chromium.org/luci/buildbucket/proto"
+	"rsc.io/github"
+)
+
+func TestArchive(t *testing.T) {
+	outcomes := "PFFFFPFS"
+	board := testBoard(outcomes)
+	var want []bbpb.Status
+	for _, r := range board.Results[0] {
+		r.BuilderConfigProperties = &BuilderConfigProperties{Repo: "go", GoBranch: "master"}
+		want = append(want, r.Status)
+	}
+	boards := []*Dashboard{board}
+	a := &Archive{}
+	a.recordStatuses(boards)
+	skipBrokenBuilders(boards)
+	a.recordIssues([]*Issue{{Issue: &github.Issue{Number: 1, Title: "net: TestDial failures"}}})
+	a.recordComments(1, []*github.IssueComment{{Body: "seen it"}})
+
+	for _, name := range []string{"a.json", "a.json.gz"} {
+		file := filepath.Join(t.TempDir(), name)
+		if err := writeArchive(file, a); err != nil {
+			t.Fatal(err)
+		}
+		b, err := readArchive(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var have []bbpb.Status
+		for _, r := range b.boards()[0].Results[0] {
+			have = append(have, r.Status)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("%s: statuses = %v, want %v", name, have, want)
+		}
+		var ids []int64
+		for _, r := range failures(b.boards()) {
+			ids = append(ids, r.ID)
+		}
+		if want := []int64{6, 4, 3, 2, 1}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("%s: failures = %v, want %v", name, ids, want)
+		}
+		issues := b.issues()
+		if len(issues) != 1 || issues[0].Number != 1 || !issues[0].Stale {
+			t.Fatalf("%s: issues = %v, want #1", name, issues)
+		}
+		if c := b.comments(1); len(c) != 1 || c[0].Body != "seen it" {
+			t.Errorf("%s: comments = %v, want one", name, c)
+		}
+		if c := b.comments(2); c != nil {
+			t.Errorf("%s: comments on unknown issue = %v, want nil", name, c)
+		}
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	newPost := func(id int64) *FailurePost {
+		r := &BuildResult{ID: id, Status: bbpb.Status_FAILURE, Commit: "abcdef0123456789", Builder: "linux-amd64",
+			BuilderConfigProperties: &BuilderConfigProperties{Repo: "go", GoBranch: "master"}}
+		return &FailurePost{BuildResult: r, Failure: &Failure{TestID: "net.TestDial"}, URL: buildURL(id),
+			Pkg: "net", Test: "TestDial", Snippet: "dial failed"}
+	}
+	fp1, fp2 := newPost(1), newPost(2)
+	issues := []*Issue{
+		{Issue: &github.Issue{Number: 20, Title: "b"}, ScriptText: "#!watchflakes\n", Post: []*FailurePost{fp2}},
+		{Issue: &github.Issue{Number: 10, Title: "a"}, ScriptText: "#!watchflakes\n", Post: []*FailurePost{fp1},
+			Messages: map[*FailurePost]string{fp1: "dial: refused"}},
+		{Issue: &github.Issue{Number: 30, Title: "c"}},
+		{Issue: &github.Issue{Title: "net: TestDial failures", Body: "new body\n"}, ScriptText: "#!watchflakes\n", Post: []*FailurePost{fp1}},
+	}
+	var buf bytes.Buffer
+	writeReport(&buf, issues)
+	out := buf.String()
+	var heads []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			heads = append(heads, line)
+		}
+	}
+	wantHeads := []string{
+		"## comment on #10 a",
+		"## comment on #20 b",
+		"## new issue: net: TestDial failures",
+		"## comment on new issue: net: TestDial failures",
+		"## 2 issues updated, 1 new issues",
+	}
+	if !reflect.DeepEqual(heads, wantHeads) {
+		t.Errorf("report headings:\nhave %q\nwant %q", heads, wantHeads)
+	}
+	if !strings.Contains(out, "dial: refused\n") || !strings.Contains(out, buildURL(2)) {
+		t.Errorf("report is missing a message or a post:\n%s", out)
+	}
+}
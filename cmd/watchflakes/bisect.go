@@ -0,0 +1,246 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	bbpb "go.chromium.org/luci/buildbucket/proto"
+)
+
+// breakagePrior is the prior probability that a new failure is a
+// deterministic breakage rather than a flake. Most new failures on
+// the dashboards are flakes.
+const breakagePrior = 0.2
+
+// fixPrior is the probability that a breakage is fixed by the next
+// result on the builder.
+const fixPrior = 0.1
+
+// A Suspect is the range of commits that a failure started at on a
+// builder, found by bisecting the builder's results on the dashboard.
+type Suspect struct {
+	Repo    string
+	Builder string
+
+	// Good is the latest result before the failures started that
+	// doesn't have the failure, or nil if there is none in the window.
+	Good *BuildResult
+	// Bad is the first result with the failure.
+	Bad *BuildResult
+	// Commits are the commits after Good up to and including Bad,
+	// newest first. One of them is likely to have caused the failure,
+	// if it is a breakage.
+	Commits []Commit
+
+	// Failed is the number of results with the failure since Bad,
+	// including Bad, and Known is the number of results since Bad
+	// whose outcome is known.
+	Failed, Known int
+
+	// Breakage is the estimated probability that the failure
+	// is a deterministic breakage rather than a flake.
+	Breakage float64
+}
+
+// A boardPos is the position of a result on a dashboard.
+type boardPos struct {
+	dash    *Dashboard
+	builder int // index in dash.Builders and dash.Results
+	commit  int // index in dash.Commits and dash.Results[builder]
+}
+
+// indexBoards returns the position of each result on boards.
+func indexBoards(boards []*Dashboard) map[*BuildResult]boardPos {
+	idx := make(map[*BuildResult]boardPos)
+	for _, dash := range boards {
+		for i, rs := range dash.Results {
+			for j, r := range rs {
+				if r != nil {
+					idx[r] = boardPos{dash, i, j}
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// outcome is whether a result has a failure.
+type outcome int
+
+const (
+	unknown outcome = iota
+	good            // the result doesn't have the failure
+	bad             // the result has the failure
+)
+
+// outcome returns whether r has the failure of fp.
+func (fp *FailurePost) outcome(r *BuildResult) outcome {
+	switch r.Status {
+	case bbpb.Status_SUCCESS:
+		return good
+	case bbpb.Status_FAILURE:
+	default:
+		// Including the results of broken commits and builders,
+		// whose failures aren't loaded.
+		return unknown
+	}
+	if fp.IsBuildFailure() {
+		// A build that fails tests built fine.
+		if len(r.Failures) > 0 {
+			return good
+		}
+		return bad
+	}
+	for _, f := range r.Failures {
+		// Failures may be coalesced into their parent tests.
+		if f.TestID == fp.TestID || strings.HasPrefix(f.TestID, fp.TestID+"/") || strings.HasPrefix(fp.TestID, f.TestID+"/") {
+			return bad
+		}
+		if f.TestID == fp.Pkg {
+			// The package failed as a whole,
+			// so the test may not have run.
+			return unknown
+		}
+	}
+	if len(r.Failures) == 0 {
+		return unknown
+	}
+	return good
+}
+
+// bisect finds the range of commits that the failure of fp started at
+// on its builder, using the results of the builder on the dashboard
+// at pos. It returns nil if the failure is not on the dashboard.
+func (fp *FailurePost) bisect(pos boardPos) *Suspect {
+	rs := pos.dash.Results[pos.builder]
+	outcomes := make([]outcome, len(rs)) // newest first, like rs
+	for j, r := range rs {
+		if r != nil {
+			outcomes[j] = fp.outcome(r)
+		}
+	}
+	if outcomes[pos.commit] != bad {
+		return nil
+	}
+
+	// Find the failing block of results around the failure:
+	// the results that have it, or are unknown, between the
+	// nearest results without it.
+	first := pos.commit // oldest result with the failure
+	passed := -1        // index of the good result before first, or -1
+	for j := pos.commit + 1; j < len(rs); j++ {
+		if outcomes[j] == good {
+			passed = j
+			break
+		}
+		if outcomes[j] == bad {
+			first = j
+		}
+	}
+	s := &Suspect{
+		Repo:    pos.dash.Repo,
+		Builder: pos.dash.Builders[pos.builder].Name,
+		Bad:     rs[first],
+	}
+	if passed >= 0 {
+		s.Good = rs[passed]
+		s.Commits = pos.dash.Commits[first:passed]
+	} else {
+		s.Commits = pos.dash.Commits[first:]
+	}
+	// Count the results since the failure started, and the failures
+	// in a row that started it: a deterministic breakage fails every
+	// time, until it is fixed.
+	inRow := 0
+	stillFailing := true
+	for j := first; j >= 0; j-- {
+		o := outcomes[j]
+		if o == unknown {
+			continue
+		}
+		s.Known++
+		if o == bad {
+			s.Failed++
+			if stillFailing {
+				inRow++
+			}
+		} else {
+			stillFailing = false
+		}
+	}
+	outside, outsideBad := 0, 0
+	for j := first + 1; j < len(outcomes); j++ {
+		if o := outcomes[j]; o != unknown {
+			outside++
+			if o == bad {
+				outsideBad++
+			}
+		}
+	}
+	s.Breakage = breakageProbability(s.Failed, s.Known, inRow, stillFailing, outsideBad, outside)
+	return s
+}
+
+// breakageProbability returns the probability that failures are a
+// deterministic breakage rather than a flake, given that the failure
+// happened failed times in the known results since it started, the
+// first inRow of them in a row, and that it is stillFailing or not.
+//
+// A flake fails at the rate observed before it started: outsideBad
+// failures in outside results. The rate is smoothed, so that a failure
+// that was never seen before still has a chance of being a flake. A
+// breakage fails every time until it is fixed, and is then like a
+// flake.
+func breakageProbability(failed, known, inRow int, stillFailing bool, outsideBad, outside int) float64 {
+	rate := float64(outsideBad+1) / float64(outside+2)
+	flake := math.Pow(rate, float64(failed)) * math.Pow(1-rate, float64(known-failed))
+	breakage := math.Pow(rate, float64(failed-inRow)) * math.Pow(1-rate, float64(known-failed))
+	if !stillFailing {
+		breakage *= fixPrior
+	}
+	return breakagePrior * breakage / (breakagePrior*breakage + (1-breakagePrior)*flake)
+}
+
+// Markdown returns a description of the suspect range of commits,
+// suitable for a GitHub issue.
+func (s *Suspect) Markdown() string {
+	var b strings.Builder
+	if s.Good != nil {
+		fmt.Fprintf(&b, "Started failing on %s between %s (passed) and %s (failed): %d %s, %s.\n",
+			s.Builder, commitLink(s.Repo, s.Good.Commit), commitLink(s.Repo, s.Bad.Commit),
+			len(s.Commits), plural(len(s.Commits), "commit"), rangeLink(s.Repo, s.Good.Commit, s.Bad.Commit))
+	} else {
+		fmt.Fprintf(&b, "Failing on %s since before the dashboard window, first seen at %s.\n",
+			s.Builder, commitLink(s.Repo, s.Bad.Commit))
+	}
+	kind := "flake"
+	p := 1 - s.Breakage
+	if s.Breakage >= 0.5 {
+		kind, p = "deterministic breakage", s.Breakage
+	}
+	fmt.Fprintf(&b, "Failed %d of %d %s since; %.0f%% likely a %s.\n",
+		s.Failed, s.Known, plural(s.Known, "build"), 100*p, kind)
+	return b.String()
+}
+
+func plural(n int, s string) string {
+	if n == 1 {
+		return s
+	}
+	return s + "s"
+}
+
+func commitLink(repo, hash string) string {
+	return fmt.Sprintf("[%s@%s](https://go.googlesource.com/%s/+/%s)", repo, shortHash(hash), repo, hash)
+}
+
+// rangeLink returns a link to the log of the commits after good up to
+// and including bad.
+func rangeLink(repo, good, bad string) string {
+	return fmt.Sprintf("[log](https://go.googlesource.com/%s/+log/%s..%s)", repo, good, bad)
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	bbpb "go.chromium.org/luci/buildbucket/proto"
+)
+
+// testBoard returns a dashboard with a single builder whose results,
+// newest first, are given by outcomes: 'P' for a pass, 'F' for a
+// failure of net.TestDial, 'O' for a failure of another test, 'S' for
+// a skipped result and '.' for no result.
+func testBoard(outcomes string) *Dashboard {
+	dash := &Dashboard{
+		Project:  Project{Repo: "go", GoBranch: "master"},
+		Builders: []Builder{{Name: "linux-amd64"}},
+		Results:  [][]*BuildResult{make([]*BuildResult, len(outcomes))},
+	}
+	for j, c := range outcomes {
+		hash := fmt.Sprintf("%040d", len(outcomes)-j)
+		dash.Commits = append(dash.Commits, Commit{Hash: hash})
+		r := &BuildResult{ID: int64(j), Commit: hash, Builder: "linux-amd64"}
+		switch c {
+		case 'P':
+			r.Status = bbpb.Status_SUCCESS
+		case 'F':
+			r.Status = bbpb.Status_FAILURE
+			r.Failures = []*Failure{{TestID: "net.TestDial/tcp"}}
+		case 'O':
+			r.Status = bbpb.Status_FAILURE
+			r.Failures = []*Failure{{TestID: "os.TestChdir"}}
+		case 'S':
+			r.Status = SKIP
+		case '.':
+			continue
+		}
+		dash.Results[0][j] = r
+	}
+	return dash
+}
+
+func TestBisect(t *testing.T) {
+	tests := []struct {
+		outcomes string
+		at       int // index of the failure to bisect
+		commits  int // number of suspect commits, or -1 for none
+		good     bool
+		failed   int
+		known    int
+		breakage bool
+	}{
+		// Failing every time since it started: a breakage
+		// in one of the two commits after the pass,
+		// one of which wasn't built.
+		{outcomes: "FFFF.PPPPPPPP", at: 0, commits: 2, good: true, failed: 4, known: 4, breakage: true},
+		// Unknown results don't stop the bisection.
+		{outcomes: "FSF.PPPPPPPP", at: 0, commits: 2, good: true, failed: 2, known: 2, breakage: true},
+		// A failure of another test is a pass of this one.
+		{outcomes: "FOPPPPPPPP", at: 0, commits: 1, good: true, failed: 1, known: 1, breakage: true},
+		// An occasional failure of a test that failed before: a flake.
+		{outcomes: "PPPFPPPFPPPPFPPP", at: 3, commits: 1, good: true, failed: 1, known: 4, breakage: false},
+		// Failing as far back as the dashboard goes.
+		{outcomes: "FFF", at: 1, commits: 1, good: false, failed: 3, known: 3, breakage: true},
+		// Not this failure.
+		{outcomes: "OPPP", at: 0, commits: -1},
+	}
+	for _, tt := range tests {
+		dash := testBoard(tt.outcomes)
+		r := dash.Results[0][tt.at]
+		fp := &FailurePost{BuildResult: r, Failure: &Failure{TestID: "net.TestDial"}, Pkg: "net", Test: "TestDial"}
+		s := fp.bisect(boardPos{dash, 0, tt.at})
+		if tt.commits < 0 {
+			if s != nil {
+				t.Errorf("%s: bisect = %+v, want nil", tt.outcomes, s)
+			}
+			continue
+		}
+		if s == nil {
+			t.Errorf("%s: bisect = nil", tt.outcomes)
+			continue
+		}
+		if len(s.Commits) != tt.commits || (s.Good != nil) != tt.good || s.Failed != tt.failed || s.Known != tt.known || (s.Breakage >= 0.5) != tt.breakage {
+			t.Errorf("%s: bisect = %d commits, good %v, failed %d of %d, breakage %.2f; want %d commits, good %v, failed %d of %d, breakage %v",
+				tt.outcomes, len(s.Commits), s.Good != nil, s.Failed, s.Known, s.Breakage,
+				tt.commits, tt.good, tt.failed, tt.known, tt.breakage)
+		}
+		if s.Commits[0].Hash != s.Bad.Commit {
+			t.Errorf("%s: newest suspect commit is %s, want the first failing one, %s", tt.outcomes, s.Commits[0].Hash, s.Bad.Commit)
+		}
+	}
+}
+
+func TestSuspectMarkdown(t *testing.T) {
+	dash := testBoard("FFPP")
+	fp := &FailurePost{BuildResult: dash.Results[0][0], Failure: &Failure{TestID: "net.TestDial"}, Pkg: "net", Test: "TestDial"}
+	s := fp.bisect(boardPos{dash, 0, 0})
+	md := s.Markdown()
+	for _, want := range []string{
+		"Started failing on linux-amd64 between [go@00000000](https://go.googlesource.com/go/+/0000000000000000000000000000000000000002) (passed)",
+		"1 commit, [log](https://go.googlesource.com/go/+log/0000000000000000000000000000000000000002..0000000000000000000000000000000000000003).",
+		"Failed 2 of 2 builds since;",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() is missing %q:\n%s", want, md)
+		}
+	}
+}
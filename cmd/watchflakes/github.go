@@ -29,8 +29,9 @@ type Issue struct {
 	Mentions map[string]bool        // log URLs that have already been posted in watchflakes comments
 
 	// what to send back to the issue
-	Error string         // error message (markdown) to post back to issue
-	Post  []*FailurePost // failures to post back to issue
+	Error    string                  // error message (markdown) to post back to issue
+	Post     []*FailurePost          // failures to post back to issue
+	Messages map[*FailurePost]string // messages of the script rules that matched Post
 }
 
 func (i *Issue) String() string { return fmt.Sprintf("#%d", i.Number) }
@@ -231,7 +232,9 @@ func updateText(issue *Issue) string {
 	fmt.Fprintf(&b, "Found new dashboard test flakes for:\n\n%s", indent(spaces[:4], issue.ScriptText))
 	for _, f := range issue.Post {
 		b.WriteString("\n")
-		_ = f
+		if msg := issue.Messages[f]; msg != "" {
+			fmt.Fprintf(&b, "%s\n\n", msg)
+		}
 		b.WriteString(f.Markdown())
 	}
 	return b.String()
@@ -264,6 +267,9 @@ func prepareNew(fp *FailurePost) (*Issue, error) {
 	fmt.Fprintf(&msg, "```\n#!watchflakes\ndefault <- %s\n```\n\n", pattern)
 	fmt.Fprintf(&msg, "Issue created automatically to collect these failures.\n\n")
 	fmt.Fprintf(&msg, "Example ([log](%s)):\n\n%s", fp.URL, indent(spaces[:4], fp.Snippet))
+	if fp.Suspect != nil {
+		fmt.Fprintf(&msg, "\n%s", fp.Suspect.Markdown())
+	}
 
 	// TODO: for a single test failure, add a link to LUCI history page.
 
@@ -299,10 +305,19 @@ func readComments(issue *Issue) {
 	if issue.Number == 0 || !issue.Stale {
 		return
 	}
-	log.Printf("readComments %d", issue.Number)
-	comments, err := gh.IssueComments(issue.Issue)
-	if err != nil {
-		log.Fatal(err)
+	var comments []*github.IssueComment
+	if replaying != nil {
+		comments = replaying.comments(issue.Number)
+	} else {
+		log.Printf("readComments %d", issue.Number)
+		var err error
+		comments, err = gh.IssueComments(issue.Issue)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if recording != nil {
+			recording.recordComments(issue.Number, comments)
+		}
 	}
 	issue.Comments = comments
 	mtime := issue.LastEditedAt
@@ -0,0 +1,73 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"log"
+
+	bbpb "go.chromium.org/luci/buildbucket/proto"
+	"golang.org/x/build/cmd/watchflakes/internal/history"
+)
+
+// recordBuilds records the finished builds in boards in the history
+// database, so that failure rates count the builds that passed too.
+func recordBuilds(hist *history.DB, boards []*Dashboard) {
+	n := 0
+	for _, dash := range boards {
+		for _, rs := range dash.Results {
+			for _, r := range rs {
+				if r == nil {
+					continue
+				}
+				var status string
+				switch r.Status {
+				case bbpb.Status_SUCCESS:
+					status = "pass"
+				case bbpb.Status_FAILURE:
+					status = "fail"
+				case SKIP:
+					status = "skip"
+				default:
+					continue
+				}
+				b := &history.Build{
+					ID:         r.ID,
+					Builder:    r.Builder,
+					Repo:       r.Repo,
+					GoBranch:   r.GoBranch,
+					Commit:     r.Commit,
+					CommitTime: r.Time,
+					GoCommit:   r.GoCommit,
+					Status:     status,
+				}
+				if err := hist.AddBuild(b); err != nil {
+					log.Printf("history: recording build %d: %v", r.ID, err)
+					continue
+				}
+				n++
+			}
+		}
+	}
+	log.Printf("history: recorded %d builds", n)
+}
+
+// recordFailure records fp in the history database.
+func recordFailure(hist *history.DB, fp *FailurePost) {
+	f := &history.Failure{
+		BuildID:   fp.ID,
+		Signature: fp.Signature(),
+		Pkg:       fp.Pkg,
+		Test:      fp.Test,
+	}
+	if fp.Parsed != nil {
+		f.Mode, f.Message = fp.Parsed.Mode, fp.Parsed.Message
+	}
+	if fp.IsBuildFailure() {
+		f.Mode = "build"
+	}
+	if err := hist.AddFailure(f); err != nil {
+		log.Printf("history: recording failure in build %d: %v", fp.ID, err)
+	}
+}
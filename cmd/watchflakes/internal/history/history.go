@@ -0,0 +1,367 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package history records the builds and failures that watchflakes
+// has seen in a SQLite database, and computes flakiness statistics
+// from them, such as how often a failure happens and on which
+// builders.
+//
+// watchflakes only looks at a window of recent builds, so recording
+// is idempotent: seeing a build or failure again updates it rather
+// than counting it twice.
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// schema is the database schema. Times are commit times,
+// stored as Unix seconds.
+const schema = `
+CREATE TABLE IF NOT EXISTS builds (
+	id          INTEGER PRIMARY KEY, -- LUCI build ID
+	builder     TEXT,
+	repo        TEXT,
+	go_branch   TEXT,
+	commit_hash TEXT,
+	commit_time INTEGER,
+	go_commit   TEXT,    -- for subrepo builds, "" otherwise
+	status      TEXT     -- "pass", "fail", or "skip" for a broken commit or builder
+);
+CREATE INDEX IF NOT EXISTS builds_builder ON builds (builder, commit_time);
+CREATE INDEX IF NOT EXISTS builds_commit ON builds (commit_hash);
+
+-- failures holds the failures in failed builds, by signature: see
+-- (*logparser.Fail).Signature.
+CREATE TABLE IF NOT EXISTS failures (
+	signature   TEXT,
+	builder     TEXT,
+	commit_hash TEXT,
+	build_id    INTEGER,
+	commit_time INTEGER,
+	pkg         TEXT,
+	test        TEXT,
+	mode        TEXT,
+	message     TEXT,
+	PRIMARY KEY (signature, builder, commit_hash, build_id)
+);
+CREATE INDEX IF NOT EXISTS failures_test ON failures (pkg, test);
+CREATE INDEX IF NOT EXISTS failures_time ON failures (commit_time);
+`
+
+// A DB is a database of build and failure history.
+type DB struct {
+	db *sql.DB
+}
+
+// Open opens the database in the named file,
+// creating it if it doesn't exist.
+func Open(file string) (*DB, error) {
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer at a time, and the web UI
+	// reads while watchflakes writes, so share one connection
+	// rather than fail with "database is locked".
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating schema: %v", err)
+	}
+	return &DB{db: db}, nil
+}
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// A Build is a build of a commit on a builder.
+type Build struct {
+	ID         int64
+	Builder    string
+	Repo       string
+	GoBranch   string
+	Commit     string
+	CommitTime time.Time
+	GoCommit   string
+	Status     string // "pass", "fail" or "skip"
+}
+
+// A Failure is a failure in a build.
+type Failure struct {
+	BuildID   int64
+	Signature string
+	Pkg       string
+	Test      string
+	Mode      string
+	Message   string
+}
+
+// AddBuild records b, updating any earlier record of the same build.
+// The status of a build can change from one run of watchflakes to the
+// next, as later builds show that its commit or builder was broken;
+// the failures of a build that turns out to be broken are forgotten.
+func (d *DB) AddBuild(b *Build) error {
+	_, err := d.db.Exec(`
+		INSERT INTO builds (id, builder, repo, go_branch, commit_hash, commit_time, go_commit, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET status = excluded.status`,
+		b.ID, b.Builder, b.Repo, b.GoBranch, b.Commit, b.CommitTime.Unix(), b.GoCommit, b.Status)
+	if err == nil && b.Status == "skip" {
+		_, err = d.db.Exec(`DELETE FROM failures WHERE build_id = ?`, b.ID)
+	}
+	return err
+}
+
+// AddFailure records f, a failure in a build already added with AddBuild.
+func (d *DB) AddFailure(f *Failure) error {
+	_, err := d.db.Exec(`
+		INSERT OR REPLACE INTO failures (signature, builder, commit_hash, build_id, commit_time, pkg, test, mode, message)
+		SELECT ?, builder, commit_hash, id, commit_time, ?, ?, ?, ? FROM builds WHERE id = ?`,
+		f.Signature, f.Pkg, f.Test, f.Mode, f.Message, f.BuildID)
+	return err
+}
+
+// Stats are the statistics of a failure, or of all failures of a test.
+type Stats struct {
+	Signature string // "" for the failures of a test
+	Pkg       string
+	Test      string
+	Message   string // message of the latest failure
+
+	Failures int // number of builds that failed this way
+	// Builds is the number of builds, other than those of broken
+	// commits and builders, on the builders that failed this way,
+	// since the first failure. It is the denominator of Rate.
+	Builds int
+
+	FirstSeen   time.Time // commit time of the first failure
+	LastSeen    time.Time // commit time of the latest failure
+	FirstCommit string    // commit of the first failure
+	Builders    []string  // builders that failed this way, sorted
+}
+
+// Rate returns the fraction of builds that failed this way.
+func (s *Stats) Rate() float64 {
+	if s.Builds == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Builds)
+}
+
+// String returns a one-line summary of s,
+// such as for a comment on an issue.
+func (s *Stats) String() string {
+	return fmt.Sprintf("%d failures in %d builds (%.1f%%) on %d builders since %s (first at %s)",
+		s.Failures, s.Builds, 100*s.Rate(), len(s.Builders), s.FirstSeen.Format("2006-01-02"), shortHash(s.FirstCommit))
+}
+
+func shortHash(s string) string {
+	if len(s) > 8 {
+		return s[:8]
+	}
+	return s
+}
+
+// SignatureStats returns the statistics of the failures with the
+// given signature, or nil if there are none.
+func (d *DB) SignatureStats(signature string) (*Stats, error) {
+	return d.stats(&Stats{Signature: signature}, "signature = ?", signature)
+}
+
+// TestStats returns the statistics of all the failures of a test,
+// whatever their signature, or nil if there are none.
+func (d *DB) TestStats(pkg, test string) (*Stats, error) {
+	return d.stats(&Stats{Pkg: pkg, Test: test}, "pkg = ? AND test = ?", pkg, test)
+}
+
+// stats fills in s with the statistics of the failures
+// that match the SQL condition where.
+func (d *DB) stats(s *Stats, where string, args ...any) (*Stats, error) {
+	var first, last int64
+	err := d.db.QueryRow(`
+		SELECT COUNT(DISTINCT build_id), COALESCE(MIN(commit_time), 0), COALESCE(MAX(commit_time), 0)
+		FROM failures WHERE `+where, args...).Scan(&s.Failures, &first, &last)
+	if err != nil {
+		return nil, err
+	}
+	if s.Failures == 0 {
+		return nil, nil
+	}
+	s.FirstSeen, s.LastSeen = time.Unix(first, 0).UTC(), time.Unix(last, 0).UTC()
+
+	err = d.db.QueryRow(`
+		SELECT commit_hash FROM failures WHERE `+where+`
+		ORDER BY commit_time LIMIT 1`, args...).Scan(&s.FirstCommit)
+	if err != nil {
+		return nil, err
+	}
+	err = d.db.QueryRow(`
+		SELECT pkg, test, message FROM failures WHERE `+where+`
+		ORDER BY commit_time DESC LIMIT 1`, args...).Scan(&s.Pkg, &s.Test, &s.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := d.db.Query(`SELECT DISTINCT builder FROM failures WHERE `+where+` ORDER BY builder`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var b string
+		if err := rows.Scan(&b); err != nil {
+			return nil, err
+		}
+		s.Builders = append(s.Builders, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = d.db.QueryRow(`
+		SELECT COUNT(*) FROM builds
+		WHERE status != 'skip' AND commit_time >= ?
+		AND builder IN (SELECT builder FROM failures WHERE `+where+`)`,
+		append([]any{first}, args...)...).Scan(&s.Builds)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// IsNewSince reports whether the failure with the given signature is
+// new since commit, that is, whether it first happened in a build of
+// a later commit. The commit must be one of a recorded build.
+func (d *DB) IsNewSince(signature, commit string) (bool, error) {
+	t, err := d.commitTime(commit)
+	if err != nil {
+		return false, err
+	}
+	var n int
+	err = d.db.QueryRow(`SELECT COUNT(*) FROM failures WHERE signature = ? AND commit_time <= ?`, signature, t).Scan(&n)
+	return n == 0, err
+}
+
+func (d *DB) commitTime(commit string) (int64, error) {
+	var t int64
+	err := d.db.QueryRow(`SELECT commit_time FROM builds WHERE commit_hash = ? LIMIT 1`, commit).Scan(&t)
+	if err == sql.ErrNoRows {
+		// Allow abbreviated hashes.
+		err = d.db.QueryRow(`SELECT commit_time FROM builds WHERE commit_hash LIKE ? || '%' LIMIT 1`, commit).Scan(&t)
+	}
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("unknown commit %s", commit)
+	}
+	return t, err
+}
+
+// A Query selects failures for List.
+type Query struct {
+	Pkg      string // only failures in this package, if set
+	NewSince string // only failures that are new since this commit, if set
+	Limit    int    // at most this many; 0 means no limit
+}
+
+// List returns the statistics of the failures that q selects,
+// by signature, most frequent first.
+func (d *DB) List(q Query) ([]*Stats, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if q.Pkg != "" {
+		where = append(where, "pkg = ?")
+		args = append(args, q.Pkg)
+	}
+	having := ""
+	if q.NewSince != "" {
+		t, err := d.commitTime(q.NewSince)
+		if err != nil {
+			return nil, err
+		}
+		having = "HAVING MIN(commit_time) > ?"
+		args = append(args, t)
+	}
+	query := `SELECT signature FROM failures`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " GROUP BY signature " + having + " ORDER BY COUNT(DISTINCT build_id) DESC, MAX(commit_time) DESC"
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var sigs []string
+	for rows.Next() {
+		var sig string
+		if err := rows.Scan(&sig); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var list []*Stats
+	for _, sig := range sigs {
+		s, err := d.SignatureStats(sig)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// A FailureRecord is a recorded failure, with its build.
+type FailureRecord struct {
+	Build
+	Failure
+}
+
+// Failures returns the recorded failures with the given signature,
+// or of the given test if signature is empty, latest first.
+func (d *DB) Failures(signature, pkg, test string, limit int) ([]*FailureRecord, error) {
+	where, args := "f.signature = ?", []any{signature}
+	if signature == "" {
+		where, args = "f.pkg = ? AND f.test = ?", []any{pkg, test}
+	}
+	rows, err := d.db.Query(`
+		SELECT b.id, b.builder, b.repo, b.go_branch, b.commit_hash, b.commit_time, b.go_commit, b.status,
+			f.signature, f.pkg, f.test, f.mode, f.message
+		FROM failures f JOIN builds b ON f.build_id = b.id
+		WHERE `+where+`
+		ORDER BY b.commit_time DESC, b.id DESC LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []*FailureRecord
+	for rows.Next() {
+		r := new(FailureRecord)
+		var t int64
+		if err := rows.Scan(&r.ID, &r.Builder, &r.Repo, &r.GoBranch, &r.Commit, &t, &r.GoCommit, &r.Status,
+			&r.Signature, &r.Pkg, &r.Test, &r.Mode, &r.Message); err != nil {
+			return nil, err
+		}
+		r.CommitTime = time.Unix(t, 0).UTC()
+		r.BuildID = r.ID
+		list = append(list, r)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	day := func(n int) time.Time { return time.Date(2024, 1, n, 12, 0, 0, 0, time.UTC) }
+	const sig = "test|net|TestDial|dial_test.go|connection refused"
+	builds := []*Build{
+		{ID: 1, Builder: "linux-amd64", Commit: "aaaa1111", CommitTime: day(1), Status: "pass"},
+		{ID: 2, Builder: "linux-amd64", Commit: "bbbb2222", CommitTime: day(2), Status: "fail"},
+		{ID: 3, Builder: "linux-amd64", Commit: "cccc3333", CommitTime: day(3), Status: "pass"},
+		{ID: 4, Builder: "darwin-arm64", Commit: "cccc3333", CommitTime: day(3), Status: "fail"},
+		{ID: 5, Builder: "linux-amd64", Commit: "dddd4444", CommitTime: day(4), Status: "fail"},
+		{ID: 6, Builder: "windows-386", Commit: "dddd4444", CommitTime: day(4), Status: "pass"},
+	}
+	failures := []*Failure{
+		{BuildID: 2, Signature: sig, Pkg: "net", Test: "TestDial", Mode: "test", Message: "connection refused"},
+		{BuildID: 4, Signature: sig, Pkg: "net", Test: "TestDial", Mode: "test", Message: "connection refused"},
+		{BuildID: 5, Signature: "other", Pkg: "net", Test: "TestDial", Mode: "test", Message: "timeout"},
+	}
+	// Record everything twice, as watchflakes sees builds
+	// again on every run.
+	for i := 0; i < 2; i++ {
+		for _, b := range builds {
+			if err := db.AddBuild(b); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, f := range failures {
+			if err := db.AddFailure(f); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	s, err := db.SignatureStats(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Stats{
+		Signature:   sig,
+		Pkg:         "net",
+		Test:        "TestDial",
+		Message:     "connection refused",
+		Failures:    2,
+		Builds:      4, // builds 2-5, on the builders that failed
+		FirstSeen:   day(2),
+		LastSeen:    day(3),
+		FirstCommit: "bbbb2222",
+		Builders:    []string{"darwin-arm64", "linux-amd64"},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("SignatureStats:\nhave %+v\nwant %+v", s, want)
+	}
+	if got, want := s.String(), "2 failures in 4 builds (50.0%) on 2 builders since 2024-01-02 (first at bbbb2222)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	ts, err := db.TestStats("net", "TestDial")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.Failures != 3 || ts.Message != "timeout" {
+		t.Errorf("TestStats: have %d failures, latest %q; want 3, %q", ts.Failures, ts.Message, "timeout")
+	}
+
+	for _, tt := range []struct {
+		commit string
+		want   bool
+	}{
+		{"aaaa1111", true},
+		{"bbbb2222", false},
+		{"dddd", false},
+	} {
+		isNew, err := db.IsNewSince(sig, tt.commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isNew != tt.want {
+			t.Errorf("IsNewSince(%s) = %v, want %v", tt.commit, isNew, tt.want)
+		}
+	}
+	if _, err := db.IsNewSince(sig, "eeee"); err == nil {
+		t.Errorf("IsNewSince of an unknown commit succeeded")
+	}
+
+	list, err := db.List(Query{NewSince: "cccc3333"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Signature != "other" {
+		t.Errorf("List(NewSince) = %v, want only %q", list, "other")
+	}
+	list, err = db.List(Query{Pkg: "net"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Signature != sig {
+		t.Errorf("List(Pkg) = %v, want %q first", list, sig)
+	}
+
+	// A build that turns out to be broken no longer counts.
+	builds[3].Status = "skip"
+	if err := db.AddBuild(builds[3]); err != nil {
+		t.Fatal(err)
+	}
+	fs, err := db.Failures(sig, "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 || fs[0].ID != 2 {
+		t.Errorf("Failures after skipping build 4 = %v, want build 2 only", fs)
+	}
+}
@@ -5,13 +5,26 @@
 // Package script implements a simple classification scripting language.
 // A script is a sequence of rules of the form “action <- pattern”,
 // meaning send results matching pattern to the named action.
+//
+// A rule can also give a message for the results it matches, as in
+// “action "message" <- pattern”, in which $name and ${name} are
+// replaced by the text of the named capture groups of the pattern's
+// regexps, or else by the fields of the result.
+//
+// A rule can end with aggregate conditions, as in
+// “action <- pattern when count >= 3 in 7d && distinct(builder) >= 2”,
+// which are conditions on all the results in a batch that match the
+// pattern: the rule only applies if they hold.
 package script
 
 import (
 	"fmt"
+	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -23,18 +36,390 @@ type Script struct {
 
 // A Rule is a single Action <- Pattern rule.
 type Rule struct {
-	Action  string // "skip", "post", and so on
-	Pattern Expr   // pattern expression
+	Action     string       // "skip", "post", and so on
+	Message    string       // message template for matching records, or ""
+	Pattern    Expr         // pattern expression
+	Aggregates []*Aggregate // conditions on all the records matching Pattern
+	Line       int          // line number of the rule in the script (1-indexed)
+}
+
+func (r *Rule) String() string {
+	s := r.Action
+	if r.Message != "" {
+		s += " " + strconv.Quote(r.Message)
+	}
+	s += " <- " + r.Pattern.String()
+	for i, a := range r.Aggregates {
+		if i == 0 {
+			s += " when "
+		} else {
+			s += " && "
+		}
+		s += a.String()
+	}
+	return s
 }
 
 // Action returns the action specified by the script for the given record.
+// Aggregate conditions are evaluated as if record were the only record.
 func (s *Script) Action(record Record) string {
+	if m := s.Run(nil, record); m != nil {
+		return m.Rule.Action
+	}
+	return ""
+}
+
+// A Match is the result of running a script on a record.
+type Match struct {
+	Rule   *Rule
+	Record Record
+
+	// Captures holds the text of the named capture groups
+	// of the regexps that matched the record.
+	Captures map[string]string
+}
+
+// Run runs the script on record, which should be one of the records
+// in the batch b, and returns the match of the first rule that
+// applies, or nil if none does. Aggregate conditions are evaluated
+// over the records in b. If b is nil, they are evaluated as if record
+// were the only record.
+func (s *Script) Run(b *Batch, record Record) *Match {
 	for _, r := range s.Rules {
-		if r.Pattern.Match(record) {
-			return r.Action
+		if r.Pattern.Match(record) && b.check(r, record) {
+			m := &Match{Rule: r, Record: record, Captures: make(map[string]string)}
+			capture(r.Pattern, record, m.Captures)
+			return m
 		}
 	}
-	return ""
+	return nil
+}
+
+// Held reports whether record matches the pattern of a rule in s
+// whose aggregate conditions do not hold over the records in b.
+// Such a record is expected to go to the script's issue once enough
+// like it have been seen, so it should not be filed elsewhere.
+func (s *Script) Held(b *Batch, record Record) bool {
+	for _, r := range s.Rules {
+		if len(r.Aggregates) > 0 && r.Pattern.Match(record) && !b.check(r, record) {
+			return true
+		}
+	}
+	return false
+}
+
+// Message returns the message of the matched rule,
+// expanded as by Expand.
+func (m *Match) Message() string {
+	return m.Expand(m.Rule.Message)
+}
+
+// Expand returns text with $name and ${name} replaced by the named
+// capture group of that name, or else by the record field of that
+// name. It is useful for issue titles and comments.
+func (m *Match) Expand(text string) string {
+	return os.Expand(text, func(name string) string {
+		if v, ok := m.Captures[name]; ok {
+			return v
+		}
+		return m.Record[name]
+	})
+}
+
+// capture adds the named capture groups of the regexps in x
+// that matched record to caps. For an OrExpr, only the groups
+// of the first operand that matched are added.
+func capture(x Expr, record Record, caps map[string]string) {
+	switch x := x.(type) {
+	case *RegExpr:
+		if x.Not {
+			return
+		}
+		m := x.Regexp.FindStringSubmatch(record[x.Field])
+		for i, name := range x.Regexp.SubexpNames() {
+			if name != "" && i < len(m) {
+				if _, ok := caps[name]; !ok {
+					caps[name] = m[i]
+				}
+			}
+		}
+	case *AndExpr:
+		capture(x.X, record, caps)
+		capture(x.Y, record, caps)
+	case *OrExpr:
+		if x.X.Match(record) {
+			capture(x.X, record, caps)
+		} else {
+			capture(x.Y, record, caps)
+		}
+	}
+}
+
+// captureNames returns the names of the capture groups in x.
+func captureNames(x Expr, names map[string]bool) {
+	switch x := x.(type) {
+	case *RegExpr:
+		for _, name := range x.Regexp.SubexpNames() {
+			if name != "" {
+				names[name] = true
+			}
+		}
+	case *NotExpr:
+		captureNames(x.X, names)
+	case *AndExpr:
+		captureNames(x.X, names)
+		captureNames(x.Y, names)
+	case *OrExpr:
+		captureNames(x.X, names)
+		captureNames(x.Y, names)
+	}
+}
+
+// An Aggregate is a condition on the records in a batch that match
+// the pattern of a rule, such as “count >= 3 in 7d”. Only records
+// within a window of time of each other, as told by their "date"
+// fields, count toward the condition if it has a Window. Since the
+// window can be anywhere that includes the record that the rule is
+// run on, the rule applies to all the records of a burst of matches,
+// not just the last ones.
+type Aggregate struct {
+	Field  string        // field whose distinct values are counted, or "" to count the records
+	Op     string        // comparison operator, such as ">="
+	N      int           // number to compare the count with
+	Window time.Duration // length of the window, or 0 for the whole batch
+}
+
+func (a *Aggregate) String() string {
+	s := "count"
+	if a.Field != "" {
+		s = "distinct(" + a.Field + ")"
+	}
+	s += " " + a.Op + " " + strconv.Itoa(a.N)
+	if a.Window != 0 {
+		s += " in " + formatWindow(a.Window)
+	}
+	return s
+}
+
+// formatWindow formats d in the syntax that parseWindow accepts.
+func formatWindow(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d%(7*day) == 0:
+		return strconv.Itoa(int(d/(7*day))) + "w"
+	case d%day == 0:
+		return strconv.Itoa(int(d/day)) + "d"
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// parseWindow parses a window length, which is a number of days
+// or weeks, such as "7d" or "2w", or a Go duration, such as "12h".
+func parseWindow(s string) (time.Duration, error) {
+	const day = 24 * time.Hour
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		d, err := strconv.Atoi(n)
+		return time.Duration(d) * day, err
+	}
+	if n, ok := strings.CutSuffix(s, "w"); ok {
+		d, err := strconv.Atoi(n)
+		return time.Duration(d) * 7 * day, err
+	}
+	return time.ParseDuration(s)
+}
+
+// count returns the value of the aggregate, before the comparison,
+// for the records in b at indexes idx, which are sorted by time, as
+// seen from a record at time t. With a window, it is the largest
+// value of any window that includes t.
+func (a *Aggregate) count(b *Batch, idx []int, t time.Time) int {
+	if a.Window == 0 {
+		return a.value(b, idx)
+	}
+	start := t.Add(-a.Window)
+	i := sort.Search(len(idx), func(i int) bool { return !b.times[idx[i]].Before(start) })
+	n := 0
+	for ; i < len(idx) && !b.times[idx[i]].After(t); i++ {
+		end := b.times[idx[i]].Add(a.Window)
+		j := sort.Search(len(idx), func(j int) bool { return b.times[idx[j]].After(end) })
+		n = max(n, a.value(b, idx[i:j]))
+	}
+	return n
+}
+
+// value returns the value of the aggregate for the records in b at idx.
+func (a *Aggregate) value(b *Batch, idx []int) int {
+	if a.Field == "" {
+		return len(idx)
+	}
+	seen := make(map[string]bool)
+	for _, i := range idx {
+		seen[b.records[i][a.Field]] = true
+	}
+	return len(seen)
+}
+
+func (a *Aggregate) match(b *Batch, idx []int, t time.Time) bool {
+	n := a.count(b, idx, t)
+	switch a.Op {
+	case "==":
+		return n == a.N
+	case "!=":
+		return n != a.N
+	case "<":
+		return n < a.N
+	case "<=":
+		return n <= a.N
+	case ">":
+		return n > a.N
+	case ">=":
+		return n >= a.N
+	}
+	return false
+}
+
+// A Batch is a set of records that scripts are run on together.
+// The aggregate conditions of rules are evaluated over the batch.
+type Batch struct {
+	records []Record
+	times   []time.Time     // parsed "date" fields of records
+	matches map[*Rule][]int // indexes of the records matching a rule's pattern, by time
+}
+
+// NewBatch returns a batch of records.
+func NewBatch(records []Record) *Batch {
+	b := &Batch{
+		records: records,
+		times:   make([]time.Time, len(records)),
+		matches: make(map[*Rule][]int),
+	}
+	for i, r := range records {
+		b.times[i] = recordTime(r)
+	}
+	return b
+}
+
+// recordTime returns the time of record, from its "date" field.
+// Records without a valid date all have the zero time.
+func recordTime(record Record) time.Time {
+	t, _ := time.Parse(time.RFC3339, record["date"])
+	return t
+}
+
+// check reports whether the aggregate conditions of r hold
+// for record.
+func (b *Batch) check(r *Rule, record Record) bool {
+	if len(r.Aggregates) == 0 {
+		return true
+	}
+	if b == nil {
+		b = NewBatch([]Record{record})
+	}
+	idx, ok := b.matches[r]
+	if !ok {
+		for i, rec := range b.records {
+			if r.Pattern.Match(rec) {
+				idx = append(idx, i)
+			}
+		}
+		sort.SliceStable(idx, func(i, j int) bool { return b.times[idx[i]].Before(b.times[idx[j]]) })
+		b.matches[r] = idx
+	}
+	t := recordTime(record)
+	for _, a := range r.Aggregates {
+		if !a.match(b, idx, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// A Problem is a problem with a rule of a script, found by Lint.
+type Problem struct {
+	File string
+	Rule *Rule
+	Err  string
+}
+
+func (p *Problem) Error() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Rule.Line, p.Err)
+}
+
+// Lint runs the script on the records in b and reports the rules
+// that never apply to any of them, and the rules that are shadowed by
+// earlier rules, which apply to every record that they apply to.
+// A rule with the same pattern as an earlier rule without aggregate
+// conditions is reported as shadowed whatever the records are.
+func (s *Script) Lint(b *Batch) []*Problem {
+	var probs []*Problem
+	report := func(r *Rule, format string, args ...any) {
+		probs = append(probs, &Problem{File: s.File, Rule: r, Err: fmt.Sprintf(format, args...)})
+	}
+	applies := make([]map[int]bool, len(s.Rules)) // indexes of records each rule applies to
+Rules:
+	for i, r := range s.Rules {
+		applies[i] = make(map[int]bool)
+		for j, rec := range b.records {
+			if r.Pattern.Match(rec) && b.check(r, rec) {
+				applies[i][j] = true
+			}
+		}
+		for _, prev := range s.Rules[:i] {
+			if len(prev.Aggregates) == 0 && prev.Pattern.String() == r.Pattern.String() {
+				report(r, "rule is shadowed by the identical pattern at line %d", prev.Line)
+				continue Rules
+			}
+		}
+		if len(applies[i]) == 0 {
+			report(r, "rule never matches")
+			continue
+		}
+		shadowed := true
+		for j := range applies[i] {
+			covered := false
+			for k := range s.Rules[:i] {
+				if applies[k][j] {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				shadowed = false
+				break
+			}
+		}
+		if !shadowed {
+			continue
+		}
+		// Blame a single earlier rule if there is one.
+		by := -1
+		for k := range s.Rules[:i] {
+			all := true
+			for j := range applies[i] {
+				if !applies[k][j] {
+					all = false
+					break
+				}
+			}
+			if all {
+				by = k
+				break
+			}
+		}
+		if by >= 0 {
+			report(r, "rule is shadowed by the rule at line %d", s.Rules[by].Line)
+		} else {
+			report(r, "rule is shadowed by earlier rules")
+		}
+	}
+	return probs
 }
 
 // A Record is a set of key:value pairs.
@@ -207,8 +592,8 @@ type parser struct {
 	i      int             // next read location in s
 	fields map[string]bool // known input fields for comparisons
 
-	tok string // last token read; "`", "\"", "a" for backquoted regexp, literal string, identifier
-	lit string // text of backquoted regexp, literal string, or identifier
+	tok string // last token read; "`", "\"", "a", "1" for backquoted regexp, literal string, identifier, number
+	lit string // text of backquoted regexp, literal string, identifier, or number
 	pos int    // position (start) of last token
 }
 
@@ -283,6 +668,8 @@ func (p *parser) unexpected() {
 		what = "quoted string " + p.lit
 	case "`":
 		what = "backquoted string " + p.lit
+	case "1":
+		what = "number " + p.lit
 	case "\n":
 		what = "end of line"
 	case "":
@@ -306,12 +693,100 @@ func (p *parser) rule() *Rule {
 	if p.tok != "a" {
 		p.unexpected()
 	}
-	action := p.lit
+	r := &Rule{Action: p.lit, Line: 1 + strings.Count(p.s[:p.pos], "\n")}
+	start := p.pos
 	p.lex()
+	if p.tok == "\"" {
+		r.Message = p.lit
+		p.lex()
+	}
 	if p.tok != "<-" {
 		p.unexpected()
 	}
-	return &Rule{Action: action, Pattern: p.or()}
+	r.Pattern = p.or()
+	if p.tok == "a" && p.lit == "when" {
+		r.Aggregates = append(r.Aggregates, p.aggregate())
+		for p.tok == "&&" {
+			r.Aggregates = append(r.Aggregates, p.aggregate())
+		}
+	}
+
+	// Check that the message only refers to capture groups and fields.
+	names := make(map[string]bool)
+	captureNames(r.Pattern, names)
+	os.Expand(r.Message, func(name string) string {
+		if !names[name] && (name == "" || !p.fields[name]) {
+			// Recover from the error at the end of the rule,
+			// not at the end of the line after it.
+			p.i = p.pos
+			p.errorAt(start, "unknown name $"+name+" in message")
+		}
+		return ""
+	})
+	return r
+}
+
+// aggregate parses an aggregate condition.
+// On entry, the next input token has not yet been lexed.
+// On exit, the next input token has been lexed and is in p.tok.
+func (p *parser) aggregate() *Aggregate {
+	a := new(Aggregate)
+	p.lex()
+	if p.tok != "a" {
+		p.unexpected()
+	}
+	switch p.lit {
+	default:
+		p.parseError("unknown aggregate " + p.lit)
+	case "count":
+	case "distinct":
+		p.lex()
+		if p.tok != "(" {
+			p.unexpected()
+		}
+		p.lex()
+		if p.tok != "a" {
+			p.unexpected()
+		}
+		if !p.fields[p.lit] {
+			p.parseError("unknown field " + p.lit)
+		}
+		a.Field = p.lit
+		p.lex()
+		if p.tok != ")" {
+			p.unexpected()
+		}
+	}
+	p.lex()
+	switch p.tok {
+	default:
+		p.unexpected()
+	case "==", "!=", "<", "<=", ">", ">=":
+		a.Op = p.tok
+	}
+	p.lex()
+	if p.tok != "1" {
+		p.parseError(a.Op + " requires number")
+	}
+	n, err := strconv.Atoi(p.lit)
+	if err != nil {
+		p.parseError("invalid number " + p.lit)
+	}
+	a.N = n
+	p.lex()
+	if p.tok == "a" && p.lit == "in" {
+		p.lex()
+		if p.tok != "1" {
+			p.parseError("in requires duration")
+		}
+		d, err := parseWindow(p.lit)
+		if err != nil || d <= 0 {
+			p.parseError("invalid duration " + p.lit)
+		}
+		a.Window = d
+		p.lex()
+	}
+	return a
 }
 
 // or parses a sequence of || expressions.
@@ -449,6 +924,11 @@ Top:
 		case "(", "&&", "||", "==", "!=", "~", "!~", "!", "<-":
 			p.i++
 			goto Top
+		case "a":
+			if p.lit == "when" {
+				p.i++
+				goto Top
+			}
 		}
 		p.pos = p.i
 		p.i++
@@ -526,6 +1006,19 @@ Top:
 		p.lexError("single-quoted strings not allowed")
 	}
 
+	// number, or duration such as 7d
+	if isdigit(p.s[p.i]) {
+		j := p.i
+		for j < len(p.s) && (isalnum(p.s[j]) || p.s[j] == '.') {
+			j++
+		}
+		p.pos = p.i
+		p.i = j
+		p.tok = "1"
+		p.lit = p.s[p.pos:p.i]
+		return
+	}
+
 	// ascii name
 	if isalpha(p.s[p.i]) {
 		j := p.i
@@ -571,3 +1064,8 @@ func isalpha(c byte) bool {
 func isalnum(c byte) bool {
 	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_'
 }
+
+// isdigit reports whether c is an ASCII digit.
+func isdigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
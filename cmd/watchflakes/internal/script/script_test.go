@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package script
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFields = []string{"", "pkg", "test", "output", "builder", "date"}
+
+var parseTests = []struct {
+	in  string
+	out string // String of the rules, one per line, or the errors
+}{
+	{`post <- pkg == "net" && test ~ ` + "`^TestDial`", `post <- pkg == "net" && test ~ ` + "`(?m)^TestDial`"},
+	{"default \"dial: $err\" <- `dial (?P<err>.*)`", "default \"dial: $err\" <- `(?m)dial (?P<err>.*)`"},
+	{`post "$pkg: $test" <- pkg == "net"`, `post "$pkg: $test" <- pkg == "net"`},
+	{`post <- pkg == "net" when count >= 3 in 7d`, `post <- pkg == "net" when count >= 3 in 1w`},
+	{"post <- pkg == \"net\" when\n\tcount >= 3 in 36h &&\n\tdistinct(builder) >= 2", `post <- pkg == "net" when count >= 3 in 36h && distinct(builder) >= 2`},
+	{`post <- pkg == "net" when count > 1 in 90m`, `post <- pkg == "net" when count > 1 in 1h30m`},
+	{"post <- pkg == \"net\"\nskip <- test == \"TestX\"", "post <- pkg == \"net\"\nskip <- test == \"TestX\""},
+
+	{`post "$err" <- pkg == "net"`, `script:1.1: unknown name $err in message`},
+	{`post <- pkg == "net" when total >= 3`, `script:1.27: unknown aggregate total`},
+	{`post <- pkg == "net" when distinct(color) >= 3`, `script:1.36: unknown field color`},
+	{`post <- pkg == "net" when count >= many`, `script:1.36: >= requires number`},
+	{`post <- pkg == "net" when count >= 3 in 7y`, `script:1.41: invalid duration 7y`},
+	{`post <- pkg == 3`, `script:1.16: == requires quoted string`},
+	{"post \"$err\" <- pkg == \"net\"\nskip <- test == \"TestX\"", "script:1.1: unknown name $err in message\nskip <- test == \"TestX\""},
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range parseTests {
+		s, errs := Parse("script", tt.in, testFields)
+		var out []string
+		for _, err := range errs {
+			out = append(out, err.Error())
+		}
+		for _, r := range s.Rules {
+			out = append(out, r.String())
+		}
+		if got := strings.Join(out, "\n"); got != tt.out {
+			t.Errorf("Parse(%q):\nhave %s\nwant %s", tt.in, got, tt.out)
+		}
+	}
+}
+
+func mustParse(t *testing.T, text string) *Script {
+	t.Helper()
+	s, errs := Parse("script", text, testFields)
+	if len(errs) > 0 {
+		t.Fatalf("Parse(%q): %v", text, errs[0])
+	}
+	return s
+}
+
+func TestCaptures(t *testing.T) {
+	s := mustParse(t, "post \"$pkg: ${err} (${addr})\" <- pkg == \"net\" && (`dial (?P<addr>\\S+): (?P<err>.*)` || `listen (?P<err>.*)`)")
+	tests := []struct {
+		output string
+		msg    string
+	}{
+		{"dial 127.0.0.1:1: connection refused\n", "net: connection refused (127.0.0.1:1)"},
+		{"listen tcp: address in use\n", "net: tcp: address in use ()"},
+		{"accept: too many files\n", ""},
+	}
+	for _, tt := range tests {
+		m := s.Run(nil, Record{"pkg": "net", "": tt.output})
+		if tt.msg == "" {
+			if m != nil {
+				t.Errorf("Run(%q) matched, want no match", tt.output)
+			}
+			continue
+		}
+		if m == nil {
+			t.Errorf("Run(%q) = nil, want match", tt.output)
+			continue
+		}
+		if got := m.Message(); got != tt.msg {
+			t.Errorf("Run(%q).Message() = %q, want %q", tt.output, got, tt.msg)
+		}
+	}
+}
+
+// testRecords returns records at the given days after a fixed date,
+// on builders named by the letters of builders.
+func testRecords(test string, days []int, builders string) []Record {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []Record
+	for i, d := range days {
+		records = append(records, Record{
+			"test":    test,
+			"builder": builders[i : i+1],
+			"date":    start.Add(time.Duration(d) * 24 * time.Hour).Format(time.RFC3339),
+		})
+	}
+	return records
+}
+
+func TestAggregates(t *testing.T) {
+	// Three failures of TestA in a week, and one more later;
+	// TestB failures spread out on one builder.
+	records := append(testRecords("TestA", []int{0, 2, 5, 20}, "xxyx"),
+		testRecords("TestB", []int{0, 10, 20, 30}, "zzzz")...)
+	b := NewBatch(records)
+
+	tests := []struct {
+		rule string
+		want string // which records the rule applies to
+	}{
+		{`post <- test == "TestA"`, "AAAA----"},
+		{`post <- test == "TestA" when count >= 3 in 7d`, "AAA-----"},
+		{`post <- test == "TestA" when count >= 4`, "AAAA----"},
+		{`post <- test == "TestA" when count >= 3 in 2d`, "--------"},
+		{`post <- test == "TestB" when count >= 2 in 10d`, "----BBBB"},
+		{`post <- test ~ ` + "`Test`" + ` when distinct(builder) >= 2 in 7d`, "AAAABBB-"},
+		{`post <- test == "TestB" when distinct(builder) < 2`, "----BBBB"},
+	}
+	for _, tt := range tests {
+		s := mustParse(t, tt.rule)
+		var got []byte
+		for _, r := range records {
+			if s.Run(b, r) != nil {
+				got = append(got, r["test"][4])
+			} else {
+				got = append(got, '-')
+			}
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s:\nhave %s\nwant %s", tt.rule, got, tt.want)
+		}
+	}
+
+	// Without a batch, a record only counts itself.
+	s := mustParse(t, `post <- test == "TestA" when count >= 2`)
+	if got := s.Action(records[0]); got != "" {
+		t.Errorf("Action without batch = %q, want \"\"", got)
+	}
+	if !s.Held(nil, records[0]) {
+		t.Errorf("Held without batch = false, want true")
+	}
+	if s.Held(b, records[4]) {
+		t.Errorf("Held(TestB record) = true, want false")
+	}
+}
+
+func TestLint(t *testing.T) {
+	records := append(testRecords("TestA", []int{0, 1}, "xy"), testRecords("TestB", []int{0}, "x")...)
+	s := mustParse(t, `#!watchflakes
+post <- test == "TestA" || test == "TestB"
+post <- test == "TestC"
+default <- test == "TestA"
+skip <- test == "TestB" when count >= 1
+post <- test == "TestA" || test == "TestB"
+post <- test == "TestA" && builder == "x"
+`)
+	var got []string
+	for _, p := range s.Lint(NewBatch(records)) {
+		got = append(got, p.Error())
+	}
+	want := []string{
+		"script:3: rule never matches",
+		"script:4: rule is shadowed by the rule at line 2",
+		"script:5: rule is shadowed by the rule at line 2",
+		"script:6: rule is shadowed by the identical pattern at line 2",
+		"script:7: rule is shadowed by the rule at line 2",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Lint:\nhave %q\nwant %q", got, want)
+	}
+}
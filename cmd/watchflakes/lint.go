@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/build/cmd/watchflakes/internal/script"
+)
+
+// lintIssues checks the scripts of the open issues against the
+// failures in records, which make up batch, and prints the problems
+// it finds: scripts that are missing or don't parse, rules that never
+// match any failure or are shadowed by earlier rules of their script,
+// and rules whose failures all go to other issues instead.
+func lintIssues(issues []*Issue, batch *script.Batch, records []script.Record) {
+	n := 0
+	for _, issue := range issues {
+		if issue.Closed {
+			continue
+		}
+		var problems []string
+		if issue.Error != "" {
+			problems = append(problems, strings.TrimSpace(issue.Error))
+		}
+		if issue.Script != nil {
+			for _, p := range issue.Script.Lint(batch) {
+				problems = append(problems, fmt.Sprintf("%s\n\t%s", p.Error(), p.Rule))
+			}
+			problems = append(problems, lintElsewhere(issue, issues, batch, records)...)
+		}
+		if len(problems) == 0 {
+			continue
+		}
+		n++
+		fmt.Printf("#%d %s\n", issue.Number, issue.Title)
+		for _, p := range problems {
+			fmt.Printf("%s", indent(spaces[:4], p))
+		}
+	}
+	fmt.Printf("%d issues with problems, checked against %d failures\n", n, len(records))
+}
+
+// lintElsewhere returns the problems of the rules of the script of
+// issue whose failures all go to other issues instead, because other
+// scripts skip them, or post them when the rule is a default.
+func lintElsewhere(issue *Issue, issues []*Issue, batch *script.Batch, records []script.Record) []string {
+	applies := make(map[*script.Rule]int)        // number of failures the rule applies to
+	elsewhere := make(map[*script.Rule]int)      // number of them that go to other issues
+	where := make(map[*script.Rule]map[int]bool) // the other issues
+	for _, record := range records {
+		m := issue.Script.Run(batch, record)
+		if m == nil || m.Rule.Action != "post" && m.Rule.Action != "default" {
+			continue
+		}
+		applies[m.Rule]++
+		_, targets, _ := run(issues, batch, record)
+		if slices.Contains(targets, issue) {
+			continue
+		}
+		elsewhere[m.Rule]++
+		if where[m.Rule] == nil {
+			where[m.Rule] = make(map[int]bool)
+		}
+		for _, t := range targets {
+			where[m.Rule][t.Number] = true
+		}
+	}
+
+	var problems []string
+	for _, r := range issue.Script.Rules {
+		if applies[r] == 0 || elsewhere[r] < applies[r] {
+			continue
+		}
+		var nums []string
+		for num := range where[r] {
+			nums = append(nums, fmt.Sprintf("#%d", num))
+		}
+		sort.Strings(nums)
+		problems = append(problems, fmt.Sprintf("%s:%d: rule's failures all go to %s instead\n\t%s",
+			issue.Script.File, r.Line, strings.Join(nums, ", "), r))
+	}
+	return problems
+}
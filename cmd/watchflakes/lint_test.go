@@ -0,0 +1,47 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/build/cmd/watchflakes/internal/script"
+	"rsc.io/github"
+)
+
+func TestLintElsewhere(t *testing.T) {
+	newIssue := func(num int, text string) *Issue {
+		s, errs := script.Parse("script", text, fields)
+		if len(errs) > 0 {
+			t.Fatalf("parsing #%d: %v", num, errs[0])
+		}
+		return &Issue{Issue: &github.Issue{Number: num}, Script: s, ScriptText: text}
+	}
+	issues := []*Issue{
+		newIssue(1, "#!watchflakes\ndefault <- pkg == \"net\"\n"),
+		newIssue(2, "#!watchflakes\npost <- pkg == \"net\" && test == \"TestDial\"\n"),
+		newIssue(3, "#!watchflakes\nskip <- builder == \"broken\"\n"),
+		newIssue(4, "#!watchflakes\ndefault <- test == \"TestDial\"\ndefault <- builder == \"broken\"\n"),
+	}
+	records := []script.Record{
+		{"pkg": "net", "test": "TestDial", "builder": "linux"},
+		{"pkg": "net", "test": "TestDial", "builder": "broken"},
+	}
+	batch := script.NewBatch(records)
+
+	want := map[int][]string{
+		1: {"script:2: rule's failures all go to #2, #3 instead\n\tdefault <- pkg == \"net\""},
+		4: {
+			"script:2: rule's failures all go to #2, #3 instead\n\tdefault <- test == \"TestDial\"",
+		},
+	}
+	for _, issue := range issues {
+		got := lintElsewhere(issue, issues, batch, records)
+		if !reflect.DeepEqual(got, want[issue.Number]) {
+			t.Errorf("lintElsewhere(#%d) = %q, want %q", issue.Number, got, want[issue.Number])
+		}
+	}
+}
@@ -129,10 +129,11 @@ type Dashboard struct {
 }
 
 type Failure struct {
-	TestID  string
-	Status  rdbpb.TestStatus
-	LogURL  string
-	LogText string
+	TestID   string
+	Status   rdbpb.TestStatus
+	Duration time.Duration
+	LogURL   string
+	LogText  string
 }
 
 // ListCommits fetches the list of commits from Gerrit.
@@ -518,9 +519,10 @@ func (c *LUCIClient) GetResultAndArtifacts(ctx context.Context, r *BuildResult)
 			}
 			url := a.GetFetchUrl()
 			f := &Failure{
-				TestID: testID,
-				Status: rr.GetStatus(),
-				LogURL: url,
+				TestID:   testID,
+				Status:   rr.GetStatus(),
+				Duration: rr.GetDuration().AsDuration(),
+				LogURL:   url,
 			}
 			failures = append(failures, f)
 		}
@@ -591,7 +593,12 @@ func (c *LUCIClient) FindFailures(ctx context.Context, boards []*Dashboard) []*B
 		}
 	}
 	wg.Wait()
+	sortResults(res)
+	return res
+}
 
+// sortResults sorts res by commit date, then repo, then builder.
+func sortResults(res []*BuildResult) {
 	slices.SortFunc(res, func(a, b *BuildResult) int {
 		if !a.Time.Equal(b.Time) {
 			return a.Time.Compare(b.Time)
@@ -604,8 +611,6 @@ func (c *LUCIClient) FindFailures(ctx context.Context, boards []*Dashboard) []*B
 		}
 		return strings.Compare(a.Commit, b.Commit)
 	})
-
-	return res
 }
 
 // PrintDashboard prints the dashboard.
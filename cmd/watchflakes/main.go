@@ -11,6 +11,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"runtime"
 	"strconv"
@@ -20,7 +21,9 @@ import (
 	bbpb "go.chromium.org/luci/buildbucket/proto"
 	rdbpb "go.chromium.org/luci/resultdb/proto/v1"
 	"golang.org/x/build/buildenv"
+	"golang.org/x/build/cmd/watchflakes/internal/history"
 	"golang.org/x/build/cmd/watchflakes/internal/script"
+	"golang.org/x/build/internal/logparser"
 	"golang.org/x/build/internal/secret"
 	"rsc.io/github"
 )
@@ -44,6 +47,13 @@ var (
 	post    = flag.Bool("post", false, "post updates to GitHub issues")
 	repeat  = flag.Duration("repeat", 0, "keep running with specified `period`; zero means to run once and exit")
 	verbose = flag.Bool("v", false, "print verbose posting decisions")
+	lint    = flag.Bool("lint", false, "check the scripts in the open issues against the failures, report problems, and exit")
+
+	historyFile = flag.String("history", "", "record builds and failures in the SQLite database `file`, and report flakiness statistics from it")
+	httpAddr    = flag.String("http", "", "serve a web UI of the -history database on `addr`, such as :8080")
+
+	recordFile = flag.String("record", "", "record the builds, logs and issues fetched from LUCI and GitHub in the archive `file` (gzipped if it ends in .gz)")
+	replayFile = flag.String("replay", "", "run offline on the archive `file` written by -record, printing a report of the updates instead of posting them")
 
 	useSecretManager = flag.Bool("use-secret-manager", false, "fetch GitHub token from Secret Manager instead of $HOME/.netrc")
 )
@@ -72,25 +82,57 @@ func main() {
 		query = &Issue{Issue: new(github.Issue), Script: s, ScriptText: flag.Arg(0)}
 	}
 
-	// Create an authenticated GitHub client.
-	if *useSecretManager {
-		// Fetch credentials from Secret Manager.
-		secretCl, err := secret.NewClientInProject(buildenv.FromFlags().ProjectName)
-		if err != nil {
-			log.Fatalln("failed to create a Secret Manager client:", err)
+	if *replayFile != "" {
+		if *post || *repeat != 0 || *build != "" || *recordFile != "" {
+			log.Fatal("-replay cannot be used with -post, -repeat, -build or -record")
 		}
-		ghToken, err := secretCl.Retrieve(context.Background(), secret.NameWatchflakesGitHubToken)
+		var err error
+		replaying, err = readArchive(*replayFile)
 		if err != nil {
-			log.Fatalln("failed to retrieve GitHub token from Secret Manager:", err)
+			log.Fatal(err)
 		}
-		gh = github.NewClient(ghToken)
-	} else {
-		// Use credentials in $HOME/.netrc.
+	}
+
+	// Create an authenticated GitHub client,
+	// which replaying an archive doesn't need.
+	if replaying == nil {
+		if *useSecretManager {
+			// Fetch credentials from Secret Manager.
+			secretCl, err := secret.NewClientInProject(buildenv.FromFlags().ProjectName)
+			if err != nil {
+				log.Fatalln("failed to create a Secret Manager client:", err)
+			}
+			ghToken, err := secretCl.Retrieve(context.Background(), secret.NameWatchflakesGitHubToken)
+			if err != nil {
+				log.Fatalln("failed to retrieve GitHub token from Secret Manager:", err)
+			}
+			gh = github.NewClient(ghToken)
+		} else {
+			// Use credentials in $HOME/.netrc.
+			var err error
+			gh, err = github.Dial("")
+			if err != nil {
+				log.Fatalln("github.Dial:", err)
+			}
+		}
+	}
+
+	var hist *history.DB
+	if *historyFile != "" {
 		var err error
-		gh, err = github.Dial("")
+		hist, err = history.Open(*historyFile)
 		if err != nil {
-			log.Fatalln("github.Dial:", err)
+			log.Fatalf("opening history: %v", err)
 		}
+		defer hist.Close()
+	}
+	if *httpAddr != "" {
+		if hist == nil {
+			log.Fatal("-http requires -history")
+		}
+		go func() {
+			log.Fatal(http.ListenAndServe(*httpAddr, &historyServer{hist}))
+		}()
 	}
 
 	// Load LUCI dashboards
@@ -106,8 +148,17 @@ func main() {
 Repeat:
 	startTime := time.Now()
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	if *recordFile != "" {
+		recording = &Archive{Time: startTime, Build: *build}
+	}
 	var boards []*Dashboard
-	if *build == "" {
+	if replaying != nil {
+		boards = replaying.boards()
+		if replaying.Build == "" {
+			skipBrokenCommits(boards)
+			skipBrokenBuilders(boards)
+		}
+	} else if *build == "" {
 		// fetch the dashboard
 		var err error
 		boards, err = c.ListBoards(ctx)
@@ -118,6 +169,9 @@ Repeat:
 		if err != nil {
 			log.Fatalln("ReadBoards:", err)
 		}
+		if recording != nil {
+			recording.recordStatuses(boards)
+		}
 		skipBrokenCommits(boards)
 		skipBrokenBuilders(boards)
 	} else {
@@ -136,10 +190,22 @@ Repeat:
 			Results:  [][]*BuildResult{{r}},
 		}
 		boards = []*Dashboard{board}
+		if recording != nil {
+			recording.recordStatuses(boards)
+		}
 	}
 
-	failRes := c.FindFailures(ctx, boards)
-	c.FetchLogs(failRes)
+	var failRes []*BuildResult
+	if replaying != nil {
+		failRes = failures(boards)
+	} else {
+		failRes = c.FindFailures(ctx, boards)
+		c.FetchLogs(failRes)
+	}
+	if hist != nil {
+		recordBuilds(hist, boards)
+	}
+	boardIdx := indexBoards(boards)
 
 	if *verbose {
 		for _, r := range failRes {
@@ -149,36 +215,53 @@ Repeat:
 
 	// Load GitHub issues
 	var issues []*Issue
-	issues, err := readIssues(issues)
-	if err != nil {
-		log.Fatal(err)
+	if replaying != nil {
+		issues = replaying.issues()
+	} else {
+		var err error
+		issues, err = readIssues(issues)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if recording != nil {
+		recording.recordIssues(issues)
 	}
 	findScripts(issues)
-	if query == nil {
+	if query == nil && !*lint {
 		postIssueErrors(issues)
 	}
 	if query != nil {
 		issues = []*Issue{query}
 	}
 
-	for _, r := range failRes {
-		newIssue := 0
-		fs := r.Failures
-		fs = coalesceFailures(fs)
-		if len(fs) == 0 {
-			// No test failure, Probably a build failure.
-			// E.g. https://ci.chromium.org/ui/b/8759448820419452721
-			// Make a dummy failure.
-			f := &Failure{
-				Status:  rdbpb.TestStatus_FAIL,
-				LogText: r.StepLogText,
+	// Find the failures of all the builds first,
+	// so that scripts can count them across builds.
+	failPosts := make([][]*FailurePost, len(failRes))
+	var records []script.Record
+	for i, r := range failRes {
+		failPosts[i] = newFailurePosts(r)
+		for _, fp := range failPosts[i] {
+			if hist != nil {
+				recordFailure(hist, fp)
 			}
-			fs = []*Failure{f}
+			fp.record = fp.Record()
+			records = append(records, fp.record)
 		}
-		for _, f := range fs {
-			fp := NewFailurePost(r, f)
-			record := fp.Record()
-			action, targets := run(issues, record)
+	}
+	batch := script.NewBatch(records)
+
+	if *lint {
+		saveRecording()
+		lintIssues(issues, batch, records)
+		return
+	}
+
+	for i, r := range failRes {
+		newIssue := 0
+		for _, fp := range failPosts[i] {
+			record := fp.record
+			action, targets, matches := run(issues, batch, record)
 			if *verbose {
 				printRecord(record, false)
 				fmt.Printf("\t%s %v\n", action, targets)
@@ -190,6 +273,12 @@ Repeat:
 					fmt.Printf("%s: skipped by #%d\n", fp.URL, targets[0].Number)
 				}
 
+			case "hold":
+				// do nothing
+				if *verbose {
+					fmt.Printf("%s: held by #%d\n", fp.URL, targets[0].Number)
+				}
+
 			case "":
 				if newIssue > 0 {
 					// If we already opened a new issue for a build, don't open another one.
@@ -203,6 +292,9 @@ Repeat:
 					if *verbose {
 						fmt.Printf("%s: new issue\n", fp.URL)
 					}
+					if pos, ok := boardIdx[r]; ok {
+						fp.Suspect = fp.bisect(pos)
+					}
 					issue, err := prepareNew(fp)
 					if err != nil {
 						log.Fatal(err)
@@ -212,7 +304,7 @@ Repeat:
 				}
 
 			case "default", "post", "take":
-				for _, issue := range targets {
+				for j, issue := range targets {
 					if !issue.Mentions[fp.URL] && issue.Stale {
 						readComments(issue)
 					}
@@ -225,11 +317,19 @@ Repeat:
 					}
 					if !issue.Mentions[fp.URL] {
 						issue.Post = append(issue.Post, fp)
+						if msg := matches[j].Message(); msg != "" {
+							if issue.Messages == nil {
+								issue.Messages = make(map[*FailurePost]string)
+							}
+							issue.Messages[fp] = msg
+						}
 					}
 				}
 			}
 		}
 	}
+	saveRecording()
+
 	for _, issue := range issues {
 		if issue.Number == 0 && len(issue.Post) >= tooManyToBeFlakes && issue.Post[0].Top {
 			// New issue. Check if it is failing consistently at top.
@@ -245,6 +345,18 @@ Repeat:
 		}
 	}
 
+	if hist != nil {
+		for _, issue := range issues {
+			for _, fp := range issue.Post {
+				s, err := hist.SignatureStats(fp.Signature())
+				if err != nil {
+					log.Printf("history: %v", err)
+				}
+				fp.History = s
+			}
+		}
+	}
+
 	if query != nil {
 		format := (*FailurePost).Text
 		if *md {
@@ -254,6 +366,9 @@ Repeat:
 			if i > 0 {
 				fmt.Printf("\n")
 			}
+			if msg := query.Messages[fp]; msg != "" {
+				fmt.Printf("%s\n", msg)
+			}
 			os.Stdout.WriteString(format(fp))
 		}
 		if *md {
@@ -262,6 +377,11 @@ Repeat:
 		return
 	}
 
+	if replaying != nil {
+		writeReport(os.Stdout, issues)
+		return
+	}
+
 	posts := 0
 	for _, issue := range issues {
 		if len(issue.Post) > 0 {
@@ -409,34 +529,52 @@ func skipBrokenBuilders(boards []*Dashboard) {
 	}
 }
 
-// run runs the scripts in issues on record.
-// It returns the desired action (skip, post, default)
-// as well as the list of target issues (for post or default).
-func run(issues []*Issue, record script.Record) (action string, targets []*Issue) {
-	var def, post []*Issue
+// run runs the scripts in issues on record, which is in batch.
+// It returns the desired action (skip, post, default, hold)
+// as well as the list of target issues (for post or default)
+// and the matches of their scripts. The action is hold when
+// a rule's pattern matches but its aggregate conditions don't
+// hold yet, in which case no new issue should be opened.
+func run(issues []*Issue, batch *script.Batch, record script.Record) (action string, targets []*Issue, matches []*script.Match) {
+	var def, post, held []*Issue
+	var defMatches, postMatches []*script.Match
 
 	for _, issue := range issues {
 		if issue.Script != nil {
-			switch issue.Script.Action(record) {
+			m := issue.Script.Run(batch, record)
+			if m == nil {
+				if issue.Script.Held(batch, record) {
+					held = append(held, issue)
+				}
+				continue
+			}
+			switch m.Rule.Action {
 			case "skip":
-				return "skip", []*Issue{issue}
+				return "skip", []*Issue{issue}, []*script.Match{m}
 			case "take":
 				println("TAKE", issue.Number)
 			case "default":
 				def = append(def, issue)
+				defMatches = append(defMatches, m)
 			case "post":
 				post = append(post, issue)
+				postMatches = append(postMatches, m)
 			}
 		}
 	}
 
 	if len(post) > 0 {
-		return "post", post
+		return "post", post, postMatches
 	}
 	if len(def) > 0 {
-		return "default", def[:1]
+		return "default", def[:1], defMatches[:1]
+	}
+	if len(held) > 0 {
+		// An issue's rule matches but its aggregate conditions
+		// don't hold yet. Don't open a new issue for the failure.
+		return "hold", held[:1], nil
 	}
-	return "", nil
+	return "", nil, nil
 }
 
 // FailurePost is a failure to be posted on an issue.
@@ -447,21 +585,79 @@ type FailurePost struct {
 	Pkg     string
 	Test    string
 	Snippet string
+
+	// Parsed is the failure as extracted from the log, if the log
+	// contains one that logparser recognizes.
+	Parsed *logparser.Fail
+
+	// History is the recorded history of failures with the same
+	// signature, including this one, if there is a -history database.
+	History *history.Stats
+
+	// Suspect is the range of commits that the failure started at
+	// on its builder, for a failure that is filed as a new issue.
+	Suspect *Suspect
+
+	record script.Record // cached Record
+}
+
+// newFailurePosts returns the failures of r to post.
+func newFailurePosts(r *BuildResult) []*FailurePost {
+	fs := coalesceFailures(r.Failures)
+	if len(fs) == 0 {
+		// No test failure, Probably a build failure.
+		// E.g. https://ci.chromium.org/ui/b/8759448820419452721
+		// Make a dummy failure.
+		f := &Failure{
+			Status:  rdbpb.TestStatus_FAIL,
+			LogText: r.StepLogText,
+		}
+		fs = []*Failure{f}
+	}
+	var fps []*FailurePost
+	for _, f := range fs {
+		fps = append(fps, NewFailurePost(r, f))
+	}
+	return fps
 }
 
 func NewFailurePost(r *BuildResult, f *Failure) *FailurePost {
 	pkg, test := splitTestID(f.TestID)
-	snip := snippet(f.LogText)
-	if snip == "" {
-		snip = snippet(r.LogText)
-	}
 	fp := &FailurePost{
 		BuildResult: r,
 		Failure:     f,
 		URL:         buildURL(r.ID),
 		Pkg:         pkg,
 		Test:        test,
-		Snippet:     snip,
+	}
+	switch {
+	case f.TestID != "" && f.LogText != "":
+		// The log of a single test, which ResultDB reported
+		// as a structured test result.
+		res := &logparser.Result{
+			Pkg:     pkg,
+			Test:    test,
+			Outcome: "fail",
+			Elapsed: f.Duration,
+			Output:  f.LogText,
+		}
+		if fails := logparser.FailsFromResults([]*logparser.Result{res}); len(fails) > 0 {
+			fp.Parsed = fails[0]
+		}
+		fp.Snippet = logparser.Shorten(f.LogText)
+	case f.LogText != "":
+		// The log of the failed step of a build without test
+		// failures, which may contain go test -json output.
+		fp.Parsed = logparser.ParseLog(f.LogText)[0]
+		fp.Snippet = logparser.Shorten(f.LogText)
+	default:
+		// Fall back to the log of the whole build, which for a build
+		// failure also tells what failed to build.
+		fp.Parsed = logparser.ParseLog(r.LogText)[0]
+		fp.Snippet = fp.Parsed.Snippet
+		if fp.Snippet == "" {
+			fp.Snippet = logparser.Shorten(r.LogText)
+		}
 	}
 	return fp
 }
@@ -470,12 +666,16 @@ func NewFailurePost(r *BuildResult, f *Failure) *FailurePost {
 // It must be in sync with the Record method below.
 var fields = []string{
 	"",
-	"section", // not used, keep for compatibility with old watchflakes
+	"section",
 	"pkg",
 	"test",
 	"mode",
 	"output",
 	"snippet",
+	"message",
+	"signature",
+	"parsedpkg",
+	"parsedmode",
 	"date",
 	"builder",
 	"repo",
@@ -501,12 +701,30 @@ func (fp *FailurePost) Record() script.Record {
 		"status":  fp.Failure.Status.String(),
 	}
 	m[""] = m["output"] // default field for `regexp` search (as opposed to field ~ `regexp`)
+	if f := fp.Parsed; f != nil {
+		// The parsed package and mode are kept apart from pkg and mode,
+		// which existing scripts match against the test ID and build status.
+		m["section"] = f.Section
+		m["message"] = f.Message
+		m["parsedpkg"] = f.Pkg
+		m["parsedmode"] = f.Mode
+	}
+	m["signature"] = fp.Signature()
 	if fp.IsBuildFailure() {
 		m["mode"] = "build"
 	}
 	return m
 }
 
+// Signature returns the signature of the failure, which groups
+// it with failures that are likely to have the same cause.
+func (fp *FailurePost) Signature() string {
+	if fp.Parsed != nil {
+		return fp.Parsed.Signature()
+	}
+	return fmt.Sprintf("||%s|%s||", fp.Pkg, fp.Test)
+}
+
 func printRecord(r script.Record, verbose bool) {
 	fmt.Printf("%s %s %s %s %s %s\n", r["date"], r["builder"], r["goos"], r["goarch"],
 		r["pkg"], r["test"])
@@ -559,8 +777,12 @@ func (fp *FailurePost) String() string {
 
 // Markdown returns Markdown suitable for posting to GitHub.
 func (fp *FailurePost) Markdown() string {
-	return fmt.Sprintf("<details><summary>%s (<a href=\"%s\">log</a>)</summary>\n\n%s</details>\n",
-		fp.String(), fp.URL, indent(spaces[:4], fp.Snippet))
+	var hist string
+	if fp.History != nil {
+		hist = "Flake history: " + fp.History.String() + ".\n\n"
+	}
+	return fmt.Sprintf("<details><summary>%s (<a href=\"%s\">log</a>)</summary>\n\n%s%s</details>\n",
+		fp.String(), fp.URL, hist, indent(spaces[:4], fp.Snippet))
 }
 
 // Text returns text suitable for reading in interactive use or debug logging.
@@ -587,52 +809,6 @@ func shortPkg(pkg string) string {
 	return pkg
 }
 
-// shorten the output lines to form a snippet
-func snippet(log string) string {
-	lines := strings.SplitAfter(log, "\n")
-
-	// Remove beginning and trailing blank lines.
-	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
-		lines = lines[1:]
-	}
-	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
-		lines = lines[:len(lines)-1]
-	}
-
-	// If we have more than 30 lines, make the snippet by taking the first 10,
-	// the last 10, and possibly a middle 10. The middle 10 is included when
-	// the interior lines (between the first and last 10) contain an important-looking
-	// message like "panic:" or "--- FAIL:". The middle 10 start at the important-looking line.
-	// such as
-	if len(lines) > 30 {
-		var keep []string
-		keep = append(keep, lines[:10]...)
-		dots := true
-		for i := 10; i < len(lines)-10; i++ {
-			s := strings.TrimSpace(lines[i])
-			if strings.HasPrefix(s, "panic:") || strings.HasPrefix(s, "fatal error:") || strings.HasPrefix(s, "--- FAIL:") || strings.Contains(s, ": internal compiler error:") {
-				if i > 10 {
-					keep = append(keep, "...\n")
-				}
-				end := i + 10
-				if end >= len(lines)-10 {
-					dots = false
-					end = len(lines) - 10
-				}
-				keep = append(keep, lines[i:end]...)
-				break
-			}
-		}
-		if dots {
-			keep = append(keep, "...\n")
-		}
-		keep = append(keep, lines[len(lines)-10:]...)
-		lines = keep
-	}
-
-	return strings.Join(lines, "")
-}
-
 // If a build that has too many failures, the build is probably broken
 // (e.g. timeout, crash). Coalesce the failures and report maxFailPerBuild
 // of them.
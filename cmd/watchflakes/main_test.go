@@ -0,0 +1,62 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/build/cmd/watchflakes/internal/script"
+	"rsc.io/github"
+)
+
+func TestRunHold(t *testing.T) {
+	newIssue := func(num int, text string) *Issue {
+		s, errs := script.Parse("script", text, fields)
+		if len(errs) > 0 {
+			t.Fatalf("parsing #%d: %v", num, errs[0])
+		}
+		return &Issue{Issue: &github.Issue{Number: num}, Script: s, ScriptText: text}
+	}
+	issues := []*Issue{
+		newIssue(1, "#!watchflakes\npost <- pkg == \"net\" && test == \"TestDial\" when count >= 3\n"),
+		newIssue(2, "#!watchflakes\npost <- pkg == \"os\" when count >= 2\n"),
+	}
+	date := func(day int) string {
+		return time.Date(2026, time.January, day, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	records := []script.Record{
+		{"pkg": "net", "test": "TestDial", "date": date(1)},
+		{"pkg": "net", "test": "TestDial", "date": date(2)},
+		{"pkg": "os", "test": "TestOpen", "date": date(3)},
+		{"pkg": "os", "test": "TestStat", "date": date(4)},
+		{"pkg": "io", "test": "TestCopy", "date": date(5)},
+	}
+	batch := script.NewBatch(records)
+
+	tests := []struct {
+		record script.Record
+		action string
+		target int
+	}{
+		// Too few TestDial failures for #1 yet: hold them
+		// rather than opening a new issue.
+		{records[0], "hold", 1},
+		{records[1], "hold", 1},
+		{records[2], "post", 2},
+		{records[3], "post", 2},
+		{records[4], "", 0},
+	}
+	for _, tt := range tests {
+		action, targets, _ := run(issues, batch, tt.record)
+		target := 0
+		if len(targets) > 0 {
+			target = targets[0].Number
+		}
+		if action != tt.action || target != tt.target {
+			t.Errorf("run(%s %s) = %q #%d, want %q #%d", tt.record["pkg"], tt.record["test"], action, target, tt.action, tt.target)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+
+	"golang.org/x/build/cmd/watchflakes/internal/history"
+)
+
+var historyFuncs = template.FuncMap{
+	"buildURL":  buildURL,
+	"shortHash": shortHash,
+	"percent":   func(f float64) float64 { return 100 * f },
+}
+
+const historyStyle = `<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { text-align: left; vertical-align: top; padding: 0.2em 0.6em; border-bottom: 1px solid #ddd; }
+td.num { text-align: right; }
+.sig { font-family: monospace; }
+</style>
+`
+
+var historyListTemplate = template.Must(template.New("list").Funcs(historyFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<title>watchflakes history</title>
+` + historyStyle + `
+<h1>watchflakes history</h1>
+<form>
+Package: <input name="pkg" value="{{.Query.Pkg}}">
+New since commit: <input name="since" value="{{.Query.NewSince}}">
+<input type="submit" value="Filter">
+</form>
+{{with .Err}}<p>Error: {{.}}</p>{{end}}
+<table>
+<tr><th>Failures</th><th>Builds</th><th>Rate</th><th>Test</th><th>Message</th><th>Builders</th><th>First seen</th><th>Last seen</th></tr>
+{{range .List}}<tr>
+<td class="num"><a href="failures?sig={{.Signature}}">{{.Failures}}</a></td>
+<td class="num">{{.Builds}}</td>
+<td class="num">{{printf "%.1f%%" (percent .Rate)}}</td>
+<td><a href="failures?pkg={{.Pkg}}&amp;test={{.Test}}">{{.Pkg}} {{.Test}}</a></td>
+<td>{{.Message}}</td>
+<td>{{len .Builders}}</td>
+<td>{{.FirstSeen.Format "2006-01-02"}} {{shortHash .FirstCommit}}</td>
+<td>{{.LastSeen.Format "2006-01-02"}}</td>
+</tr>
+{{end}}</table>
+</html>
+`))
+
+var historyFailuresTemplate = template.Must(template.New("failures").Funcs(historyFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<title>watchflakes history: {{.Stats.Pkg}} {{.Stats.Test}}</title>
+` + historyStyle + `
+<p><a href="./">all failures</a></p>
+{{with .Stats}}
+<h1>{{.Pkg}} {{.Test}}</h1>
+{{with .Signature}}<p class="sig">{{.}}</p>{{end}}
+<p>{{.}}.</p>
+<p>Builders: {{range $i, $b := .Builders}}{{if $i}}, {{end}}{{$b}}{{end}}</p>
+{{end}}
+<table>
+<tr><th>Commit time</th><th>Builder</th><th>Commit</th><th>Message</th><th>Build</th></tr>
+{{range .Failures}}<tr>
+<td>{{.CommitTime.Format "2006-01-02 15:04"}}</td>
+<td>{{.Builder}}</td>
+<td>{{.Repo}}@{{shortHash .Commit}}{{with .GoCommit}} go@{{shortHash .}}{{end}}</td>
+<td>{{.Message}}</td>
+<td><a href="{{buildURL .ID}}">{{.ID}}</a></td>
+</tr>
+{{end}}</table>
+</html>
+`))
+
+// historyServer serves a web UI of the failure history in db.
+type historyServer struct {
+	db *history.DB
+}
+
+func (s *historyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		buf bytes.Buffer
+		err error
+	)
+	switch r.URL.Path {
+	case "/":
+		q := history.Query{Pkg: r.FormValue("pkg"), NewSince: r.FormValue("since"), Limit: 500}
+		data := struct {
+			Query history.Query
+			List  []*history.Stats
+			Err   error
+		}{Query: q}
+		// A bad filter, such as an unknown commit,
+		// is reported on the page rather than as an error.
+		data.List, data.Err = s.db.List(q)
+		err = historyListTemplate.Execute(&buf, data)
+	case "/failures":
+		sig, pkg, test := r.FormValue("sig"), r.FormValue("pkg"), r.FormValue("test")
+		var data struct {
+			Stats    *history.Stats
+			Failures []*history.FailureRecord
+		}
+		if sig != "" {
+			data.Stats, err = s.db.SignatureStats(sig)
+		} else {
+			data.Stats, err = s.db.TestStats(pkg, test)
+		}
+		if err == nil && data.Stats == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if err == nil {
+			data.Failures, err = s.db.Failures(sig, pkg, test, 500)
+		}
+		if err == nil {
+			err = historyFailuresTemplate.Execute(&buf, data)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("serving history: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,371 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gerrit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"time"
+)
+
+// Possible values for the Event Type field.
+// See https://gerrit-review.googlesource.com/Documentation/cmd-stream-events.html#events
+const (
+	EventAssigneeChanged     = "assignee-changed"
+	EventChangeAbandoned     = "change-abandoned"
+	EventChangeDeleted       = "change-deleted"
+	EventChangeMerged        = "change-merged"
+	EventChangeRestored      = "change-restored"
+	EventCommentAdded        = "comment-added"
+	EventDroppedOutput       = "dropped-output"
+	EventHashtagsChanged     = "hashtags-changed"
+	EventPatchSetCreated     = "patchset-created"
+	EventPrivateStateChanged = "private-state-changed"
+	EventProjectCreated      = "project-created"
+	EventRefUpdated          = "ref-updated"
+	EventReviewerAdded       = "reviewer-added"
+	EventReviewerDeleted     = "reviewer-deleted"
+	EventTopicChanged        = "topic-changed"
+	EventVoteDeleted         = "vote-deleted"
+	EventWIPStateChanged     = "wip-state-changed"
+)
+
+// Event is a Gerrit event, as sent by the stream-events SSH command
+// and the events-log plugin. Which fields are set depends on Type.
+// See https://gerrit-review.googlesource.com/Documentation/cmd-stream-events.html#events
+type Event struct {
+	Type      string `json:"type"`
+	CreatedOn int64  `json:"eventCreatedOn"` // seconds since the Unix epoch
+
+	Change    *EventChange    `json:"change,omitempty"`
+	PatchSet  *EventPatchSet  `json:"patchSet,omitempty"`
+	RefUpdate *EventRefUpdate `json:"refUpdate,omitempty"`
+
+	// Accounts that caused the event, for the event types they
+	// apply to.
+	Uploader  *EventAccount `json:"uploader,omitempty"`  // patchset-created
+	Author    *EventAccount `json:"author,omitempty"`    // comment-added
+	Submitter *EventAccount `json:"submitter,omitempty"` // change-merged, ref-updated
+	Abandoner *EventAccount `json:"abandoner,omitempty"` // change-abandoned
+	Restorer  *EventAccount `json:"restorer,omitempty"`  // change-restored
+	Reviewer  *EventAccount `json:"reviewer,omitempty"`  // reviewer-added, reviewer-deleted
+	Remover   *EventAccount `json:"remover,omitempty"`   // reviewer-deleted, vote-deleted
+	Editor    *EventAccount `json:"editor,omitempty"`    // hashtags-changed
+	Changer   *EventAccount `json:"changer,omitempty"`   // assignee-changed, topic-changed, *-state-changed
+	Deleter   *EventAccount `json:"deleter,omitempty"`   // change-deleted
+
+	Comment   string          `json:"comment,omitempty"`   // comment-added
+	Approvals []EventApproval `json:"approvals,omitempty"` // comment-added, reviewer-deleted, vote-deleted
+	NewRev    string          `json:"newRev,omitempty"`    // change-merged
+	Reason    string          `json:"reason,omitempty"`    // change-abandoned, change-restored
+	OldTopic  string          `json:"oldTopic,omitempty"`  // topic-changed
+
+	// hashtags-changed
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Hashtags []string `json:"hashtags,omitempty"`
+
+	// project-created
+	ProjectName string `json:"projectName,omitempty"`
+	HeadName    string `json:"headName,omitempty"`
+
+	// Raw is the event's JSON encoding, as received.
+	Raw json.RawMessage `json:"-"`
+}
+
+// Time returns the time the event was created.
+func (e *Event) Time() time.Time { return time.Unix(e.CreatedOn, 0) }
+
+// key returns a string identifying e, to drop events received twice
+// when resuming a stream.
+func (e *Event) key() string {
+	var change int
+	var patchSet int
+	var ref, rev string
+	if e.Change != nil {
+		change = e.Change.Number
+	}
+	if e.PatchSet != nil {
+		patchSet = e.PatchSet.Number
+	}
+	if e.RefUpdate != nil {
+		ref, rev = e.RefUpdate.RefName, e.RefUpdate.NewRev
+	}
+	return fmt.Sprintf("%s %d %d %d %s %s %q", e.Type, e.CreatedOn, change, patchSet, ref, rev, e.Comment)
+}
+
+// ParseEvent decodes a JSON-encoded Gerrit event.
+func ParseEvent(data []byte) (*Event, error) {
+	e := new(Event)
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	if e.Type == "" {
+		return nil, fmt.Errorf("gerrit: event has no type: %.100s", data)
+	}
+	e.Raw = append(json.RawMessage(nil), data...)
+	return e, nil
+}
+
+// EventAccount is the account attribute of a Gerrit event.
+// See https://gerrit-review.googlesource.com/Documentation/json.html#account
+type EventAccount struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// EventChange is the change attribute of a Gerrit event.
+// See https://gerrit-review.googlesource.com/Documentation/json.html#change
+type EventChange struct {
+	Project       string        `json:"project"`
+	Branch        string        `json:"branch"`
+	Topic         string        `json:"topic,omitempty"`
+	ID            string        `json:"id"` // the Change-Id
+	Number        int           `json:"number"`
+	Subject       string        `json:"subject"`
+	Owner         *EventAccount `json:"owner,omitempty"`
+	URL           string        `json:"url"`
+	CommitMessage string        `json:"commitMessage,omitempty"`
+	CreatedOn     int64         `json:"createdOn"`
+	Status        string        `json:"status"` // "NEW", "MERGED" or "ABANDONED"
+	WIP           bool          `json:"wip,omitempty"`
+	Private       bool          `json:"private,omitempty"`
+}
+
+// EventPatchSet is the patchSet attribute of a Gerrit event.
+// See https://gerrit-review.googlesource.com/Documentation/json.html#patchSet
+type EventPatchSet struct {
+	Number         int           `json:"number"`
+	Revision       string        `json:"revision"`
+	Parents        []string      `json:"parents,omitempty"`
+	Ref            string        `json:"ref"`
+	Uploader       *EventAccount `json:"uploader,omitempty"`
+	Author         *EventAccount `json:"author,omitempty"`
+	CreatedOn      int64         `json:"createdOn"`
+	Kind           string        `json:"kind,omitempty"` // "REWORK", "TRIVIAL_REBASE", etc.
+	SizeInsertions int           `json:"sizeInsertions,omitempty"`
+	SizeDeletions  int           `json:"sizeDeletions,omitempty"`
+}
+
+// EventApproval is the approval attribute of a Gerrit event.
+// See https://gerrit-review.googlesource.com/Documentation/json.html#approval
+type EventApproval struct {
+	Type        string        `json:"type"` // the label, such as "Code-Review"
+	Description string        `json:"description,omitempty"`
+	Value       string        `json:"value"`              // such as "+2"
+	OldValue    string        `json:"oldValue,omitempty"` // set if the vote changed
+	GrantedOn   int64         `json:"grantedOn,omitempty"`
+	By          *EventAccount `json:"by,omitempty"`
+}
+
+// EventRefUpdate is the refUpdate attribute of a Gerrit event.
+// See https://gerrit-review.googlesource.com/Documentation/json.html#refUpdate
+type EventRefUpdate struct {
+	OldRev  string `json:"oldRev"`
+	NewRev  string `json:"newRev"`
+	RefName string `json:"refName"`
+	Project string `json:"project"`
+}
+
+// An EventSource is a source of Gerrit events.
+type EventSource interface {
+	// Events calls fn for each event, in order, until ctx is
+	// done, fn returns an error, or the source fails, and returns
+	// that error.
+	//
+	// If since is non-zero, the source first replays events
+	// created at or after since, if it can. Events created in the
+	// same second as since may be sent again, so callers should
+	// use StreamEvents rather than call Events directly.
+	Events(ctx context.Context, since time.Time, fn func(*Event) error) error
+}
+
+// StreamEventsOpt are options for StreamEvents.
+type StreamEventsOpt struct {
+	// MinBackoff and MaxBackoff bound the time to wait before
+	// reconnecting after the source fails. They default to 1
+	// second and 1 minute.
+	MinBackoff, MaxBackoff time.Duration
+
+	// Logf, if non-nil, is used to log reconnections instead of
+	// log.Printf.
+	Logf func(format string, args ...interface{})
+}
+
+// StreamEvents calls fn for each event from src created at or after
+// since, or for each new event if since is zero. When src fails, it
+// reconnects with exponential backoff and resumes from the time of
+// the last event it delivered, dropping duplicates.
+//
+// It returns only once ctx is done or fn returns an error.
+func StreamEvents(ctx context.Context, src EventSource, since time.Time, fn func(*Event) error, opts ...StreamEventsOpt) error {
+	var opt StreamEventsOpt
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.MinBackoff == 0 {
+		opt.MinBackoff = time.Second
+	}
+	if opt.MaxBackoff == 0 {
+		opt.MaxBackoff = time.Minute
+	}
+	logf := opt.Logf
+	if logf == nil {
+		logf = log.Printf
+	}
+
+	// lastSec is the creation time of the last event delivered,
+	// and seen the keys of the events delivered in that second.
+	lastSec := since.Unix()
+	if since.IsZero() {
+		lastSec = 0
+	}
+	seen := make(map[string]bool)
+
+	backoff := opt.MinBackoff
+	for {
+		resume := since
+		if lastSec != 0 {
+			resume = time.Unix(lastSec, 0)
+		}
+		err := src.Events(ctx, resume, func(e *Event) error {
+			if e.Type != EventDroppedOutput {
+				if e.CreatedOn < lastSec || seen[e.key()] {
+					return nil
+				}
+				if e.CreatedOn > lastSec {
+					lastSec = e.CreatedOn
+					seen = make(map[string]bool)
+				}
+				seen[e.key()] = true
+			}
+			backoff = opt.MinBackoff
+			if err := fn(e); err != nil {
+				return eventFuncError{err}
+			}
+			return nil
+		})
+		var fe eventFuncError
+		if errors.As(err, &fe) {
+			return fe.err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logf("gerrit: event stream failed; reconnecting in %v: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > opt.MaxBackoff {
+			backoff = opt.MaxBackoff
+		}
+	}
+}
+
+// eventFuncError wraps an error returned by the StreamEvents caller's
+// function, which is returned rather than retried.
+type eventFuncError struct{ err error }
+
+func (e eventFuncError) Error() string { return e.err.Error() }
+
+// eventsLogTimeFormat is the time format of the events-log plugin's
+// t1 and t2 parameters.
+const eventsLogTimeFormat = "2006-01-02 15:04:05"
+
+// GetEventsLog returns the events recorded by the events-log plugin
+// that were created at or after since and before until. If until is
+// zero, it returns events up to the present.
+//
+// See https://gerrit.googlesource.com/plugins/events-log/+/refs/heads/master/src/main/resources/Documentation/rest-api-events.md
+func (c *Client) GetEventsLog(ctx context.Context, since, until time.Time) ([]*Event, error) {
+	v := url.Values{}
+	v.Set("t1", since.UTC().Format(eventsLogTimeFormat))
+	if !until.IsZero() {
+		v.Set("t2", until.UTC().Format(eventsLogTimeFormat))
+	}
+	var body io.ReadCloser
+	if err := c.do(ctx, nil, "GET", "/plugins/events-log/events/", urlValues(v), respBodyRaw{&body}); err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var events []*Event
+	err := readEvents(body, func(e *Event) error {
+		events = append(events, e)
+		return nil
+	})
+	return events, err
+}
+
+// readEvents calls fn for each newline-separated JSON event in r.
+func readEvents(r io.Reader, fn func(*Event) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 || bytes.HasPrefix(line, []byte(")]}'")) {
+			continue
+		}
+		e, err := ParseEvent(line)
+		if err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// EventsLogSource is an EventSource that polls the events-log plugin.
+type EventsLogSource struct {
+	Client *Client
+
+	// PollInterval is how often to poll for new events. It
+	// defaults to 30 seconds.
+	PollInterval time.Duration
+}
+
+// Events implements EventSource. If since is zero, it starts with
+// events created after the first poll.
+func (s *EventsLogSource) Events(ctx context.Context, since time.Time, fn func(*Event) error) error {
+	interval := s.PollInterval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+	if since.IsZero() {
+		since = time.Now()
+	}
+	for {
+		events, err := s.Client.GetEventsLog(ctx, since, time.Time{})
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+			// Poll from the last event's second again, since more
+			// events may arrive within it; StreamEvents drops
+			// the repeats.
+			since = e.Time()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
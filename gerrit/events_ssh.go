@@ -0,0 +1,118 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHEventSource is an EventSource that runs Gerrit's stream-events
+// command over SSH.
+//
+// stream-events only reports new events. To replay the events missed
+// while disconnected, set Backfill to a client for the same server
+// with the events-log plugin installed.
+//
+// See https://gerrit-review.googlesource.com/Documentation/cmd-stream-events.html
+type SSHEventSource struct {
+	Addr   string // host and port, such as "gerrit.example.com:29418"
+	Config *ssh.ClientConfig
+
+	// Backfill, if non-nil, is used to fetch events since the
+	// time passed to Events.
+	Backfill *Client
+}
+
+// errStopEvents stops reading events once the consumer is gone.
+var errStopEvents = errors.New("gerrit: stop reading events")
+
+// Events implements EventSource.
+func (s *SSHEventSource) Events(ctx context.Context, since time.Time, fn func(*Event) error) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, s.Addr, s.Config)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	defer client.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			client.Close()
+		case <-done:
+		}
+	}()
+
+	sess, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := sess.Start("gerrit stream-events"); err != nil {
+		return fmt.Errorf("starting stream-events: %v", err)
+	}
+
+	// Start reading the stream before backfilling, so no events
+	// are missed in between. StreamEvents drops the overlap.
+	ch := make(chan *Event, 1000)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		errc <- readEvents(stdout, func(e *Event) error {
+			select {
+			case ch <- e:
+				return nil
+			case <-done:
+				return errStopEvents
+			}
+		})
+	}()
+
+	if !since.IsZero() && s.Backfill != nil {
+		events, err := s.Backfill.GetEventsLog(ctx, since, time.Time{})
+		if err != nil {
+			return fmt.Errorf("backfilling events: %v", err)
+		}
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-ch:
+			if !ok {
+				if err := <-errc; err != nil {
+					return err
+				}
+				return errors.New("gerrit: stream-events ended")
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+	}
+}
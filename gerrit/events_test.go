@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gerrit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Adapted from the examples at
+// https://gerrit-review.googlesource.com/Documentation/cmd-stream-events.html.
+const (
+	examplePatchSetCreated = `{"uploader":{"name":"Gopher","email":"gopher@golang.org","username":"gopher"},"patchSet":{"number":2,"revision":"4b8d10b9d09a2f21bbd8a5a3d8f82e02b9d3b4a1","parents":["8e3b4c4fbcd49b2b2b8c8b6f6b8a2f2b9d3b4a10"],"ref":"refs/changes/45/12345/2","uploader":{"name":"Gopher","email":"gopher@golang.org"},"createdOn":1700000000,"author":{"name":"Gopher","email":"gopher@golang.org"},"kind":"REWORK","sizeInsertions":10,"sizeDeletions":-2},"change":{"project":"build","branch":"master","id":"I0123456789abcdef0123456789abcdef01234567","number":12345,"subject":"gerrit: add events","owner":{"name":"Gopher","email":"gopher@golang.org"},"url":"https://go-review.googlesource.com/c/build/+/12345","commitMessage":"gerrit: add events\n","createdOn":1699990000,"status":"NEW"},"project":"build","refName":"refs/heads/master","changeKey":{"id":"I0123456789abcdef0123456789abcdef01234567"},"type":"patchset-created","eventCreatedOn":1700000001}`
+	exampleCommentAdded    = `{"author":{"name":"Reviewer","email":"reviewer@golang.org"},"approvals":[{"type":"Code-Review","description":"Code-Review","value":"2","oldValue":"0"}],"comment":"Patch Set 2: Code-Review+2\n\nLGTM","patchSet":{"number":2,"revision":"4b8d10b9d09a2f21bbd8a5a3d8f82e02b9d3b4a1","ref":"refs/changes/45/12345/2","createdOn":1700000000},"change":{"project":"build","branch":"master","id":"I0123456789abcdef0123456789abcdef01234567","number":12345,"subject":"gerrit: add events","url":"https://go-review.googlesource.com/c/build/+/12345","createdOn":1699990000,"status":"NEW"},"type":"comment-added","eventCreatedOn":1700000100}`
+	exampleRefUpdated      = `{"submitter":{"name":"Gopher","email":"gopher@golang.org"},"refUpdate":{"oldRev":"8e3b4c4fbcd49b2b2b8c8b6f6b8a2f2b9d3b4a10","newRev":"4b8d10b9d09a2f21bbd8a5a3d8f82e02b9d3b4a1","refName":"refs/heads/master","project":"build"},"type":"ref-updated","eventCreatedOn":1700000200}`
+)
+
+func TestParseEvent(t *testing.T) {
+	e, err := ParseEvent([]byte(examplePatchSetCreated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != EventPatchSetCreated || e.Change.Number != 12345 || e.Change.Project != "build" ||
+		e.PatchSet.Number != 2 || e.PatchSet.Ref != "refs/changes/45/12345/2" || e.Uploader.Username != "gopher" {
+		t.Errorf("patchset-created event decoded as %+v", e)
+	}
+	if got, want := e.Time(), time.Unix(1700000001, 0); !got.Equal(want) {
+		t.Errorf("Time() = %v; want %v", got, want)
+	}
+	if string(e.Raw) != examplePatchSetCreated {
+		t.Errorf("Raw = %s", e.Raw)
+	}
+
+	e, err = ParseEvent([]byte(exampleCommentAdded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []EventApproval{{Type: "Code-Review", Description: "Code-Review", Value: "2", OldValue: "0"}}
+	if e.Type != EventCommentAdded || !reflect.DeepEqual(e.Approvals, want) || !strings.HasSuffix(e.Comment, "LGTM") {
+		t.Errorf("comment-added event decoded as %+v", e)
+	}
+
+	e, err = ParseEvent([]byte(exampleRefUpdated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != EventRefUpdated || e.RefUpdate.RefName != "refs/heads/master" || e.Submitter.Name != "Gopher" {
+		t.Errorf("ref-updated event decoded as %+v", e)
+	}
+
+	if _, err := ParseEvent([]byte(`{"eventCreatedOn":1}`)); err == nil {
+		t.Errorf("ParseEvent of event without type succeeded")
+	}
+}
+
+func TestStreamEventsResume(t *testing.T) {
+	src := NewFakeEventSource()
+	src.Send(&Event{Type: EventRefUpdated, CreatedOn: 100, RefUpdate: &EventRefUpdate{RefName: "refs/heads/old"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	got := make(chan *Event)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- StreamEvents(ctx, src, time.Unix(200, 0), func(e *Event) error {
+			got <- e
+			if e.Change != nil && e.Change.Number == 3 {
+				return errors.New("done")
+			}
+			return nil
+		}, StreamEventsOpt{MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Logf: t.Logf})
+	}()
+	recv := func() int {
+		t.Helper()
+		select {
+		case e := <-got:
+			return e.Change.Number
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for event")
+			return 0
+		}
+	}
+	change := func(created int64, n int) *Event {
+		return &Event{Type: EventPatchSetCreated, CreatedOn: created, Change: &EventChange{Number: n}}
+	}
+
+	// Two events in the same second, then a dropped connection.
+	src.Send(change(300, 1))
+	src.Send(change(300, 2))
+	if n := recv(); n != 1 {
+		t.Fatalf("first event is for change %d; want 1", n)
+	}
+	if n := recv(); n != 2 {
+		t.Fatalf("second event is for change %d; want 2", n)
+	}
+	src.FailNext(2)
+	src.Disconnect()
+	src.Send(change(301, 3))
+
+	// The consumer reconnects, resumes from second 300, and drops
+	// the two events it already has.
+	if n := recv(); n != 3 {
+		t.Fatalf("third event is for change %d; want 3", n)
+	}
+	if err := <-errc; err == nil || err.Error() != "done" {
+		t.Errorf("StreamEvents = %v; want the callback's error", err)
+	}
+	if n := src.Connections(); n < 4 {
+		t.Errorf("source had %d connections; want at least 4", n)
+	}
+}
+
+func TestGetEventsLog(t *testing.T) {
+	var gotQuery string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/plugins/events-log/events/" {
+			http.NotFound(w, r)
+			return
+		}
+		gotQuery = r.URL.Query().Get("t1")
+		fmt.Fprintf(w, "%s\n%s\n", examplePatchSetCreated, exampleCommentAdded)
+	}))
+	defer s.Close()
+
+	c := NewClient(s.URL, NoAuth)
+	events, err := c.GetEventsLog(context.Background(), time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotQuery != "2023-11-14 22:13:20" {
+		t.Errorf("t1 = %q", gotQuery)
+	}
+	if len(events) != 2 || events[0].Type != EventPatchSetCreated || events[1].Type != EventCommentAdded {
+		t.Errorf("got %d events: %+v", len(events), events)
+	}
+}
+
+func TestSSHEventSource(t *testing.T) {
+	_, hostKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_, chans, reqs, err := ssh.NewServerConn(conn, config)
+		if err != nil {
+			t.Errorf("NewServerConn: %v", err)
+			return
+		}
+		go ssh.DiscardRequests(reqs)
+		for nc := range chans {
+			ch, reqs, err := nc.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				for req := range reqs {
+					var payload struct{ Command string }
+					ssh.Unmarshal(req.Payload, &payload)
+					ok := req.Type == "exec" && payload.Command == "gerrit stream-events"
+					req.Reply(ok, nil)
+					if !ok {
+						continue
+					}
+					fmt.Fprintf(ch, "%s\n%s\n", examplePatchSetCreated, exampleRefUpdated)
+					ch.Close()
+				}
+			}()
+		}
+	}()
+
+	src := &SSHEventSource{
+		Addr: ln.Addr().String(),
+		Config: &ssh.ClientConfig{
+			User:            "gopher",
+			HostKeyCallback: ssh.FixedHostKey(signer.PublicKey()),
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var types []string
+	err = src.Events(ctx, time.Time{}, func(e *Event) error {
+		types = append(types, e.Type)
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "ended") {
+		t.Errorf("Events = %v; want stream ended error", err)
+	}
+	if want := []string{EventPatchSetCreated, EventRefUpdated}; !reflect.DeepEqual(types, want) {
+		t.Errorf("got events %q; want %q", types, want)
+	}
+}
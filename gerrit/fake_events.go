@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// FakeEventSource is an EventSource for tests. It replays the events
+// sent to it, like a server with the events-log plugin.
+type FakeEventSource struct {
+	mu       sync.Mutex
+	events   []*Event
+	changed  chan struct{} // closed and replaced when events or conns change
+	conns    int           // number of calls to Events so far
+	failNext int           // number of connections to fail immediately
+	drop     bool          // disconnect current connections
+}
+
+// NewFakeEventSource returns a FakeEventSource with no events.
+func NewFakeEventSource() *FakeEventSource {
+	return &FakeEventSource{changed: make(chan struct{})}
+}
+
+// ErrFakeDisconnect is returned by FakeEventSource.Events when the
+// connection is dropped by Disconnect or FailNext.
+var ErrFakeDisconnect = errors.New("gerrit: fake event source disconnected")
+
+func (f *FakeEventSource) broadcastLocked() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// Send adds e to the event log and sends it to connected callers of
+// Events. If e.CreatedOn is zero, it's set to the current time.
+func (f *FakeEventSource) Send(e *Event) {
+	if e.CreatedOn == 0 {
+		e.CreatedOn = time.Now().Unix()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, e)
+	f.broadcastLocked()
+}
+
+// Disconnect makes current calls to Events return ErrFakeDisconnect.
+// Events sent before their callers reconnect are replayed to them,
+// if they resume from an earlier time.
+func (f *FakeEventSource) Disconnect() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drop = true
+	f.broadcastLocked()
+}
+
+// FailNext makes the next n calls to Events fail immediately.
+func (f *FakeEventSource) FailNext(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failNext = n
+}
+
+// Connections returns the number of calls to Events so far.
+func (f *FakeEventSource) Connections() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conns
+}
+
+// Events implements EventSource. If since is zero, only events sent
+// after the call are delivered.
+func (f *FakeEventSource) Events(ctx context.Context, since time.Time, fn func(*Event) error) error {
+	f.mu.Lock()
+	f.conns++
+	f.drop = false
+	if f.failNext > 0 {
+		f.failNext--
+		f.mu.Unlock()
+		return ErrFakeDisconnect
+	}
+	next := len(f.events)
+	if !since.IsZero() {
+		next = 0
+		for next < len(f.events) && f.events[next].CreatedOn < since.Unix() {
+			next++
+		}
+	}
+	f.mu.Unlock()
+
+	for {
+		f.mu.Lock()
+		if f.drop {
+			f.mu.Unlock()
+			return ErrFakeDisconnect
+		}
+		pending := f.events[next:]
+		next = len(f.events)
+		changed := f.changed
+		f.mu.Unlock()
+
+		for _, e := range pending {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
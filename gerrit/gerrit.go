@@ -25,6 +25,9 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/build/internal/lru"
+	"golang.org/x/time/rate"
 )
 
 // Client is a Gerrit client.
@@ -35,21 +38,32 @@ type Client struct {
 	// HTTPClient optionally specifies an HTTP client to use
 	// instead of http.DefaultClient.
 	HTTPClient *http.Client
+
+	limiter *rate.Limiter // or nil
+	retry   *RetryPolicy  // or nil
+	cache   *lru.Cache    // of URL to *cacheEntry, or nil
+	stats   clientStats
 }
 
 // NewClient returns a new Gerrit client with the given URL prefix
 // and authentication mode.
 // The url should be just the scheme and hostname. For example, "https://go-review.googlesource.com".
 // If auth is nil, a default is used, or requests are made unauthenticated.
-func NewClient(url string, auth Auth) *Client {
+// By default, requests are neither rate limited, retried nor cached;
+// see WithRateLimit, WithRetry and WithResponseCache.
+func NewClient(url string, auth Auth, opts ...ClientOpt) *Client {
 	if auth == nil {
 		// TODO(bradfitz): use GitCookies auth, once that exists
 		auth = NoAuth
 	}
-	return &Client{
+	c := &Client{
 		url:  strings.TrimSuffix(url, "/"),
 		auth: auth,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *Client) httpClient() *http.Client {
@@ -162,19 +176,15 @@ func (c *Client) do(ctx context.Context, dst interface{}, method, path string, o
 	if arg != nil {
 		u += "?" + arg.Encode()
 	}
-	req, err := http.NewRequestWithContext(ctx, method, u, requestBody)
+	cached := c.cacheLookup(method, u)
+	res, err := c.send(ctx, method, u, requestBody, contentType, cached)
 	if err != nil {
 		return err
 	}
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
-	}
-	if err := c.auth.setAuth(c, req); err != nil {
-		return fmt.Errorf("setting Gerrit auth: %v", err)
-	}
-	res, err := c.httpClient().Do(req)
-	if err != nil {
-		return err
+	if c.cache != nil && method == http.MethodGet {
+		if err := c.cacheResponse(u, res, cached); err != nil {
+			return err
+		}
 	}
 	defer func() {
 		if responseBody != nil && *responseBody != nil {
@@ -320,6 +330,9 @@ type ChangeInfo struct {
 	// ready for review in the past (not as a work in progress).
 	HasReviewStarted bool `json:"has_review_started"`
 
+	// IsPrivate indicates that the change is marked private.
+	IsPrivate bool `json:"is_private"`
+
 	// RevertOf lists the numeric Change-Id of the change that this change reverts.
 	RevertOf int `json:"revert_of"`
 
@@ -569,15 +582,18 @@ func (c *Client) ListChangeComments(ctx context.Context, changeID string) (map[s
 // CommentInfo contains information about an inline comment.
 // See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#comment-info.
 type CommentInfo struct {
-	PatchSet   int          `json:"patch_set,omitempty"`
-	ID         string       `json:"id"`
-	Path       string       `json:"path,omitempty"`
-	Message    string       `json:"message,omitempty"`
-	Updated    TimeStamp    `json:"updated"`
-	Author     *AccountInfo `json:"author,omitempty"`
-	InReplyTo  string       `json:"in_reply_to,omitempty"`
-	Unresolved *bool        `json:"unresolved,omitempty"`
-	Tag        string       `json:"tag,omitempty"`
+	PatchSet   int           `json:"patch_set,omitempty"`
+	ID         string        `json:"id"`
+	Path       string        `json:"path,omitempty"`
+	Side       string        `json:"side,omitempty"` // "REVISION" (default) or "PARENT"
+	Line       int           `json:"line,omitempty"` // 0 for a file comment
+	Range      *CommentRange `json:"range,omitempty"`
+	Message    string        `json:"message,omitempty"`
+	Updated    TimeStamp     `json:"updated"`
+	Author     *AccountInfo  `json:"author,omitempty"`
+	InReplyTo  string        `json:"in_reply_to,omitempty"`
+	Unresolved *bool         `json:"unresolved,omitempty"`
+	Tag        string        `json:"tag,omitempty"`
 }
 
 // ListFiles retrieves a map of filenames to FileInfo's for the given change ID and revision.
@@ -622,7 +638,22 @@ type CommentInput struct {
 	InReplyTo  string `json:"in_reply_to,omitempty"`
 	Unresolved *bool  `json:"unresolved,omitempty"`
 
-	// TODO(haya14busa): more, as needed.
+	// Path is the file the comment is on. It's required when
+	// creating a draft, and ignored in a ReviewInput, whose
+	// Comments are keyed by path.
+	Path string `json:"path,omitempty"`
+
+	Side  string        `json:"side,omitempty"` // "REVISION" (default) or "PARENT"
+	Range *CommentRange `json:"range,omitempty"`
+}
+
+// CommentRange is the range of text a comment applies to.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#comment-range
+type CommentRange struct {
+	StartLine      int `json:"start_line"`
+	StartCharacter int `json:"start_character"`
+	EndLine        int `json:"end_line"`
+	EndCharacter   int `json:"end_character"`
 }
 
 type reviewInfo struct {
@@ -1195,3 +1226,311 @@ func (c *Client) GetCommitsInRefs(ctx context.Context, project string, commits,
 	err := c.do(ctx, &result, "GET", "/projects/"+url.PathEscape(project)+"/commits:in", urlValues(vals))
 	return result, err
 }
+
+// DiffOpt are options for GetDiff.
+type DiffOpt struct {
+	// Base is the patch set number to diff against, instead of
+	// the revision's parent.
+	Base int
+
+	// Context is the number of lines of context around each
+	// change. Zero means Gerrit's default; -1 means the whole file.
+	Context int
+
+	// Whitespace controls how whitespace changes are treated:
+	// "IGNORE_NONE" (the default), "IGNORE_TRAILING",
+	// "IGNORE_LEADING_AND_TRAILING" or "IGNORE_ALL".
+	Whitespace string
+}
+
+// DiffInfo contains information about the diff of a file in a revision.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#diff-info
+type DiffInfo struct {
+	MetaA      *DiffFileMetaInfo `json:"meta_a,omitempty"` // not set if the file was added
+	MetaB      *DiffFileMetaInfo `json:"meta_b,omitempty"` // not set if the file was deleted
+	ChangeType string            `json:"change_type"`      // "ADDED", "MODIFIED", "DELETED", "RENAMED", "COPIED" or "REWRITE"
+	DiffHeader []string          `json:"diff_header"`
+	Content    []DiffContent     `json:"content"`
+	Binary     bool              `json:"binary,omitempty"`
+}
+
+// DiffFileMetaInfo contains meta information about a file diff.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#diff-file-meta-info
+type DiffFileMetaInfo struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Lines       int    `json:"lines"`
+}
+
+// DiffContent is a hunk of a file diff. Exactly one of AB, or A and/or
+// B, is set.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#diff-content
+type DiffContent struct {
+	A    []string `json:"a,omitempty"`    // lines only in side A
+	B    []string `json:"b,omitempty"`    // lines only in side B
+	AB   []string `json:"ab,omitempty"`   // lines common to both sides
+	Skip int      `json:"skip,omitempty"` // number of common lines omitted
+}
+
+// GetDiff returns the diff of a file in a revision.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-diff.
+func (c *Client) GetDiff(ctx context.Context, changeID, revision, path string, opts ...DiffOpt) (*DiffInfo, error) {
+	var opt DiffOpt
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		return nil, errors.New("only 1 option struct supported")
+	}
+	v := url.Values{}
+	if opt.Base != 0 {
+		v.Set("base", strconv.Itoa(opt.Base))
+	}
+	if opt.Context != 0 {
+		if opt.Context < 0 {
+			v.Set("context", "ALL")
+		} else {
+			v.Set("context", strconv.Itoa(opt.Context))
+		}
+	}
+	if opt.Whitespace != "" {
+		v.Set("whitespace", opt.Whitespace)
+	}
+	var diff DiffInfo
+	err := c.do(ctx, &diff, "GET", fmt.Sprintf("/changes/%s/revisions/%s/files/%s/diff", changeID, revision, url.PathEscape(path)), urlValues(v))
+	if err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
+// GetContent gets the contents of a file in a revision of a change.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-content.
+func (c *Client) GetContent(ctx context.Context, changeID, revision, path string) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := c.do(ctx, nil, "GET", fmt.Sprintf("/changes/%s/revisions/%s/files/%s/content", changeID, revision, url.PathEscape(path)), respBodyRaw{&body})
+	if err != nil {
+		return nil, err
+	}
+	return readCloser{
+		Reader: base64.NewDecoder(base64.StdEncoding, body),
+		Closer: body,
+	}, nil
+}
+
+// ListDrafts returns the calling user's draft comments on a change,
+// keyed by file path.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-change-drafts.
+func (c *Client) ListDrafts(ctx context.Context, changeID string) (map[string][]CommentInfo, error) {
+	var m map[string][]CommentInfo
+	if err := c.do(ctx, &m, "GET", "/changes/"+changeID+"/drafts"); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CreateDraft creates a draft comment on a revision. The comment's
+// Path must be set.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#create-draft.
+func (c *Client) CreateDraft(ctx context.Context, changeID, revision string, comment CommentInput) (CommentInfo, error) {
+	var res CommentInfo
+	err := c.do(ctx, &res, "PUT", fmt.Sprintf("/changes/%s/revisions/%s/drafts", changeID, revision),
+		reqBodyJSON{&comment}, wantResStatus(http.StatusCreated))
+	return res, err
+}
+
+// UpdateDraft updates a draft comment on a revision.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#update-draft.
+func (c *Client) UpdateDraft(ctx context.Context, changeID, revision, draftID string, comment CommentInput) (CommentInfo, error) {
+	var res CommentInfo
+	err := c.do(ctx, &res, "PUT", fmt.Sprintf("/changes/%s/revisions/%s/drafts/%s", changeID, revision, draftID),
+		reqBodyJSON{&comment})
+	return res, err
+}
+
+// DeleteDraft deletes a draft comment from a revision.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-draft.
+func (c *Client) DeleteDraft(ctx context.Context, changeID, revision, draftID string) error {
+	return c.do(ctx, nil, "DELETE", fmt.Sprintf("/changes/%s/revisions/%s/drafts/%s", changeID, revision, draftID),
+		wantResStatus(http.StatusNoContent))
+}
+
+// AttentionSetInfo is an account in a change's attention set.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#attention-set-info
+type AttentionSetInfo struct {
+	Account    AccountInfo `json:"account"`
+	LastUpdate TimeStamp   `json:"last_update"`
+	Reason     string      `json:"reason"`
+}
+
+// AttentionSetInput is the input for adding a user to, or removing a
+// user from, a change's attention set.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#attention-set-input
+type AttentionSetInput struct {
+	// User is the account to add. It's ignored when removing,
+	// since the account is part of the request path.
+	User   string `json:"user,omitempty"`
+	Reason string `json:"reason"`
+	Notify string `json:"notify,omitempty"` // "NONE", "OWNER", "OWNER_REVIEWERS" or "ALL"
+}
+
+// GetAttentionSet returns the accounts in a change's attention set.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-attention-set.
+func (c *Client) GetAttentionSet(ctx context.Context, changeID string) ([]AttentionSetInfo, error) {
+	var res []AttentionSetInfo
+	err := c.do(ctx, &res, "GET", "/changes/"+changeID+"/attention")
+	return res, err
+}
+
+// AddToAttentionSet adds input.User to a change's attention set.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#add-to-attention-set.
+func (c *Client) AddToAttentionSet(ctx context.Context, changeID string, input AttentionSetInput) (AccountInfo, error) {
+	var res AccountInfo
+	err := c.do(ctx, &res, "POST", "/changes/"+changeID+"/attention", reqBodyJSON{&input})
+	return res, err
+}
+
+// RemoveFromAttentionSet removes an account from a change's attention set.
+// The accountID is https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html#account-id.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#remove-from-attention-set.
+func (c *Client) RemoveFromAttentionSet(ctx context.Context, changeID, accountID string, input AttentionSetInput) error {
+	input.User = ""
+	return c.do(ctx, nil, "POST", fmt.Sprintf("/changes/%s/attention/%s/delete", changeID, url.PathEscape(accountID)),
+		reqBodyJSON{&input}, wantResStatus(http.StatusNoContent))
+}
+
+// DeleteVote deletes a reviewer's vote on a label of a change.
+// The accountID is https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html#account-id.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-vote.
+func (c *Client) DeleteVote(ctx context.Context, changeID, accountID, label string) error {
+	return c.do(ctx, nil, "POST", fmt.Sprintf("/changes/%s/reviewers/%s/votes/%s/delete", changeID, url.PathEscape(accountID), url.PathEscape(label)),
+		wantResStatus(http.StatusNoContent))
+}
+
+// CherryPickInput contains the options for cherry-picking a revision.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#cherrypick-input
+type CherryPickInput struct {
+	Message        string `json:"message,omitempty"` // defaults to the revision's commit message
+	Destination    string `json:"destination"`       // destination branch
+	Base           string `json:"base,omitempty"`    // commit to cherry-pick onto, instead of the branch head
+	KeepReviewers  bool   `json:"keep_reviewers,omitempty"`
+	AllowConflicts bool   `json:"allow_conflicts,omitempty"`
+	Topic          string `json:"topic,omitempty"`
+}
+
+// CherryPick cherry-picks a revision to another branch, creating a
+// new change.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#cherry-pick.
+func (c *Client) CherryPick(ctx context.Context, changeID, revision string, input CherryPickInput) (ChangeInfo, error) {
+	var res ChangeInfo
+	err := c.do(ctx, &res, "POST", fmt.Sprintf("/changes/%s/revisions/%s/cherrypick", changeID, revision), reqBodyJSON{&input})
+	return res, err
+}
+
+// RebaseInput contains the options for rebasing a change.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#rebase-input
+type RebaseInput struct {
+	// Base is the commit or change to rebase onto. If empty, the
+	// change is rebased onto its parent change or the branch head.
+	Base           string `json:"base,omitempty"`
+	AllowConflicts bool   `json:"allow_conflicts,omitempty"`
+}
+
+// RebaseChange rebases the current revision of a change. A change that
+// can't be rebased, such as one that's already up to date, results in
+// an *HTTPError with status 409 Conflict.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#rebase-change.
+func (c *Client) RebaseChange(ctx context.Context, changeID string, input RebaseInput) (ChangeInfo, error) {
+	var res ChangeInfo
+	err := c.do(ctx, &res, "POST", "/changes/"+changeID+"/rebase", reqBodyJSON{&input})
+	return res, err
+}
+
+// RevertInput contains the options for reverting a change.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#revert-input
+type RevertInput struct {
+	Message        string `json:"message,omitempty"`
+	Topic          string `json:"topic,omitempty"`
+	WorkInProgress bool   `json:"work_in_progress,omitempty"`
+}
+
+// RevertChange creates a change reverting a merged change.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#revert-change.
+func (c *Client) RevertChange(ctx context.Context, changeID string, input RevertInput) (ChangeInfo, error) {
+	var res ChangeInfo
+	err := c.do(ctx, &res, "POST", "/changes/"+changeID+"/revert", reqBodyJSON{&input})
+	return res, err
+}
+
+// MoveInput contains the options for moving a change.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#move-input
+type MoveInput struct {
+	DestinationBranch string `json:"destination_branch"`
+	Message           string `json:"message,omitempty"`
+	KeepAllVotes      bool   `json:"keep_all_votes,omitempty"`
+}
+
+// MoveChange moves a change to another branch of its project.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#move-change.
+func (c *Client) MoveChange(ctx context.Context, changeID string, input MoveInput) (ChangeInfo, error) {
+	var res ChangeInfo
+	err := c.do(ctx, &res, "POST", "/changes/"+changeID+"/move", reqBodyJSON{&input})
+	return res, err
+}
+
+// changeMessage is the input for calls that only take an optional
+// change message, such as Gerrit's WorkInProgressInput and PrivateInput.
+type changeMessage struct {
+	Message string `json:"message,omitempty"`
+}
+
+// SetWorkInProgress marks a change as work in progress, optionally
+// leaving a message.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-work-in-pogress.
+func (c *Client) SetWorkInProgress(ctx context.Context, changeID, message string) error {
+	return c.do(ctx, nil, "POST", "/changes/"+changeID+"/wip", reqBodyJSON{&changeMessage{message}})
+}
+
+// SetReadyForReview marks a change as ready for review, optionally
+// leaving a message.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-ready-for-review.
+func (c *Client) SetReadyForReview(ctx context.Context, changeID, message string) error {
+	return c.do(ctx, nil, "POST", "/changes/"+changeID+"/ready", reqBodyJSON{&changeMessage{message}})
+}
+
+// MarkPrivate marks a change as private, optionally leaving a message.
+// It returns an error matching ErrNotModified if the change was
+// already private.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#mark-private.
+func (c *Client) MarkPrivate(ctx context.Context, changeID, message string) error {
+	err := c.do(ctx, nil, "POST", "/changes/"+changeID+"/private", reqBodyJSON{&changeMessage{message}}, wantResStatus(http.StatusCreated))
+	if he := (*HTTPError)(nil); errors.As(err, &he) && he.Res.StatusCode == http.StatusOK {
+		return fmt.Errorf("change %s is already private: %w", changeID, ErrNotModified)
+	}
+	return err
+}
+
+// UnmarkPrivate unmarks a change as private, optionally leaving a message.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#unmark-private.
+func (c *Client) UnmarkPrivate(ctx context.Context, changeID, message string) error {
+	return c.do(ctx, nil, "POST", "/changes/"+changeID+"/private.delete", reqBodyJSON{&changeMessage{message}}, wantResStatus(http.StatusNoContent))
+}
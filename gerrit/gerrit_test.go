@@ -6,11 +6,13 @@ package gerrit
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -339,3 +341,262 @@ func TestGetProjectTags(t *testing.T) {
 		}
 	}
 }
+
+func TestChangeEndpoints(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		call       func(c *Client) (interface{}, error)
+		wantMethod string
+		wantURI    string
+		wantBody   string // JSON; empty for none
+		status     int
+		resBody    string
+		want       interface{}
+		wantErr    error // matched with errors.Is
+	}{
+		{
+			name: "GetDiff",
+			call: func(c *Client) (interface{}, error) {
+				return c.GetDiff(ctx, "123", "current", "dir/a.go", DiffOpt{Base: 1, Context: -1})
+			},
+			wantMethod: "GET",
+			wantURI:    "/changes/123/revisions/current/files/dir%2Fa.go/diff?base=1&context=ALL",
+			status:     200,
+			resBody:    `{"meta_a":{"name":"dir/a.go","lines":2},"meta_b":{"name":"dir/a.go","lines":2},"change_type":"MODIFIED","content":[{"ab":["package a"]},{"a":["// old"],"b":["// new"]}]}`,
+			want: &DiffInfo{
+				MetaA:      &DiffFileMetaInfo{Name: "dir/a.go", Lines: 2},
+				MetaB:      &DiffFileMetaInfo{Name: "dir/a.go", Lines: 2},
+				ChangeType: "MODIFIED",
+				Content:    []DiffContent{{AB: []string{"package a"}}, {A: []string{"// old"}, B: []string{"// new"}}},
+			},
+		},
+		{
+			name: "GetContent",
+			call: func(c *Client) (interface{}, error) {
+				rc, err := c.GetContent(ctx, "123", "2", "a.go")
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				b, err := io.ReadAll(rc)
+				return string(b), err
+			},
+			wantMethod: "GET",
+			wantURI:    "/changes/123/revisions/2/files/a.go/content",
+			status:     200,
+			resBody:    "cGFja2FnZSBhCg==",
+			want:       "package a\n",
+		},
+		{
+			name: "CreateDraft",
+			call: func(c *Client) (interface{}, error) {
+				return c.CreateDraft(ctx, "123", "current", CommentInput{Path: "a.go", Line: 3, Message: "typo"})
+			},
+			wantMethod: "PUT",
+			wantURI:    "/changes/123/revisions/current/drafts",
+			wantBody:   `{"path":"a.go","line":3,"message":"typo"}`,
+			status:     201,
+			resBody:    `{"id":"d1","path":"a.go","line":3,"message":"typo"}`,
+			want:       CommentInfo{ID: "d1", Path: "a.go", Line: 3, Message: "typo"},
+		},
+		{
+			name: "DeleteDraft",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.DeleteDraft(ctx, "123", "current", "d1")
+			},
+			wantMethod: "DELETE",
+			wantURI:    "/changes/123/revisions/current/drafts/d1",
+			status:     204,
+		},
+		{
+			name: "ListDrafts",
+			call: func(c *Client) (interface{}, error) {
+				return c.ListDrafts(ctx, "123")
+			},
+			wantMethod: "GET",
+			wantURI:    "/changes/123/drafts",
+			status:     200,
+			resBody:    `{"a.go":[{"id":"d1","line":3,"message":"typo"}]}`,
+			want:       map[string][]CommentInfo{"a.go": {{ID: "d1", Line: 3, Message: "typo"}}},
+		},
+		{
+			name: "AddToAttentionSet",
+			call: func(c *Client) (interface{}, error) {
+				return c.AddToAttentionSet(ctx, "123", AttentionSetInput{User: "1000", Reason: "ping"})
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/attention",
+			wantBody:   `{"user":"1000","reason":"ping"}`,
+			status:     200,
+			resBody:    `{"_account_id":1000}`,
+			want:       AccountInfo{NumericID: 1000},
+		},
+		{
+			name: "RemoveFromAttentionSet",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.RemoveFromAttentionSet(ctx, "123", "gopher@golang.org", AttentionSetInput{User: "ignored", Reason: "done"})
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/attention/gopher@golang.org/delete",
+			wantBody:   `{"reason":"done"}`,
+			status:     204,
+		},
+		{
+			name: "GetAttentionSet",
+			call: func(c *Client) (interface{}, error) {
+				return c.GetAttentionSet(ctx, "123")
+			},
+			wantMethod: "GET",
+			wantURI:    "/changes/123/attention",
+			status:     200,
+			resBody:    `[{"account":{"_account_id":1000},"reason":"Reviewer was added"}]`,
+			want:       []AttentionSetInfo{{Account: AccountInfo{NumericID: 1000}, Reason: "Reviewer was added"}},
+		},
+		{
+			name: "DeleteVote",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.DeleteVote(ctx, "123", "1000", "Code-Review")
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/reviewers/1000/votes/Code-Review/delete",
+			status:     204,
+		},
+		{
+			name: "CherryPick",
+			call: func(c *Client) (interface{}, error) {
+				return c.CherryPick(ctx, "123", "current", CherryPickInput{Destination: "release-branch.go1.22"})
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/revisions/current/cherrypick",
+			wantBody:   `{"destination":"release-branch.go1.22"}`,
+			status:     200,
+			resBody:    `{"_number":124,"branch":"release-branch.go1.22"}`,
+			want:       ChangeInfo{ChangeNumber: 124, Branch: "release-branch.go1.22"},
+		},
+		{
+			name: "RebaseChange conflict",
+			call: func(c *Client) (interface{}, error) {
+				_, err := c.RebaseChange(ctx, "123", RebaseInput{})
+				return nil, err
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/rebase",
+			wantBody:   `{}`,
+			status:     409,
+			resBody:    "Change is already up to date.",
+			wantErr:    &HTTPError{},
+		},
+		{
+			name: "RevertChange",
+			call: func(c *Client) (interface{}, error) {
+				return c.RevertChange(ctx, "123", RevertInput{Message: "Revert it."})
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/revert",
+			wantBody:   `{"message":"Revert it."}`,
+			status:     200,
+			resBody:    `{"_number":125,"revert_of":123}`,
+			want:       ChangeInfo{ChangeNumber: 125, RevertOf: 123},
+		},
+		{
+			name: "MoveChange",
+			call: func(c *Client) (interface{}, error) {
+				return c.MoveChange(ctx, "123", MoveInput{DestinationBranch: "dev"})
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/move",
+			wantBody:   `{"destination_branch":"dev"}`,
+			status:     200,
+			resBody:    `{"_number":123,"branch":"dev"}`,
+			want:       ChangeInfo{ChangeNumber: 123, Branch: "dev"},
+		},
+		{
+			name: "SetWorkInProgress",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.SetWorkInProgress(ctx, "123", "")
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/wip",
+			wantBody:   `{}`,
+			status:     200,
+		},
+		{
+			name: "SetReadyForReview",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.SetReadyForReview(ctx, "123", "PTAL")
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/ready",
+			wantBody:   `{"message":"PTAL"}`,
+			status:     200,
+		},
+		{
+			name: "MarkPrivate already private",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.MarkPrivate(ctx, "123", "")
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/private",
+			wantBody:   `{}`,
+			status:     200,
+			wantErr:    ErrNotModified,
+		},
+		{
+			name: "UnmarkPrivate",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.UnmarkPrivate(ctx, "123", "")
+			},
+			wantMethod: "POST",
+			wantURI:    "/changes/123/private.delete",
+			wantBody:   `{}`,
+			status:     204,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.wantMethod || r.URL.RequestURI() != tt.wantURI {
+					t.Errorf("got request %s %s; want %s %s", r.Method, r.URL.RequestURI(), tt.wantMethod, tt.wantURI)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if tt.wantBody == "" {
+					if len(body) != 0 {
+						t.Errorf("got request body %s; want none", body)
+					}
+				} else {
+					var got, want interface{}
+					json.Unmarshal(body, &got)
+					json.Unmarshal([]byte(tt.wantBody), &want)
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("got request body %s; want %s", body, tt.wantBody)
+					}
+				}
+				w.WriteHeader(tt.status)
+				if tt.status/100 == 2 && tt.resBody != "" && !strings.HasSuffix(tt.wantURI, "/content") {
+					io.WriteString(w, ")]}'\n")
+				}
+				io.WriteString(w, tt.resBody)
+			}))
+			defer s.Close()
+
+			got, err := tt.call(NewClient(s.URL, NoAuth))
+			if tt.wantErr != nil {
+				if he, ok := tt.wantErr.(*HTTPError); ok {
+					if !errors.As(err, &he) {
+						t.Fatalf("got error %v; want *HTTPError", err)
+					}
+				} else if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v; want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v; want %#v", got, tt.want)
+			}
+		})
+	}
+}
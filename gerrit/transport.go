@@ -0,0 +1,294 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"golang.org/x/build/internal/lru"
+	"golang.org/x/time/rate"
+)
+
+// ClientOpt is an option for NewClient.
+type ClientOpt func(*Client)
+
+// WithRateLimit limits the client to r requests per second, with
+// bursts of up to burst requests. Retries count against the limit.
+func WithRateLimit(r rate.Limit, burst int) ClientOpt {
+	return func(c *Client) {
+		c.limiter = rate.NewLimiter(r, burst)
+	}
+}
+
+// RetryPolicy configures how a client retries failed requests.
+//
+// Only idempotent requests (GET and HEAD) are retried. A request is
+// retried if it fails with a network error or with one of the
+// statuses 429, 500, 502, 503 or 504.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the
+	// first one. If zero, 4 is used.
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff bound the delay between attempts,
+	// which doubles after each attempt. If zero, 500ms and 30s are used.
+	MinBackoff, MaxBackoff time.Duration
+
+	// MaxRetryAfter is the longest delay requested by a Retry-After
+	// response header that is honored. A response asking for a longer
+	// delay, or one that would pass the context's deadline, is
+	// returned as is. If zero, 5 minutes is used.
+	MaxRetryAfter time.Duration
+}
+
+// WithRetry makes the client retry idempotent requests that fail
+// with transient errors, according to p.
+func WithRetry(p RetryPolicy) ClientOpt {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = 4
+	}
+	if p.MinBackoff == 0 {
+		p.MinBackoff = 500 * time.Millisecond
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = 30 * time.Second
+	}
+	if p.MaxRetryAfter == 0 {
+		p.MaxRetryAfter = 5 * time.Minute
+	}
+	return func(c *Client) {
+		c.retry = &p
+	}
+}
+
+// WithResponseCache makes the client keep the bodies of up to
+// maxEntries GET responses that carry an ETag header, and revalidate
+// them with If-None-Match. A 304 Not Modified response is then
+// served from the cache.
+func WithResponseCache(maxEntries int) ClientOpt {
+	return func(c *Client) {
+		c.cache = lru.New(maxEntries)
+	}
+}
+
+// ClientStats are counters of a client's activity.
+type ClientStats struct {
+	Requests    int64 // HTTP requests sent, including retries
+	Retries     int64 // requests retried after a transient error
+	Throttled   int64 // requests delayed by the rate limit
+	CacheHits   int64 // responses served from the cache after a 304
+	CacheMisses int64 // cacheable GET responses fetched in full
+}
+
+type clientStats struct {
+	requests, retries, throttled, cacheHits, cacheMisses atomic.Int64
+}
+
+// Stats returns the client's counters so far.
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		Requests:    c.stats.requests.Load(),
+		Retries:     c.stats.retries.Load(),
+		Throttled:   c.stats.throttled.Load(),
+		CacheHits:   c.stats.cacheHits.Load(),
+		CacheMisses: c.stats.cacheMisses.Load(),
+	}
+}
+
+var (
+	kHost   = tag.MustNewKey("go-build/gerrit/keys/host")
+	kReason = tag.MustNewKey("go-build/gerrit/keys/reason")
+
+	mRetries   = stats.Int64("go-build/gerrit/retries", "Gerrit API requests retried", stats.UnitDimensionless)
+	mCacheHits = stats.Int64("go-build/gerrit/cache_hits", "Gerrit API responses served from the ETag cache", stats.UnitDimensionless)
+)
+
+// Views are the OpenCensus views of the metrics recorded by clients
+// created with WithRetry or WithResponseCache. Register them to
+// export the metrics.
+var Views = []*view.View{
+	{
+		Name:        "go-build/gerrit/retry_count",
+		Description: "Count of retried Gerrit API requests by host and reason",
+		Measure:     mRetries,
+		TagKeys:     []tag.Key{kHost, kReason},
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "go-build/gerrit/cache_hit_count",
+		Description: "Count of Gerrit API responses served from the ETag cache by host",
+		Measure:     mCacheHits,
+		TagKeys:     []tag.Key{kHost},
+		Aggregation: view.Count(),
+	},
+}
+
+// cacheEntry is a cached response body.
+type cacheEntry struct {
+	etag string
+	body []byte
+}
+
+// send sends a request, waiting for the rate limit and retrying
+// according to the client's options. cached, if non-nil, is
+// revalidated with If-None-Match.
+func (c *Client) send(ctx context.Context, method, u string, body io.Reader, contentType string, cached *cacheEntry) (*http.Response, error) {
+	idempotent := method == http.MethodGet || method == http.MethodHead
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if c.limiter.Tokens() < 1 {
+				c.stats.throttled.Add(1)
+			}
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, method, u, body)
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if cached != nil {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if err := c.auth.setAuth(c, req); err != nil {
+			return nil, fmt.Errorf("setting Gerrit auth: %v", err)
+		}
+		c.stats.requests.Add(1)
+		res, err := c.httpClient().Do(req)
+		if c.retry == nil || !idempotent || attempt >= c.retry.MaxAttempts {
+			return res, err
+		}
+		delay, reason, ok := c.retryDelay(ctx, attempt, res, err)
+		if !ok {
+			return res, err
+		}
+		if res != nil {
+			io.Copy(io.Discard, io.LimitReader(res.Body, 4<<10))
+			res.Body.Close()
+		}
+		c.stats.retries.Add(1)
+		stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(kHost, req.URL.Host), tag.Upsert(kReason, reason)}, mRetries.M(1))
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// retryDelay reports whether the result of an attempt should be
+// retried, after how long, and a short description of why.
+func (c *Client) retryDelay(ctx context.Context, attempt int, res *http.Response, err error) (delay time.Duration, reason string, ok bool) {
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, "", false
+		}
+		reason = "error"
+	} else {
+		switch res.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			reason = strconv.Itoa(res.StatusCode)
+		default:
+			return 0, "", false
+		}
+	}
+
+	delay = c.retry.MinBackoff << (attempt - 1)
+	if delay > c.retry.MaxBackoff || delay <= 0 {
+		delay = c.retry.MaxBackoff
+	}
+	if res != nil {
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			if d > c.retry.MaxRetryAfter {
+				return 0, "", false
+			}
+			if d > delay {
+				delay = d
+			}
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return 0, "", false
+	}
+	return delay, reason, true
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is
+// either a number of seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// cacheLookup returns the cached response for the GET request to u, if any.
+func (c *Client) cacheLookup(method, u string) *cacheEntry {
+	if c.cache == nil || method != http.MethodGet {
+		return nil
+	}
+	if v, ok := c.cache.Get(u); ok {
+		return v.(*cacheEntry)
+	}
+	return nil
+}
+
+// cacheResponse handles the response res to a GET request to u when
+// the client has a cache. It turns a 304 response for the cached entry
+// into a 200 response with the cached body, and stores the bodies of
+// 200 responses that carry an ETag. res.Body is replaced as needed.
+func (c *Client) cacheResponse(u string, res *http.Response, cached *cacheEntry) error {
+	if cached != nil && res.StatusCode == http.StatusNotModified {
+		res.Body.Close()
+		res.StatusCode = http.StatusOK
+		res.Status = "200 OK"
+		res.Body = io.NopCloser(bytes.NewReader(cached.body))
+		c.stats.cacheHits.Add(1)
+		stats.RecordWithTags(res.Request.Context(), []tag.Mutator{tag.Upsert(kHost, res.Request.URL.Host)}, mCacheHits.M(1))
+		return nil
+	}
+	etag := res.Header.Get("ETag")
+	if res.StatusCode != http.StatusOK || etag == "" {
+		return nil
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	c.cache.Add(u, &cacheEntry{etag: etag, body: body})
+	c.stats.cacheMisses.Add(1)
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRetry(t *testing.T) {
+	var n atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			io.WriteString(w, ")]}'\n"+`{"_account_id":1000}`)
+		}
+	}))
+	defer s.Close()
+
+	c := NewClient(s.URL, NoAuth, WithRetry(RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+	acct, err := c.GetAccountInfo(context.Background(), "self")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acct.NumericID != 1000 {
+		t.Errorf("got account %d; want 1000", acct.NumericID)
+	}
+	if got, want := c.Stats(), (ClientStats{Requests: 3, Retries: 2}); got != want {
+		t.Errorf("Stats = %+v; want %+v", got, want)
+	}
+
+	// Non-idempotent requests aren't retried.
+	n.Store(0)
+	err = c.SetReview(context.Background(), "123", "current", ReviewInput{Message: "hi"})
+	var he *HTTPError
+	if !errors.As(err, &he) || he.Res.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("SetReview = %v; want 429 error", err)
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	c := NewClient(s.URL, NoAuth, WithRetry(RetryPolicy{MaxRetryAfter: time.Minute}))
+	_, err := c.GetAccountInfo(context.Background(), "self")
+	var he *HTTPError
+	if !errors.As(err, &he) || he.Res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("GetAccountInfo = %v; want 503 error", err)
+	}
+	if got := c.Stats().Requests; got != 1 {
+		t.Errorf("sent %d requests; want 1", got)
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer s.Close()
+
+	c := NewClient(s.URL, NoAuth, WithRetry(RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}))
+	_, err := c.GetAccountInfo(context.Background(), "self")
+	var he *HTTPError
+	if !errors.As(err, &he) || he.Res.StatusCode != http.StatusBadGateway {
+		t.Errorf("GetAccountInfo = %v; want 502 error", err)
+	}
+	if got := c.Stats().Requests; got != 2 {
+		t.Errorf("sent %d requests; want 2", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		in     string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Mon, 01 Jan 2024 00:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 31 Dec 2023 00:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	} {
+		got, ok := parseRetryAfter(tt.in, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	var full atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const etag = `"v1"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", etag)
+		io.WriteString(w, ")]}'\n"+`{"_number":123,"subject":"cached"}`)
+	}))
+	defer s.Close()
+
+	c := NewClient(s.URL, NoAuth, WithResponseCache(10))
+	for i := 0; i < 3; i++ {
+		ci, err := c.GetChange(context.Background(), "123")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ci.Subject != "cached" {
+			t.Errorf("request %d: got subject %q", i, ci.Subject)
+		}
+	}
+	if got := full.Load(); got != 1 {
+		t.Errorf("server sent %d full responses; want 1", got)
+	}
+	if st := c.Stats(); st.CacheHits != 2 || st.CacheMisses != 1 {
+		t.Errorf("Stats = %+v; want 2 hits and 1 miss", st)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, ")]}'\n{}")
+	}))
+	defer s.Close()
+
+	c := NewClient(s.URL, NoAuth, WithRateLimit(rate.Every(20*time.Millisecond), 1))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetAccountInfo(context.Background(), "self"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Errorf("3 requests took %v; want at least 40ms", d)
+	}
+	if got := c.Stats().Throttled; got != 2 {
+		t.Errorf("Throttled = %d; want 2", got)
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package logindex implements an on-disk trigram index of build logs,
+// which tells which logs can match a regular expression without
+// reading them.
+//
+// The index records the trigrams (three-byte sequences) of the text
+// of each log. A log can only match a regular expression if it has
+// all the trigrams of some string that every match must contain, so
+// a search only needs to read the logs that have them. The index also
+// records the metadata of the builds that the logs are from.
+//
+// The index is written by fetchlogs and read by greplogs.
+package logindex
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// version is the version of the index file format.
+const version = 1
+
+// FileName is the name of the index file in the directory of fetchlogs.
+const FileName = "logindex"
+
+// A Doc is the metadata of a log in the index: the build that it is
+// the log of.
+type Doc struct {
+	Path       string    // path of the log, relative to the directory of the index
+	Log        string    // name of the log's content, such as its hash
+	Builder    string    // builder name
+	Repo       string    // repo of the build, such as "go" or "tools"
+	Branch     string    // branch of Repo
+	Revision   string    // commit of Repo
+	GoBranch   string    // branch of GoRevision, for subrepos
+	GoRevision string    // commit of the go repo, for subrepos
+	Date       time.Time // commit date
+}
+
+// An Index is a trigram index of logs.
+// The methods of an Index are not safe for concurrent use.
+type Index struct {
+	logs     []string            // names of the indexed logs, by ID
+	logID    map[string]uint32   // inverse of logs
+	postings map[uint32]*posting // logs that have each trigram
+	docs     map[string]*Doc     // by Path
+}
+
+// A posting is the list of IDs of the logs that have a trigram, in
+// increasing order, as varint-encoded deltas.
+type posting struct {
+	data []byte
+	last uint32 // last ID in data, plus one, or 0 if data is empty
+}
+
+func (p *posting) add(id uint32) {
+	p.data = binary.AppendUvarint(p.data, uint64(id+1-p.last))
+	p.last = id + 1
+}
+
+// ids returns the IDs in p.
+func (p *posting) ids() []uint32 {
+	var ids []uint32
+	var id uint32
+	for data := p.data; len(data) > 0; {
+		d, n := binary.Uvarint(data)
+		if n <= 0 {
+			break // corrupt; unreachable for data written by add
+		}
+		data = data[n:]
+		id += uint32(d)
+		ids = append(ids, id-1)
+	}
+	return ids
+}
+
+// New returns a new, empty index.
+func New() *Index {
+	return &Index{
+		logID:    make(map[string]uint32),
+		postings: make(map[uint32]*posting),
+		docs:     make(map[string]*Doc),
+	}
+}
+
+// fileData is the encoding of an Index in a file.
+type fileData struct {
+	Version  int
+	Logs     []string
+	Trigrams []uint32 // sorted
+	Postings [][]byte // parallel to Trigrams
+	Lasts    []uint32 // posting.last, parallel to Trigrams
+	Docs     []*Doc   // sorted by Path
+}
+
+// Open reads the index in file. If file doesn't exist,
+// Open returns a new, empty index.
+func Open(file string) (*Index, error) {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var fd fileData
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&fd); err != nil {
+		return nil, fmt.Errorf("reading index %s: %v", file, err)
+	}
+	if fd.Version != version {
+		return nil, fmt.Errorf("reading index %s: version %d, want %d", file, fd.Version, version)
+	}
+	if len(fd.Trigrams) != len(fd.Postings) || len(fd.Trigrams) != len(fd.Lasts) {
+		return nil, fmt.Errorf("reading index %s: corrupt postings", file)
+	}
+	ix := New()
+	ix.logs = fd.Logs
+	for id, name := range fd.Logs {
+		ix.logID[name] = uint32(id)
+	}
+	for i, t := range fd.Trigrams {
+		ix.postings[t] = &posting{data: fd.Postings[i], last: fd.Lasts[i]}
+	}
+	for _, d := range fd.Docs {
+		ix.docs[d.Path] = d
+	}
+	return ix, nil
+}
+
+// Write writes the index to file, atomically replacing it.
+func (ix *Index) Write(file string) error {
+	fd := fileData{Version: version, Logs: ix.logs}
+	for t := range ix.postings {
+		fd.Trigrams = append(fd.Trigrams, t)
+	}
+	sort.Slice(fd.Trigrams, func(i, j int) bool { return fd.Trigrams[i] < fd.Trigrams[j] })
+	for _, t := range fd.Trigrams {
+		p := ix.postings[t]
+		fd.Postings = append(fd.Postings, p.data)
+		fd.Lasts = append(fd.Lasts, p.last)
+	}
+	for _, d := range ix.docs {
+		fd.Docs = append(fd.Docs, d)
+	}
+	sort.Slice(fd.Docs, func(i, j int) bool { return fd.Docs[i].Path < fd.Docs[j].Path })
+
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	err = gob.NewEncoder(w).Encode(&fd)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(tmp, file)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Has reports whether the log named log is in the index.
+func (ix *Index) Has(log string) bool {
+	_, ok := ix.logID[log]
+	return ok
+}
+
+// Len returns the number of logs in the index.
+func (ix *Index) Len() int {
+	return len(ix.logs)
+}
+
+// Add adds the log named log, whose text is the set of trigrams ts,
+// to the index. If the log is already in the index, Add does nothing.
+func (ix *Index) Add(log string, ts Trigrams) {
+	if ix.Has(log) {
+		return
+	}
+	id := uint32(len(ix.logs))
+	ix.logs = append(ix.logs, log)
+	ix.logID[log] = id
+	for _, t := range ts {
+		p := ix.postings[t]
+		if p == nil {
+			p = new(posting)
+			ix.postings[t] = p
+		}
+		p.add(id)
+	}
+}
+
+// SetDocs sets the metadata of the logs to docs,
+// replacing all the earlier metadata.
+func (ix *Index) SetDocs(docs []*Doc) {
+	ix.docs = make(map[string]*Doc)
+	for _, d := range docs {
+		ix.docs[d.Path] = d
+	}
+}
+
+// Doc returns the metadata of the log at path, or nil if there is none.
+func (ix *Index) Doc(path string) *Doc {
+	return ix.docs[path]
+}
+
+// Docs returns the metadata of all the logs, sorted by path.
+func (ix *Index) Docs() []*Doc {
+	var docs []*Doc
+	for _, d := range ix.docs {
+		docs = append(docs, d)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Path < docs[j].Path })
+	return docs
+}
+
+// A Trigrams is the set of trigrams of a text, in increasing order.
+// The trigram of bytes a, b, c is a<<16 | b<<8 | c.
+type Trigrams []uint32
+
+// TextTrigrams returns the trigrams of text.
+func TextTrigrams(text []byte) Trigrams {
+	seen := make(map[uint32]bool)
+	var ts Trigrams
+	var t uint32
+	for i, c := range text {
+		t = (t<<8 | uint32(c)) & 0xffffff
+		if i >= 2 && !seen[t] {
+			seen[t] = true
+			ts = append(ts, t)
+		}
+	}
+	sort.Slice(ts, func(i, j int) bool { return ts[i] < ts[j] })
+	return ts
+}
+
+// FileTrigrams returns the trigrams of the text in file.
+func FileTrigrams(file string) (Trigrams, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return TextTrigrams(data), nil
+}
+
+// Candidates returns the logs in the index that may match all of the
+// regular expressions in res. The other logs in the index can't match
+// all of them. Logs not in the index are not included.
+func (ix *Index) Candidates(res []*regexp.Regexp) map[string]bool {
+	var ids []uint32
+	all := true
+	for _, re := range res {
+		q := regexpQuery(re)
+		if q.op == qAll {
+			continue
+		}
+		qids := ix.eval(q)
+		if all {
+			ids, all = qids, false
+		} else {
+			ids = intersect(ids, qids)
+		}
+	}
+	cands := make(map[string]bool)
+	if all {
+		for _, log := range ix.logs {
+			cands[log] = true
+		}
+		return cands
+	}
+	for _, id := range ids {
+		cands[ix.logs[id]] = true
+	}
+	return cands
+}
+
+// eval returns the IDs of the logs that satisfy q, in increasing order.
+// q must not be qAll.
+func (ix *Index) eval(q *query) []uint32 {
+	switch q.op {
+	case qNone:
+		return nil
+	case qAnd:
+		var ids []uint32
+		first := true
+		for _, t := range q.trigrams {
+			p := ix.postings[t]
+			if p == nil {
+				return nil
+			}
+			if first {
+				ids, first = p.ids(), false
+			} else {
+				ids = intersect(ids, p.ids())
+			}
+		}
+		for _, sub := range q.sub {
+			if sub.op == qAll {
+				continue
+			}
+			if first {
+				ids, first = ix.eval(sub), false
+			} else {
+				ids = intersect(ids, ix.eval(sub))
+			}
+		}
+		if first {
+			// An empty conjunction: all logs.
+			ids = make([]uint32, len(ix.logs))
+			for i := range ids {
+				ids[i] = uint32(i)
+			}
+		}
+		return ids
+	case qOr:
+		var ids []uint32
+		for _, sub := range q.sub {
+			if sub.op == qAll {
+				return ix.eval(&query{op: qAnd})
+			}
+			ids = union(ids, ix.eval(sub))
+		}
+		return ids
+	}
+	panic("unreachable")
+}
+
+// intersect returns the IDs in both x and y, which are sorted.
+func intersect(x, y []uint32) []uint32 {
+	var z []uint32
+	for i, j := 0, 0; i < len(x) && j < len(y); {
+		switch {
+		case x[i] < y[j]:
+			i++
+		case x[i] > y[j]:
+			j++
+		default:
+			z = append(z, x[i])
+			i++
+			j++
+		}
+	}
+	return z
+}
+
+// union returns the IDs in either x or y, which are sorted.
+func union(x, y []uint32) []uint32 {
+	var z []uint32
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] < y[j]:
+			z = append(z, x[i])
+			i++
+		case x[i] > y[j]:
+			z = append(z, y[j])
+			j++
+		default:
+			z = append(z, x[i])
+			i++
+			j++
+		}
+	}
+	z = append(z, x[i:]...)
+	return append(z, y[j:]...)
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package logindex
+
+import (
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+)
+
+var testLogs = map[string]string{
+	"a": "--- FAIL: TestDial (0.10s)\n    dial_test.go:120: connection refused\nFAIL\tnet\n",
+	"b": "panic: runtime error: index out of range [3] with length 3\n\ngoroutine 7 [running]:\n",
+	"c": "ok  \tnet\t1.2s\nok  \tos\t0.5s\n",
+	"d": "# os\nos/file.go:10:2: undefined: foo\nFAIL\tos [build failed]\n",
+}
+
+func newTestIndex() *Index {
+	ix := New()
+	var names []string
+	for name := range testLogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ix.Add(name, TextTrigrams([]byte(testLogs[name])))
+	}
+	return ix
+}
+
+func TestCandidates(t *testing.T) {
+	ix := newTestIndex()
+	tests := []struct {
+		res  []string
+		want string // logs that can match
+	}{
+		{nil, "abcd"},
+		{[]string{`connection refused`}, "a"},
+		{[]string{`FAIL`}, "ad"},
+		{[]string{`FAIL`, `undefined: \w+`}, "d"},
+		{[]string{`(?m)^panic: `}, "b"},
+		{[]string{`index out of range|undefined`}, "bd"},
+		{[]string{`TestDial|.*`}, "abcd"},
+		{[]string{`(?i)fail`}, "abcd"},
+		{[]string{`ne+t`}, "abcd"},
+		{[]string{`goroutine \d+ \[running\]`}, "b"},
+		{[]string{`xyzzy+`}, ""},
+		{[]string{`Test(Dial|Listen)`}, "a"},
+		{[]string{`TestListen`}, ""},
+		{[]string{`os`}, "abcd"}, // too short to use the index
+	}
+	for _, tt := range tests {
+		var res []*regexp.Regexp
+		for _, s := range tt.res {
+			res = append(res, regexp.MustCompile(s))
+		}
+		cands := ix.Candidates(res)
+		var got []byte
+		for _, name := range "abcd" {
+			if cands[string(name)] {
+				got = append(got, byte(name))
+			}
+		}
+		if string(got) != tt.want {
+			t.Errorf("Candidates(%q) = %q, want %q", tt.res, got, tt.want)
+		}
+		// The index must never rule out a log that matches.
+		for name, text := range testLogs {
+			all := true
+			for _, re := range res {
+				all = all && re.MatchString(text)
+			}
+			if all && !cands[name] {
+				t.Errorf("Candidates(%q) excludes %s, which matches", tt.res, name)
+			}
+		}
+	}
+}
+
+func TestWriteOpen(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "index", FileName)
+	ix, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ix.Len() != 0 {
+		t.Fatalf("Open of missing file has %d logs, want 0", ix.Len())
+	}
+	ix = newTestIndex()
+	doc := &Doc{Path: "rev/x/linux-amd64", Log: "a", Builder: "linux-amd64", Repo: "go", Branch: "master",
+		Revision: "abc", Date: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	ix.SetDocs([]*Doc{doc})
+	if err := ix.Write(file); err != nil {
+		t.Fatal(err)
+	}
+	ix2, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ix2.Doc(doc.Path), doc) {
+		t.Errorf("Doc = %+v, want %+v", ix2.Doc(doc.Path), doc)
+	}
+
+	// Logs added after reopening get postings after the old ones.
+	ix2.Add("e", TextTrigrams([]byte("connection refused again")))
+	ix2.Add("a", nil) // already indexed
+	re := regexp.MustCompile(`connection refused`)
+	want := map[string]bool{"a": true, "e": true}
+	if got := ix2.Candidates([]*regexp.Regexp{re}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Candidates after Add = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package logindex
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"unicode/utf8"
+)
+
+// A query is a condition on the trigrams of a text
+// that every text matching a regular expression satisfies.
+type query struct {
+	op       qop
+	trigrams []uint32 // for qAnd, trigrams that the text must all have
+	sub      []*query // for qAnd and qOr, the subqueries
+}
+
+type qop int
+
+const (
+	qAll  qop = iota // every text
+	qNone            // no text
+	qAnd             // texts that have all the trigrams and satisfy all the subqueries
+	qOr              // texts that satisfy any of the subqueries
+)
+
+var (
+	allQuery  = &query{op: qAll}
+	noneQuery = &query{op: qNone}
+)
+
+// regexpQuery returns the query for re.
+func regexpQuery(re *regexp.Regexp) *query {
+	x, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		// Unreachable for a compiled regexp.
+		return allQuery
+	}
+	return syntaxQuery(x.Simplify())
+}
+
+// syntaxQuery returns the query for the regexp x.
+// It is conservative: it only uses the literal strings that every
+// match of x must contain.
+func syntaxQuery(x *syntax.Regexp) *query {
+	switch x.Op {
+	case syntax.OpNoMatch:
+		return noneQuery
+	case syntax.OpLiteral:
+		if x.Flags&syntax.FoldCase != 0 {
+			return allQuery
+		}
+		return literalQuery(string(x.Rune))
+	case syntax.OpCapture:
+		return syntaxQuery(x.Sub[0])
+	case syntax.OpPlus:
+		return syntaxQuery(x.Sub[0])
+	case syntax.OpRepeat:
+		if x.Min >= 1 {
+			return syntaxQuery(x.Sub[0])
+		}
+	case syntax.OpConcat:
+		return concatQuery(x.Sub)
+	case syntax.OpAlternate:
+		q := &query{op: qOr}
+		for _, sub := range x.Sub {
+			sq := syntaxQuery(sub)
+			switch sq.op {
+			case qAll:
+				return allQuery
+			case qNone:
+				continue
+			}
+			q.sub = append(q.sub, sq)
+		}
+		if len(q.sub) == 0 {
+			return noneQuery
+		}
+		return q
+	}
+	// Anything else, such as a character class or a repetition
+	// that can be empty, can match without any literal text.
+	return allQuery
+}
+
+// concatQuery returns the query for the concatenation of subs.
+// Adjacent literals are joined, so that their trigrams include
+// those that span them.
+func concatQuery(subs []*syntax.Regexp) *query {
+	q := &query{op: qAnd}
+	var lit []rune
+	flush := func() {
+		if len(lit) > 0 {
+			add(q, literalQuery(string(lit)))
+			lit = lit[:0]
+		}
+	}
+	for _, sub := range subs {
+		if sub.Op == syntax.OpLiteral && sub.Flags&syntax.FoldCase == 0 {
+			lit = append(lit, sub.Rune...)
+			continue
+		}
+		flush()
+		add(q, syntaxQuery(sub))
+	}
+	flush()
+	for _, sub := range q.sub {
+		if sub.op == qNone {
+			return noneQuery
+		}
+	}
+	if len(q.trigrams) == 0 && len(q.sub) == 0 {
+		return allQuery
+	}
+	return q
+}
+
+// add adds the condition sq to the qAnd query q.
+func add(q, sq *query) {
+	switch sq.op {
+	case qAll:
+		return
+	case qAnd:
+		q.trigrams = append(q.trigrams, sq.trigrams...)
+		q.sub = append(q.sub, sq.sub...)
+		return
+	}
+	q.sub = append(q.sub, sq)
+}
+
+// literalQuery returns the query for text that contains s.
+func literalQuery(s string) *query {
+	if !utf8.ValidString(s) || len(s) < 3 {
+		return allQuery
+	}
+	ts := TextTrigrams([]byte(s))
+	return &query{op: qAnd, trigrams: ts}
+}
@@ -2,28 +2,133 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package logparser parses build.golang.org dashboard logs.
+// Package logparser parses build and test logs, such as those of
+// build.golang.org dashboard and LUCI builds, extracting the failures
+// they report. It is shared by the tools that look for failures in
+// logs, such as watchflakes and greplogs, so that they agree on what
+// a failure is.
+//
+// Logs that contain go test -json output, and test results from
+// ResultDB, tell exactly which tests failed; see ParseLog and
+// FailsFromResults. Other logs are parsed as text; see Parse.
 package logparser
 
 import (
+	"fmt"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // A Fail is a single failure mentioned in a dashboard log.
 // (There can be multiple failures in a single log.)
 type Fail struct {
-	Section string
-	Pkg     string
-	Test    string
-	Mode    string
-	Output  string
-	Snippet string
+	Section string // section of the build, such as a cmd/dist test name
+	Pkg     string // package that failed, if known
+	Test    string // top-level test that failed, if known
+	Subtest string // path of the failing subtest under Test, such as "a/b", if any
+	Mode    string // "build" for build failures, "test" for test failures, or ""
+
+	// Message is a one-line summary of the failure, such as the
+	// first test error, the panic value or the compiler error.
+	Message string
+
+	// Function, File and Line locate the failure, when known:
+	// the panicking function for panics and fatal errors, or the
+	// position of the test error or compiler error.
+	Function string
+	File     string
+	Line     int
+
+	Output  string // the part of the log about the failure
+	Snippet string // a shortened Output, suitable for an issue comment
+
+	Elapsed time.Duration // how long the test ran, if known
+}
+
+// String returns a one-line description of the failure.
+func (f *Fail) String() string {
+	s := f.Pkg
+	if t := f.TestPath(); t != "" {
+		if s != "" {
+			s += "."
+		}
+		s += t
+	}
+	if where := f.where(); where != "" {
+		if s != "" {
+			s += " "
+		}
+		s += "at " + where
+	}
+	if s != "" {
+		s += ": "
+	}
+	return s + f.Message
+}
+
+// TestPath returns the full name of the failing test,
+// including the subtest path, such as "TestFoo/a/b".
+func (f *Fail) TestPath() string {
+	if f.Subtest == "" {
+		return f.Test
+	}
+	return f.Test + "/" + f.Subtest
+}
+
+func (f *Fail) where() string {
+	if f.Function != "" {
+		return f.Function
+	}
+	if f.File != "" && f.Line != 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// Signature returns a canonical description of the failure, for
+// grouping failures that are likely to have the same cause. It leaves
+// out details that vary from run to run, such as line numbers,
+// directories, and the numbers, addresses and temporary names in the
+// message.
+func (f *Fail) Signature() string {
+	where := f.Function
+	if where == "" && f.File != "" {
+		where = path.Base(strings.ReplaceAll(f.File, `\`, "/"))
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s", f.Mode, f.Pkg, canonical(f.TestPath()), where, canonical(f.Message))
+}
+
+// numberWords matches words that consist of both letters and
+// digits. Since this is meant to canonicalize numeric fields
+// of error messages, we accept any Unicode letter, but only
+// digits 0-9. We match the whole word to catch things like
+// hexadecimal and temporary file names.
+var numberWords = regexp.MustCompile(`\pL*[0-9][\pL0-9]*`)
+
+// canonical replaces the words of s that contain numbers with "…".
+func canonical(s string) string {
+	return numberWords.ReplaceAllString(s, "…")
 }
 
 // compileRE matches compiler errors, with file:line:[col:] at the start of the line.
-var compileRE = regexp.MustCompile(`^[a-zA-Z0-9_./\\]+:\d+:(\d+:)? `)
+var compileRE = regexp.MustCompile(`^([a-zA-Z0-9_./\\]+):(\d+):(\d+:)? (.*)`)
+
+// testErrorRE matches the errors reported by package testing,
+// with an indented file:line: at the start of the line.
+var testErrorRE = regexp.MustCompile(`^\s+([a-zA-Z0-9_./\\-]+\.go):(\d+): (.*)`)
+
+// panicRE matches the start of a panic or a fatal runtime error.
+var panicRE = regexp.MustCompile(`^(panic|fatal error): (.*?)(?: \[recovered.*\])?$`)
+
+// frameRE matches a function and the file:line that follows it in a goroutine stack.
+var frameRE = regexp.MustCompile(`^(\S+)\(.*\)$`)
+var frameFileRE = regexp.MustCompile(`^\t(.*):(\d+)(?: .*)?$`)
+
+// coordinatorTimeoutRE matches a build timeout reported by the coordinator.
+var coordinatorTimeoutRE = regexp.MustCompile(`(?m)^Build complete.*Result: error: timed out|^Test "[^"]+" ran over [0-9a-z]+ limit`)
 
 // runningRE matches the buildlet :: Running messages,
 // which are displayed at the start of each operation the buildlet does.
@@ -60,6 +165,9 @@ func Parse(log string) []*Fail {
 	var (
 		section string
 		hold    []string
+		// pkgDone is the number of fails that a "FAIL\tpkg" line
+		// has already attributed to a package.
+		pkgDone int
 		fails   []*Fail
 		lines   [][]string
 		f       *Fail
@@ -101,6 +209,9 @@ Lines:
 		if strings.HasPrefix(line, "go: downloading ") {
 			continue
 		}
+		// The Android wrapper used to print "exitcode=1" without
+		// a newline before go test's FAIL line (go.dev/issue/49317).
+		line = strings.TrimPrefix(line, "exitcode=1")
 		// replace lines with trailing spaces with empty line
 		if strings.TrimSpace(line) == "" {
 			line = "\n"
@@ -165,9 +276,11 @@ Lines:
 		if strings.HasPrefix(line, "--- FAIL: ") {
 			if fields := strings.Fields(line); len(fields) >= 3 {
 				// Found start of test function failure.
+				test, subtest, _ := strings.Cut(fields[2], "/")
 				f = &Fail{
 					Section: section,
-					Test:    fields[2],
+					Test:    test,
+					Subtest: subtest,
 					Mode:    "test",
 				}
 				if strings.HasPrefix(section, "../") {
@@ -241,7 +354,7 @@ Lines:
 				if f != nil && f.Section == "../test" {
 					// already collecting
 				} else if f != nil {
-					for i := len(fails) - 1; i >= 0 && fails[i].Test != ""; i-- {
+					for i := len(fails) - 1; i >= pkgDone && fails[i].Test != ""; i-- {
 						fails[i].Pkg = pkg
 					}
 				} else {
@@ -254,6 +367,7 @@ Lines:
 					lines = append(lines, hold)
 					hold = nil
 				}
+				pkgDone = len(fails)
 				flush()
 				continue
 			}
@@ -292,11 +406,162 @@ Lines:
 			// Figure that out by parsing the goroutine stacks.
 			findRunningTest(f, out)
 		}
+		// Packages built in temporary directories, such as misc/cgo
+		// tests in old logs, are named like _/tmp/buildlet-scratch/go/misc/cgo/test.
+		if strings.HasPrefix(f.Pkg, "_/tmp/") {
+			if parts := strings.SplitN(f.Pkg, "/", 4); len(parts) == 4 {
+				f.Pkg = strings.TrimPrefix(parts[3], "go/")
+			}
+		}
+		if f.Test != "" && f.Subtest == "" {
+			f.Subtest = findFailingSubtest(f.Test, out)
+		}
+		describe(f, out, log)
 	}
 
 	return fails
 }
 
+// findFailingSubtest returns the path of the first failing leaf subtest
+// of test that is reported in lines, or "" if none is.
+func findFailingSubtest(test string, lines []string) string {
+	var names []string
+	for _, line := range lines {
+		s := strings.TrimSpace(line)
+		if !strings.HasPrefix(s, "--- FAIL: "+test+"/") {
+			continue
+		}
+		if fields := strings.Fields(s); len(fields) >= 3 {
+			names = append(names, fields[2])
+		}
+	}
+	for i, name := range names {
+		if i+1 == len(names) || !strings.HasPrefix(names[i+1], name+"/") {
+			return strings.TrimPrefix(name, test+"/")
+		}
+	}
+	return ""
+}
+
+// describe sets the Message, Function, File and Line of f
+// from its output lines and, if needed, the whole log.
+func describe(f *Fail, lines []string, log string) {
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\n")
+		if m := panicRE.FindStringSubmatch(line); m != nil {
+			f.Message = m[2]
+			if m[1] == "fatal error" {
+				f.Message = "fatal error: " + m[2]
+			}
+			if strings.HasPrefix(m[2], "test timed out after ") {
+				// The stack of a timeout is the alarm's,
+				// which doesn't tell the timeouts apart.
+				f.Message = "test timed out"
+				return
+			}
+			f.Function, f.File, f.Line = panicWhere(lines[i+1:])
+			return
+		}
+	}
+	// Look for the errors of the failing subtest, if known,
+	// rather than those of its parent or its siblings.
+	errLines := lines
+	if f.Subtest != "" {
+		for i, line := range lines {
+			if strings.HasPrefix(strings.TrimSpace(line), "--- FAIL: "+f.TestPath()+" ") {
+				errLines = lines[i+1:]
+				break
+			}
+		}
+	}
+	// With go test -v and -json, the errors come before the
+	// --- FAIL line instead, so look at all the lines after that.
+	for _, errLines := range [][]string{errLines, lines} {
+		for _, line := range errLines {
+			line = strings.TrimRight(line, "\n")
+			if m := testErrorRE.FindStringSubmatch(line); m != nil && f.Mode == "test" {
+				f.File, f.Line, f.Message = m[1], atoi(m[2]), m[3]
+				return
+			}
+		}
+	}
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\n")
+		if m := compileRE.FindStringSubmatch(line); m != nil && f.Mode != "" {
+			f.File, f.Line, f.Message = m[1], atoi(m[2]), m[4]
+			return
+		}
+	}
+	switch {
+	case f.Mode == "build" && f.Pkg != "":
+		f.Message = "build failed"
+	case f.Mode != "":
+		f.Message = "unknown failure"
+		for _, line := range lines {
+			if s := strings.TrimSpace(line); s != "" && !strings.HasPrefix(s, "--- FAIL: ") && !strings.HasPrefix(s, "=== ") &&
+				!strings.HasPrefix(s, "# ") && !strings.HasPrefix(s, "exit status ") {
+				f.Message = s
+				break
+			}
+		}
+	case strings.Contains(log, "no space left on device"):
+		f.Message = "build failed (no space left on device)"
+	case coordinatorTimeoutRE.MatchString(log):
+		f.Message = "build failed (timed out)"
+	case strings.Contains(log, "Failed to schedule"):
+		f.Message = "build failed (failed to schedule)"
+	case strings.Contains(log, "nosplit stack overflow"):
+		f.Message = "build failed (nosplit stack overflow)"
+	default:
+		f.Message = "unknown failure"
+		for i := len(lines) - 1; i >= 0; i-- {
+			if s := strings.TrimSpace(lines[i]); s != "" {
+				f.Message = s
+				break
+			}
+		}
+	}
+}
+
+// panicWhere returns the function, file and line of the first frame
+// in the goroutine stack in lines that isn't part of panic handling.
+func panicWhere(lines []string) (fn, file string, line int) {
+	for i := 0; i+1 < len(lines); i++ {
+		m := frameRE.FindStringSubmatch(strings.TrimRight(lines[i], "\n"))
+		if m == nil {
+			continue
+		}
+		fm := frameFileRE.FindStringSubmatch(strings.TrimRight(lines[i+1], "\n"))
+		if fm == nil {
+			continue
+		}
+		if fn := m[1]; fn == "panic" || strings.HasPrefix(fn, "runtime.panic") || strings.HasPrefix(fn, "runtime.gopanic") || strings.HasPrefix(fn, "runtime.goPanic") ||
+			strings.HasPrefix(fn, "runtime.fatal") || fn == "runtime.throw" || fn == "runtime.sigpanic" ||
+			strings.HasPrefix(fn, "testing.tRunner.func") {
+			continue
+		}
+		return m[1], fm[1], atoi(fm[2])
+	}
+	return "", "", 0
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// Shorten shortens output, such as the log of a single failed test,
+// to form a snippet. It removes the goroutine stacks that aren't
+// running, and keeps the start, the end, and the first important-looking
+// part of the middle of long output.
+func Shorten(output string) string {
+	lines := strings.SplitAfter(output, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	return strings.Join(shorten(lines, true), "")
+}
+
 var goroutineStack = regexp.MustCompile(`^goroutine \d+ \[(.*)\]:$`)
 
 // findRunningTest looks at the test output to find the running test goroutine,
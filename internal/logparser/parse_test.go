@@ -6,6 +6,7 @@ package logparser
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,11 +16,18 @@ import (
 	"golang.org/x/build/internal/diff"
 )
 
+var update = flag.Bool("update", false, "update the .fail files in testdata")
+
 func Test(t *testing.T) {
 	// testdata/x.log is a build log, and
 	// testdata/x.fail is fmtFails(Parse(log)).
+	// testdata/x.json is go test -json output, and
+	// testdata/x.fail is fmtFails(ParseLog(log)).
 	// Check that we get the same result as in x.fail.
-	files, _ := filepath.Glob("testdata/*.log")
+	// Run with -update after changing the parser to regenerate them.
+	logs, _ := filepath.Glob("testdata/*.log")
+	jsons, _ := filepath.Glob("testdata/*.json")
+	files := append(logs, jsons...)
 	if len(files) == 0 {
 		t.Fatalf("no testdata")
 	}
@@ -30,11 +38,22 @@ func Test(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			want, err := os.ReadFile(strings.TrimSuffix(file, ".log") + ".fail")
+			parse := Parse
+			if filepath.Ext(file) == ".json" {
+				parse = ParseLog
+			}
+			have := fmtFails(parse(string(data)))
+			golden := strings.TrimSuffix(file, filepath.Ext(file)) + ".fail"
+			if *update {
+				if err := os.WriteFile(golden, have, 0666); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
 			if err != nil {
 				t.Fatal(err)
 			}
-			have := fmtFails(Parse(string(data)))
 			if !bytes.Equal(have, want) {
 				t.Errorf("mismatch:\n%s", diff.Diff("want", want, "have", have))
 			}
@@ -48,7 +67,18 @@ func fmtFails(fails []*Fail) []byte {
 		if i > 0 {
 			fmt.Fprintf(&b, "---\n")
 		}
-		fmt.Fprintf(&b, "Section: %q\nPkg: %q\nTest: %q\nMode: %q\n", f.Section, f.Pkg, f.Test, f.Mode)
+		fmt.Fprintf(&b, "Section: %q\nPkg: %q\nTest: %q\n", f.Section, f.Pkg, f.Test)
+		if f.Subtest != "" {
+			fmt.Fprintf(&b, "Subtest: %q\n", f.Subtest)
+		}
+		fmt.Fprintf(&b, "Mode: %q\nMessage: %q\n", f.Mode, f.Message)
+		if f.Function != "" || f.File != "" {
+			fmt.Fprintf(&b, "Where: %q %q %d\n", f.Function, f.File, f.Line)
+		}
+		if f.Elapsed != 0 {
+			fmt.Fprintf(&b, "Elapsed: %v\n", f.Elapsed)
+		}
+		fmt.Fprintf(&b, "Signature: %q\n", f.Signature())
 		fmt.Fprintf(&b, "Snippet:\n%s", indent(f.Snippet))
 		fmt.Fprintf(&b, "Output:\n%s", indent(f.Output))
 	}
@@ -65,3 +95,51 @@ func indent(s string) string {
 	s = strings.ReplaceAll(s, "\t\n", "\n")
 	return s
 }
+
+func TestSignature(t *testing.T) {
+	// The same failure in two runs, with different temporary
+	// names, addresses and line numbers.
+	logs := []string{
+		"--- FAIL: TestDial (0.10s)\n    dial_test.go:120: dial tcp 127.0.0.1:40123: connect: connection refused\nFAIL\nFAIL\tnet\t1.234s\n",
+		"--- FAIL: TestDial (0.31s)\n    dial_test.go:125: dial tcp 127.0.0.1:51877: connect: connection refused\nFAIL\nFAIL\tnet\t2.001s\n",
+	}
+	var sigs []string
+	for _, log := range logs {
+		fails := Parse(log)
+		if len(fails) != 1 {
+			t.Fatalf("Parse found %d failures, want 1", len(fails))
+		}
+		sigs = append(sigs, fails[0].Signature())
+	}
+	want := "test|net|TestDial|dial_test.go|dial tcp ….….….…:…: connect: connection refused"
+	for _, sig := range sigs {
+		if sig != want {
+			t.Errorf("Signature() = %q, want %q", sig, want)
+		}
+	}
+
+	f := &Fail{Pkg: "net", Test: "TestDial", Subtest: "tcp", File: "dial_test.go", Line: 120, Message: "bad"}
+	if got, want := f.String(), "net.TestDial/tcp at dial_test.go:120: bad"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestShorten(t *testing.T) {
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, fmt.Sprintf("line %d\n", i))
+	}
+	lines[25] = "panic: boom\n"
+	output := "\n\n" + strings.Join(lines, "") + "\ngoroutine 7 [chan receive]:\nmain.wait()\n\t/tmp/x.go:1 +0x1\n"
+	got := Shorten(output)
+	for _, want := range []string{"line 0\n", "line 9\n", "...\npanic: boom\n", "line 34\n", "...\nline 40\n", "line 49\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Shorten output is missing %q:\n%s", want, got)
+		}
+	}
+	for _, notWant := range []string{"line 10\n", "line 35\n", "goroutine 7"} {
+		if strings.Contains(got, notWant) {
+			t.Errorf("Shorten output contains %q:\n%s", notWant, got)
+		}
+	}
+}
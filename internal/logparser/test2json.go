@@ -0,0 +1,291 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package logparser
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A TestEvent is an event in the output of go test -json.
+// See go doc cmd/test2json.
+type TestEvent struct {
+	Time        time.Time `json:",omitempty"`
+	Action      string
+	Package     string  `json:",omitempty"`
+	Test        string  `json:",omitempty"`
+	Elapsed     float64 `json:",omitempty"` // seconds
+	Output      string  `json:",omitempty"`
+	FailedBuild string  `json:",omitempty"` // import path of the package that failed to build
+
+	// ImportPath is the package of build-output and build-fail events.
+	ImportPath string `json:",omitempty"`
+}
+
+// A Result is the outcome of a single test, or of a whole package,
+// as reported by structured test output such as go test -json or
+// a ResultDB test result.
+type Result struct {
+	Pkg  string
+	Test string // full name of the test, such as "TestFoo/a/b"; "" for the package itself
+
+	// Outcome is "pass", "fail" or "skip", or "" if the test
+	// started but never finished, such as when the test binary
+	// crashed or timed out while it was running.
+	Outcome string
+
+	Elapsed time.Duration
+	Output  string
+
+	// BuildFailed reports whether the package failed to build.
+	// It is only set for the result of a package.
+	BuildFailed bool
+
+	// chunks is Output as ParseTestJSON found it, numbered in the
+	// order of the whole log, so that the output of a test can be
+	// merged with that of the tests it is a subtest of.
+	chunks []chunk
+}
+
+type chunk struct {
+	seq  int
+	text string
+}
+
+// ParseTestJSON parses a log that contains go test -json output,
+// returning the result of each test and package, in the order that
+// they started, and the text of the lines of the log that aren't test
+// events, such as a build error that go test printed to stderr.
+// Builds often mix the two, so it isn't an error for either to be
+// missing.
+func ParseTestJSON(log string) (results []*Result, text string) {
+	log = strings.ReplaceAll(log, "\r", "")
+	type key struct{ pkg, test string }
+	var (
+		byKey    = make(map[key]*Result)
+		buildOut = make(map[string][]chunk) // by import path
+		textOut  strings.Builder
+	)
+	for seq, line := range strings.SplitAfter(log, "\n") {
+		e, ok := decodeEvent(line)
+		if !ok {
+			textOut.WriteString(line)
+			continue
+		}
+		switch {
+		case e.Action == "build-output":
+			buildOut[e.ImportPath] = append(buildOut[e.ImportPath], chunk{seq, e.Output})
+			continue
+		case e.Action == "build-fail":
+			continue
+		case e.Package == "":
+			textOut.WriteString(e.Output)
+			continue
+		}
+		k := key{e.Package, e.Test}
+		r := byKey[k]
+		if r == nil {
+			r = &Result{Pkg: e.Package, Test: e.Test}
+			byKey[k] = r
+			results = append(results, r)
+		}
+		switch e.Action {
+		case "output":
+			r.chunks = append(r.chunks, chunk{seq, e.Output})
+		case "pass", "fail", "skip":
+			r.Outcome = e.Action
+			r.Elapsed = time.Duration(e.Elapsed * float64(time.Second))
+			if e.FailedBuild != "" {
+				r.BuildFailed = true
+				// Packages can share a failed build, so don't
+				// append to the slice of its output in place.
+				r.chunks = append(slices.Clip(buildOut[e.FailedBuild]), r.chunks...)
+			}
+		}
+	}
+	for _, r := range results {
+		r.Output = joinChunks(r.chunks)
+		if r.Test == "" && r.Outcome == "fail" &&
+			(strings.Contains(r.Output, " [build failed]") || strings.Contains(r.Output, " [setup failed]")) {
+			r.BuildFailed = true
+		}
+	}
+	return results, textOut.String()
+}
+
+// decodeEvent decodes line as a test event,
+// reporting whether it is one.
+func decodeEvent(line string) (*TestEvent, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return nil, false
+	}
+	e := new(TestEvent)
+	if err := json.Unmarshal([]byte(line), e); err != nil || e.Action == "" {
+		return nil, false
+	}
+	return e, true
+}
+
+func joinChunks(chunks []chunk) string {
+	var b strings.Builder
+	for _, c := range chunks {
+		b.WriteString(c.text)
+	}
+	return b.String()
+}
+
+// FailsFromResults returns the failures that results report.
+//
+// A test whose subtests failed isn't reported itself, since its
+// failing subtests are, but its output is included in theirs: a panic
+// in a subtest, for one, is reported in the output of its top-level
+// test. A package that failed without a failing test is attributed to
+// a test that was still running when the package failed, if there is
+// one, or else reported on its own, as a build failure if the package
+// failed to build.
+func FailsFromResults(results []*Result) []*Fail {
+	var fails []*Fail
+	for _, r := range results {
+		if r.Outcome != "fail" {
+			continue
+		}
+		if r.Test == "" {
+			if f := packageFail(r, results); f != nil {
+				fails = append(fails, f)
+			}
+			continue
+		}
+		if hasFailingSubtest(r, results) {
+			continue
+		}
+		f := &Fail{Pkg: r.Pkg, Mode: "test", Elapsed: r.Elapsed}
+		f.Test, f.Subtest, _ = strings.Cut(r.Test, "/")
+		setOutput(f, testOutput(r, results))
+		fails = append(fails, f)
+	}
+	return fails
+}
+
+// packageFail returns the failure of the package that r is the
+// result of, or nil if it is explained by the failure of its tests.
+func packageFail(r *Result, results []*Result) *Fail {
+	f := &Fail{Pkg: r.Pkg, Mode: "test", Elapsed: r.Elapsed}
+	output := r.Output
+	if r.BuildFailed {
+		f.Mode = "build"
+	} else {
+		var running *Result
+		for _, t := range results {
+			if t.Pkg != r.Pkg || t.Test == "" {
+				continue
+			}
+			switch t.Outcome {
+			case "fail":
+				return nil
+			case "":
+				// The innermost of the tests that were running
+				// is the one that started last.
+				running = t
+			}
+		}
+		if running != nil {
+			f.Test, f.Subtest, _ = strings.Cut(running.Test, "/")
+			output = testOutput(running, results) + output
+		}
+	}
+	setOutput(f, output)
+	return f
+}
+
+func hasFailingSubtest(r *Result, results []*Result) bool {
+	for _, t := range results {
+		if t.Pkg == r.Pkg && t.Outcome == "fail" && strings.HasPrefix(t.Test, r.Test+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// testOutput returns the output of the test that r is the result of,
+// merged with that of its parent tests if it came from ParseTestJSON.
+func testOutput(r *Result, results []*Result) string {
+	if r.chunks == nil {
+		return r.Output
+	}
+	var chunks []chunk
+	for _, t := range results {
+		if t.Pkg == r.Pkg && t.Test != "" && (t == r || strings.HasPrefix(r.Test, t.Test+"/")) {
+			chunks = append(chunks, t.chunks...)
+		}
+	}
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].seq < chunks[j].seq })
+	return joinChunks(chunks)
+}
+
+// setOutput sets the Output and Snippet of f to output,
+// and describes the failure from it.
+func setOutput(f *Fail, output string) {
+	out := strings.SplitAfter(output, "\n")
+	for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+		out = out[:len(out)-1]
+	}
+	f.Output = strings.Join(out, "")
+	f.Snippet = strings.Join(shorten(out, true), "")
+	if f.Test == "" && strings.Contains(f.Output, "\n\ngoroutine ") {
+		findRunningTest(f, out)
+	}
+	if f.Test != "" && f.Subtest == "" {
+		f.Subtest = findFailingSubtest(f.Test, out)
+	}
+	describe(f, out, f.Output)
+}
+
+// ParseLog parses a build log, returning all the failures it finds.
+// If the log contains go test -json output, the failures come from
+// its test events, which tell exactly which tests failed. Otherwise,
+// or if the test events don't report any failures, ParseLog parses
+// the log as text, as Parse does. Like Parse, it always returns at
+// least one failure.
+func ParseLog(log string) []*Fail {
+	results, text := ParseTestJSON(log)
+	if len(results) == 0 {
+		return Parse(log)
+	}
+	if fails := FailsFromResults(results); len(fails) > 0 {
+		// Before Go 1.24, go test -json printed compiler errors
+		// as text rather than as build-output events.
+		var textFails []*Fail
+		for _, f := range fails {
+			if f.Mode != "build" || f.Message != "build failed" {
+				continue
+			}
+			if textFails == nil {
+				textFails = Parse(text)
+			}
+			for _, tf := range textFails {
+				if tf.Mode == "build" && tf.Pkg == f.Pkg {
+					f.Output, f.Snippet = tf.Output, tf.Snippet
+					f.Message, f.File, f.Line = tf.Message, tf.File, tf.Line
+					break
+				}
+			}
+		}
+		return fails
+	}
+	// Reconstruct the text of the whole log,
+	// with the output of the tests in place of their events.
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(strings.ReplaceAll(log, "\r", ""), "\n") {
+		if e, ok := decodeEvent(line); ok {
+			b.WriteString(e.Output)
+		} else {
+			b.WriteString(line)
+		}
+	}
+	return Parse(b.String())
+}
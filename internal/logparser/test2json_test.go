@@ -0,0 +1,41 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package logparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTestJSONSharedFailedBuild(t *testing.T) {
+	// Packages a and b both fail because their dependency c
+	// failed to build, so both have c's build output.
+	log := strings.Join([]string{
+		`{"ImportPath":"c","Action":"build-output","Output":"c0\n"}`,
+		`{"ImportPath":"c","Action":"build-output","Output":"c1\n"}`,
+		`{"ImportPath":"c","Action":"build-output","Output":"c2\n"}`,
+		`{"ImportPath":"c","Action":"build-fail"}`,
+		`{"Action":"output","Package":"a","Output":"FAIL\ta [build failed]\n"}`,
+		`{"Action":"fail","Package":"a","FailedBuild":"c"}`,
+		`{"Action":"output","Package":"b","Output":"FAIL\tb [build failed]\n"}`,
+		`{"Action":"fail","Package":"b","FailedBuild":"c"}`,
+	}, "\n") + "\n"
+	results, _ := ParseTestJSON(log)
+	want := map[string]string{
+		"a": "c0\nc1\nc2\nFAIL\ta [build failed]\n",
+		"b": "c0\nc1\nc2\nFAIL\tb [build failed]\n",
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results; want %d", len(results), len(want))
+	}
+	for _, r := range results {
+		if !r.BuildFailed {
+			t.Errorf("package %s: BuildFailed = false; want true", r.Pkg)
+		}
+		if r.Output != want[r.Pkg] {
+			t.Errorf("package %s: Output = %q; want %q", r.Pkg, r.Output, want[r.Pkg])
+		}
+	}
+}
@@ -53,6 +53,10 @@ const (
 	// NameMaintnerGitHubToken is the secret name for the Maintner GitHub token.
 	NameMaintnerGitHubToken = "maintner-github-token"
 
+	// NameMaintnerManifestKey is the secret name for the PEM-encoded
+	// Ed25519 key Maintner signs its mutation log manifest with.
+	NameMaintnerManifestKey = "maintner-manifest-key"
+
 	// NameWatchflakesGitHubToken is the secret name for the watchflakes GitHub token.
 	NameWatchflakesGitHubToken = "watchflakes-github-token"
 
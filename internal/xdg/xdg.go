@@ -0,0 +1,41 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package xdg locates the directories of the XDG Base Directory
+// Specification, such as the cache directory that fetchlogs saves
+// logs to and that greplogs and difflogs read them from.
+package xdg
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+)
+
+// CacheDir returns the XDG Base Directory Specification cache
+// directory.
+func CacheDir() string {
+	cache := os.Getenv("XDG_CACHE_HOME")
+	if cache != "" {
+		return cache
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		if u, err := user.Current(); err == nil {
+			home = u.HomeDir
+		}
+	}
+	// Not XDG but standard for OS X.
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library/Caches")
+	}
+	return filepath.Join(home, ".cache")
+}
+
+// CreateDir creates a directory and its parents in accordance with
+// the XDG Base Directory Specification.
+func CreateDir(path string) error {
+	return os.MkdirAll(path, 0700)
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package xdg
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCacheDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	if got, want := CacheDir(), "/xdg/cache"; got != want {
+		t.Errorf("CacheDir() with XDG_CACHE_HOME = %q; want %q", got, want)
+	}
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "/home/gopher")
+	want := filepath.Join("/home/gopher", ".cache")
+	if runtime.GOOS == "darwin" {
+		want = filepath.Join("/home/gopher", "Library/Caches")
+	}
+	if got := CacheDir(); got != want {
+		t.Errorf("CacheDir() with HOME = %q; want %q", got, want)
+	}
+
+}
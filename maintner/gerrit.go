@@ -310,6 +310,24 @@ type GerritCL struct {
 
 	// Messages contains all of the messages for this CL, in sorted order.
 	Messages []*GerritMessage
+
+	// Comments contains the published inline comments on the files
+	// of this CL's patch sets, sorted by time.
+	Comments []*GerritComment
+
+	// UnresolvedCommentCount is the number of comment threads
+	// whose most recent comment is unresolved.
+	UnresolvedCommentCount int
+
+	// AttentionSet contains the users whose attention this CL
+	// currently requires, in the order they were added.
+	AttentionSet []*GerritAttention
+
+	// notes are the NoteDb notes in the tree of the meta commit
+	// notesMeta, keyed by the hex hash of their patch set commit.
+	// They're only set by syncNotes, to skip unchanged notes.
+	notes     map[string]gerritNote
+	notesMeta GitHash
 }
 
 // complete reports whether cl is complete.
@@ -468,16 +486,7 @@ func (cl *GerritCL) OwnerID() int {
 	}
 	// Meta commits caused by the owner of a change have an email of the form
 	// <user id>@<uuid of gerrit server>.
-	email := cl.Metas[0].Commit.Author.Email()
-	idx := strings.Index(email, "@")
-	if idx == -1 {
-		return -1
-	}
-	id, err := strconv.Atoi(email[:idx])
-	if err != nil {
-		return -1
-	}
-	return id
+	return gerritAccountID(cl.Metas[0].Commit.Author.Email())
 }
 
 // Owner returns the author of the first commit to the CL. It returns nil on error.
@@ -768,6 +777,10 @@ func (gp *GerritProject) processMutation(gm *maintpb.GerritMutation) {
 		}
 	}
 
+	for _, np := range gm.Notes {
+		gp.processNotes(np)
+	}
+
 	for _, refName := range gm.DeletedRefs {
 		delete(gp.ref, refName)
 		// TODO: this doesn't delete change refs (from
@@ -792,6 +805,14 @@ func (gp *GerritProject) processMutation(gm *maintpb.GerritMutation) {
 				gp.logf("ERROR: non-change ref %v references unknown hash %v; ignoring", refp, hash)
 				continue
 			}
+			if old, ok := gp.ref[refName]; !ok || old != hash {
+				c.noteChange(ChangeEvent{
+					Kind:          RefMoved,
+					GerritProject: gp.proj,
+					Ref:           refName,
+					Hash:          hash,
+				})
+			}
 			gp.ref[refName] = hash
 			continue
 		}
@@ -814,6 +835,16 @@ func (gp *GerritProject) processMutation(gm *maintpb.GerritMutation) {
 			cl.Meta = newGerritMeta(gc, cl)
 			gp.noteDirtyCL(cl) // needs processing at end of sync
 		} else {
+			if clv.Version > cl.Version {
+				c.noteChange(ChangeEvent{
+					Kind:          CLNewPatchSet,
+					Time:          gc.CommitTime,
+					GerritProject: gp.proj,
+					CL:            cl.Number,
+					PatchSet:      clv.Version,
+					Hash:          hash,
+				})
+			}
 			cl.Commit = gc
 			cl.Version = clv.Version
 			cl.updateGithubIssueRefs()
@@ -863,6 +894,7 @@ func (gp *GerritProject) finishProcessingCL(cl *GerritCL) {
 	}
 
 	foundStatus := ""
+	old := watchState{status: cl.Status, metas: len(cl.Metas)}
 
 	// Walk from the newest meta commit backwards, so we store the messages
 	// in reverse order and then flip the array before setting on the
@@ -910,6 +942,9 @@ func (gp *GerritProject) finishProcessingCL(cl *GerritCL) {
 	cl.Created = cl.Metas[0].Commit.CommitTime
 
 	cl.updateBranch()
+	cl.updateAttentionSet()
+
+	gp.gerritCLChanges(cl, old)
 }
 
 // clSliceContains reports whether cls contains cl.
@@ -1189,7 +1224,7 @@ func (gp *GerritProject) syncOnce(ctx context.Context) error {
 		changedRefs = changedRefs[len(batch):]
 	}
 
-	return nil
+	return gp.syncNotes(ctx)
 }
 
 func (gp *GerritProject) syncCommits(ctx context.Context) (n int, err error) {
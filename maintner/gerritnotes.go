@@ -0,0 +1,547 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Logic to interact with the NoteDb notes of Gerrit meta commits,
+// which hold inline comments, and the attention set footers.
+
+package maintner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/build/internal/envutil"
+	"golang.org/x/build/maintner/maintpb"
+)
+
+// GerritComment is a published inline comment on a file of a CL's
+// patch set.
+type GerritComment struct {
+	// ID is the comment's UUID.
+	ID string
+	// InReplyTo is the ID of the comment this one replies to,
+	// or empty if it starts a thread.
+	InReplyTo string
+
+	PatchSet int32
+	File     string // file path, or a magic path like "/COMMIT_MSG"
+	// Line is the line the comment is on, or 0 for a file comment.
+	Line int32
+	// Range, if non-nil, is the range of the file the comment is on.
+	Range *GerritCommentRange
+	// Side is 1 if the comment is on the patch set, or 0 for its
+	// parent. Negative values select a parent of a merge commit.
+	Side int
+
+	// AuthorID is the Gerrit account ID of the comment's author.
+	AuthorID int
+	Message  string
+	Date     time.Time
+
+	// Unresolved is whether the comment left its thread unresolved.
+	Unresolved bool
+}
+
+// GerritCommentRange is a range of characters in a file.
+type GerritCommentRange struct {
+	StartLine, StartChar int32
+	EndLine, EndChar     int32
+}
+
+// GerritAttention is an account in a CL's attention set.
+type GerritAttention struct {
+	// AccountID is the Gerrit account ID of the user.
+	AccountID int
+	// Reason is why the user was added to the attention set,
+	// such as "Reviewer was added".
+	Reason string
+	// Added is when the user was added.
+	Added time.Time
+}
+
+// InAttentionSet reports whether the Gerrit account ID is in the CL's
+// attention set.
+func (cl *GerritCL) InAttentionSet(accountID int) bool {
+	for _, a := range cl.AttentionSet {
+		if a.AccountID == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// gerritAccountID returns the Gerrit account ID in a NoteDb email of
+// the form <account id>@<uuid of gerrit server>, or -1 if there isn't one.
+func gerritAccountID(email string) int {
+	idx := strings.Index(email, "@")
+	if idx == -1 {
+		return -1
+	}
+	id, err := strconv.Atoi(email[:idx])
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+// updateAttentionSet recomputes cl.AttentionSet from the "Attention:"
+// footers of its meta commits.
+//
+// Corpus.mu must be held.
+func (cl *GerritCL) updateAttentionSet() {
+	var set []*GerritAttention
+	for _, m := range cl.Metas {
+		footer := m.Footer()
+		for {
+			v, rest, ok := lineValueOK(footer, "Attention:")
+			if !ok {
+				break
+			}
+			footer = rest
+			var upd struct {
+				PersonIdent string `json:"person_ident"`
+				Operation   string `json:"operation"`
+				Reason      string `json:"reason"`
+			}
+			if err := json.Unmarshal([]byte(v), &upd); err != nil {
+				continue
+			}
+			id := gerritAccountID((&GitPerson{Str: upd.PersonIdent}).Email())
+			if id == -1 {
+				continue
+			}
+			for i, a := range set {
+				if a.AccountID == id {
+					set = append(set[:i:i], set[i+1:]...)
+					break
+				}
+			}
+			if upd.Operation == "ADD" {
+				set = append(set, &GerritAttention{
+					AccountID: id,
+					Reason:    upd.Reason,
+					Added:     m.Commit.CommitTime,
+				})
+			}
+		}
+	}
+	cl.AttentionSet = set
+}
+
+// processNotes applies a GerritNotes mutation to its CL.
+//
+// Corpus.mu must be held.
+func (gp *GerritProject) processNotes(np *maintpb.GerritNotes) {
+	cl := gp.getOrCreateCL(np.Cl)
+	// Copy the comments, which syncNotes might be reading.
+	comments := append([]*GerritComment(nil), cl.Comments...)
+	for _, pc := range np.Comments {
+		i := 0
+		for i < len(comments) && comments[i].ID != pc.Uuid {
+			i++
+		}
+		if pc.Deleted {
+			if i < len(comments) {
+				comments = append(comments[:i:i], comments[i+1:]...)
+			}
+			continue
+		}
+		cm := gerritCommentFromProto(pc)
+		if i < len(comments) {
+			comments = append(comments[:i:i], comments[i+1:]...)
+		}
+		comments = append(comments, cm)
+	}
+	cl.updateComments(comments)
+}
+
+// gerritCommentFromProto returns the comment recorded in pc.
+func gerritCommentFromProto(pc *maintpb.GerritComment) *GerritComment {
+	cm := &GerritComment{
+		ID:         pc.Uuid,
+		InReplyTo:  pc.ParentUuid,
+		PatchSet:   pc.PatchSet,
+		File:       pc.File,
+		Line:       pc.Line,
+		Side:       int(pc.Side),
+		AuthorID:   int(pc.AuthorId),
+		Message:    pc.Message,
+		Unresolved: pc.Unresolved,
+	}
+	if r := pc.Range; r != nil {
+		cm.Range = &GerritCommentRange{
+			StartLine: r.StartLine,
+			StartChar: r.StartChar,
+			EndLine:   r.EndLine,
+			EndChar:   r.EndChar,
+		}
+	}
+	if pc.Date != nil {
+		cm.Date, _ = ptypes.Timestamp(pc.Date)
+	}
+	return cm
+}
+
+// gerritCommentProto returns the mutation recording cm.
+func gerritCommentProto(cm *GerritComment) *maintpb.GerritComment {
+	pc := &maintpb.GerritComment{
+		Uuid:       cm.ID,
+		ParentUuid: cm.InReplyTo,
+		PatchSet:   cm.PatchSet,
+		File:       cm.File,
+		Line:       cm.Line,
+		Side:       int32(cm.Side),
+		AuthorId:   int64(cm.AuthorID),
+		Message:    cm.Message,
+		Unresolved: cm.Unresolved,
+	}
+	if r := cm.Range; r != nil {
+		pc.Range = &maintpb.GerritCommentRange{
+			StartLine: r.StartLine,
+			StartChar: r.StartChar,
+			EndLine:   r.EndLine,
+			EndChar:   r.EndChar,
+		}
+	}
+	if !cm.Date.IsZero() {
+		pc.Date, _ = ptypes.TimestampProto(cm.Date)
+	}
+	return pc
+}
+
+// updateComments sets cl.Comments to comments, sorted by time,
+// and recomputes cl.UnresolvedCommentCount.
+//
+// Corpus.mu must be held.
+func (cl *GerritCL) updateComments(comments []*GerritComment) {
+	sort.Slice(comments, func(i, j int) bool {
+		ci, cj := comments[i], comments[j]
+		if !ci.Date.Equal(cj.Date) {
+			return ci.Date.Before(cj.Date)
+		}
+		return ci.ID < cj.ID
+	})
+	cl.Comments = comments
+
+	// A thread is unresolved if its most recent comment is.
+	byID := make(map[string]*GerritComment, len(comments))
+	for _, cm := range comments {
+		byID[cm.ID] = cm
+	}
+	root := func(cm *GerritComment) string {
+		for i := 0; i < len(comments) && cm.InReplyTo != ""; i++ {
+			parent, ok := byID[cm.InReplyTo]
+			if !ok {
+				break
+			}
+			cm = parent
+		}
+		return cm.ID
+	}
+	latest := make(map[string]*GerritComment) // thread root ID => latest comment
+	for _, cm := range comments {
+		latest[root(cm)] = cm // comments are sorted by time
+	}
+	cl.UnresolvedCommentCount = 0
+	for _, cm := range latest {
+		if cm.Unresolved {
+			cl.UnresolvedCommentCount++
+		}
+	}
+}
+
+// gerritCommentTimeFormats are the formats NoteDb has used for
+// comment timestamps, which are in UTC.
+var gerritCommentTimeFormats = []string{
+	"2006-01-02 15:04:05.999999999",
+	"Jan 2, 2006 3:04:05 PM",
+}
+
+// parseGerritCommentNote parses the JSON comments in a NoteDb note.
+func parseGerritCommentNote(data []byte) ([]*GerritComment, error) {
+	var note struct {
+		Comments []struct {
+			Key struct {
+				UUID       string `json:"uuid"`
+				Filename   string `json:"filename"`
+				PatchSetID int32  `json:"patchSetId"`
+			} `json:"key"`
+			LineNbr int32 `json:"lineNbr"`
+			Author  struct {
+				ID int `json:"id"`
+			} `json:"author"`
+			WrittenOn  string `json:"writtenOn"`
+			Side       int    `json:"side"`
+			Message    string `json:"message"`
+			ParentUUID string `json:"parentUuid"`
+			Range      *struct {
+				StartLine int32 `json:"startLine"`
+				StartChar int32 `json:"startChar"`
+				EndLine   int32 `json:"endLine"`
+				EndChar   int32 `json:"endChar"`
+			} `json:"range"`
+			Unresolved bool `json:"unresolved"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(data, &note); err != nil {
+		return nil, err
+	}
+	var comments []*GerritComment
+	for _, jc := range note.Comments {
+		cm := &GerritComment{
+			ID:         jc.Key.UUID,
+			InReplyTo:  jc.ParentUUID,
+			PatchSet:   jc.Key.PatchSetID,
+			File:       jc.Key.Filename,
+			Line:       jc.LineNbr,
+			Side:       jc.Side,
+			AuthorID:   jc.Author.ID,
+			Message:    jc.Message,
+			Unresolved: jc.Unresolved,
+		}
+		if r := jc.Range; r != nil {
+			cm.Range = &GerritCommentRange{
+				StartLine: r.StartLine,
+				StartChar: r.StartChar,
+				EndLine:   r.EndLine,
+				EndChar:   r.EndChar,
+			}
+		}
+		for _, layout := range gerritCommentTimeFormats {
+			if t, err := time.Parse(layout, jc.WrittenOn); err == nil {
+				cm.Date = t
+				break
+			}
+		}
+		comments = append(comments, cm)
+	}
+	return comments, nil
+}
+
+// A gerritNote is a NoteDb note holding the comments on a patch set.
+type gerritNote struct {
+	blob     string // hex hash of the note's blob
+	comments []*GerritComment
+}
+
+// syncNotes records the new or changed comments of CLs whose meta
+// commits have changed since their notes were last read.
+func (gp *GerritProject) syncNotes(ctx context.Context) error {
+	c := gp.gerrit.c
+	type work struct {
+		cl       *GerritCL
+		meta     GitHash
+		notes    map[string]gerritNote
+		comments []*GerritComment
+	}
+	var todo []work
+	c.mu.RLock()
+	for _, cl := range gp.cls {
+		if cl.Meta != nil && cl.notesMeta != cl.Meta.Commit.Hash {
+			todo = append(todo, work{cl, cl.Meta.Commit.Hash, cl.notes, cl.Comments})
+		}
+	}
+	c.mu.RUnlock()
+	if len(todo) == 0 {
+		return nil
+	}
+	sort.Slice(todo, func(i, j int) bool { return todo[i].cl.Number < todo[j].cl.Number })
+
+	gp.logf("syncing notes of %d CLs", len(todo))
+	batch, err := gp.startCatFile(ctx)
+	if err != nil {
+		return err
+	}
+	defer batch.Close()
+	for i, w := range todo {
+		if i > 0 && i%1000 == 0 {
+			gp.logf("synced notes of %d CLs, %d remain", i, len(todo)-i)
+		}
+		notes, err := gp.readNotes(batch, w.meta, w.notes)
+		if err != nil {
+			return fmt.Errorf("notes of CL %d: %v", w.cl.Number, err)
+		}
+		if changes := commentChanges(w.comments, notes); len(changes) > 0 {
+			c.addMutation(&maintpb.Mutation{
+				Gerrit: &maintpb.GerritMutation{
+					Project: gp.proj,
+					Notes: []*maintpb.GerritNotes{{
+						Cl:       w.cl.Number,
+						Comments: changes,
+					}},
+				},
+			})
+		}
+		c.mu.Lock()
+		w.cl.notes, w.cl.notesMeta = notes, w.meta
+		c.mu.Unlock()
+	}
+	return batch.Close()
+}
+
+// commentChanges returns the mutations that turn the comments old
+// into the comments in notes: the new or changed comments, and the
+// removed ones.
+func commentChanges(old []*GerritComment, notes map[string]gerritNote) []*maintpb.GerritComment {
+	oldByID := make(map[string]*GerritComment, len(old))
+	for _, cm := range old {
+		oldByID[cm.ID] = cm
+	}
+	var changes []*maintpb.GerritComment
+	seen := make(map[string]bool)
+	for _, n := range notes {
+		for _, cm := range n.comments {
+			seen[cm.ID] = true
+			pc := gerritCommentProto(cm)
+			if o := oldByID[cm.ID]; o == nil || !proto.Equal(gerritCommentProto(o), pc) {
+				changes = append(changes, pc)
+			}
+		}
+	}
+	for _, cm := range old {
+		if !seen[cm.ID] {
+			changes = append(changes, &maintpb.GerritComment{Uuid: cm.ID, Deleted: true})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Uuid < changes[j].Uuid })
+	return changes
+}
+
+// readNotes returns the notes in the tree of the meta commit, keyed by
+// the hex hash of their patch set commit. Notes whose blobs are the
+// same as in prev are reused rather than read and parsed again.
+// Notes are named by the hex hash of the annotated object, possibly
+// split into fan-out directories such as "ab/cdef...".
+func (gp *GerritProject) readNotes(batch *catFile, meta GitHash, prev map[string]gerritNote) (map[string]gerritNote, error) {
+	notes := make(map[string]gerritNote)
+	var walk func(rev, dir string) error
+	walk = func(rev, dir string) error {
+		tree, err := batch.read(rev, "tree")
+		if err != nil {
+			return err
+		}
+		for len(tree) > 0 {
+			// <mode> SP <name> NUL <20-byte object hash>
+			mode, rest, ok := bytes.Cut(tree, []byte(" "))
+			name, rest, ok2 := bytes.Cut(rest, []byte{0})
+			if !ok || !ok2 || len(rest) < 20 {
+				return fmt.Errorf("malformed tree %s", rev)
+			}
+			obj := fmt.Sprintf("%x", rest[:20])
+			tree = rest[20:]
+			if string(mode) == "40000" {
+				if err := walk(obj, dir+string(name)); err != nil {
+					return err
+				}
+				continue
+			}
+			annotated := dir + string(name)
+			if len(annotated) != 40 || strings.Trim(annotated, "0123456789abcdef") != "" {
+				continue
+			}
+			if n, ok := prev[annotated]; ok && n.blob == obj {
+				notes[annotated] = n
+				continue
+			}
+			data, err := batch.read(obj, "blob")
+			if err != nil {
+				return err
+			}
+			cs, err := parseGerritCommentNote(data)
+			if err != nil {
+				// Record the note anyway, so as not to read it again.
+				gp.logf("bad comment note for %s in %v: %v", annotated, meta, err)
+			}
+			notes[annotated] = gerritNote{blob: obj, comments: cs}
+		}
+		return nil
+	}
+	if err := walk(meta.String()+"^{tree}", ""); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// A catFile reads objects from a "git cat-file --batch" process,
+// so that reading many objects doesn't fork a process for each.
+type catFile struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr bytes.Buffer
+	closed bool
+}
+
+// startCatFile starts a "git cat-file --batch" process in the
+// project's git directory.
+func (gp *GerritProject) startCatFile(ctx context.Context) (*catFile, error) {
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	envutil.SetDir(cmd, gp.gitDir())
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	b := &catFile{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}
+	cmd.Stderr = &b.stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git cat-file --batch: %v", err)
+	}
+	return b, nil
+}
+
+// read returns the contents of the object rev, which must be of type typ.
+func (b *catFile) read(rev, typ string) ([]byte, error) {
+	if _, err := fmt.Fprintf(b.stdin, "%s\n", rev); err != nil {
+		return nil, fmt.Errorf("git cat-file --batch: %v", err)
+	}
+	header, err := b.stdout.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file --batch: %v", err)
+	}
+	// <object> SP <type> SP <size> LF <contents> LF
+	f := strings.Fields(header)
+	if len(f) != 3 {
+		return nil, fmt.Errorf("git cat-file %s: %s", rev, strings.TrimSpace(header))
+	}
+	size, err := strconv.Atoi(f[2])
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file %s: bad header %q", rev, header)
+	}
+	data := make([]byte, size+1)
+	if _, err := io.ReadFull(b.stdout, data); err != nil {
+		return nil, fmt.Errorf("git cat-file %s: %v", rev, err)
+	}
+	if f[1] != typ {
+		return nil, fmt.Errorf("git cat-file %s: got %s, want %s", rev, f[1], typ)
+	}
+	return data[:size], nil
+}
+
+// Close stops the process. It's safe to call more than once.
+func (b *catFile) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	b.stdin.Close()
+	if err := b.cmd.Wait(); err != nil {
+		return fmt.Errorf("git cat-file --batch: %v; stderr=%q", err, b.stderr.Bytes())
+	}
+	return nil
+}
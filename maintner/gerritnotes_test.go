@@ -0,0 +1,292 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/build/maintner/maintpb"
+)
+
+const testCommentNote = `{
+  "comments": [
+    {
+      "key": {"uuid": "aaa", "filename": "main.go", "patchSetId": 1},
+      "lineNbr": 10,
+      "author": {"id": 1000},
+      "writtenOn": "2024-01-02 03:04:05.0",
+      "side": 1,
+      "message": "Why?",
+      "range": {"startLine": 10, "startChar": 2, "endLine": 10, "endChar": 8},
+      "revId": "1111111111111111111111111111111111111111",
+      "serverId": "62eb7196-b449-3ce5-99f1-c037f21e1705",
+      "unresolved": true
+    },
+    {
+      "key": {"uuid": "bbb", "filename": "main.go", "patchSetId": 1},
+      "lineNbr": 10,
+      "author": {"id": 2000},
+      "writtenOn": "2024-01-02 04:00:00.0",
+      "side": 1,
+      "message": "Done",
+      "parentUuid": "aaa",
+      "unresolved": false
+    },
+    {
+      "key": {"uuid": "ccc", "filename": "/COMMIT_MSG", "patchSetId": 1},
+      "author": {"id": 1000},
+      "writtenOn": "Jan 3, 2024 1:00:00 PM",
+      "side": 1,
+      "message": "Typo.",
+      "unresolved": true
+    }
+  ]
+}`
+
+func TestParseGerritCommentNote(t *testing.T) {
+	got, err := parseGerritCommentNote([]byte(testCommentNote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*GerritComment{
+		{
+			ID:         "aaa",
+			PatchSet:   1,
+			File:       "main.go",
+			Line:       10,
+			Range:      &GerritCommentRange{StartLine: 10, StartChar: 2, EndLine: 10, EndChar: 8},
+			Side:       1,
+			AuthorID:   1000,
+			Message:    "Why?",
+			Date:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Unresolved: true,
+		},
+		{
+			ID:        "bbb",
+			InReplyTo: "aaa",
+			PatchSet:  1,
+			File:      "main.go",
+			Line:      10,
+			Side:      1,
+			AuthorID:  2000,
+			Message:   "Done",
+			Date:      time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:         "ccc",
+			PatchSet:   1,
+			File:       "/COMMIT_MSG",
+			Side:       1,
+			AuthorID:   1000,
+			Message:    "Typo.",
+			Date:       time.Date(2024, 1, 3, 13, 0, 0, 0, time.UTC),
+			Unresolved: true,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for i := range got {
+			t.Errorf("comment %d = %+v", i, got[i])
+		}
+		t.Fatalf("parse mismatch")
+	}
+}
+
+func TestProcessGerritNotes(t *testing.T) {
+	var c Corpus
+	c.EnableLeaderMode(new(dummyMutationLogger), "/fake/dir")
+	c.initGerrit()
+	gp := c.gerrit.getOrCreateProject("go.googlesource.com/build")
+	apply := func(comments ...*maintpb.GerritComment) {
+		c.processMutationLocked(&maintpb.Mutation{Gerrit: &maintpb.GerritMutation{
+			Project: "go.googlesource.com/build",
+			Notes:   []*maintpb.GerritNotes{{Cl: 123, Comments: comments}},
+		}})
+	}
+	parsed, err := parseGerritCommentNote([]byte(testCommentNote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pcs []*maintpb.GerritComment
+	for _, cm := range parsed {
+		pcs = append(pcs, gerritCommentProto(cm))
+	}
+	apply(pcs...)
+	cl := gp.cls[123]
+	if !reflect.DeepEqual(cl.Comments, parsed) {
+		for _, cm := range cl.Comments {
+			t.Errorf("got %+v", cm)
+		}
+		t.Fatalf("comments don't round-trip through their mutations")
+	}
+	// Thread "aaa" was resolved by its reply; "ccc" is unresolved.
+	if cl.UnresolvedCommentCount != 1 {
+		t.Errorf("UnresolvedCommentCount = %d; want 1", cl.UnresolvedCommentCount)
+	}
+
+	// Deleting the reply unresolves its thread, and changed
+	// comments replace the old ones.
+	edited := gerritCommentProto(parsed[2])
+	edited.Message = "Comment removed."
+	apply(&maintpb.GerritComment{Uuid: "bbb", Deleted: true}, edited)
+	var ids []string
+	for _, cm := range cl.Comments {
+		ids = append(ids, cm.ID)
+	}
+	if want := []string{"aaa", "ccc"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("comment IDs = %q; want %q", ids, want)
+	}
+	if got := cl.Comments[1].Message; got != "Comment removed." {
+		t.Errorf("edited message = %q", got)
+	}
+	if cl.UnresolvedCommentCount != 2 {
+		t.Errorf("UnresolvedCommentCount = %d; want 2", cl.UnresolvedCommentCount)
+	}
+}
+
+func TestUpdateAttentionSet(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	meta := func(t time.Time, footer string) *GerritMeta {
+		return &GerritMeta{Commit: &GitCommit{
+			Msg:        "Update patch set 1\n\nPatch-set: 1\n" + footer,
+			CommitTime: t,
+		}}
+	}
+	cl := &GerritCL{Metas: []*GerritMeta{
+		meta(t1, `Attention: {"person_ident":"Gerrit User 1000 <1000@62eb7196-b449-3ce5-99f1-c037f21e1705>","operation":"ADD","reason":"Reviewer was added"}
+Attention: {"person_ident":"Gerrit User 2000 <2000@62eb7196-b449-3ce5-99f1-c037f21e1705>","operation":"ADD","reason":"Reviewer was added"}`),
+		meta(t2, `Label: Code-Review=+2
+Attention: {"person_ident":"Gerrit User 1000 <1000@62eb7196-b449-3ce5-99f1-c037f21e1705>","operation":"REMOVE","reason":"<GERRIT_ACCOUNT_1000> replied on the change"}
+Attention: {"person_ident":"Gerrit User 3000 <3000@62eb7196-b449-3ce5-99f1-c037f21e1705>","operation":"ADD","reason":"Owner"}
+Attention: bogus`),
+	}}
+	cl.updateAttentionSet()
+	want := []*GerritAttention{
+		{AccountID: 2000, Reason: "Reviewer was added", Added: t1},
+		{AccountID: 3000, Reason: "Owner", Added: t2},
+	}
+	if !reflect.DeepEqual(cl.AttentionSet, want) {
+		for _, a := range cl.AttentionSet {
+			t.Errorf("got %+v", a)
+		}
+		t.Errorf("attention set mismatch")
+	}
+	if cl.InAttentionSet(1000) || !cl.InAttentionSet(3000) {
+		t.Errorf("InAttentionSet(1000), InAttentionSet(3000) = %v, %v; want false, true",
+			cl.InAttentionSet(1000), cl.InAttentionSet(3000))
+	}
+}
+
+func TestReadNotes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	var c Corpus
+	c.EnableLeaderMode(new(dummyMutationLogger), t.TempDir())
+	c.initGerrit()
+	gp := c.gerrit.getOrCreateProject("go.googlesource.com/build")
+	dir := gp.gitDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=gopher", "GIT_AUTHOR_EMAIL=gopher@example.com",
+			"GIT_COMMITTER_NAME=gopher", "GIT_COMMITTER_EMAIL=gopher@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name, data string) {
+		t.Helper()
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const (
+		ps1 = "1111111111111111111111111111111111111111"
+		ps2 = "2222222222222222222222222222222222222222"
+	)
+	git("init", "-q")
+	write(ps1, testCommentNote)
+	git("add", ".")
+	git("commit", "-q", "-m", "Create change")
+	meta1 := git("rev-parse", "HEAD")
+	write("22/"+ps2[2:], `{"comments": [{"key": {"uuid": "ddd", "filename": "main.go", "patchSetId": 2}, "message": "New."}]}`) // fan-out
+	git("add", ".")
+	git("commit", "-q", "-m", "Update patch set 2")
+	meta2 := git("rev-parse", "HEAD")
+
+	hash := c.gitHashFromHexStr
+	ids := func(changes []*maintpb.GerritComment) []string {
+		var ids []string
+		for _, pc := range changes {
+			id := pc.Uuid
+			if pc.Deleted {
+				id += " (deleted)"
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	batch, err := gp.startCatFile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer batch.Close()
+	notes1, err := gp.readNotes(batch, hash(meta1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes1) != 1 || len(notes1[ps1].comments) != 3 {
+		t.Fatalf("notes of meta1 = %+v; want 3 comments on %s", notes1, ps1)
+	}
+	if got, want := ids(commentChanges(nil, notes1)), []string{"aaa", "bbb", "ccc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changes from scratch = %q; want %q", got, want)
+	}
+	// Comments that are already recorded aren't recorded again.
+	if got := commentChanges(notes1[ps1].comments, notes1); len(got) != 0 {
+		t.Errorf("changes with no new comments = %v; want none", got)
+	}
+
+	notes2, err := gp.readNotes(batch, hash(meta2), notes1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes2) != 2 {
+		t.Fatalf("notes of meta2 = %+v; want 2", notes2)
+	}
+	if notes2[ps1].comments[0] != notes1[ps1].comments[0] {
+		t.Errorf("unchanged note of %s was read again", ps1)
+	}
+	old := append(notes1[ps1].comments, &GerritComment{ID: "zzz"})
+	if got, want := ids(commentChanges(old, notes2)), []string{"ddd", "zzz (deleted)"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %q; want %q", got, want)
+	}
+
+	if _, err := gp.readNotes(batch, hash(strings.Repeat("3", 40)), nil); err == nil {
+		t.Errorf("reading the notes of a missing commit succeeded")
+	}
+	if err := batch.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
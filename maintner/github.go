@@ -21,10 +21,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/go-github/v48/github"
 	"github.com/gregjones/httpcache"
+	"github.com/shurcooL/githubv4"
 	"golang.org/x/build/maintner/maintpb"
 	"golang.org/x/oauth2"
 	"golang.org/x/sync/errgroup"
@@ -112,6 +114,8 @@ type GitHubRepo struct {
 	issues     map[int32]*GitHubIssue // num -> issue
 	milestones map[int64]*GitHubMilestone
 	labels     map[int64]*GitHubLabel
+
+	discussions map[int32]*GitHubDiscussion // num -> discussion
 }
 
 func (gr *GitHubRepo) ID() GitHubRepoID { return gr.id }
@@ -190,6 +194,106 @@ func (pr *GitHubIssue) ForeachReview(fn func(*GitHubReview) error) error {
 	return nil
 }
 
+// ForeachReviewComment calls fn for each review comment on the
+// lines of the pull request's diff.
+//
+// If the issue is not a PullRequest, then it returns early with no error.
+//
+// If fn returns an error, iteration ends and ForeachReviewComment
+// returns with that error.
+//
+// The fn function is called serially, in order of the comment's time.
+func (pr *GitHubIssue) ForeachReviewComment(fn func(*GitHubReviewComment) error) error {
+	if !pr.PullRequest {
+		return nil
+	}
+	s := make([]*GitHubReviewComment, 0, len(pr.reviewComments))
+	for _, rc := range pr.reviewComments {
+		s = append(s, rc)
+	}
+	sort.Slice(s, func(i, j int) bool {
+		ci, cj := s[i].Created, s[j].Created
+		if ci.Before(cj) {
+			return true
+		}
+		return ci.Equal(cj) && s[i].ID < s[j].ID
+	})
+	for _, rc := range s {
+		if err := fn(rc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForeachCheckRun calls fn for each known check run on the pull
+// request's commits. Callers interested only in the current state
+// should skip check runs whose CommitID is not pr.HeadCommitID.
+//
+// If the issue is not a PullRequest, then it returns early with no error.
+//
+// If fn returns an error, iteration ends and ForeachCheckRun returns
+// with that error.
+//
+// The fn function is called serially, in order of the check run's
+// start time.
+func (pr *GitHubIssue) ForeachCheckRun(fn func(*GitHubCheckRun) error) error {
+	if !pr.PullRequest {
+		return nil
+	}
+	s := make([]*GitHubCheckRun, 0, len(pr.checkRuns))
+	for _, cr := range pr.checkRuns {
+		s = append(s, cr)
+	}
+	sort.Slice(s, func(i, j int) bool {
+		ti, tj := s[i].Started, s[j].Started
+		if ti.Before(tj) {
+			return true
+		}
+		return ti.Equal(tj) && s[i].ID < s[j].ID
+	})
+	for _, cr := range s {
+		if err := fn(cr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForeachCommitStatus calls fn for each known commit status on the
+// pull request's commits. Callers interested only in the current
+// state should skip statuses whose CommitID is not pr.HeadCommitID.
+//
+// If the issue is not a PullRequest, then it returns early with no error.
+//
+// If fn returns an error, iteration ends and ForeachCommitStatus
+// returns with that error.
+//
+// The fn function is called serially, in order of the status's
+// creation time.
+func (pr *GitHubIssue) ForeachCommitStatus(fn func(*GitHubCommitStatus) error) error {
+	if !pr.PullRequest {
+		return nil
+	}
+	s := make([]*GitHubCommitStatus, 0, len(pr.commitStatuses))
+	for _, st := range pr.commitStatuses {
+		s = append(s, st)
+	}
+	sort.Slice(s, func(i, j int) bool {
+		ti, tj := s[i].Created, s[j].Created
+		if ti.Before(tj) {
+			return true
+		}
+		return ti.Equal(tj) && s[i].ID < s[j].ID
+	})
+	for _, st := range s {
+		if err := fn(st); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (g *GitHubRepo) getOrCreateMilestone(id int64) *GitHubMilestone {
 	if id == 0 {
 		panic("zero id")
@@ -276,6 +380,15 @@ type GitHubIssue struct {
 	Milestone   *GitHubMilestone       // nil for unknown, noMilestone for none
 	Labels      map[int64]*GitHubLabel // label ID => label
 
+	// AuthorAssociation is the author's relationship to the repository,
+	// like "MEMBER", "CONTRIBUTOR" or "NONE". It may be empty for
+	// issues last synced before it was tracked.
+	AuthorAssociation string
+
+	// HeadCommitID is the head commit of a pull request, as of the
+	// last sync of its checks. It is empty for issues.
+	HeadCommitID string
+
 	commentsUpdatedTil time.Time                   // max comment modtime seen
 	commentsSyncedAsOf time.Time                   // as of server's Date header
 	comments           map[int64]*GitHubComment    // by comment.ID
@@ -284,6 +397,13 @@ type GitHubIssue struct {
 	reviewsSyncedAsOf  time.Time                   // as of server's Date header
 	events             map[int64]*GitHubIssueEvent // by event.ID
 	reviews            map[int64]*GitHubReview     // by event.ID
+
+	reviewCommentsUpdatedTil time.Time                      // max review comment modtime seen
+	reviewCommentsSyncedAsOf time.Time                      // as of server's Date header
+	reviewComments           map[int64]*GitHubReviewComment // by comment.ID
+	checksSyncedAsOf         time.Time                      // as of server's Date header
+	checkRuns                map[int64]*GitHubCheckRun      // by check run ID
+	commitStatuses           map[int64]*GitHubCommitStatus  // by status ID
 }
 
 // LastModified reports the most recent time that any known metadata was updated.
@@ -563,6 +683,110 @@ func (r *GitHubRepo) newGithubReview(p *maintpb.GithubReview) *GitHubReview {
 	return e
 }
 
+// GitHubReviewComment is a comment on a line of a pull request's diff.
+// For more details, see https://docs.github.com/en/rest/pulls/comments
+type GitHubReviewComment struct {
+	ID        int64
+	ReviewID  int64 // the GitHubReview this comment is part of
+	User      *GitHubUser
+	Body      string
+	Path      string // file path, relative to the repo root
+	Line      int32  // line of the diff; 0 if the comment is outdated
+	Side      string // "LEFT" or "RIGHT"
+	CommitID  string
+	InReplyTo int64 // ID of the comment this replies to, if any
+	Created   time.Time
+	Updated   time.Time
+}
+
+// Proto converts GitHubReviewComment to a protobuf.
+func (rc *GitHubReviewComment) Proto() *maintpb.GithubReviewComment {
+	p := &maintpb.GithubReviewComment{
+		Id:        rc.ID,
+		ReviewId:  rc.ReviewID,
+		Body:      rc.Body,
+		Path:      rc.Path,
+		Line:      rc.Line,
+		Side:      rc.Side,
+		CommitId:  rc.CommitID,
+		InReplyTo: rc.InReplyTo,
+	}
+	if rc.User != nil {
+		p.User = &maintpb.GithubUser{Id: rc.User.ID, Login: rc.User.Login}
+	}
+	if !rc.Created.IsZero() {
+		p.Created, _ = ptypes.TimestampProto(rc.Created)
+	}
+	if !rc.Updated.IsZero() {
+		p.Updated, _ = ptypes.TimestampProto(rc.Updated)
+	}
+	return p
+}
+
+// GitHubCheckRun is a check run on a commit.
+// For more details, see https://docs.github.com/en/rest/checks/runs
+type GitHubCheckRun struct {
+	ID         int64
+	CommitID   string
+	Name       string
+	Status     string // "queued", "in_progress", "completed"
+	Conclusion string // "success", "failure", "neutral", etc.; empty until completed
+	DetailsURL string
+	Started    time.Time
+	Completed  time.Time
+}
+
+// Proto converts GitHubCheckRun to a protobuf.
+func (cr *GitHubCheckRun) Proto() *maintpb.GithubCheckRun {
+	p := &maintpb.GithubCheckRun{
+		Id:         cr.ID,
+		CommitId:   cr.CommitID,
+		Name:       cr.Name,
+		Status:     cr.Status,
+		Conclusion: cr.Conclusion,
+		DetailsUrl: cr.DetailsURL,
+	}
+	if !cr.Started.IsZero() {
+		p.Started, _ = ptypes.TimestampProto(cr.Started)
+	}
+	if !cr.Completed.IsZero() {
+		p.Completed, _ = ptypes.TimestampProto(cr.Completed)
+	}
+	return p
+}
+
+// GitHubCommitStatus is a commit status, the older form of check.
+// For more details, see https://docs.github.com/en/rest/commits/statuses
+type GitHubCommitStatus struct {
+	ID          int64
+	CommitID    string
+	Context     string // "continuous-integration/travis-ci"
+	State       string // "pending", "success", "failure", "error"
+	Description string
+	TargetURL   string
+	Created     time.Time
+	Updated     time.Time
+}
+
+// Proto converts GitHubCommitStatus to a protobuf.
+func (st *GitHubCommitStatus) Proto() *maintpb.GithubCommitStatus {
+	p := &maintpb.GithubCommitStatus{
+		Id:          st.ID,
+		CommitId:    st.CommitID,
+		Context:     st.Context,
+		State:       st.State,
+		Description: st.Description,
+		TargetUrl:   st.TargetURL,
+	}
+	if !st.Created.IsZero() {
+		p.Created, _ = ptypes.TimestampProto(st.Created)
+	}
+	if !st.Updated.IsZero() {
+		p.Updated, _ = ptypes.TimestampProto(st.Updated)
+	}
+	return p
+}
+
 type GitHubComment struct {
 	ID      int64
 	User    *GitHubUser
@@ -766,6 +990,67 @@ func (gi *GitHubIssue) reviewsSynced() bool {
 	return gi.reviewsSyncedAsOf.After(gi.Updated)
 }
 
+// reviewCommentsBackfillWindow is how recently a closed pull request
+// must have been updated for its review comments to be synced, if they
+// never have been. It keeps the first sync from fetching the review
+// comments of every pull request in a repo's history.
+const reviewCommentsBackfillWindow = 90 * 24 * time.Hour
+
+// reviewCommentsSynced reports whether the pull request's review
+// comments are up to date as of now. The review comments of a pull
+// request that was closed before the backfill window and never synced
+// are not synced until it is updated again.
+//
+// (requires corpus be locked for reads)
+func (gi *GitHubIssue) reviewCommentsSynced(now time.Time) bool {
+	if gi.NotExist || !gi.PullRequest {
+		return true
+	}
+	if gi.reviewCommentsSyncedAsOf.IsZero() && gi.Closed && now.Sub(gi.Updated) > reviewCommentsBackfillWindow {
+		return true
+	}
+	return gi.reviewCommentsSyncedAsOf.After(gi.Updated)
+}
+
+// checksSynced reports whether the pull request's checks are up to
+// date as of now. Checks are only synced while a pull request is open.
+// They can change without the pull request being updated, so an open
+// pull request with unfinished checks on its head commit is synced
+// again once pendingChecksBackoff has passed since the last sync.
+//
+// (requires corpus be locked for reads)
+func (gi *GitHubIssue) checksSynced(now time.Time) bool {
+	if gi.NotExist || !gi.PullRequest || gi.Closed {
+		return true
+	}
+	if !gi.checksSyncedAsOf.After(gi.Updated) {
+		return false
+	}
+	pending := false
+	for _, cr := range gi.checkRuns {
+		if cr.CommitID == gi.HeadCommitID && cr.Status != "completed" {
+			pending = true
+		}
+	}
+	for _, st := range gi.commitStatuses {
+		if st.CommitID == gi.HeadCommitID && st.State == "pending" {
+			pending = true
+		}
+	}
+	if !pending {
+		return true
+	}
+	return now.Sub(gi.checksSyncedAsOf) < pendingChecksBackoff(gi.checksSyncedAsOf.Sub(gi.Updated))
+}
+
+// pendingChecksBackoff returns how long to wait before syncing the
+// unfinished checks of a pull request again, given how long the pull
+// request had gone without an update when they were last synced.
+// Checks that have been pending for long are polled less often.
+func pendingChecksBackoff(age time.Duration) time.Duration {
+	return min(max(age/4, time.Minute), time.Hour)
+}
+
 func (c *Corpus) initGithub() {
 	if c.github != nil {
 		return
@@ -1016,6 +1301,7 @@ var issueDiffMethods = []func(githubIssueDiffer, *maintpb.GithubIssueMutation) b
 	githubIssueDiffer.diffCreatedAt,
 	githubIssueDiffer.diffUpdatedAt,
 	githubIssueDiffer.diffUser,
+	githubIssueDiffer.diffAuthorAssociation,
 	githubIssueDiffer.diffBody,
 	githubIssueDiffer.diffTitle,
 	githubIssueDiffer.diffMilestone,
@@ -1060,6 +1346,14 @@ func (d githubIssueDiffer) diffUser(m *maintpb.GithubIssueMutation) bool {
 	return m.User != nil
 }
 
+func (d githubIssueDiffer) diffAuthorAssociation(m *maintpb.GithubIssueMutation) bool {
+	if d.b.GetAuthorAssociation() == "" || d.a != nil && d.a.AuthorAssociation == d.b.GetAuthorAssociation() {
+		return false
+	}
+	m.AuthorAssociation = d.b.GetAuthorAssociation()
+	return true
+}
+
 func (d githubIssueDiffer) diffClosedBy(m *maintpb.GithubIssueMutation) bool {
 	var existing *GitHubUser
 	if d.a != nil {
@@ -1264,6 +1558,7 @@ func (c *Corpus) processGithubIssueMutation(m *maintpb.GithubIssueMutation) {
 		return
 	}
 	gi, ok := gr.issues[m.Number]
+	isNew := !ok
 	if !ok {
 		gi = &GitHubIssue{
 			// User added below
@@ -1313,6 +1608,7 @@ func (c *Corpus) processGithubIssueMutation(m *maintpb.GithubIssueMutation) {
 	if m.User != nil {
 		gi.User = c.github.getUser(m.User)
 	}
+	oldMilestone := gi.Milestone
 	if m.NoMilestone {
 		gi.Milestone = noMilestone
 	} else if m.MilestoneId != 0 {
@@ -1328,6 +1624,13 @@ func (c *Corpus) processGithubIssueMutation(m *maintpb.GithubIssueMutation) {
 		gi.ClosedBy = c.github.getUser(m.ClosedBy)
 	}
 	if b := m.Closed; b != nil {
+		if b.Val != gi.Closed && !isNew {
+			kind := IssueClosed
+			if !b.Val {
+				kind = IssueReopened
+			}
+			c.noteChange(gr.issueChange(kind, gi, gi.ClosedAt))
+		}
 		gi.Closed = b.Val
 	}
 	if b := m.Locked; b != nil {
@@ -1336,6 +1639,14 @@ func (c *Corpus) processGithubIssueMutation(m *maintpb.GithubIssueMutation) {
 	if m.PullRequest {
 		gi.PullRequest = true
 	}
+	if isNew {
+		c.noteChange(gr.issueChange(IssueOpened, gi, gi.Created))
+	}
+	if gi.Milestone != oldMilestone && gi.Milestone != nil && !(isNew && gi.Milestone.IsNone()) {
+		e := gr.issueChange(IssueMilestoned, gi, gi.Updated)
+		e.Milestone = gi.Milestone.Title
+		c.noteChange(e)
+	}
 
 	gi.Assignees = c.github.setAssigneesFromProto(gi.Assignees, m.Assignees, m.DeletedAssignees)
 
@@ -1348,16 +1659,29 @@ func (c *Corpus) processGithubIssueMutation(m *maintpb.GithubIssueMutation) {
 	if m.Title != "" {
 		gi.Title = m.Title
 	}
+	if m.AuthorAssociation != "" {
+		gi.AuthorAssociation = m.AuthorAssociation
+	}
 	if len(m.RemoveLabel) > 0 || len(m.AddLabel) > 0 {
 		if gi.Labels == nil {
 			gi.Labels = make(map[int64]*GitHubLabel)
 		}
 		for _, lid := range m.RemoveLabel {
+			if lb, ok := gi.Labels[lid]; ok {
+				e := gr.issueChange(IssueUnlabeled, gi, gi.Updated)
+				e.Label = lb.Name
+				c.noteChange(e)
+			}
 			delete(gi.Labels, lid)
 		}
 		for _, lp := range m.AddLabel {
 			lb := gr.getOrCreateLabel(lp.Id)
 			lb.processMutation(*lp)
+			if _, ok := gi.Labels[lp.Id]; !ok {
+				e := gr.issueChange(IssueLabeled, gi, gi.Updated)
+				e.Label = lb.Name
+				c.noteChange(e)
+			}
 			gi.Labels[lp.Id] = lb
 		}
 	}
@@ -1374,6 +1698,12 @@ func (c *Corpus) processGithubIssueMutation(m *maintpb.GithubIssueMutation) {
 			}
 			gc = &GitHubComment{ID: cmut.Id}
 			gi.comments[gc.ID] = gc
+			e := gr.issueChange(IssueCommented, gi, time.Time{})
+			e.CommentID = gc.ID
+			if cmut.Created != nil {
+				e.Time, _ = ptypes.Timestamp(cmut.Created)
+			}
+			c.noteChange(e)
 		}
 		if cmut.User != nil {
 			gc.User = c.github.getUser(cmut.User)
@@ -1435,6 +1765,107 @@ func (c *Corpus) processGithubIssueMutation(m *maintpb.GithubIssueMutation) {
 			gi.reviewsSyncedAsOf = serverDate.UTC()
 		}
 	}
+
+	for _, rcmut := range m.ReviewComment {
+		if rcmut.Id == 0 {
+			log.Printf("Ignoring bogus review comment mutation lacking Id: %v", rcmut)
+			continue
+		}
+		if gi.reviewComments == nil {
+			gi.reviewComments = make(map[int64]*GitHubReviewComment)
+		}
+		rc := &GitHubReviewComment{
+			ID:        rcmut.Id,
+			ReviewID:  rcmut.ReviewId,
+			Body:      rcmut.Body,
+			Path:      rcmut.Path,
+			Line:      rcmut.Line,
+			Side:      rcmut.Side,
+			CommitID:  rcmut.CommitId,
+			InReplyTo: rcmut.InReplyTo,
+		}
+		if rcmut.User != nil {
+			rc.User = c.github.getUser(rcmut.User)
+		}
+		if rcmut.Created != nil {
+			rc.Created, _ = ptypes.Timestamp(rcmut.Created)
+			rc.Created = rc.Created.UTC()
+		}
+		if rcmut.Updated != nil {
+			rc.Updated, _ = ptypes.Timestamp(rcmut.Updated)
+			rc.Updated = rc.Updated.UTC()
+		}
+		gi.reviewComments[rc.ID] = rc
+		if rc.Updated.After(gi.reviewCommentsUpdatedTil) {
+			gi.reviewCommentsUpdatedTil = rc.Updated
+		}
+	}
+	if m.ReviewCommentStatus != nil && m.ReviewCommentStatus.ServerDate != nil {
+		if serverDate, err := ptypes.Timestamp(m.ReviewCommentStatus.ServerDate); err == nil {
+			gi.reviewCommentsSyncedAsOf = serverDate.UTC()
+		}
+	}
+
+	if m.HeadCommitId != "" {
+		gi.HeadCommitID = m.HeadCommitId
+	}
+	for _, crmut := range m.CheckRun {
+		if crmut.Id == 0 {
+			log.Printf("Ignoring bogus check run mutation lacking Id: %v", crmut)
+			continue
+		}
+		if gi.checkRuns == nil {
+			gi.checkRuns = make(map[int64]*GitHubCheckRun)
+		}
+		cr := &GitHubCheckRun{
+			ID:         crmut.Id,
+			CommitID:   crmut.CommitId,
+			Name:       crmut.Name,
+			Status:     crmut.Status,
+			Conclusion: crmut.Conclusion,
+			DetailsURL: crmut.DetailsUrl,
+		}
+		if crmut.Started != nil {
+			cr.Started, _ = ptypes.Timestamp(crmut.Started)
+			cr.Started = cr.Started.UTC()
+		}
+		if crmut.Completed != nil {
+			cr.Completed, _ = ptypes.Timestamp(crmut.Completed)
+			cr.Completed = cr.Completed.UTC()
+		}
+		gi.checkRuns[cr.ID] = cr
+	}
+	for _, smut := range m.CommitStatus {
+		if smut.Id == 0 {
+			log.Printf("Ignoring bogus commit status mutation lacking Id: %v", smut)
+			continue
+		}
+		if gi.commitStatuses == nil {
+			gi.commitStatuses = make(map[int64]*GitHubCommitStatus)
+		}
+		st := &GitHubCommitStatus{
+			ID:          smut.Id,
+			CommitID:    smut.CommitId,
+			Context:     smut.Context,
+			State:       smut.State,
+			Description: smut.Description,
+			TargetURL:   smut.TargetUrl,
+		}
+		if smut.Created != nil {
+			st.Created, _ = ptypes.Timestamp(smut.Created)
+			st.Created = st.Created.UTC()
+		}
+		if smut.Updated != nil {
+			st.Updated, _ = ptypes.Timestamp(smut.Updated)
+			st.Updated = st.Updated.UTC()
+		}
+		gi.commitStatuses[st.ID] = st
+	}
+	if m.CheckStatus != nil && m.CheckStatus.ServerDate != nil {
+		if serverDate, err := ptypes.Timestamp(m.CheckStatus.ServerDate); err == nil {
+			gi.checksSyncedAsOf = serverDate.UTC()
+		}
+	}
 }
 
 // githubCache is an httpcache.Cache wrapper that only
@@ -1501,6 +1932,7 @@ func (gr *GitHubRepo) sync(ctx context.Context, token string, loop bool) error {
 		gr:            gr,
 		githubDirect:  github.NewClient(&http.Client{Transport: directTransport}),
 		githubCaching: github.NewClient(&http.Client{Transport: cachingTransport}),
+		githubV4:      githubv4.NewClient(&http.Client{Transport: directTransport}),
 		client:        http.DefaultClient,
 	}
 	activityCh := gr.github.c.activityChan("github:" + gr.id.String())
@@ -1562,7 +1994,8 @@ type githubRepoPoller struct {
 	lastUpdate    time.Time // modified by sync
 	githubCaching *github.Client
 	githubDirect  *github.Client // not caching
-	client        httpClient     // the client used to poll github
+	githubV4      *githubv4.Client
+	client        httpClient // the client used to poll github
 }
 
 func (p *githubRepoPoller) Owner() string { return p.gr.id.Owner }
@@ -1586,6 +2019,21 @@ func (p *githubRepoPoller) sync(ctx context.Context, expectChanges bool) error {
 	if err := p.syncReviews(ctx); err != nil {
 		return err
 	}
+	if err := p.syncReviewComments(ctx); err != nil {
+		return err
+	}
+	if err := p.syncChecks(ctx); err != nil {
+		return err
+	}
+	if err := p.syncDiscussions(ctx); err != nil {
+		// Discussions come from a different API, which may be
+		// unavailable or not enabled for the repo. Don't let
+		// that hold up the sync of everything else.
+		p.logf("error syncing discussions: %v", err)
+	}
+	// GitHub Projects aren't synced. They belong to users and
+	// organizations rather than to repos, so they don't fit the
+	// per-repo polling and mutations here.
 	return nil
 }
 
@@ -2630,6 +3078,317 @@ func (t limitTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	return t.base.RoundTrip(r)
 }
 
+func (p *githubRepoPoller) issueNumbersWithStaleReviewCommentsSync() (issueNums []int32) {
+	p.c.mu.RLock()
+	defer p.c.mu.RUnlock()
+
+	now := time.Now()
+	for n, gi := range p.gr.issues {
+		if !gi.reviewCommentsSynced(now) {
+			issueNums = append(issueNums, n)
+		}
+	}
+	sort.Slice(issueNums, func(i, j int) bool {
+		return issueNums[i] < issueNums[j]
+	})
+	return issueNums
+}
+
+func (p *githubRepoPoller) syncReviewComments(ctx context.Context) error {
+	for {
+		nums := p.issueNumbersWithStaleReviewCommentsSync()
+		if len(nums) == 0 {
+			return nil
+		}
+		remain := len(nums)
+		for _, num := range nums {
+			p.logf("review comment sync: %d issues remaining; syncing issue %v", remain, num)
+			if err := p.syncReviewCommentsOnPullRequest(ctx, num); err != nil {
+				p.logf("review comment sync on issue %d: %v", num, err)
+				return err
+			}
+			remain--
+		}
+	}
+}
+
+func (p *githubRepoPoller) syncReviewCommentsOnPullRequest(ctx context.Context, issueNum int32) error {
+	p.c.mu.RLock()
+	gi := p.gr.issues[issueNum]
+	if gi == nil {
+		p.c.mu.RUnlock()
+		return fmt.Errorf("unknown issue number %v", issueNum)
+	}
+	since := gi.reviewCommentsUpdatedTil
+	p.c.mu.RUnlock()
+
+	owner, repo := p.gr.id.Owner, p.gr.id.Repo
+	morePages := true // at least try the first. might be empty.
+	for morePages {
+		opt := &github.PullRequestListCommentsOptions{
+			Direction:   "asc",
+			Sort:        "updated",
+			Since:       since,
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		rcs, res, err := p.githubDirect.PullRequests.ListComments(ctx, owner, repo, int(issueNum), opt)
+		if canRetry(ctx, err) {
+			continue
+		} else if isGone(err) {
+			p.logf("PR %d review comments are gone, marking as NotExist", issueNum)
+			p.markIssueNotExist(issueNum)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		serverDate, err := http.ParseTime(res.Header.Get("Date"))
+		if err != nil {
+			return fmt.Errorf("invalid server Date response: %v", err)
+		}
+		serverDate = serverDate.UTC()
+		p.logf("Number of review comments on PR %d since %v: %v", issueNum, since, len(rcs))
+
+		mut := &maintpb.Mutation{
+			GithubIssue: &maintpb.GithubIssueMutation{
+				Owner:  owner,
+				Repo:   repo,
+				Number: issueNum,
+			},
+		}
+
+		p.c.mu.RLock()
+		for _, rc := range rcs {
+			if rc.ID == nil || rc.User == nil || rc.CreatedAt == nil || rc.UpdatedAt == nil {
+				// Bogus.
+				p.logf("bogus review comment: %v", rc)
+				continue
+			}
+			since = *rc.UpdatedAt // for next round
+			cur := gi.reviewComments[rc.GetID()]
+			if cur != nil && cur.Updated.Equal(*rc.UpdatedAt) && cur.Body == rc.GetBody() && cur.Line == int32(rc.GetLine()) {
+				continue
+			}
+			rcp := &maintpb.GithubReviewComment{
+				Id:        rc.GetID(),
+				ReviewId:  rc.GetPullRequestReviewID(),
+				User:      &maintpb.GithubUser{Id: rc.User.GetID(), Login: rc.User.GetLogin()},
+				Body:      rc.GetBody(),
+				Path:      rc.GetPath(),
+				Line:      int32(rc.GetLine()),
+				Side:      rc.GetSide(),
+				CommitId:  rc.GetCommitID(),
+				InReplyTo: rc.GetInReplyTo(),
+			}
+			rcp.Created, _ = ptypes.TimestampProto(*rc.CreatedAt)
+			rcp.Updated, _ = ptypes.TimestampProto(*rc.UpdatedAt)
+			mut.GithubIssue.ReviewComment = append(mut.GithubIssue.ReviewComment, rcp)
+		}
+		p.c.mu.RUnlock()
+
+		if res.NextPage == 0 {
+			sdp, _ := ptypes.TimestampProto(serverDate)
+			mut.GithubIssue.ReviewCommentStatus = &maintpb.GithubIssueSyncStatus{ServerDate: sdp}
+			morePages = false
+		}
+
+		p.c.addMutation(mut)
+	}
+	return nil
+}
+
+func (p *githubRepoPoller) issueNumbersWithStaleChecksSync() (issueNums []int32) {
+	p.c.mu.RLock()
+	defer p.c.mu.RUnlock()
+
+	now := time.Now()
+	for n, gi := range p.gr.issues {
+		if !gi.checksSynced(now) {
+			issueNums = append(issueNums, n)
+		}
+	}
+	sort.Slice(issueNums, func(i, j int) bool {
+		return issueNums[i] < issueNums[j]
+	})
+	return issueNums
+}
+
+// syncChecks syncs the check runs and commit statuses of open pull
+// requests. Unlike the other syncs, it makes a single pass: checks
+// that are still running keep a pull request stale until the next
+// poll.
+func (p *githubRepoPoller) syncChecks(ctx context.Context) error {
+	nums := p.issueNumbersWithStaleChecksSync()
+	remain := len(nums)
+	for _, num := range nums {
+		p.logf("checks sync: %d issues remaining; syncing issue %v", remain, num)
+		if err := p.syncChecksOnPullRequest(ctx, num); err != nil {
+			p.logf("checks sync on issue %d: %v", num, err)
+			return err
+		}
+		remain--
+	}
+	return nil
+}
+
+func (p *githubRepoPoller) syncChecksOnPullRequest(ctx context.Context, issueNum int32) error {
+	owner, repo := p.gr.id.Owner, p.gr.id.Repo
+
+	var pr *github.PullRequest
+	var res *github.Response
+	for {
+		var err error
+		pr, res, err = p.githubDirect.PullRequests.Get(ctx, owner, repo, int(issueNum))
+		if canRetry(ctx, err) {
+			continue
+		} else if isGone(err) {
+			p.logf("PR %d is gone, marking as NotExist", issueNum)
+			p.markIssueNotExist(issueNum)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		break
+	}
+	serverDate, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		return fmt.Errorf("invalid server Date response: %v", err)
+	}
+	head := pr.GetHead().GetSHA()
+	if head == "" {
+		return fmt.Errorf("pull request %d has no head commit", issueNum)
+	}
+
+	var runs []*github.CheckRun
+	runOpt := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		rs, res, err := p.githubDirect.Checks.ListCheckRunsForRef(ctx, owner, repo, head, runOpt)
+		if canRetry(ctx, err) {
+			continue
+		} else if isGone(err) {
+			// The head commit is gone, and its check runs with it.
+			break
+		} else if err != nil {
+			return err
+		}
+		runs = append(runs, rs.CheckRuns...)
+		if res.NextPage == 0 {
+			break
+		}
+		runOpt.Page = res.NextPage
+	}
+
+	var statuses []*github.RepoStatus
+	statusOpt := &github.ListOptions{PerPage: 100}
+	for {
+		ss, res, err := p.githubDirect.Repositories.ListStatuses(ctx, owner, repo, head, statusOpt)
+		if canRetry(ctx, err) {
+			continue
+		} else if isGone(err) {
+			break
+		} else if err != nil {
+			return err
+		}
+		statuses = append(statuses, ss...)
+		if res.NextPage == 0 {
+			break
+		}
+		statusOpt.Page = res.NextPage
+	}
+
+	mut := &maintpb.Mutation{
+		GithubIssue: &maintpb.GithubIssueMutation{
+			Owner:  owner,
+			Repo:   repo,
+			Number: issueNum,
+		},
+	}
+	p.c.mu.RLock()
+	gi := p.gr.issues[issueNum]
+	if gi == nil {
+		p.c.mu.RUnlock()
+		return fmt.Errorf("unknown issue number %v", issueNum)
+	}
+	if gi.HeadCommitID != head {
+		mut.GithubIssue.HeadCommitId = head
+	}
+	for _, cr := range runs {
+		if cr.ID == nil {
+			p.logf("bogus check run: %v", cr)
+			continue
+		}
+		cp := &maintpb.GithubCheckRun{
+			Id:         cr.GetID(),
+			CommitId:   cr.GetHeadSHA(),
+			Name:       cr.GetName(),
+			Status:     cr.GetStatus(),
+			Conclusion: cr.GetConclusion(),
+			DetailsUrl: cr.GetDetailsURL(),
+		}
+		if cr.StartedAt != nil {
+			cp.Started, _ = ptypes.TimestampProto(cr.StartedAt.Time)
+		}
+		if cr.CompletedAt != nil {
+			cp.Completed, _ = ptypes.TimestampProto(cr.CompletedAt.Time)
+		}
+		if cur := gi.checkRuns[cp.Id]; cur != nil && proto.Equal(cur.Proto(), cp) {
+			continue
+		}
+		mut.GithubIssue.CheckRun = append(mut.GithubIssue.CheckRun, cp)
+	}
+	for _, st := range statuses {
+		if st.ID == nil {
+			p.logf("bogus commit status: %v", st)
+			continue
+		}
+		sp := &maintpb.GithubCommitStatus{
+			Id:          st.GetID(),
+			CommitId:    head,
+			Context:     st.GetContext(),
+			State:       st.GetState(),
+			Description: st.GetDescription(),
+			TargetUrl:   st.GetTargetURL(),
+		}
+		if st.CreatedAt != nil {
+			sp.Created, _ = ptypes.TimestampProto(*st.CreatedAt)
+		}
+		if st.UpdatedAt != nil {
+			sp.Updated, _ = ptypes.TimestampProto(*st.UpdatedAt)
+		}
+		if cur := gi.commitStatuses[sp.Id]; cur != nil && proto.Equal(cur.Proto(), sp) {
+			continue
+		}
+		mut.GithubIssue.CommitStatus = append(mut.GithubIssue.CommitStatus, sp)
+	}
+	p.c.mu.RUnlock()
+
+	p.logf("PR %d at %.8s: %d check runs, %d statuses; %d changed", issueNum, head,
+		len(runs), len(statuses), len(mut.GithubIssue.CheckRun)+len(mut.GithubIssue.CommitStatus))
+	sdp, _ := ptypes.TimestampProto(serverDate.UTC())
+	mut.GithubIssue.CheckStatus = &maintpb.GithubIssueSyncStatus{ServerDate: sdp}
+	p.c.addMutation(mut)
+	return nil
+}
+
+// isGone reports whether err is a GitHub response saying that the
+// requested resource doesn't exist or was deleted.
+func isGone(err error) bool {
+	ge, ok := err.(*github.ErrorResponse)
+	return ok && (ge.Response.StatusCode == http.StatusNotFound || ge.Response.StatusCode == http.StatusGone)
+}
+
+// markIssueNotExist records that the issue or pull request numbered
+// issueNum no longer exists, so that it isn't synced anymore.
+func (p *githubRepoPoller) markIssueNotExist(issueNum int32) {
+	p.c.addMutation(&maintpb.Mutation{
+		GithubIssue: &maintpb.GithubIssueMutation{
+			Owner:    p.gr.id.Owner,
+			Repo:     p.gr.id.Repo,
+			Number:   issueNum,
+			NotExist: true,
+		},
+	})
+}
+
 // canRetry reports whether ctx hasn't been canceled and err is a non-nil retryable error.
 // If so, it blocks until enough time passes so that it's acceptable to retry immediately.
 func canRetry(ctx context.Context, err error) bool {
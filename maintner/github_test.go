@@ -1094,3 +1094,145 @@ func TestParseGithubRefs(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessReviewCommentsAndChecks(t *testing.T) {
+	c := singleIssueGitHubCorpus()
+	gi := c.github.repos[GitHubRepoID{"golang", "go"}].issues[3]
+	gi.PullRequest = true
+	gi.Updated = t1
+
+	c.processGithubIssueMutation(&maintpb.GithubIssueMutation{
+		Owner:  "golang",
+		Repo:   "go",
+		Number: 3,
+		ReviewComment: []*maintpb.GithubReviewComment{
+			{Id: 2, ReviewId: 10, User: &maintpb.GithubUser{Id: u1.ID, Login: u1.Login}, Body: "nit", Path: "a.go", Line: 5, Created: tp2, Updated: tp2},
+			{Id: 1, ReviewId: 10, Body: "typo", Path: "b.go", Line: 1, Created: tp1, Updated: tp1},
+		},
+		ReviewCommentStatus: &maintpb.GithubIssueSyncStatus{ServerDate: tp2},
+		HeadCommitId:        "abc",
+		CheckRun: []*maintpb.GithubCheckRun{
+			{Id: 7, CommitId: "abc", Name: "build", Status: "in_progress", Started: tp1},
+		},
+		CommitStatus: []*maintpb.GithubCommitStatus{
+			{Id: 8, CommitId: "abc", Context: "ci", State: "success", Created: tp1},
+		},
+		CheckStatus: &maintpb.GithubIssueSyncStatus{ServerDate: tp2},
+	})
+	// An edit replaces the whole comment.
+	c.processGithubIssueMutation(&maintpb.GithubIssueMutation{
+		Owner:  "golang",
+		Repo:   "go",
+		Number: 3,
+		ReviewComment: []*maintpb.GithubReviewComment{
+			{Id: 1, ReviewId: 10, Body: "typo, fixed", Path: "b.go", Line: 1, Created: tp1, Updated: tp2},
+		},
+	})
+
+	var bodies []string
+	gi.ForeachReviewComment(func(rc *GitHubReviewComment) error {
+		bodies = append(bodies, rc.Body)
+		return nil
+	})
+	if want := []string{"typo, fixed", "nit"}; !reflect.DeepEqual(bodies, want) {
+		t.Errorf("review comments = %q; want %q", bodies, want)
+	}
+	if rc := gi.reviewComments[2]; rc.User != u1 {
+		t.Errorf("review comment user = %v; want %v", rc.User, u1)
+	}
+	if !gi.reviewCommentsSynced(time.Now()) {
+		t.Error("review comments not synced")
+	}
+
+	if gi.HeadCommitID != "abc" {
+		t.Errorf("HeadCommitID = %q; want abc", gi.HeadCommitID)
+	}
+	if gi.checksSynced(time.Now()) {
+		t.Error("checks synced with a check run in progress")
+	}
+	c.processGithubIssueMutation(&maintpb.GithubIssueMutation{
+		Owner:  "golang",
+		Repo:   "go",
+		Number: 3,
+		CheckRun: []*maintpb.GithubCheckRun{
+			{Id: 7, CommitId: "abc", Name: "build", Status: "completed", Conclusion: "success", Started: tp1, Completed: tp2},
+		},
+	})
+	if !gi.checksSynced(time.Now()) {
+		t.Error("checks not synced after check run completed")
+	}
+	var checks []string
+	gi.ForeachCheckRun(func(cr *GitHubCheckRun) error {
+		checks = append(checks, cr.Name+":"+cr.Conclusion)
+		return nil
+	})
+	gi.ForeachCommitStatus(func(st *GitHubCommitStatus) error {
+		checks = append(checks, st.Context+":"+st.State)
+		return nil
+	})
+	if want := []string{"build:success", "ci:success"}; !reflect.DeepEqual(checks, want) {
+		t.Errorf("checks = %q; want %q", checks, want)
+	}
+}
+
+func TestChecksSyncedBackoff(t *testing.T) {
+	updated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	gi := &GitHubIssue{
+		PullRequest:      true,
+		Updated:          updated,
+		HeadCommitID:     "abc",
+		checksSyncedAsOf: updated.Add(8 * time.Minute),
+		checkRuns: map[int64]*GitHubCheckRun{
+			7: {ID: 7, CommitID: "abc", Status: "in_progress"},
+		},
+	}
+	// Pending for 8 minutes when synced: wait 2 minutes.
+	if !gi.checksSynced(gi.checksSyncedAsOf.Add(time.Minute)) {
+		t.Error("pending checks not synced 1 minute after sync")
+	}
+	if gi.checksSynced(gi.checksSyncedAsOf.Add(3 * time.Minute)) {
+		t.Error("pending checks synced 3 minutes after sync")
+	}
+	// Pending for a day: wait an hour.
+	gi.checksSyncedAsOf = updated.Add(24 * time.Hour)
+	if !gi.checksSynced(gi.checksSyncedAsOf.Add(59 * time.Minute)) {
+		t.Error("day-old pending checks not synced 59 minutes after sync")
+	}
+	if gi.checksSynced(gi.checksSyncedAsOf.Add(61 * time.Minute)) {
+		t.Error("day-old pending checks synced 61 minutes after sync")
+	}
+}
+
+func TestReviewCommentsBackfill(t *testing.T) {
+	updated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	gi := &GitHubIssue{PullRequest: true, Updated: updated}
+	if gi.reviewCommentsSynced(updated.Add(24 * time.Hour)) {
+		t.Error("open PR synced without syncing its review comments")
+	}
+	gi.Closed = true
+	if gi.reviewCommentsSynced(updated.Add(24 * time.Hour)) {
+		t.Error("recently closed PR synced without syncing its review comments")
+	}
+	if !gi.reviewCommentsSynced(updated.Add(reviewCommentsBackfillWindow + time.Hour)) {
+		t.Error("long-closed PR not synced; want it left out of the backfill")
+	}
+}
+
+func TestIssueAuthorAssociation(t *testing.T) {
+	c := singleIssueGitHubCorpus()
+	gr := c.github.repos[GitHubRepoID{"golang", "go"}]
+	gi := gr.issues[3]
+
+	issue := &github.Issue{Number: github.Int(3), AuthorAssociation: github.String("FIRST_TIME_CONTRIBUTOR")}
+	m := githubIssueDiffer{gr: gr, a: gi, b: issue}.Diff()
+	if got := m.GetAuthorAssociation(); got != "FIRST_TIME_CONTRIBUTOR" {
+		t.Fatalf("mutation author association = %q; want FIRST_TIME_CONTRIBUTOR", got)
+	}
+	c.processGithubIssueMutation(m)
+	if gi.AuthorAssociation != "FIRST_TIME_CONTRIBUTOR" {
+		t.Errorf("AuthorAssociation = %q; want FIRST_TIME_CONTRIBUTOR", gi.AuthorAssociation)
+	}
+	if m := (githubIssueDiffer{gr: gr, a: gi, b: issue}).Diff(); m.GetAuthorAssociation() != "" {
+		t.Errorf("unchanged author association diffed as %q", m.GetAuthorAssociation())
+	}
+}
@@ -0,0 +1,463 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/build/maintner/maintpb"
+)
+
+// GitHubDiscussion represents a GitHub Discussion.
+// For more details, see https://docs.github.com/en/discussions.
+type GitHubDiscussion struct {
+	ID       int64
+	Number   int32
+	NotExist bool // if true, rest of fields should be ignored.
+
+	User     *GitHubUser
+	Title    string
+	Body     string
+	Category string // "Q&A", "Ideas", etc.
+	Created  time.Time
+	Updated  time.Time
+	Closed   bool
+	Locked   bool
+
+	// AnswerID is the ID of the comment marked as the answer,
+	// or zero if there is none.
+	AnswerID int64
+
+	comments map[int64]*GitHubDiscussionComment // by comment.ID
+}
+
+// GitHubDiscussionComment is a comment on a GitHub Discussion,
+// or a reply to such a comment.
+type GitHubDiscussionComment struct {
+	ID      int64
+	ReplyTo int64 // ID of the top-level comment replied to, if any
+	User    *GitHubUser
+	Body    string
+	Created time.Time
+	Updated time.Time
+}
+
+// Discussion returns the provided discussion number, or nil if it's not known.
+func (gr *GitHubRepo) Discussion(n int32) *GitHubDiscussion { return gr.discussions[n] }
+
+// ForeachDiscussion calls fn for each discussion in the repo.
+//
+// If fn returns an error, iteration ends and ForeachDiscussion returns
+// with that error.
+//
+// The fn function is called serially, with increasingly numbered
+// discussions.
+func (gr *GitHubRepo) ForeachDiscussion(fn func(*GitHubDiscussion) error) error {
+	s := make([]*GitHubDiscussion, 0, len(gr.discussions))
+	for _, d := range gr.discussions {
+		s = append(s, d)
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Number < s[j].Number })
+	for _, d := range s {
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForeachComment calls fn for each comment and reply on the discussion.
+//
+// If fn returns an error, iteration ends and ForeachComment returns
+// with that error.
+//
+// The fn function is called serially, in order of the comment's time.
+func (d *GitHubDiscussion) ForeachComment(fn func(*GitHubDiscussionComment) error) error {
+	s := make([]*GitHubDiscussionComment, 0, len(d.comments))
+	for _, dc := range d.comments {
+		s = append(s, dc)
+	}
+	sort.Slice(s, func(i, j int) bool {
+		ci, cj := s[i].Created, s[j].Created
+		if ci.Before(cj) {
+			return true
+		}
+		return ci.Equal(cj) && s[i].ID < s[j].ID
+	})
+	for _, dc := range s {
+		if err := fn(dc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discussionsUpdatedTil returns the latest update time of any
+// discussion in the repo.
+//
+// (requires corpus be locked for reads)
+func (gr *GitHubRepo) discussionsUpdatedTil() time.Time {
+	var t time.Time
+	for _, d := range gr.discussions {
+		if d.Updated.After(t) {
+			t = d.Updated
+		}
+	}
+	return t
+}
+
+func (c *Corpus) processGithubDiscussionMutation(m *maintpb.GithubDiscussionMutation) {
+	if c == nil {
+		panic("nil corpus")
+	}
+	c.initGithub()
+	gr := c.github.getOrCreateRepo(m.Owner, m.Repo)
+	if gr == nil {
+		log.Printf("bogus Owner/Repo %q/%q in mutation: %v", m.Owner, m.Repo, m)
+		return
+	}
+	if m.Number == 0 {
+		log.Printf("bogus zero Number in mutation: %v", m)
+		return
+	}
+	d, ok := gr.discussions[m.Number]
+	if !ok {
+		d = &GitHubDiscussion{
+			Number: m.Number,
+			ID:     m.Id,
+		}
+		if gr.discussions == nil {
+			gr.discussions = make(map[int32]*GitHubDiscussion)
+		}
+		gr.discussions[m.Number] = d
+	}
+	if m.NotExist != d.NotExist {
+		d.NotExist = m.NotExist
+	}
+	if d.NotExist {
+		return
+	}
+	if m.Id != 0 {
+		d.ID = m.Id
+	}
+	if m.User != nil {
+		d.User = c.github.getUser(m.User)
+	}
+	if m.Created != nil {
+		d.Created, _ = ptypes.Timestamp(m.Created)
+		d.Created = d.Created.UTC()
+	}
+	if m.Updated != nil {
+		t, err := ptypes.Timestamp(m.Updated)
+		if err == nil && t.After(d.Updated) {
+			d.Updated = t.UTC()
+		}
+	}
+	if m.Title != "" {
+		d.Title = m.Title
+	}
+	if m.Body != nil {
+		d.Body = m.Body.Val
+	}
+	if m.Category != "" {
+		d.Category = m.Category
+	}
+	if m.Closed != nil {
+		d.Closed = m.Closed.Val
+	}
+	if m.Locked != nil {
+		d.Locked = m.Locked.Val
+	}
+	switch {
+	case m.AnswerId > 0:
+		d.AnswerID = m.AnswerId
+	case m.AnswerId < 0:
+		d.AnswerID = 0
+	}
+	for _, cmut := range m.Comment {
+		if cmut.Id == 0 {
+			log.Printf("Ignoring bogus discussion comment mutation lacking Id: %v", cmut)
+			continue
+		}
+		if d.comments == nil {
+			d.comments = make(map[int64]*GitHubDiscussionComment)
+		}
+		dc := &GitHubDiscussionComment{
+			ID:      cmut.Id,
+			ReplyTo: cmut.ReplyTo,
+			Body:    cmut.Body,
+		}
+		if cmut.User != nil {
+			dc.User = c.github.getUser(cmut.User)
+		}
+		if cmut.Created != nil {
+			dc.Created, _ = ptypes.Timestamp(cmut.Created)
+			dc.Created = dc.Created.UTC()
+		}
+		if cmut.Updated != nil {
+			dc.Updated, _ = ptypes.Timestamp(cmut.Updated)
+			dc.Updated = dc.Updated.UTC()
+		}
+		d.comments[dc.ID] = dc
+	}
+}
+
+// githubV4Actor is the author of a discussion or comment, as returned
+// by the GitHub GraphQL API. Non-user authors, such as bots, have a
+// zero DatabaseID.
+type githubV4Actor struct {
+	User struct {
+		DatabaseID int64 `graphql:"databaseId"`
+		Login      string
+	} `graphql:"... on User"`
+}
+
+func (a githubV4Actor) proto() *maintpb.GithubUser {
+	if a.User.DatabaseID == 0 {
+		return nil
+	}
+	return &maintpb.GithubUser{Id: a.User.DatabaseID, Login: a.User.Login}
+}
+
+type githubV4DiscussionComment struct {
+	DatabaseID int64 `graphql:"databaseId"`
+	Author     githubV4Actor
+	Body       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (dc githubV4DiscussionComment) proto(replyTo int64) *maintpb.GithubDiscussionComment {
+	p := &maintpb.GithubDiscussionComment{
+		Id:      dc.DatabaseID,
+		ReplyTo: replyTo,
+		User:    dc.Author.proto(),
+		Body:    dc.Body,
+	}
+	p.Created, _ = ptypes.TimestampProto(dc.CreatedAt)
+	p.Updated, _ = ptypes.TimestampProto(dc.UpdatedAt)
+	return p
+}
+
+type githubV4Discussion struct {
+	DatabaseID int64 `graphql:"databaseId"`
+	Number     int32
+	Author     githubV4Actor
+	Title      string
+	Body       string
+	Category   struct{ Name string }
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Closed     bool
+	Locked     bool
+	Answer     *struct {
+		DatabaseID int64 `graphql:"databaseId"`
+	}
+}
+
+// syncDiscussions syncs the repo's discussions and their comments.
+// Discussions are only available from GitHub's GraphQL API, so
+// unlike issues they're fetched with p.githubV4.
+func (p *githubRepoPoller) syncDiscussions(ctx context.Context) error {
+	if p.githubV4 == nil {
+		return nil
+	}
+	p.c.mu.RLock()
+	since := p.gr.discussionsUpdatedTil()
+	p.c.mu.RUnlock()
+
+	var q struct {
+		Repository struct {
+			Discussions struct {
+				Nodes    []githubV4Discussion
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   githubv4.String
+				}
+			} `graphql:"discussions(first: 50, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC})"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	vars := map[string]interface{}{
+		"owner":  githubv4.String(p.Owner()),
+		"repo":   githubv4.String(p.Repo()),
+		"cursor": (*githubv4.String)(nil),
+	}
+	var changed []githubV4Discussion
+	for {
+		if err := p.githubV4.Query(ctx, &q, vars); err != nil {
+			return fmt.Errorf("listing discussions: %v", err)
+		}
+		done := !q.Repository.Discussions.PageInfo.HasNextPage
+		for _, d := range q.Repository.Discussions.Nodes {
+			if !d.UpdatedAt.After(since) {
+				// Sorted by update time, so everything
+				// from here on has already been synced.
+				done = true
+				break
+			}
+			changed = append(changed, d)
+		}
+		if done {
+			break
+		}
+		vars["cursor"] = githubv4.NewString(q.Repository.Discussions.PageInfo.EndCursor)
+	}
+	p.logf("%d discussions updated since %v", len(changed), since)
+
+	// Apply the oldest first, so that an interrupted sync resumes
+	// where it left off.
+	for i := len(changed) - 1; i >= 0; i-- {
+		if err := p.syncDiscussion(ctx, changed[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncDiscussion adds a mutation for discussion d, which has changed
+// since the last sync.
+func (p *githubRepoPoller) syncDiscussion(ctx context.Context, d githubV4Discussion) error {
+	comments, err := p.discussionComments(ctx, d.Number)
+	if err != nil {
+		return fmt.Errorf("discussion %d comments: %v", d.Number, err)
+	}
+
+	mut := &maintpb.GithubDiscussionMutation{
+		Owner:    p.Owner(),
+		Repo:     p.Repo(),
+		Number:   d.Number,
+		Id:       d.DatabaseID,
+		User:     d.Author.proto(),
+		Title:    d.Title,
+		Category: d.Category.Name,
+	}
+	mut.Created, _ = ptypes.TimestampProto(d.CreatedAt)
+	mut.Updated, _ = ptypes.TimestampProto(d.UpdatedAt)
+
+	p.c.mu.RLock()
+	cur := p.gr.discussions[d.Number]
+	if cur == nil || cur.Body != d.Body {
+		mut.Body = &maintpb.StringChange{Val: d.Body}
+	}
+	if cur == nil || cur.Closed != d.Closed {
+		mut.Closed = &maintpb.BoolChange{Val: d.Closed}
+	}
+	if cur == nil || cur.Locked != d.Locked {
+		mut.Locked = &maintpb.BoolChange{Val: d.Locked}
+	}
+	var answer int64
+	if d.Answer != nil {
+		answer = d.Answer.DatabaseID
+	}
+	switch {
+	case answer != 0:
+		mut.AnswerId = answer
+	case cur != nil && cur.AnswerID != 0:
+		mut.AnswerId = -1
+	}
+	for _, dc := range comments {
+		if cur != nil {
+			if old := cur.comments[dc.Id]; old != nil && old.Body == dc.Body && old.ReplyTo == dc.ReplyTo {
+				continue
+			}
+		}
+		mut.Comment = append(mut.Comment, dc)
+	}
+	p.c.mu.RUnlock()
+
+	p.c.addMutation(&maintpb.Mutation{GithubDiscussion: mut})
+	return nil
+}
+
+// discussionComments returns all comments and replies on discussion number.
+func (p *githubRepoPoller) discussionComments(ctx context.Context, number int32) ([]*maintpb.GithubDiscussionComment, error) {
+	var q struct {
+		Repository struct {
+			Discussion struct {
+				Comments struct {
+					Nodes []struct {
+						githubV4DiscussionComment
+						ID      githubv4.ID
+						Replies githubV4DiscussionReplies `graphql:"replies(first: 100)"`
+					}
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   githubv4.String
+					}
+				} `graphql:"comments(first: 50, after: $cursor)"`
+			} `graphql:"discussion(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	vars := map[string]interface{}{
+		"owner":  githubv4.String(p.Owner()),
+		"repo":   githubv4.String(p.Repo()),
+		"number": githubv4.Int(number),
+		"cursor": (*githubv4.String)(nil),
+	}
+	var comments []*maintpb.GithubDiscussionComment
+	for {
+		if err := p.githubV4.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+		for _, c := range q.Repository.Discussion.Comments.Nodes {
+			comments = append(comments, c.proto(0))
+			replies := c.Replies
+			for {
+				for _, r := range replies.Nodes {
+					comments = append(comments, r.proto(c.DatabaseID))
+				}
+				if !replies.PageInfo.HasNextPage {
+					break
+				}
+				var err error
+				replies, err = p.discussionReplies(ctx, c.ID, replies.PageInfo.EndCursor)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		if !q.Repository.Discussion.Comments.PageInfo.HasNextPage {
+			return comments, nil
+		}
+		vars["cursor"] = githubv4.NewString(q.Repository.Discussion.Comments.PageInfo.EndCursor)
+	}
+}
+
+// githubV4DiscussionReplies is a page of the replies to a discussion
+// comment.
+type githubV4DiscussionReplies struct {
+	Nodes    []githubV4DiscussionComment
+	PageInfo struct {
+		HasNextPage bool
+		EndCursor   githubv4.String
+	}
+}
+
+// discussionReplies returns the page of the replies to the discussion
+// comment with node ID id that follows cursor, for comments with more
+// replies than discussionComments gets with them.
+func (p *githubRepoPoller) discussionReplies(ctx context.Context, id githubv4.ID, cursor githubv4.String) (githubV4DiscussionReplies, error) {
+	var q struct {
+		Node struct {
+			DiscussionComment struct {
+				Replies githubV4DiscussionReplies `graphql:"replies(first: 100, after: $cursor)"`
+			} `graphql:"... on DiscussionComment"`
+		} `graphql:"node(id: $id)"`
+	}
+	vars := map[string]interface{}{
+		"id":     id,
+		"cursor": cursor,
+	}
+	if err := p.githubV4.Query(ctx, &q, vars); err != nil {
+		return githubV4DiscussionReplies{}, err
+	}
+	return q.Node.DiscussionComment.Replies, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/build/maintner/maintpb"
+)
+
+func TestProcessGithubDiscussionMutation(t *testing.T) {
+	c := singleIssueGitHubCorpus()
+	gr := c.github.repos[GitHubRepoID{"golang", "go"}]
+	apply := func(m *maintpb.GithubDiscussionMutation) {
+		m.Owner, m.Repo = "golang", "go"
+		c.processMutationLocked(&maintpb.Mutation{GithubDiscussion: m})
+	}
+	apply(&maintpb.GithubDiscussionMutation{
+		Number:   5,
+		Id:       500,
+		User:     &maintpb.GithubUser{Id: u1.ID, Login: u1.Login},
+		Title:    "Question",
+		Body:     &maintpb.StringChange{Val: "How?"},
+		Category: "Q&A",
+		Created:  tp1,
+		Updated:  tp1,
+		Comment: []*maintpb.GithubDiscussionComment{
+			{Id: 2, ReplyTo: 1, Body: "Thanks", Created: tp2},
+			{Id: 1, Body: "Like this.", Created: tp1},
+		},
+	})
+	apply(&maintpb.GithubDiscussionMutation{
+		Number:   5,
+		Closed:   &maintpb.BoolChange{Val: true},
+		AnswerId: 1,
+	})
+
+	d := gr.Discussion(5)
+	if d == nil {
+		t.Fatal("discussion 5 not found")
+	}
+	want := &GitHubDiscussion{
+		ID:       500,
+		Number:   5,
+		User:     u1,
+		Title:    "Question",
+		Body:     "How?",
+		Category: "Q&A",
+		Created:  t1,
+		Updated:  t1,
+		Closed:   true,
+		AnswerID: 1,
+	}
+	got := *d
+	got.comments = nil
+	if !reflect.DeepEqual(&got, want) {
+		t.Errorf("discussion = %+v; want %+v", got, want)
+	}
+	var comments []string
+	d.ForeachComment(func(dc *GitHubDiscussionComment) error {
+		comments = append(comments, dc.Body)
+		return nil
+	})
+	if want := []string{"Like this.", "Thanks"}; !reflect.DeepEqual(comments, want) {
+		t.Errorf("comments = %q; want %q", comments, want)
+	}
+
+	apply(&maintpb.GithubDiscussionMutation{Number: 5, AnswerId: -1})
+	if d.AnswerID != 0 {
+		t.Errorf("AnswerID = %d after unset; want 0", d.AnswerID)
+	}
+}
+
+func TestSyncDiscussions(t *testing.T) {
+	const discussions = `{"data": {"repository": {"discussions": {
+		"nodes": [
+			{"databaseId": 902, "number": 2, "title": "New", "body": "b", "category": {"name": "Ideas"},
+			 "author": {"databaseId": 1, "login": "gopher"},
+			 "createdAt": "2024-01-02T00:00:00Z", "updatedAt": "2024-01-03T00:00:00Z"},
+			{"databaseId": 901, "number": 1, "title": "Old",
+			 "createdAt": "2023-01-01T00:00:00Z", "updatedAt": "2023-01-01T00:00:00Z"}
+		],
+		"pageInfo": {"hasNextPage": true, "endCursor": "c1"}
+	}}}}`
+	const comments = `{"data": {"repository": {"discussion": {"comments": {
+		"nodes": [
+			{"databaseId": 10, "id": "DC_10", "body": "first", "createdAt": "2024-01-02T01:00:00Z", "updatedAt": "2024-01-02T01:00:00Z",
+			 "author": {"databaseId": 2, "login": "gopher2"},
+			 "replies": {
+				"nodes": [
+					{"databaseId": 11, "body": "reply", "createdAt": "2024-01-02T02:00:00Z", "updatedAt": "2024-01-02T02:00:00Z"}
+				],
+				"pageInfo": {"hasNextPage": true, "endCursor": "r1"}
+			 }}
+		],
+		"pageInfo": {"hasNextPage": false}
+	}}}}}`
+	const replies = `{"data": {"node": {"replies": {
+		"nodes": [
+			{"databaseId": 12, "body": "another reply", "createdAt": "2024-01-02T03:00:00Z", "updatedAt": "2024-01-02T03:00:00Z"}
+		],
+		"pageInfo": {"hasNextPage": false}
+	}}}}`
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Query     string
+			Variables map[string]any
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("bad request: %v", err)
+		}
+		switch {
+		case strings.Contains(req.Query, "discussions("):
+			io.WriteString(w, discussions)
+		case strings.Contains(req.Query, "node("):
+			if req.Variables["id"] != "DC_10" || req.Variables["cursor"] != "r1" {
+				t.Errorf("replies query variables = %v; want id DC_10, cursor r1", req.Variables)
+			}
+			io.WriteString(w, replies)
+		default:
+			io.WriteString(w, comments)
+		}
+	}))
+	defer server.Close()
+
+	c := singleIssueGitHubCorpus()
+	gr := c.github.repos[GitHubRepoID{"golang", "go"}]
+	// Discussion 1 is already known, so syncing stops there.
+	old := &timestamp.Timestamp{Seconds: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix()}
+	c.processMutationLocked(&maintpb.Mutation{GithubDiscussion: &maintpb.GithubDiscussionMutation{
+		Owner: "golang", Repo: "go", Number: 1, Title: "Old", Created: old, Updated: old,
+	}})
+	p := &githubRepoPoller{
+		c:        c,
+		gr:       gr,
+		githubV4: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+	if err := p.syncDiscussions(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if queries != 3 {
+		t.Errorf("made %d queries; want 3", queries)
+	}
+	d := gr.Discussion(2)
+	if d == nil {
+		t.Fatal("discussion 2 not synced")
+	}
+	if d.ID != 902 || d.Title != "New" || d.Category != "Ideas" || d.User == nil || d.User.Login != "gopher" {
+		t.Errorf("discussion = %+v", d)
+	}
+	var got []string
+	d.ForeachComment(func(dc *GitHubDiscussionComment) error {
+		got = append(got, dc.Body)
+		return nil
+	})
+	if want := []string{"first", "reply", "another reply"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("comments = %q; want %q", got, want)
+	}
+	for _, id := range []int64{11, 12} {
+		if r := d.comments[id]; r == nil || r.ReplyTo != 10 {
+			t.Errorf("reply %d = %+v; want ReplyTo 10", id, r)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/build/maintner"
+)
+
+// schema is the database schema. Times are stored in SQLite's text
+// format, in UTC, and are NULL when unknown. GitHub users are
+// identified by login; Gerrit users by the email Gerrit uses for them
+// in NoteDb, of the form "<account id>@<server uuid>", or by
+// account ID.
+const schema = `
+-- meta holds the export's state. Its "mutation_offset" key is the
+-- number of mutations in the log that have been exported.
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT
+);
+
+-- issues holds GitHub issues and pull requests.
+CREATE TABLE IF NOT EXISTS issues (
+	repo         TEXT,    -- "golang/go"
+	number       INTEGER,
+	id           INTEGER,
+	pull_request BOOLEAN,
+	title        TEXT,
+	body         TEXT,
+	author       TEXT,
+	created      TIMESTAMP,
+	updated      TIMESTAMP,
+	closed       BOOLEAN,
+	closed_at    TIMESTAMP,
+	locked       BOOLEAN,
+	milestone    TEXT,    -- NULL if none
+	PRIMARY KEY (repo, number)
+);
+
+CREATE TABLE IF NOT EXISTS issue_labels (
+	repo   TEXT,
+	number INTEGER,
+	label  TEXT
+);
+CREATE INDEX IF NOT EXISTS issue_labels_issue ON issue_labels (repo, number);
+
+CREATE TABLE IF NOT EXISTS issue_assignees (
+	repo     TEXT,
+	number   INTEGER,
+	assignee TEXT
+);
+CREATE INDEX IF NOT EXISTS issue_assignees_issue ON issue_assignees (repo, number);
+
+CREATE TABLE IF NOT EXISTS issue_comments (
+	repo    TEXT,
+	number  INTEGER,
+	id      INTEGER,
+	author  TEXT,
+	created TIMESTAMP,
+	updated TIMESTAMP,
+	body    TEXT
+);
+CREATE INDEX IF NOT EXISTS issue_comments_issue ON issue_comments (repo, number);
+
+-- issue_events holds issue timeline events, such as "labeled" or
+-- "closed". Columns other than type, actor and created are only set
+-- for the event types they apply to.
+CREATE TABLE IF NOT EXISTS issue_events (
+	repo      TEXT,
+	number    INTEGER,
+	id        INTEGER,
+	type      TEXT,
+	actor     TEXT,
+	created   TIMESTAMP,
+	label     TEXT,
+	milestone TEXT,
+	assignee  TEXT,
+	commit_id TEXT
+);
+CREATE INDEX IF NOT EXISTS issue_events_issue ON issue_events (repo, number);
+
+-- cls holds Gerrit changes. Private CLs are omitted.
+CREATE TABLE IF NOT EXISTS cls (
+	project             TEXT,    -- "go.googlesource.com/go"
+	number              INTEGER,
+	status              TEXT,    -- "new", "merged", "abandoned"
+	branch              TEXT,
+	subject             TEXT,
+	owner_id            INTEGER,
+	owner_email         TEXT,
+	created             TIMESTAMP,
+	updated             TIMESTAMP, -- time of the latest meta commit
+	version             INTEGER,   -- latest patch set
+	commit_hash         TEXT,      -- commit of the latest patch set
+	wip                 BOOLEAN,
+	unresolved_comments INTEGER,
+	PRIMARY KEY (project, number)
+);
+
+CREATE TABLE IF NOT EXISTS cl_patch_sets (
+	project     TEXT,
+	number      INTEGER,
+	version     INTEGER,
+	commit_hash TEXT,
+	commit_time TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS cl_patch_sets_cl ON cl_patch_sets (project, number);
+
+CREATE TABLE IF NOT EXISTS cl_messages (
+	project TEXT,
+	number  INTEGER,
+	version INTEGER, -- patch set the message was sent on
+	author  TEXT,    -- NoteDb email
+	date    TIMESTAMP,
+	message TEXT
+);
+CREATE INDEX IF NOT EXISTS cl_messages_cl ON cl_messages (project, number);
+
+-- cl_votes holds the current label votes on CLs.
+CREATE TABLE IF NOT EXISTS cl_votes (
+	project TEXT,
+	number  INTEGER,
+	label   TEXT,    -- "Code-Review"
+	voter   TEXT,    -- NoteDb email
+	value   INTEGER
+);
+CREATE INDEX IF NOT EXISTS cl_votes_cl ON cl_votes (project, number);
+
+-- cl_comments holds published inline comments on CLs' files.
+CREATE TABLE IF NOT EXISTS cl_comments (
+	project     TEXT,
+	number      INTEGER,
+	id          TEXT,
+	in_reply_to TEXT,
+	patch_set   INTEGER,
+	file        TEXT,
+	line        INTEGER, -- 0 for a file comment
+	author_id   INTEGER,
+	date        TIMESTAMP,
+	unresolved  BOOLEAN,
+	message     TEXT
+);
+CREATE INDEX IF NOT EXISTS cl_comments_cl ON cl_comments (project, number);
+
+CREATE TABLE IF NOT EXISTS cl_attention (
+	project    TEXT,
+	number     INTEGER,
+	account_id INTEGER,
+	reason     TEXT,
+	added      TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS cl_attention_cl ON cl_attention (project, number);
+
+-- commits holds the commits reachable from Gerrit projects' branches.
+CREATE TABLE IF NOT EXISTS commits (
+	project     TEXT,
+	hash        TEXT,
+	parent      TEXT,    -- first parent, or NULL for a root commit
+	author      TEXT,    -- "Name <email>"
+	author_time TIMESTAMP,
+	committer   TEXT,
+	commit_time TIMESTAMP,
+	subject     TEXT,
+	message     TEXT,
+	PRIMARY KEY (project, hash)
+);
+`
+
+// issueTables and clTables are the tables holding per-issue and per-CL
+// rows, which are rewritten when an issue or CL changes.
+var (
+	issueTables = []string{"issues", "issue_labels", "issue_assignees", "issue_comments", "issue_events"}
+	clTables    = []string{"cls", "cl_patch_sets", "cl_messages", "cl_votes", "cl_comments", "cl_attention"}
+)
+
+// readOffset returns the number of mutations already exported to db.
+func readOffset(db *sql.DB) (int64, error) {
+	var v string
+	err := db.QueryRow(`SELECT value FROM meta WHERE key = 'mutation_offset'`).Scan(&v)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// exportStats counts the rows rewritten by an export.
+type exportStats struct {
+	issues, cls, commits int
+}
+
+// An exporter writes corpus entities to a database transaction.
+type exporter struct {
+	tx    *sql.Tx
+	stmts map[string]*sql.Stmt
+	stats exportStats
+}
+
+// exec executes query, preparing it on first use.
+func (x *exporter) exec(query string, args ...interface{}) error {
+	st, ok := x.stmts[query]
+	if !ok {
+		var err error
+		st, err = x.tx.Prepare(query)
+		if err != nil {
+			return fmt.Errorf("preparing %q: %v", query, err)
+		}
+		x.stmts[query] = st
+	}
+	_, err := st.Exec(args...)
+	return err
+}
+
+// export writes the entities in dirty from the corpus to db, along
+// with any new branch commits, and records that the first offset
+// mutations have been exported.
+func export(db *sql.DB, c *maintner.Corpus, offset int64, dirty *dirtySet) (exportStats, error) {
+	old, err := readOffset(db)
+	if err != nil {
+		return exportStats{}, err
+	}
+	if offset < old {
+		return exportStats{}, fmt.Errorf("mutation log has %d mutations, but database already has %d; was it built from a different log?", offset, old)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return exportStats{}, err
+	}
+	defer tx.Rollback()
+	x := &exporter{tx: tx, stmts: make(map[string]*sql.Stmt)}
+
+	err = c.GitHub().ForeachRepo(func(gr *maintner.GitHubRepo) error {
+		repo := gr.ID().String()
+		return gr.ForeachIssue(func(gi *maintner.GitHubIssue) error {
+			if !dirty.issue(repo, gi.Number) {
+				return nil
+			}
+			return x.writeIssue(repo, gi)
+		})
+	})
+	if err != nil {
+		return exportStats{}, err
+	}
+
+	err = c.Gerrit().ForeachProjectUnsorted(func(gp *maintner.GerritProject) error {
+		proj := gp.ServerSlashProject()
+		err := gp.ForeachCLUnsorted(func(cl *maintner.GerritCL) error {
+			if !dirty.cl(proj, cl.Number) {
+				return nil
+			}
+			return x.writeCL(proj, cl)
+		})
+		if err != nil {
+			return err
+		}
+		return x.writeBranchCommits(gp)
+	})
+	if err != nil {
+		return exportStats{}, err
+	}
+
+	err = x.exec(`INSERT OR REPLACE INTO meta (key, value) VALUES ('mutation_offset', ?)`, strconv.FormatInt(offset, 10))
+	if err != nil {
+		return exportStats{}, err
+	}
+	return x.stats, tx.Commit()
+}
+
+func (x *exporter) writeIssue(repo string, gi *maintner.GitHubIssue) error {
+	for _, t := range issueTables {
+		if err := x.exec(`DELETE FROM `+t+` WHERE repo = ? AND number = ?`, repo, gi.Number); err != nil {
+			return err
+		}
+	}
+	if gi.NotExist {
+		return nil
+	}
+	x.stats.issues++
+
+	var milestone interface{}
+	if gi.Milestone != nil && !gi.Milestone.IsNone() {
+		milestone = gi.Milestone.Title
+	}
+	err := x.exec(`INSERT INTO issues (repo, number, id, pull_request, title, body, author, created, updated, closed, closed_at, locked, milestone)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		repo, gi.Number, gi.ID, gi.PullRequest, gi.Title, gi.Body, login(gi.User),
+		sqlTime(gi.Created), sqlTime(gi.Updated), gi.Closed, sqlTime(gi.ClosedAt), gi.Locked, milestone)
+	if err != nil {
+		return err
+	}
+	for _, lb := range gi.Labels {
+		if err := x.exec(`INSERT INTO issue_labels (repo, number, label) VALUES (?, ?, ?)`, repo, gi.Number, lb.Name); err != nil {
+			return err
+		}
+	}
+	for _, u := range gi.Assignees {
+		if err := x.exec(`INSERT INTO issue_assignees (repo, number, assignee) VALUES (?, ?, ?)`, repo, gi.Number, login(u)); err != nil {
+			return err
+		}
+	}
+	err = gi.ForeachComment(func(co *maintner.GitHubComment) error {
+		return x.exec(`INSERT INTO issue_comments (repo, number, id, author, created, updated, body) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			repo, gi.Number, co.ID, login(co.User), sqlTime(co.Created), sqlTime(co.Updated), co.Body)
+	})
+	if err != nil {
+		return err
+	}
+	return gi.ForeachEvent(func(e *maintner.GitHubIssueEvent) error {
+		return x.exec(`INSERT INTO issue_events (repo, number, id, type, actor, created, label, milestone, assignee, commit_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			repo, gi.Number, e.ID, e.Type, login(e.Actor), sqlTime(e.Created),
+			sqlString(e.Label), sqlString(e.Milestone), sqlString(login(e.Assignee)), sqlString(e.CommitID))
+	})
+}
+
+func (x *exporter) writeCL(proj string, cl *maintner.GerritCL) error {
+	for _, t := range clTables {
+		if err := x.exec(`DELETE FROM `+t+` WHERE project = ? AND number = ?`, proj, cl.Number); err != nil {
+			return err
+		}
+	}
+	if cl.Private {
+		return nil
+	}
+	x.stats.cls++
+
+	var ownerEmail interface{}
+	if o := cl.Owner(); o != nil {
+		ownerEmail = o.Email()
+	}
+	err := x.exec(`INSERT INTO cls (project, number, status, branch, subject, owner_id, owner_email, created, updated, version, commit_hash, wip, unresolved_comments)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		proj, cl.Number, cl.Status, cl.Branch(), cl.Subject(), cl.OwnerID(), ownerEmail,
+		sqlTime(cl.Created), sqlTime(cl.Meta.Commit.CommitTime), cl.Version, cl.Commit.Hash.String(),
+		cl.WorkInProgress(), cl.UnresolvedCommentCount)
+	if err != nil {
+		return err
+	}
+	for v := int32(1); v <= cl.Version; v++ {
+		gc := cl.CommitAtVersion(v)
+		if gc == nil {
+			continue
+		}
+		err := x.exec(`INSERT INTO cl_patch_sets (project, number, version, commit_hash, commit_time) VALUES (?, ?, ?, ?, ?)`,
+			proj, cl.Number, v, gc.Hash.String(), sqlTime(gc.CommitTime))
+		if err != nil {
+			return err
+		}
+	}
+	for _, m := range cl.Messages {
+		var author interface{}
+		if m.Author != nil {
+			author = m.Author.Email()
+		}
+		err := x.exec(`INSERT INTO cl_messages (project, number, version, author, date, message) VALUES (?, ?, ?, ?, ?, ?)`,
+			proj, cl.Number, m.Version, author, sqlTime(m.Date), m.Message)
+		if err != nil {
+			return err
+		}
+	}
+	votes, err := cl.Meta.LabelVotes()
+	if err != nil {
+		return fmt.Errorf("%s/%d: label votes: %v", proj, cl.Number, err)
+	}
+	for label, byVoter := range votes {
+		for voter, value := range byVoter {
+			err := x.exec(`INSERT INTO cl_votes (project, number, label, voter, value) VALUES (?, ?, ?, ?, ?)`,
+				proj, cl.Number, label, voter, value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, cm := range cl.Comments {
+		err := x.exec(`INSERT INTO cl_comments (project, number, id, in_reply_to, patch_set, file, line, author_id, date, unresolved, message)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			proj, cl.Number, cm.ID, sqlString(cm.InReplyTo), cm.PatchSet, cm.File, cm.Line, cm.AuthorID,
+			sqlTime(cm.Date), cm.Unresolved, cm.Message)
+		if err != nil {
+			return err
+		}
+	}
+	for _, a := range cl.AttentionSet {
+		err := x.exec(`INSERT INTO cl_attention (project, number, account_id, reason, added) VALUES (?, ?, ?, ?, ?)`,
+			proj, cl.Number, a.AccountID, a.Reason, sqlTime(a.Added))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBranchCommits writes the commits reachable from gp's branches
+// that aren't yet in the database.
+func (x *exporter) writeBranchCommits(gp *maintner.GerritProject) error {
+	proj := gp.ServerSlashProject()
+	var heads []maintner.GitHash
+	gp.ForeachNonChangeRef(func(ref string, hash maintner.GitHash) error {
+		if strings.HasPrefix(ref, "refs/heads/") {
+			heads = append(heads, hash)
+		}
+		return nil
+	})
+
+	seen := make(map[maintner.GitHash]bool)
+	var stack []*maintner.GitCommit
+	for _, h := range heads {
+		if gc, err := gp.GitCommit(h.String()); err == nil {
+			stack = append(stack, gc)
+		}
+	}
+	for len(stack) > 0 {
+		gc := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[gc.Hash] {
+			continue
+		}
+		seen[gc.Hash] = true
+
+		var exists int
+		err := x.tx.QueryRow(`SELECT COUNT(*) FROM commits WHERE project = ? AND hash = ?`, proj, gc.Hash.String()).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists > 0 {
+			// Commits are only written once all their
+			// ancestors are, so the history from here on
+			// has already been exported.
+			continue
+		}
+		var parent interface{}
+		if len(gc.Parents) > 0 {
+			parent = gc.Parents[0].Hash.String()
+		}
+		subject, _, _ := strings.Cut(gc.Msg, "\n")
+		err = x.exec(`INSERT INTO commits (project, hash, parent, author, author_time, committer, commit_time, subject, message)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			proj, gc.Hash.String(), parent, person(gc.Author), sqlTime(gc.AuthorTime),
+			person(gc.Committer), sqlTime(gc.CommitTime), subject, gc.Msg)
+		if err != nil {
+			return err
+		}
+		x.stats.commits++
+		stack = append(stack, gc.Parents...)
+	}
+	return nil
+}
+
+// sqlTime returns t in UTC, or nil if t is the zero time.
+func sqlTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
+
+// sqlString returns s, or nil if s is empty.
+func sqlString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func login(u *maintner.GitHubUser) string {
+	if u == nil {
+		return ""
+	}
+	return u.Login
+}
+
+func person(p *maintner.GitPerson) interface{} {
+	if p == nil {
+		return nil
+	}
+	return p.Str
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintpb"
+)
+
+// sliceSource is a MutationSource that sends a fixed set of mutations.
+type sliceSource []*maintpb.Mutation
+
+func (s sliceSource) GetMutations(ctx context.Context) <-chan maintner.MutationStreamEvent {
+	ch := make(chan maintner.MutationStreamEvent, len(s)+1)
+	for _, m := range s {
+		ch <- maintner.MutationStreamEvent{Mutation: m}
+	}
+	ch <- maintner.MutationStreamEvent{End: true}
+	return ch
+}
+
+func issueMutation(number int32, f func(*maintpb.GithubIssueMutation)) *maintpb.Mutation {
+	im := &maintpb.GithubIssueMutation{Owner: "golang", Repo: "go", Number: number}
+	f(im)
+	return &maintpb.Mutation{GithubIssue: im}
+}
+
+func TestExportIncremental(t *testing.T) {
+	ts, _ := ptypes.TimestampProto(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	log := sliceSource{
+		issueMutation(1, func(im *maintpb.GithubIssueMutation) {
+			im.Title = "one"
+			im.User = &maintpb.GithubUser{Id: 1, Login: "gopher"}
+			im.Created = ts
+		}),
+		issueMutation(2, func(im *maintpb.GithubIssueMutation) {
+			im.Title = "two"
+			im.Created = ts
+			im.Comment = []*maintpb.GithubIssueCommentMutation{{
+				Id:   10,
+				User: &maintpb.GithubUser{Id: 2, Login: "reviewer"},
+				Body: "LGTM",
+			}}
+		}),
+	}
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(src sliceSource) exportStats {
+		t.Helper()
+		offset, err := readOffset(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tsrc := newTrackingSource(src, offset)
+		c := new(maintner.Corpus)
+		if err := c.Initialize(context.Background(), tsrc); err != nil {
+			t.Fatal(err)
+		}
+		st, err := export(db, c, tsrc.n, tsrc.dirty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return st
+	}
+	query := func(q string, args ...interface{}) string {
+		t.Helper()
+		var s sql.NullString
+		if err := db.QueryRow(q, args...).Scan(&s); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+		return s.String
+	}
+
+	if st := run(log); st.issues != 2 {
+		t.Errorf("first export wrote %d issues; want 2", st.issues)
+	}
+	if got := query(`SELECT author FROM issues WHERE repo = 'golang/go' AND number = 1`); got != "gopher" {
+		t.Errorf("issue 1 author = %q; want gopher", got)
+	}
+	if got := query(`SELECT created FROM issues WHERE number = 1`); got != "2024-01-02T03:04:05Z" {
+		t.Errorf("issue 1 created = %q", got)
+	}
+	if got := query(`SELECT body FROM issue_comments WHERE number = 2 AND id = 10`); got != "LGTM" {
+		t.Errorf("issue 2 comment = %q; want LGTM", got)
+	}
+	if got := query(`SELECT value FROM meta WHERE key = 'mutation_offset'`); got != "2" {
+		t.Errorf("mutation_offset = %q; want 2", got)
+	}
+
+	// Mark issue 1's row so we can tell whether it gets rewritten.
+	if _, err := db.Exec(`UPDATE issues SET body = 'untouched' WHERE number = 1`); err != nil {
+		t.Fatal(err)
+	}
+
+	log = append(log,
+		issueMutation(2, func(im *maintpb.GithubIssueMutation) {
+			im.Title = "two, retitled"
+		}),
+		issueMutation(3, func(im *maintpb.GithubIssueMutation) {
+			im.Title = "three"
+			im.Created = ts
+		}),
+	)
+	if st := run(log); st.issues != 2 {
+		t.Errorf("second export wrote %d issues; want 2", st.issues)
+	}
+	if got := query(`SELECT body FROM issues WHERE number = 1`); got != "untouched" {
+		t.Errorf("issue 1 was rewritten by incremental export")
+	}
+	if got := query(`SELECT title FROM issues WHERE number = 2`); got != "two, retitled" {
+		t.Errorf("issue 2 title = %q; want retitled", got)
+	}
+	if got := query(`SELECT COUNT(*) FROM issue_comments WHERE number = 2`); got != "1" {
+		t.Errorf("issue 2 has %s comments; want 1", got)
+	}
+	if got := query(`SELECT COUNT(*) FROM issues`); got != "3" {
+		t.Errorf("got %s issues; want 3", got)
+	}
+	if got := query(`SELECT value FROM meta WHERE key = 'mutation_offset'`); got != "4" {
+		t.Errorf("mutation_offset = %q; want 4", got)
+	}
+
+	// A shorter log than the database has seen is an error.
+	offset, _ := readOffset(db)
+	tsrc := newTrackingSource(log[:1], offset)
+	c := new(maintner.Corpus)
+	if err := c.Initialize(context.Background(), tsrc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := export(db, c, tsrc.n, tsrc.dirty); err == nil {
+		t.Errorf("export of truncated log succeeded; want error")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The maintexport command exports a maintner corpus to a SQLite
+// database for analytics.
+//
+// It materializes GitHub issues with their labels, assignees, comments
+// and events, Gerrit CLs with their patch sets, messages, votes,
+// inline comments and attention sets, and the commits on Gerrit
+// projects' branches. See the schema constant in export.go for the
+// tables and their columns.
+//
+// The database records how many mutations of the log it has seen.
+// Subsequent runs against the same database only rewrite the issues
+// and CLs touched by mutations after that offset, so it's cheap to run
+// periodically:
+//
+//	maintexport -db=maintner.db
+//	sqlite3 maintner.db 'SELECT status, COUNT(*) FROM cls GROUP BY status'
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/godata"
+)
+
+var (
+	dbFile = flag.String("db", "maintner.db", "SQLite database file to create or update")
+	server = flag.String("server", godata.Server, "maintner server's /logs URL")
+	dir    = flag.String("dir", "", "if non-empty, a local directory of mutation logs to read instead of -server")
+)
+
+func main() {
+	flag.Parse()
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", *dbFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(schema); err != nil {
+		log.Fatalf("creating schema: %v", err)
+	}
+	offset, err := readOffset(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var src maintner.MutationSource
+	if *dir != "" {
+		src = maintner.NewDiskMutationLogger(*dir)
+	} else {
+		cacheDir := godata.Dir()
+		if err := os.MkdirAll(cacheDir, 0700); err != nil {
+			log.Fatal(err)
+		}
+		src = maintner.NewNetworkMutationSource(*server, cacheDir)
+	}
+	ts := newTrackingSource(src, offset)
+	corpus := new(maintner.Corpus)
+	t0 := time.Now()
+	if err := corpus.Initialize(ctx, ts); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("loaded corpus in %v; %d mutations, %d new since offset %d",
+		time.Since(t0).Round(time.Second), ts.n, ts.n-offset, offset)
+
+	t0 = time.Now()
+	st, err := export(db, corpus, ts.n, ts.dirty)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("exported %d issues, %d CLs and %d commits in %v",
+		st.issues, st.cls, st.commits, time.Since(t0).Round(time.Second))
+}
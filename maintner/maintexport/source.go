@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintpb"
+)
+
+// dirtySet is the set of entities changed by mutations after an offset.
+type dirtySet struct {
+	all    bool // everything is dirty; the database is new
+	issues map[issueKey]bool
+	cls    map[clKey]bool
+}
+
+type issueKey struct {
+	repo   string // "golang/go"
+	number int32
+}
+
+type clKey struct {
+	project string // "go.googlesource.com/go"
+	number  int32
+}
+
+func (d *dirtySet) issue(repo string, number int32) bool {
+	return d.all || d.issues[issueKey{repo, number}]
+}
+
+func (d *dirtySet) cl(project string, number int32) bool {
+	return d.all || d.cls[clKey{project, number}]
+}
+
+// rxChangeRef matches Gerrit change refs, capturing the CL number.
+var rxChangeRef = regexp.MustCompile(`^refs/changes/[0-9a-f]{2}/([0-9]+)/`)
+
+// note records the entities changed by m.
+func (d *dirtySet) note(m *maintpb.Mutation) {
+	if d.all {
+		return
+	}
+	if im := m.GithubIssue; im != nil {
+		d.issues[issueKey{im.Owner + "/" + im.Repo, im.Number}] = true
+	}
+	if gm := m.Gerrit; gm != nil {
+		for _, ref := range gm.Refs {
+			if sm := rxChangeRef.FindStringSubmatch(ref.Ref); sm != nil {
+				n, err := strconv.ParseInt(sm[1], 10, 32)
+				if err == nil {
+					d.cls[clKey{gm.Project, int32(n)}] = true
+				}
+			}
+		}
+		for _, np := range gm.Notes {
+			d.cls[clKey{gm.Project, np.Cl}] = true
+		}
+	}
+}
+
+// A trackingSource is a MutationSource that counts the mutations
+// read from an underlying source, and notes the entities changed by
+// mutations after the first offset ones.
+type trackingSource struct {
+	src    maintner.MutationSource
+	offset int64
+
+	// n and dirty are updated as mutations are read, and may
+	// be read once the corpus has been loaded.
+	n     int64
+	dirty *dirtySet
+}
+
+func newTrackingSource(src maintner.MutationSource, offset int64) *trackingSource {
+	return &trackingSource{
+		src:    src,
+		offset: offset,
+		dirty: &dirtySet{
+			all:    offset == 0,
+			issues: make(map[issueKey]bool),
+			cls:    make(map[clKey]bool),
+		},
+	}
+}
+
+func (s *trackingSource) GetMutations(ctx context.Context) <-chan maintner.MutationStreamEvent {
+	in := s.src.GetMutations(ctx)
+	out := make(chan maintner.MutationStreamEvent, 50)
+	go func() {
+		for {
+			var e maintner.MutationStreamEvent
+			select {
+			case <-ctx.Done():
+				return
+			case e = <-in:
+			}
+			if e.Mutation != nil {
+				s.n++
+				if s.n > s.offset {
+					s.dirty.note(e.Mutation)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- e:
+			}
+			if e.Err != nil || e.End {
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -34,6 +34,7 @@ type Corpus struct {
 	verbose        bool
 	dataDir        string
 	sawErrSplit    bool
+	watchMu        sync.Mutex // serializes delivery of change events to watchers
 
 	mu sync.RWMutex // guards all following fields
 	// corpus state:
@@ -44,6 +45,10 @@ type Corpus struct {
 	// pubsub:
 	activityChans map[string]chan struct{} // keyed by topic
 
+	// change notification:
+	watchers       []*watcher
+	pendingChanges []ChangeEvent // not yet delivered to watchers
+
 	// github-specific
 	github             *GitHub
 	gerrit             *Gerrit
@@ -258,6 +263,7 @@ func (c *Corpus) Update(ctx context.Context) error {
 	}
 	log.Printf("Updating data from log %T ...", c.mutationSource)
 	err := c.update(ctx, nil)
+	c.deliverChanges()
 	if err == ErrSplit {
 		c.sawErrSplit = true
 	}
@@ -275,6 +281,7 @@ func (c *Corpus) UpdateWithLocker(ctx context.Context, lk sync.Locker) error {
 	}
 	log.Printf("Updating data from log %T ...", c.mutationSource)
 	err := c.update(ctx, lk)
+	c.deliverChanges()
 	if err == ErrSplit {
 		c.sawErrSplit = true
 	}
@@ -332,14 +339,14 @@ func (c *Corpus) addMutation(m *maintpb.Mutation) {
 	c.finishProcessing()
 	c.mu.Unlock()
 
-	if c.mutationLogger == nil {
-		return
-	}
-	err := c.mutationLogger.Log(m)
-	if err != nil {
-		// TODO: handle errors better? failing is only safe option.
-		log.Fatalf("could not log mutation %v: %v\n", m, err)
+	if c.mutationLogger != nil {
+		err := c.mutationLogger.Log(m)
+		if err != nil {
+			// TODO: handle errors better? failing is only safe option.
+			log.Fatalf("could not log mutation %v: %v\n", m, err)
+		}
 	}
+	c.deliverChanges()
 }
 
 // c.mu must be held.
@@ -350,6 +357,9 @@ func (c *Corpus) processMutationLocked(m *maintpb.Mutation) {
 	if gm := m.Github; gm != nil {
 		c.processGithubMutation(gm)
 	}
+	if dm := m.GithubDiscussion; dm != nil {
+		c.processGithubDiscussionMutation(dm)
+	}
 	if gm := m.Git; gm != nil {
 		c.processGitMutation(gm)
 	}
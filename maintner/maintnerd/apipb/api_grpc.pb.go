@@ -42,6 +42,11 @@ type MaintnerServiceClient interface {
 	// contain any pass/fail information; it only contains information on the branches
 	// and commits themselves.
 	GetDashboard(ctx context.Context, in *DashboardRequest, opts ...grpc.CallOption) (*DashboardResponse, error)
+	// WatchChanges streams changes to the corpus as they are applied,
+	// starting from the time of the call. The stream ends with an
+	// error if the client falls too far behind; clients should then
+	// reconnect and rescan any state they depend on.
+	WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (MaintnerService_WatchChangesClient, error)
 }
 
 type maintnerServiceClient struct {
@@ -97,6 +102,38 @@ func (c *maintnerServiceClient) GetDashboard(ctx context.Context, in *DashboardR
 	return out, nil
 }
 
+func (c *maintnerServiceClient) WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (MaintnerService_WatchChangesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MaintnerService_ServiceDesc.Streams[0], "/apipb.MaintnerService/WatchChanges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &maintnerServiceWatchChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MaintnerService_WatchChangesClient interface {
+	Recv() (*ChangeEvent, error)
+	grpc.ClientStream
+}
+
+type maintnerServiceWatchChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *maintnerServiceWatchChangesClient) Recv() (*ChangeEvent, error) {
+	m := new(ChangeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // MaintnerServiceServer is the server API for MaintnerService service.
 // All implementations must embed UnimplementedMaintnerServiceServer
 // for forward compatibility
@@ -124,6 +161,11 @@ type MaintnerServiceServer interface {
 	// contain any pass/fail information; it only contains information on the branches
 	// and commits themselves.
 	GetDashboard(context.Context, *DashboardRequest) (*DashboardResponse, error)
+	// WatchChanges streams changes to the corpus as they are applied,
+	// starting from the time of the call. The stream ends with an
+	// error if the client falls too far behind; clients should then
+	// reconnect and rescan any state they depend on.
+	WatchChanges(*WatchChangesRequest, MaintnerService_WatchChangesServer) error
 	mustEmbedUnimplementedMaintnerServiceServer()
 }
 
@@ -146,6 +188,9 @@ func (UnimplementedMaintnerServiceServer) ListGoReleases(context.Context, *ListG
 func (UnimplementedMaintnerServiceServer) GetDashboard(context.Context, *DashboardRequest) (*DashboardResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDashboard not implemented")
 }
+func (UnimplementedMaintnerServiceServer) WatchChanges(*WatchChangesRequest, MaintnerService_WatchChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchChanges not implemented")
+}
 func (UnimplementedMaintnerServiceServer) mustEmbedUnimplementedMaintnerServiceServer() {}
 
 // UnsafeMaintnerServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -249,6 +294,27 @@ func _MaintnerService_GetDashboard_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MaintnerService_WatchChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MaintnerServiceServer).WatchChanges(m, &maintnerServiceWatchChangesServer{stream})
+}
+
+type MaintnerService_WatchChangesServer interface {
+	Send(*ChangeEvent) error
+	grpc.ServerStream
+}
+
+type maintnerServiceWatchChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *maintnerServiceWatchChangesServer) Send(m *ChangeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // MaintnerService_ServiceDesc is the grpc.ServiceDesc for MaintnerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -277,6 +343,12 @@ var MaintnerService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _MaintnerService_GetDashboard_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchChanges",
+			Handler:       _MaintnerService_WatchChanges_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "api.proto",
 }
@@ -8,6 +8,7 @@ package gcslog
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
@@ -50,6 +51,11 @@ type GCSLog struct {
 	bucket        *storage.BucketHandle
 	segmentPrefix string
 	debug         bool
+	manifestKey   ed25519.PrivateKey // or nil to not serve a manifest
+
+	manifestMu     sync.Mutex            // guards the following, and signing
+	manifest       *maintner.LogManifest // last signed manifest, or nil
+	manifestLoaded bool                  // whether manifest was loaded from GCS
 
 	mu         sync.Mutex // guards the following
 	cond       *sync.Cond
@@ -128,6 +134,9 @@ func (gl *GCSLog) initLoad(ctx context.Context) error {
 			log.Printf("Ignoring GCS object with invalid prefix %q", objAttrs.Name)
 			continue
 		}
+		if objAttrs.Name == path.Join(gl.segmentPrefix, manifestObject) {
+			continue
+		}
 		m := objnameRx.FindStringSubmatch(objAttrs.Name)
 		if m == nil {
 			log.Printf("Ignoring unrecognized GCS object %q", objAttrs.Name)
@@ -262,6 +271,174 @@ func (gl *GCSLog) serveJSONLogsIndex(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+func (gl *GCSLog) serveManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, "bad method", http.StatusBadRequest)
+		return
+	}
+	if gl.manifestKey == nil {
+		http.Error(w, "no manifest signing key configured", http.StatusNotFound)
+		return
+	}
+	m, err := gl.signedManifest(r.Context())
+	if err != nil {
+		log.Printf("serving manifest: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body, _ := json.MarshalIndent(m, "", "\t")
+	w.Write(body)
+}
+
+// manifestObject is the name of the GCS object, next to the log
+// segments, that holds the last signed manifest.
+const manifestObject = "manifest.json"
+
+// signedManifest returns a signed manifest of the log. It only signs a
+// new one when the log has changed since the last one, which it
+// persists in GCS. It refuses to sign a log that doesn't extend the
+// last signed one, such as after a restart lost mutations that weren't
+// flushed yet, since clients may have synced the log it signed. To
+// start a new history, delete the manifest object.
+func (gl *GCSLog) signedManifest(ctx context.Context) (*maintner.LogManifest, error) {
+	gl.manifestMu.Lock()
+	defer gl.manifestMu.Unlock()
+	if !gl.manifestLoaded {
+		m, err := gl.loadManifest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		gl.manifest, gl.manifestLoaded = m, true
+	}
+
+	segs := gl.getJSONLogs(0)
+	if prev := gl.manifest; prev != nil {
+		if sameSegments(prev.Segments, segs) {
+			return prev, nil
+		}
+		if err := gl.checkExtends(ctx, prev, segs); err != nil {
+			return nil, fmt.Errorf("log doesn't extend the manifest signed at %v: %v", prev.Time, err)
+		}
+	}
+	m := maintner.NewLogManifest(segs, time.Now(), gl.manifestKey)
+	if err := gl.saveManifest(ctx, m); err != nil {
+		return nil, err
+	}
+	gl.manifest = m
+	return m, nil
+}
+
+// sameSegments reports whether the manifest segments signed are segs.
+func sameSegments(signed []maintner.ManifestSegment, segs []maintner.LogSegmentJSON) bool {
+	if len(signed) != len(segs) {
+		return false
+	}
+	for i, seg := range signed {
+		if seg.Size != segs[i].Size || seg.SHA224 != segs[i].SHA224 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkExtends checks that the log segments segs extend the log signed
+// by prev: that they keep its segments, except that its last segment
+// may have grown since.
+func (gl *GCSLog) checkExtends(ctx context.Context, prev *maintner.LogManifest, segs []maintner.LogSegmentJSON) error {
+	if len(segs) < len(prev.Segments) {
+		return fmt.Errorf("log has %d segments; signed %d", len(segs), len(prev.Segments))
+	}
+	for i, old := range prev.Segments {
+		seg := segs[i]
+		if seg.Size == old.Size && seg.SHA224 == old.SHA224 {
+			continue
+		}
+		if i == len(prev.Segments)-1 && seg.Size > old.Size {
+			sum, err := gl.segmentPrefixSum(ctx, i, old.Size)
+			if err != nil {
+				return err
+			}
+			if sum == old.SHA224 {
+				continue
+			}
+		}
+		return fmt.Errorf("segment %d has size %d, SHA-224 %s; signed size %d, SHA-224 %s", i, seg.Size, seg.SHA224, old.Size, old.SHA224)
+	}
+	return nil
+}
+
+// segmentPrefixSum returns the lowercase hex SHA-224 of the first size
+// bytes of segment num, from memory if it's the segment being written
+// or else from GCS.
+func (gl *GCSLog) segmentPrefixSum(ctx context.Context, num int, size int64) (string, error) {
+	gl.mu.Lock()
+	if num == gl.curNum && int64(gl.logBuf.Len()) >= size {
+		sum := sha256.Sum224(gl.logBuf.Bytes()[:size])
+		gl.mu.Unlock()
+		return fmt.Sprintf("%x", sum), nil
+	}
+	seg, ok := gl.seg[num]
+	gl.mu.Unlock()
+	if !ok || gl.bucket == nil {
+		return "", fmt.Errorf("segment %d not found", num)
+	}
+	rd, err := gl.bucket.Object(gl.objectPath(seg)).NewRangeReader(ctx, 0, size)
+	if err != nil {
+		return "", err
+	}
+	defer rd.Close()
+	h := sha256.New224()
+	if _, err := io.Copy(h, rd); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// loadManifest returns the last signed manifest saved in GCS,
+// or nil if there is none.
+func (gl *GCSLog) loadManifest(ctx context.Context) (*maintner.LogManifest, error) {
+	if gl.bucket == nil {
+		return nil, nil
+	}
+	rd, err := gl.bucket.Object(path.Join(gl.segmentPrefix, manifestObject)).NewReader(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading signed manifest: %v", err)
+	}
+	defer rd.Close()
+	m := new(maintner.LogManifest)
+	if err := json.NewDecoder(rd).Decode(m); err != nil {
+		return nil, fmt.Errorf("decoding signed manifest: %v", err)
+	}
+	if err := m.Verify(gl.manifestKey.Public().(ed25519.PublicKey)); err != nil {
+		return nil, fmt.Errorf("signed manifest: %v", err)
+	}
+	return m, nil
+}
+
+// saveManifest saves m in GCS as the last signed manifest.
+func (gl *GCSLog) saveManifest(ctx context.Context, m *maintner.LogManifest) error {
+	if gl.bucket == nil {
+		return nil
+	}
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return try(4, time.Second, func() error {
+		w := gl.bucket.Object(path.Join(gl.segmentPrefix, manifestObject)).NewWriter(ctx)
+		w.ContentType = "application/json"
+		if _, err := w.Write(body); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	})
+}
+
 // sumSegmentSizes returns the sum of each seg.Size in segs.
 func sumSegmentSizes(segs []maintner.LogSegmentJSON) (sum int64) {
 	for _, seg := range segs {
@@ -370,6 +547,12 @@ func (w gcsLogWriter) Write(p []byte) (n int, err error) {
 // It must only be called before it's used.
 func (gl *GCSLog) SetDebug(v bool) { gl.debug = v }
 
+// SetManifestKey sets the key used to sign the log manifest served at
+// /logs/manifest. If it's not set, no manifest is served.
+//
+// It must only be called before it's used.
+func (gl *GCSLog) SetManifestKey(key ed25519.PrivateKey) { gl.manifestKey = key }
+
 // Log writes m to GCS after the buffer is full or after a periodic flush.
 func (gl *GCSLog) Log(m *maintpb.Mutation) error {
 	data, err := proto.Marshal(m)
@@ -582,8 +765,10 @@ func (gl *GCSLog) CopyFrom(src maintner.MutationSource) error {
 	panic("unexpected channel close")
 }
 
-// RegisterHandlers adds handlers for the default paths (/logs and /logs/).
+// RegisterHandlers adds handlers for the default paths (/logs, /logs/
+// and /logs/manifest).
 func (gl *GCSLog) RegisterHandlers(mux *http.ServeMux) {
 	mux.HandleFunc("/logs", gl.serveJSONLogsIndex)
 	mux.HandleFunc("/logs/", gl.serveLogFile)
+	mux.HandleFunc("/logs/manifest", gl.serveManifest)
 }
@@ -5,10 +5,16 @@
 package gcslog
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"golang.org/x/build/maintner"
 	"golang.org/x/build/maintner/maintpb"
 )
 
@@ -42,3 +48,79 @@ func testGCSLogWakeup(t *testing.T, activity bool) {
 		t.Errorf("timeout")
 	}
 }
+
+func TestServeManifest(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gl := newGCSLogBase()
+	gl.SetManifestKey(priv)
+	mux := http.NewServeMux()
+	gl.RegisterHandlers(mux)
+	logIssues := func(first, n int) {
+		t.Helper()
+		for i := first; i < first+n; i++ {
+			if err := gl.Log(&maintpb.Mutation{GithubIssue: &maintpb.GithubIssueMutation{Number: int32(i)}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		gl.mu.Lock()
+		if gl.flushTimer != nil {
+			gl.flushTimer.Stop()
+			gl.flushTimer = nil
+		}
+		gl.mu.Unlock()
+	}
+	get := func() (*maintner.LogManifest, int) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/logs/manifest", nil))
+		if rec.Code != http.StatusOK {
+			return nil, rec.Code
+		}
+		m := new(maintner.LogManifest)
+		if err := json.Unmarshal(rec.Body.Bytes(), m); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Verify(pub); err != nil {
+			t.Fatal(err)
+		}
+		return m, rec.Code
+	}
+
+	logIssues(0, 3)
+	m, code := get()
+	if m == nil {
+		t.Fatalf("status = %v", code)
+	}
+	if len(m.Segments) != 1 || m.Segments[0].Size != int64(gl.logBuf.Len()) {
+		t.Errorf("manifest segments = %+v; want the one growing segment", m.Segments)
+	}
+
+	// The log hasn't changed, so the manifest isn't signed again.
+	if m2, _ := get(); m2 == nil || !bytes.Equal(m2.Signature, m.Signature) {
+		t.Errorf("manifest was signed again for an unchanged log")
+	}
+
+	// The growing segment extends the signed one.
+	logIssues(3, 2)
+	m3, code := get()
+	if m3 == nil {
+		t.Fatalf("status = %v after the log grew", code)
+	}
+	if len(m3.Segments) != 1 || m3.Segments[0].Size <= m.Segments[0].Size {
+		t.Errorf("manifest segments = %+v; want the grown segment", m3.Segments)
+	}
+
+	// A log that diverges from the signed one, as after a restart
+	// that lost unflushed mutations, isn't signed.
+	gl.mu.Lock()
+	gl.logBuf.Reset()
+	gl.logSHA224.Reset()
+	gl.mu.Unlock()
+	logIssues(10, 6)
+	if m4, code := get(); code != http.StatusInternalServerError {
+		t.Errorf("diverged log: status = %v, manifest = %+v; want %v", code, m4, http.StatusInternalServerError)
+	}
+}
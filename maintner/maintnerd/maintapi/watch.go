@@ -0,0 +1,115 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintapi
+
+import (
+	"sync"
+
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintnerd/apipb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// watchBufferSize is the number of change events buffered for each
+// WatchChanges stream before the client is considered too slow
+// and disconnected.
+const watchBufferSize = 4096
+
+func (s apiService) WatchChanges(req *apipb.WatchChangesRequest, stream apipb.MaintnerService_WatchChangesServer) error {
+	match, err := newChangeFilter(req)
+	if err != nil {
+		return err
+	}
+	var (
+		ch           = make(chan maintner.ChangeEvent, watchBufferSize)
+		overflow     = make(chan struct{})
+		overflowOnce sync.Once
+	)
+	stop := s.c.Watch(func(e maintner.ChangeEvent) {
+		if !match(e) {
+			return
+		}
+		select {
+		case ch <- e:
+		default:
+			overflowOnce.Do(func() { close(overflow) })
+		}
+	})
+	defer stop()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-overflow:
+			return grpc.Errorf(codes.ResourceExhausted, "client fell more than %d events behind", watchBufferSize)
+		case e := <-ch:
+			if err := stream.Send(changeEventProto(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newChangeFilter returns a func reporting whether a change event
+// is selected by req.
+func newChangeFilter(req *apipb.WatchChangesRequest) (func(maintner.ChangeEvent) bool, error) {
+	set := func(ss []string) map[string]bool {
+		if len(ss) == 0 {
+			return nil
+		}
+		m := make(map[string]bool)
+		for _, s := range ss {
+			m[s] = true
+		}
+		return m
+	}
+	repos := set(req.GithubRepos)
+	projects := set(req.GerritProjects)
+	var kinds map[maintner.ChangeKind]bool
+	for _, name := range req.Kinds {
+		k, ok := maintner.ParseChangeKind(name)
+		if !ok {
+			return nil, grpc.Errorf(codes.InvalidArgument, "unknown change kind %q", name)
+		}
+		if kinds == nil {
+			kinds = make(map[maintner.ChangeKind]bool)
+		}
+		kinds[k] = true
+	}
+	return func(e maintner.ChangeEvent) bool {
+		if kinds != nil && !kinds[e.Kind] {
+			return false
+		}
+		if e.Kind.IsGitHub() {
+			return repos == nil || repos[e.GitHubRepo.String()]
+		}
+		return projects == nil || projects[e.GerritProject]
+	}, nil
+}
+
+func changeEventProto(e maintner.ChangeEvent) *apipb.ChangeEvent {
+	pe := &apipb.ChangeEvent{
+		Kind:          e.Kind.String(),
+		TimeUnixNano:  e.Time.UnixNano(),
+		Issue:         e.Issue,
+		Label:         e.Label,
+		Milestone:     e.Milestone,
+		CommentId:     e.CommentID,
+		PullRequest:   e.PullRequest,
+		GerritProject: e.GerritProject,
+		Cl:            e.CL,
+		PatchSet:      e.PatchSet,
+		Vote:          int32(e.Vote),
+		Ref:           e.Ref,
+		Hash:          e.Hash.String(),
+	}
+	if e.Kind.IsGitHub() {
+		pe.GithubRepo = e.GitHubRepo.String()
+	}
+	return pe
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintapi
+
+import (
+	"testing"
+
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/maintnerd/apipb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestChangeFilter(t *testing.T) {
+	var (
+		goIssue   = maintner.ChangeEvent{Kind: maintner.IssueLabeled, GitHubRepo: maintner.GitHubRepoID{Owner: "golang", Repo: "go"}, Issue: 1}
+		vulnIssue = maintner.ChangeEvent{Kind: maintner.IssueOpened, GitHubRepo: maintner.GitHubRepoID{Owner: "golang", Repo: "vulndb"}, Issue: 2}
+		goCL      = maintner.ChangeEvent{Kind: maintner.CLMerged, GerritProject: "go.googlesource.com/go", CL: 3}
+		buildRef  = maintner.ChangeEvent{Kind: maintner.RefMoved, GerritProject: "go.googlesource.com/build", Ref: "refs/heads/master"}
+	)
+	all := []maintner.ChangeEvent{goIssue, vulnIssue, goCL, buildRef}
+	tests := []struct {
+		name string
+		req  *apipb.WatchChangesRequest
+		want []maintner.ChangeEvent
+	}{
+		{"all", &apipb.WatchChangesRequest{}, all},
+		{"repo", &apipb.WatchChangesRequest{GithubRepos: []string{"golang/go"}}, []maintner.ChangeEvent{goIssue, goCL, buildRef}},
+		{"project", &apipb.WatchChangesRequest{GerritProjects: []string{"go.googlesource.com/build"}}, []maintner.ChangeEvent{goIssue, vulnIssue, buildRef}},
+		{"kinds", &apipb.WatchChangesRequest{Kinds: []string{"IssueOpened", "clmerged"}}, []maintner.ChangeEvent{vulnIssue, goCL}},
+		{"combined", &apipb.WatchChangesRequest{GithubRepos: []string{"golang/go"}, Kinds: []string{"IssueOpened", "IssueLabeled"}}, []maintner.ChangeEvent{goIssue}},
+	}
+	for _, tt := range tests {
+		match, err := newChangeFilter(tt.req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []maintner.ChangeEvent
+		for _, e := range all {
+			if match(e) {
+				got = append(got, e)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v; want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v; want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	_, err := newChangeFilter(&apipb.WatchChangesRequest{Kinds: []string{"NoSuchKind"}})
+	if grpc.Code(err) != codes.InvalidArgument {
+		t.Errorf("bogus kind: got error %v; want InvalidArgument", err)
+	}
+}
+
+func TestChangeEventProto(t *testing.T) {
+	pe := changeEventProto(maintner.ChangeEvent{
+		Kind:       maintner.IssueCommented,
+		GitHubRepo: maintner.GitHubRepoID{Owner: "golang", Repo: "go"},
+		Issue:      42,
+		CommentID:  7,
+	})
+	if pe.Kind != "IssueCommented" || pe.GithubRepo != "golang/go" || pe.Issue != 42 || pe.CommentId != 7 {
+		t.Errorf("changeEventProto = %v", pe)
+	}
+	pe = changeEventProto(maintner.ChangeEvent{Kind: maintner.CLVote, GerritProject: "go.googlesource.com/go", CL: 1, Label: "Code-Review", Vote: 2})
+	if pe.GithubRepo != "" || pe.Label != "Code-Review" || pe.Vote != 2 {
+		t.Errorf("changeEventProto = %v", pe)
+	}
+}
@@ -8,6 +8,10 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -50,6 +54,7 @@ var (
 
 	bucket         = flag.String("bucket", "", "if non-empty, Google Cloud Storage bucket to use for log storage. If the bucket name contains a \"/\", the part after the slash will be a prefix for the segments.")
 	migrateGCSFlag = flag.Bool("migrate-disk-to-gcs", false, "[dev] If true, migrate from disk-based logs to GCS logs on start-up, then quit.")
+	manifestKey    = flag.String("manifest-key", "", "if non-empty, file containing a PEM-encoded PKCS #8 Ed25519 private key to sign the log manifest at /logs/manifest with. On GCE, it defaults to the key in Secret Manager.")
 )
 
 func init() {
@@ -139,6 +144,11 @@ func main() {
 				log.Fatalf("newGCSLog: %v", err)
 			}
 			gl.SetDebug(*debug)
+			if key, err := getManifestKey(ctx); err != nil {
+				log.Printf("Not serving a log manifest: %v", err)
+			} else {
+				gl.SetManifestKey(key)
+			}
 			gl.RegisterHandlers(http.DefaultServeMux)
 			if *migrateGCSFlag {
 				diskLog := maintner.NewDiskMutationLogger(*dataDir)
@@ -338,6 +348,44 @@ func getGithubToken(ctx context.Context) (string, error) {
 	return token, nil
 }
 
+// getManifestKey returns the key to sign the mutation log manifest with.
+func getManifestKey(ctx context.Context) (ed25519.PrivateKey, error) {
+	var slurp []byte
+	if *manifestKey != "" {
+		var err error
+		slurp, err = os.ReadFile(*manifestKey)
+		if err != nil {
+			return nil, err
+		}
+	} else if metadata.OnGCE() {
+		sc := secret.MustNewClient()
+
+		ctxSc, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		key, err := sc.Retrieve(ctxSc, secret.NameMaintnerManifestKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve secret manager %q: %v", secret.NameMaintnerManifestKey, err)
+		}
+		slurp = []byte(key)
+	} else {
+		return nil, errors.New("no --manifest-key specified")
+	}
+	block, _ := pem.Decode(slurp)
+	if block == nil {
+		return nil, errors.New("manifest key isn't PEM-encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("manifest key is a %T, not an Ed25519 key", key)
+	}
+	return edKey, nil
+}
+
 func syncProdToDevMutationLogs() {
 	src := godata.Dir()
 	dst := *dataDir
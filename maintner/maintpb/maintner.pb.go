@@ -32,6 +32,14 @@ It has these top-level messages:
 	GitDiffTreeFile
 	GerritMutation
 	GitRef
+	GithubReviewComment
+	GithubCheckRun
+	GithubCommitStatus
+	GithubDiscussionMutation
+	GithubDiscussionComment
+	GerritNotes
+	GerritComment
+	GerritCommentRange
 */
 package maintpb
 
@@ -57,10 +65,11 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Mutation struct {
-	GithubIssue *GithubIssueMutation `protobuf:"bytes,1,opt,name=github_issue,json=githubIssue" json:"github_issue,omitempty"`
-	Github      *GithubMutation      `protobuf:"bytes,3,opt,name=github" json:"github,omitempty"`
-	Git         *GitMutation         `protobuf:"bytes,2,opt,name=git" json:"git,omitempty"`
-	Gerrit      *GerritMutation      `protobuf:"bytes,4,opt,name=gerrit" json:"gerrit,omitempty"`
+	GithubIssue      *GithubIssueMutation      `protobuf:"bytes,1,opt,name=github_issue,json=githubIssue" json:"github_issue,omitempty"`
+	Github           *GithubMutation           `protobuf:"bytes,3,opt,name=github" json:"github,omitempty"`
+	GithubDiscussion *GithubDiscussionMutation `protobuf:"bytes,5,opt,name=github_discussion,json=githubDiscussion" json:"github_discussion,omitempty"`
+	Git              *GitMutation              `protobuf:"bytes,2,opt,name=git" json:"git,omitempty"`
+	Gerrit           *GerritMutation           `protobuf:"bytes,4,opt,name=gerrit" json:"gerrit,omitempty"`
 }
 
 func (m *Mutation) Reset()                    { *m = Mutation{} }
@@ -82,6 +91,13 @@ func (m *Mutation) GetGithub() *GithubMutation {
 	return nil
 }
 
+func (m *Mutation) GetGithubDiscussion() *GithubDiscussionMutation {
+	if m != nil {
+		return m.GithubDiscussion
+	}
+	return nil
+}
+
 func (m *Mutation) GetGit() *GitMutation {
 	if m != nil {
 		return m.Git
@@ -180,6 +196,22 @@ type GithubIssueMutation struct {
 	EventStatus    *GithubIssueSyncStatus        `protobuf:"bytes,27,opt,name=event_status,json=eventStatus" json:"event_status,omitempty"`
 	Review         []*GithubReview               `protobuf:"bytes,29,rep,name=review" json:"review,omitempty"`
 	ReviewStatus   *GithubIssueSyncStatus        `protobuf:"bytes,30,opt,name=review_status,json=reviewStatus" json:"review_status,omitempty"`
+	// Pull request review comments (comments on lines of the diff)
+	// that are new or updated. Each holds the comment's full state.
+	ReviewComment       []*GithubReviewComment `protobuf:"bytes,32,rep,name=review_comment,json=reviewComment" json:"review_comment,omitempty"`
+	ReviewCommentStatus *GithubIssueSyncStatus `protobuf:"bytes,33,opt,name=review_comment_status,json=reviewCommentStatus" json:"review_comment_status,omitempty"`
+	// head_commit_id is the pull request's head commit, as of the
+	// accompanying check_status.
+	HeadCommitId string `protobuf:"bytes,37,opt,name=head_commit_id,json=headCommitId" json:"head_commit_id,omitempty"`
+	// author_association is the issue author's relationship to the
+	// repository, like "MEMBER" or "FIRST_TIME_CONTRIBUTOR", as of
+	// the issue's creation or last update.
+	AuthorAssociation string `protobuf:"bytes,38,opt,name=author_association,json=authorAssociation" json:"author_association,omitempty"`
+	// Check runs and commit statuses on the pull request's head
+	// commit that are new or updated. Each holds the full state.
+	CheckRun     []*GithubCheckRun      `protobuf:"bytes,34,rep,name=check_run,json=checkRun" json:"check_run,omitempty"`
+	CommitStatus []*GithubCommitStatus  `protobuf:"bytes,35,rep,name=commit_status,json=commitStatus" json:"commit_status,omitempty"`
+	CheckStatus  *GithubIssueSyncStatus `protobuf:"bytes,36,opt,name=check_status,json=checkStatus" json:"check_status,omitempty"`
 }
 
 func (m *GithubIssueMutation) Reset()                    { *m = GithubIssueMutation{} }
@@ -397,6 +429,55 @@ func (m *GithubIssueMutation) GetReviewStatus() *GithubIssueSyncStatus {
 	return nil
 }
 
+func (m *GithubIssueMutation) GetReviewComment() []*GithubReviewComment {
+	if m != nil {
+		return m.ReviewComment
+	}
+	return nil
+}
+
+func (m *GithubIssueMutation) GetReviewCommentStatus() *GithubIssueSyncStatus {
+	if m != nil {
+		return m.ReviewCommentStatus
+	}
+	return nil
+}
+
+func (m *GithubIssueMutation) GetHeadCommitId() string {
+	if m != nil {
+		return m.HeadCommitId
+	}
+	return ""
+}
+
+func (m *GithubIssueMutation) GetAuthorAssociation() string {
+	if m != nil {
+		return m.AuthorAssociation
+	}
+	return ""
+}
+
+func (m *GithubIssueMutation) GetCheckRun() []*GithubCheckRun {
+	if m != nil {
+		return m.CheckRun
+	}
+	return nil
+}
+
+func (m *GithubIssueMutation) GetCommitStatus() []*GithubCommitStatus {
+	if m != nil {
+		return m.CommitStatus
+	}
+	return nil
+}
+
+func (m *GithubIssueMutation) GetCheckStatus() *GithubIssueSyncStatus {
+	if m != nil {
+		return m.CheckStatus
+	}
+	return nil
+}
+
 // BoolChange represents a change to a boolean value.
 // (Notably, the wrapper type permits representing a change to false.)
 type BoolChange struct {
@@ -1063,6 +1144,9 @@ type GerritMutation struct {
 	Refs []*GitRef `protobuf:"bytes,3,rep,name=refs" json:"refs,omitempty"`
 	// deleted_refs are ref names to delete.
 	DeletedRefs []string `protobuf:"bytes,4,rep,name=deleted_refs,json=deletedRefs" json:"deleted_refs,omitempty"`
+	// notes are the new or changed inline comments of CLs,
+	// from the NoteDb notes of their meta commits.
+	Notes []*GerritNotes `protobuf:"bytes,5,rep,name=notes" json:"notes,omitempty"`
 }
 
 func (m *GerritMutation) Reset()                    { *m = GerritMutation{} }
@@ -1098,6 +1182,13 @@ func (m *GerritMutation) GetDeletedRefs() []string {
 	return nil
 }
 
+func (m *GerritMutation) GetNotes() []*GerritNotes {
+	if m != nil {
+		return m.Notes
+	}
+	return nil
+}
+
 type GitRef struct {
 	// ref is the git ref name, such as:
 	//    HEAD
@@ -1129,6 +1220,617 @@ func (m *GitRef) GetSha1() string {
 	return ""
 }
 
+// GithubReviewComment is a comment on a line of a pull request's diff.
+// See https://docs.github.com/en/rest/pulls/comments.
+type GithubReviewComment struct {
+	Id        int64                      `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	ReviewId  int64                      `protobuf:"varint,2,opt,name=review_id,json=reviewId" json:"review_id,omitempty"`
+	User      *GithubUser                `protobuf:"bytes,3,opt,name=user" json:"user,omitempty"`
+	Body      string                     `protobuf:"bytes,4,opt,name=body" json:"body,omitempty"`
+	Path      string                     `protobuf:"bytes,5,opt,name=path" json:"path,omitempty"`
+	Line      int32                      `protobuf:"varint,6,opt,name=line" json:"line,omitempty"`
+	Side      string                     `protobuf:"bytes,7,opt,name=side" json:"side,omitempty"`
+	CommitId  string                     `protobuf:"bytes,8,opt,name=commit_id,json=commitId" json:"commit_id,omitempty"`
+	InReplyTo int64                      `protobuf:"varint,9,opt,name=in_reply_to,json=inReplyTo" json:"in_reply_to,omitempty"`
+	Created   *google_protobuf.Timestamp `protobuf:"bytes,10,opt,name=created" json:"created,omitempty"`
+	Updated   *google_protobuf.Timestamp `protobuf:"bytes,11,opt,name=updated" json:"updated,omitempty"`
+}
+
+func (m *GithubReviewComment) Reset()                    { *m = GithubReviewComment{} }
+func (m *GithubReviewComment) String() string            { return proto.CompactTextString(m) }
+func (*GithubReviewComment) ProtoMessage()               {}
+func (*GithubReviewComment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *GithubReviewComment) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GithubReviewComment) GetReviewId() int64 {
+	if m != nil {
+		return m.ReviewId
+	}
+	return 0
+}
+
+func (m *GithubReviewComment) GetUser() *GithubUser {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *GithubReviewComment) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *GithubReviewComment) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *GithubReviewComment) GetLine() int32 {
+	if m != nil {
+		return m.Line
+	}
+	return 0
+}
+
+func (m *GithubReviewComment) GetSide() string {
+	if m != nil {
+		return m.Side
+	}
+	return ""
+}
+
+func (m *GithubReviewComment) GetCommitId() string {
+	if m != nil {
+		return m.CommitId
+	}
+	return ""
+}
+
+func (m *GithubReviewComment) GetInReplyTo() int64 {
+	if m != nil {
+		return m.InReplyTo
+	}
+	return 0
+}
+
+func (m *GithubReviewComment) GetCreated() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *GithubReviewComment) GetUpdated() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
+// GithubCheckRun is a check run on a commit.
+// See https://docs.github.com/en/rest/checks/runs.
+type GithubCheckRun struct {
+	Id         int64                      `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	CommitId   string                     `protobuf:"bytes,2,opt,name=commit_id,json=commitId" json:"commit_id,omitempty"`
+	Name       string                     `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	Status     string                     `protobuf:"bytes,4,opt,name=status" json:"status,omitempty"`
+	Conclusion string                     `protobuf:"bytes,5,opt,name=conclusion" json:"conclusion,omitempty"`
+	DetailsUrl string                     `protobuf:"bytes,6,opt,name=details_url,json=detailsUrl" json:"details_url,omitempty"`
+	Started    *google_protobuf.Timestamp `protobuf:"bytes,7,opt,name=started" json:"started,omitempty"`
+	Completed  *google_protobuf.Timestamp `protobuf:"bytes,8,opt,name=completed" json:"completed,omitempty"`
+}
+
+func (m *GithubCheckRun) Reset()                    { *m = GithubCheckRun{} }
+func (m *GithubCheckRun) String() string            { return proto.CompactTextString(m) }
+func (*GithubCheckRun) ProtoMessage()               {}
+func (*GithubCheckRun) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *GithubCheckRun) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GithubCheckRun) GetCommitId() string {
+	if m != nil {
+		return m.CommitId
+	}
+	return ""
+}
+
+func (m *GithubCheckRun) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GithubCheckRun) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *GithubCheckRun) GetConclusion() string {
+	if m != nil {
+		return m.Conclusion
+	}
+	return ""
+}
+
+func (m *GithubCheckRun) GetDetailsUrl() string {
+	if m != nil {
+		return m.DetailsUrl
+	}
+	return ""
+}
+
+func (m *GithubCheckRun) GetStarted() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *GithubCheckRun) GetCompleted() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Completed
+	}
+	return nil
+}
+
+// GithubCommitStatus is a commit status, the older form of check.
+// See https://docs.github.com/en/rest/commits/statuses.
+type GithubCommitStatus struct {
+	Id          int64                      `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	CommitId    string                     `protobuf:"bytes,2,opt,name=commit_id,json=commitId" json:"commit_id,omitempty"`
+	Context     string                     `protobuf:"bytes,3,opt,name=context" json:"context,omitempty"`
+	State       string                     `protobuf:"bytes,4,opt,name=state" json:"state,omitempty"`
+	Description string                     `protobuf:"bytes,5,opt,name=description" json:"description,omitempty"`
+	TargetUrl   string                     `protobuf:"bytes,6,opt,name=target_url,json=targetUrl" json:"target_url,omitempty"`
+	Created     *google_protobuf.Timestamp `protobuf:"bytes,7,opt,name=created" json:"created,omitempty"`
+	Updated     *google_protobuf.Timestamp `protobuf:"bytes,8,opt,name=updated" json:"updated,omitempty"`
+}
+
+func (m *GithubCommitStatus) Reset()                    { *m = GithubCommitStatus{} }
+func (m *GithubCommitStatus) String() string            { return proto.CompactTextString(m) }
+func (*GithubCommitStatus) ProtoMessage()               {}
+func (*GithubCommitStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *GithubCommitStatus) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GithubCommitStatus) GetCommitId() string {
+	if m != nil {
+		return m.CommitId
+	}
+	return ""
+}
+
+func (m *GithubCommitStatus) GetContext() string {
+	if m != nil {
+		return m.Context
+	}
+	return ""
+}
+
+func (m *GithubCommitStatus) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *GithubCommitStatus) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *GithubCommitStatus) GetTargetUrl() string {
+	if m != nil {
+		return m.TargetUrl
+	}
+	return ""
+}
+
+func (m *GithubCommitStatus) GetCreated() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *GithubCommitStatus) GetUpdated() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
+// GithubDiscussionMutation is a change to a GitHub Discussion.
+// See https://docs.github.com/en/graphql/guides/using-the-graphql-api-for-discussions.
+type GithubDiscussionMutation struct {
+	Owner  string `protobuf:"bytes,1,opt,name=owner" json:"owner,omitempty"`
+	Repo   string `protobuf:"bytes,2,opt,name=repo" json:"repo,omitempty"`
+	Number int32  `protobuf:"varint,3,opt,name=number" json:"number,omitempty"`
+	// not_exist is set true if the discussion has been found to not exist.
+	// If true, the owner/repo/number fields above must still be set.
+	NotExist bool                       `protobuf:"varint,4,opt,name=not_exist,json=notExist" json:"not_exist,omitempty"`
+	Id       int64                      `protobuf:"varint,15,opt,name=id" json:"id,omitempty"`
+	User     *GithubUser                `protobuf:"bytes,5,opt,name=user" json:"user,omitempty"`
+	Created  *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=created" json:"created,omitempty"`
+	Updated  *google_protobuf.Timestamp `protobuf:"bytes,7,opt,name=updated" json:"updated,omitempty"`
+	Title    string                     `protobuf:"bytes,8,opt,name=title" json:"title,omitempty"`
+	Body     *StringChange              `protobuf:"bytes,9,opt,name=body" json:"body,omitempty"`
+	Category string                     `protobuf:"bytes,10,opt,name=category" json:"category,omitempty"`
+	Closed   *BoolChange                `protobuf:"bytes,11,opt,name=closed" json:"closed,omitempty"`
+	Locked   *BoolChange                `protobuf:"bytes,12,opt,name=locked" json:"locked,omitempty"`
+	AnswerId int64                      `protobuf:"varint,13,opt,name=answer_id,json=answerId" json:"answer_id,omitempty"`
+	// New or updated comments. Each holds the comment's full state.
+	Comment []*GithubDiscussionComment `protobuf:"bytes,14,rep,name=comment" json:"comment,omitempty"`
+}
+
+func (m *GithubDiscussionMutation) Reset()                    { *m = GithubDiscussionMutation{} }
+func (m *GithubDiscussionMutation) String() string            { return proto.CompactTextString(m) }
+func (*GithubDiscussionMutation) ProtoMessage()               {}
+func (*GithubDiscussionMutation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *GithubDiscussionMutation) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *GithubDiscussionMutation) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *GithubDiscussionMutation) GetNumber() int32 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *GithubDiscussionMutation) GetNotExist() bool {
+	if m != nil {
+		return m.NotExist
+	}
+	return false
+}
+
+func (m *GithubDiscussionMutation) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GithubDiscussionMutation) GetUser() *GithubUser {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *GithubDiscussionMutation) GetCreated() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *GithubDiscussionMutation) GetUpdated() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
+func (m *GithubDiscussionMutation) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *GithubDiscussionMutation) GetBody() *StringChange {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+func (m *GithubDiscussionMutation) GetCategory() string {
+	if m != nil {
+		return m.Category
+	}
+	return ""
+}
+
+func (m *GithubDiscussionMutation) GetClosed() *BoolChange {
+	if m != nil {
+		return m.Closed
+	}
+	return nil
+}
+
+func (m *GithubDiscussionMutation) GetLocked() *BoolChange {
+	if m != nil {
+		return m.Locked
+	}
+	return nil
+}
+
+func (m *GithubDiscussionMutation) GetAnswerId() int64 {
+	if m != nil {
+		return m.AnswerId
+	}
+	return 0
+}
+
+func (m *GithubDiscussionMutation) GetComment() []*GithubDiscussionComment {
+	if m != nil {
+		return m.Comment
+	}
+	return nil
+}
+
+// GithubDiscussionComment is a top-level comment or reply on a GitHub Discussion.
+type GithubDiscussionComment struct {
+	Id      int64                      `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	ReplyTo int64                      `protobuf:"varint,2,opt,name=reply_to,json=replyTo" json:"reply_to,omitempty"`
+	User    *GithubUser                `protobuf:"bytes,3,opt,name=user" json:"user,omitempty"`
+	Body    string                     `protobuf:"bytes,4,opt,name=body" json:"body,omitempty"`
+	Created *google_protobuf.Timestamp `protobuf:"bytes,5,opt,name=created" json:"created,omitempty"`
+	Updated *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=updated" json:"updated,omitempty"`
+}
+
+func (m *GithubDiscussionComment) Reset()                    { *m = GithubDiscussionComment{} }
+func (m *GithubDiscussionComment) String() string            { return proto.CompactTextString(m) }
+func (*GithubDiscussionComment) ProtoMessage()               {}
+func (*GithubDiscussionComment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *GithubDiscussionComment) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GithubDiscussionComment) GetReplyTo() int64 {
+	if m != nil {
+		return m.ReplyTo
+	}
+	return 0
+}
+
+func (m *GithubDiscussionComment) GetUser() *GithubUser {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *GithubDiscussionComment) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *GithubDiscussionComment) GetCreated() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *GithubDiscussionComment) GetUpdated() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
+// GerritNotes records the inline comments of a CL that are new or
+// changed in the NoteDb notes of its meta commit.
+type GerritNotes struct {
+	Cl       int32            `protobuf:"varint,1,opt,name=cl" json:"cl,omitempty"`
+	Comments []*GerritComment `protobuf:"bytes,2,rep,name=comments" json:"comments,omitempty"`
+}
+
+func (m *GerritNotes) Reset()                    { *m = GerritNotes{} }
+func (m *GerritNotes) String() string            { return proto.CompactTextString(m) }
+func (*GerritNotes) ProtoMessage()               {}
+func (*GerritNotes) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *GerritNotes) GetCl() int32 {
+	if m != nil {
+		return m.Cl
+	}
+	return 0
+}
+
+func (m *GerritNotes) GetComments() []*GerritComment {
+	if m != nil {
+		return m.Comments
+	}
+	return nil
+}
+
+// GerritComment is a published inline comment on a file of a CL's
+// patch set, parsed from a NoteDb note.
+type GerritComment struct {
+	Uuid       string                     `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
+	ParentUuid string                     `protobuf:"bytes,2,opt,name=parent_uuid,json=parentUuid" json:"parent_uuid,omitempty"`
+	PatchSet   int32                      `protobuf:"varint,3,opt,name=patch_set,json=patchSet" json:"patch_set,omitempty"`
+	File       string                     `protobuf:"bytes,4,opt,name=file" json:"file,omitempty"`
+	Line       int32                      `protobuf:"varint,5,opt,name=line" json:"line,omitempty"`
+	Range      *GerritCommentRange        `protobuf:"bytes,6,opt,name=range" json:"range,omitempty"`
+	Side       int32                      `protobuf:"varint,7,opt,name=side" json:"side,omitempty"`
+	AuthorId   int64                      `protobuf:"varint,8,opt,name=author_id,json=authorId" json:"author_id,omitempty"`
+	Message    string                     `protobuf:"bytes,9,opt,name=message" json:"message,omitempty"`
+	Date       *google_protobuf.Timestamp `protobuf:"bytes,10,opt,name=date" json:"date,omitempty"`
+	Unresolved bool                       `protobuf:"varint,11,opt,name=unresolved" json:"unresolved,omitempty"`
+	// deleted is whether the comment was removed from the notes.
+	// If set, only uuid is set.
+	Deleted bool `protobuf:"varint,12,opt,name=deleted" json:"deleted,omitempty"`
+}
+
+func (m *GerritComment) Reset()                    { *m = GerritComment{} }
+func (m *GerritComment) String() string            { return proto.CompactTextString(m) }
+func (*GerritComment) ProtoMessage()               {}
+func (*GerritComment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *GerritComment) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *GerritComment) GetParentUuid() string {
+	if m != nil {
+		return m.ParentUuid
+	}
+	return ""
+}
+
+func (m *GerritComment) GetPatchSet() int32 {
+	if m != nil {
+		return m.PatchSet
+	}
+	return 0
+}
+
+func (m *GerritComment) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *GerritComment) GetLine() int32 {
+	if m != nil {
+		return m.Line
+	}
+	return 0
+}
+
+func (m *GerritComment) GetRange() *GerritCommentRange {
+	if m != nil {
+		return m.Range
+	}
+	return nil
+}
+
+func (m *GerritComment) GetSide() int32 {
+	if m != nil {
+		return m.Side
+	}
+	return 0
+}
+
+func (m *GerritComment) GetAuthorId() int64 {
+	if m != nil {
+		return m.AuthorId
+	}
+	return 0
+}
+
+func (m *GerritComment) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *GerritComment) GetDate() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Date
+	}
+	return nil
+}
+
+func (m *GerritComment) GetUnresolved() bool {
+	if m != nil {
+		return m.Unresolved
+	}
+	return false
+}
+
+func (m *GerritComment) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+// GerritCommentRange is a range of characters in a file.
+type GerritCommentRange struct {
+	StartLine int32 `protobuf:"varint,1,opt,name=start_line,json=startLine" json:"start_line,omitempty"`
+	StartChar int32 `protobuf:"varint,2,opt,name=start_char,json=startChar" json:"start_char,omitempty"`
+	EndLine   int32 `protobuf:"varint,3,opt,name=end_line,json=endLine" json:"end_line,omitempty"`
+	EndChar   int32 `protobuf:"varint,4,opt,name=end_char,json=endChar" json:"end_char,omitempty"`
+}
+
+func (m *GerritCommentRange) Reset()                    { *m = GerritCommentRange{} }
+func (m *GerritCommentRange) String() string            { return proto.CompactTextString(m) }
+func (*GerritCommentRange) ProtoMessage()               {}
+func (*GerritCommentRange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *GerritCommentRange) GetStartLine() int32 {
+	if m != nil {
+		return m.StartLine
+	}
+	return 0
+}
+
+func (m *GerritCommentRange) GetStartChar() int32 {
+	if m != nil {
+		return m.StartChar
+	}
+	return 0
+}
+
+func (m *GerritCommentRange) GetEndLine() int32 {
+	if m != nil {
+		return m.EndLine
+	}
+	return 0
+}
+
+func (m *GerritCommentRange) GetEndChar() int32 {
+	if m != nil {
+		return m.EndChar
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Mutation)(nil), "maintpb.Mutation")
 	proto.RegisterType((*GithubMutation)(nil), "maintpb.GithubMutation")
@@ -1152,107 +1854,161 @@ func init() {
 	proto.RegisterType((*GitDiffTreeFile)(nil), "maintpb.GitDiffTreeFile")
 	proto.RegisterType((*GerritMutation)(nil), "maintpb.GerritMutation")
 	proto.RegisterType((*GitRef)(nil), "maintpb.GitRef")
+	proto.RegisterType((*GithubReviewComment)(nil), "maintpb.GithubReviewComment")
+	proto.RegisterType((*GithubCheckRun)(nil), "maintpb.GithubCheckRun")
+	proto.RegisterType((*GithubCommitStatus)(nil), "maintpb.GithubCommitStatus")
+	proto.RegisterType((*GithubDiscussionMutation)(nil), "maintpb.GithubDiscussionMutation")
+	proto.RegisterType((*GithubDiscussionComment)(nil), "maintpb.GithubDiscussionComment")
+	proto.RegisterType((*GerritNotes)(nil), "maintpb.GerritNotes")
+	proto.RegisterType((*GerritComment)(nil), "maintpb.GerritComment")
+	proto.RegisterType((*GerritCommentRange)(nil), "maintpb.GerritCommentRange")
 }
 
 func init() { proto.RegisterFile("maintner.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1540 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0x6d, 0x6f, 0xdb, 0xb6,
-	0x13, 0x87, 0x9f, 0xa5, 0xb3, 0x93, 0xb8, 0x4c, 0xd3, 0xb2, 0xe9, 0x53, 0xfe, 0xea, 0x1f, 0x6b,
-	0xd0, 0x07, 0xa7, 0xed, 0x86, 0xae, 0x40, 0x31, 0x0c, 0x69, 0xda, 0x0e, 0x2e, 0xd6, 0xbe, 0x60,
-	0xd3, 0xd7, 0x82, 0x6c, 0xd1, 0x8e, 0x3a, 0x49, 0xf4, 0x24, 0x3a, 0x5d, 0x80, 0xbd, 0xea, 0xd7,
-	0xd8, 0x8b, 0x0d, 0xfb, 0x4e, 0xfb, 0x3e, 0x03, 0x8f, 0xa4, 0x24, 0x3b, 0x4e, 0x93, 0xee, 0x1d,
-	0xef, 0xee, 0x77, 0xe4, 0x1d, 0xef, 0x89, 0x84, 0xf5, 0x24, 0x88, 0x52, 0x99, 0xf2, 0x6c, 0x30,
-	0xcb, 0x84, 0x14, 0xa4, 0x83, 0xf4, 0x6c, 0xb4, 0xfd, 0x7c, 0x1a, 0xc9, 0xa3, 0xf9, 0x68, 0x30,
-	0x16, 0xc9, 0xde, 0x54, 0xc4, 0x41, 0x3a, 0xdd, 0x43, 0xc4, 0x68, 0x3e, 0xd9, 0x9b, 0xc9, 0x93,
-	0x19, 0xcf, 0xf7, 0x64, 0x94, 0xf0, 0x5c, 0x06, 0xc9, 0xac, 0x5c, 0xe9, 0x5d, 0xbc, 0x7f, 0x6a,
-	0xe0, 0xbc, 0x9d, 0xcb, 0x40, 0x46, 0x22, 0x25, 0x3f, 0x42, 0x4f, 0xef, 0xe5, 0x47, 0x79, 0x3e,
-	0xe7, 0xb4, 0xb6, 0x53, 0xdb, 0xed, 0x3e, 0xb9, 0x31, 0x30, 0x27, 0x0d, 0x7e, 0x42, 0xe1, 0x50,
-	0xc9, 0xac, 0x0e, 0xeb, 0x4e, 0x4b, 0x26, 0xd9, 0x83, 0xb6, 0x26, 0x69, 0x03, 0x55, 0xaf, 0x2e,
-	0xa9, 0x16, 0x5a, 0x06, 0x46, 0xbe, 0x81, 0xc6, 0x34, 0x92, 0xb4, 0x8e, 0xe8, 0xcb, 0x55, 0x74,
-	0x01, 0x55, 0x00, 0xdc, 0x98, 0x67, 0x59, 0x24, 0x69, 0x73, 0x79, 0x63, 0x64, 0x57, 0x36, 0x46,
-	0xda, 0xfb, 0xbb, 0x06, 0xeb, 0x8b, 0x67, 0x92, 0xcb, 0xd0, 0x12, 0x9f, 0x52, 0x9e, 0xa1, 0x5b,
-	0x2e, 0xd3, 0x04, 0x21, 0xd0, 0xcc, 0xf8, 0x4c, 0xa0, 0x09, 0x2e, 0xc3, 0x35, 0x79, 0x00, 0xed,
-	0x38, 0x18, 0xf1, 0x38, 0xa7, 0x8d, 0x9d, 0xc6, 0xb2, 0x61, 0x47, 0xf3, 0xd1, 0xcf, 0x4a, 0xc8,
-	0x0c, 0x86, 0x3c, 0x03, 0x48, 0xa2, 0x98, 0xe7, 0x52, 0xa4, 0x3c, 0xa7, 0x4d, 0xd4, 0xa0, 0xcb,
-	0x8e, 0x5b, 0x00, 0xab, 0x60, 0xbd, 0x3f, 0x00, 0x36, 0x57, 0xdc, 0xe9, 0x57, 0x58, 0x7a, 0x05,
-	0xda, 0xe9, 0x3c, 0x19, 0xf1, 0x0c, 0x2f, 0xbc, 0xc5, 0x0c, 0x45, 0xae, 0x83, 0x9b, 0x0a, 0xe9,
-	0xf3, 0xdf, 0xa2, 0x5c, 0xd2, 0xb5, 0x9d, 0xda, 0xae, 0xc3, 0x9c, 0x54, 0xc8, 0x57, 0x8a, 0x26,
-	0xeb, 0x50, 0x8f, 0x42, 0xda, 0xdb, 0xa9, 0xed, 0x36, 0x58, 0x3d, 0x0a, 0xc9, 0x5d, 0x68, 0xce,
-	0x73, 0x9e, 0x99, 0xab, 0xdd, 0x5c, 0x32, 0xfd, 0x43, 0xce, 0x33, 0x86, 0x00, 0xf2, 0x18, 0xdc,
-	0x20, 0xcf, 0xa3, 0x69, 0xca, 0x79, 0x4e, 0x01, 0x1d, 0x5d, 0x89, 0x2e, 0x51, 0xe4, 0x3e, 0x5c,
-	0x0a, 0x79, 0xcc, 0x25, 0x0f, 0xfd, 0x52, 0xb5, 0xbb, 0xd3, 0xd8, 0x6d, 0xb0, 0xbe, 0x11, 0xec,
-	0x17, 0xe0, 0xef, 0xa0, 0x33, 0xce, 0x78, 0x20, 0x79, 0x48, 0x5b, 0x68, 0xcb, 0xf6, 0x60, 0x2a,
-	0xc4, 0x34, 0xe6, 0x03, 0x9b, 0xd0, 0x83, 0x43, 0x9b, 0xbf, 0xcc, 0x42, 0x95, 0xd6, 0x7c, 0x16,
-	0xa2, 0x56, 0xfb, 0x7c, 0x2d, 0x03, 0x55, 0x77, 0x2c, 0x23, 0x19, 0x73, 0xea, 0xea, 0x3b, 0x46,
-	0x82, 0x3c, 0x85, 0xee, 0x48, 0x84, 0x27, 0xfe, 0xf8, 0x28, 0x48, 0xa7, 0x9c, 0xde, 0xc6, 0xfd,
-	0xb6, 0x0a, 0x1f, 0xdf, 0xcb, 0x2c, 0x4a, 0xa7, 0x07, 0x28, 0x64, 0xa0, 0x90, 0x7a, 0xad, 0x62,
-	0xa3, 0x28, 0xda, 0xd1, 0xb1, 0x51, 0x6b, 0xf2, 0x3f, 0xe8, 0xa5, 0xc2, 0x2f, 0xc2, 0x4d, 0x37,
-	0x30, 0x0c, 0xdd, 0x54, 0x14, 0xc9, 0xa0, 0x20, 0x85, 0xdc, 0x8f, 0x42, 0xda, 0xc7, 0x98, 0x74,
-	0x0b, 0xde, 0x30, 0x24, 0x77, 0x60, 0xad, 0x84, 0xa4, 0xf3, 0x84, 0x5e, 0x42, 0x4c, 0xa9, 0xf7,
-	0x6e, 0x9e, 0x90, 0xbb, 0xb0, 0x51, 0x82, 0xb4, 0x5b, 0x04, 0x2d, 0x59, 0x2f, 0xd8, 0x87, 0xe8,
-	0xdf, 0x7d, 0x68, 0x8f, 0x63, 0x91, 0xf3, 0x90, 0x6e, 0x2e, 0x05, 0xfb, 0x85, 0x10, 0xb1, 0x71,
-	0xcc, 0x40, 0x14, 0x38, 0x16, 0xe3, 0x5f, 0x78, 0x48, 0xaf, 0x7d, 0x01, 0xac, 0x21, 0xca, 0x95,
-	0xd9, 0x3c, 0x8e, 0xfd, 0x8c, 0xff, 0x3a, 0xe7, 0xb9, 0xa4, 0x37, 0xb4, 0xb7, 0x8a, 0xc7, 0x34,
-	0x8b, 0x7c, 0x0f, 0xae, 0xde, 0xd9, 0x0f, 0x24, 0xdd, 0x3a, 0x37, 0x54, 0x8e, 0x06, 0xef, 0x4b,
-	0xf2, 0xa8, 0x50, 0x1c, 0x9d, 0xd0, 0x2b, 0x67, 0x67, 0xa9, 0xd1, 0x78, 0x81, 0x77, 0x9f, 0xf1,
-	0x44, 0x1c, 0x73, 0x1f, 0x8b, 0x94, 0x5e, 0xc5, 0x8c, 0xeb, 0x6a, 0x1e, 0x96, 0x2f, 0x26, 0x73,
-	0x18, 0x1a, 0x39, 0xfd, 0x42, 0x9d, 0x3b, 0x41, 0x18, 0x6a, 0x95, 0x1f, 0xa0, 0x33, 0x16, 0x49,
-	0xc2, 0x53, 0x49, 0x1d, 0x54, 0xb8, 0xb3, 0xaa, 0x35, 0x1e, 0x68, 0x48, 0xd1, 0x92, 0xac, 0x0e,
-	0x79, 0x05, 0xeb, 0x66, 0xe9, 0xe7, 0x32, 0x90, 0xf3, 0x9c, 0xae, 0xa3, 0x2f, 0xb7, 0x56, 0xed,
-	0xf2, 0xfe, 0x24, 0x1d, 0xbf, 0x47, 0x14, 0x5b, 0x33, 0x5a, 0x9a, 0x24, 0x7b, 0xd0, 0xe2, 0xc7,
-	0xca, 0x86, 0x6d, 0xb4, 0xe1, 0xda, 0x2a, 0xed, 0x57, 0x0a, 0xc0, 0x34, 0x8e, 0xec, 0x43, 0x0f,
-	0x17, 0xf6, 0xd4, 0xeb, 0x17, 0x3a, 0xb5, 0x8b, 0x3a, 0xe6, 0xcc, 0x87, 0xd0, 0xce, 0xf8, 0x71,
-	0xc4, 0x3f, 0xd1, 0x9b, 0x78, 0xe8, 0xd6, 0x92, 0x32, 0x43, 0x21, 0x33, 0x20, 0x72, 0x00, 0x6b,
-	0x7a, 0x65, 0x8f, 0xbc, 0x75, 0xa1, 0x23, 0x7b, 0x5a, 0x49, 0x53, 0xde, 0x2d, 0x80, 0x32, 0xcf,
-	0x48, 0x1f, 0x1a, 0xc7, 0x41, 0x8c, 0x1d, 0xd1, 0x61, 0x6a, 0xe9, 0xed, 0x40, 0xaf, 0x5a, 0x8f,
-	0x55, 0x84, 0xab, 0x11, 0x8f, 0xa1, 0x5b, 0x09, 0xa4, 0xe9, 0x7b, 0xb5, 0xa2, 0xef, 0x11, 0x68,
-	0xa6, 0x41, 0xc2, 0x6d, 0x43, 0x55, 0x6b, 0xef, 0x77, 0xd8, 0x58, 0xea, 0xd8, 0xa7, 0xd4, 0x8a,
-	0xce, 0x51, 0xaf, 0x76, 0x8e, 0xb2, 0xb2, 0x1a, 0xe7, 0x57, 0x56, 0xd9, 0xb6, 0x9b, 0xb8, 0xad,
-	0xa1, 0xbc, 0x3f, 0x5b, 0xd0, 0x5f, 0x8e, 0xe2, 0xa9, 0xf3, 0x6f, 0x02, 0xe8, 0x70, 0xaa, 0xd9,
-	0x6e, 0x8c, 0x70, 0x91, 0x73, 0x78, 0x32, 0xe3, 0xe4, 0x1a, 0x38, 0xc1, 0x58, 0x8a, 0x4c, 0xf5,
-	0x93, 0x06, 0x2a, 0x75, 0x90, 0x1e, 0x86, 0xd5, 0xfe, 0xda, 0xbc, 0x78, 0x7f, 0xbd, 0x07, 0x2d,
-	0x5d, 0x24, 0xad, 0xd3, 0x53, 0xba, 0x28, 0x12, 0x0d, 0x21, 0x4f, 0xc1, 0x2d, 0x1b, 0x9e, 0xee,
-	0xc6, 0x67, 0x8f, 0xc2, 0x12, 0x4a, 0x6e, 0x43, 0xd7, 0x8e, 0x07, 0x65, 0x77, 0x07, 0xed, 0x06,
-	0xcb, 0x1a, 0x86, 0x15, 0x00, 0x3a, 0xe6, 0x2c, 0x00, 0x94, 0x6f, 0x0f, 0xa1, 0xad, 0xca, 0x24,
-	0x92, 0xd8, 0xd0, 0x4f, 0x67, 0xe8, 0x01, 0x0a, 0x99, 0x01, 0xa9, 0xfd, 0x32, 0xae, 0x22, 0xee,
-	0x4f, 0x32, 0x91, 0xd0, 0x2e, 0xde, 0x22, 0x68, 0xd6, 0xeb, 0x4c, 0x24, 0x6a, 0x82, 0x1a, 0x80,
-	0x14, 0x38, 0x2b, 0x5d, 0xe6, 0x68, 0xc6, 0xa1, 0xd0, 0xda, 0x2a, 0x55, 0xb5, 0x35, 0x6b, 0xda,
-	0x1a, 0xcb, 0x1a, 0x86, 0x64, 0x00, 0x9b, 0xa6, 0x00, 0x4c, 0x3f, 0xd4, 0xc0, 0x75, 0x04, 0x5e,
-	0xd2, 0x22, 0x66, 0x25, 0xc3, 0x90, 0x3c, 0x83, 0x35, 0xc9, 0x83, 0xc4, 0xb7, 0x5b, 0xe0, 0x24,
-	0x38, 0xdd, 0xe5, 0x0e, 0x79, 0x90, 0xb0, 0x9e, 0x42, 0x32, 0x03, 0x24, 0xef, 0xa0, 0x1f, 0x46,
-	0x79, 0x12, 0xe5, 0xaa, 0x3d, 0x9a, 0x1a, 0xdd, 0x40, 0xe5, 0xe5, 0xe6, 0xf4, 0xd2, 0xc2, 0xb4,
-	0xae, 0x6e, 0x11, 0x1b, 0xe1, 0x22, 0x57, 0x65, 0x97, 0x90, 0x47, 0x3c, 0xf3, 0x3f, 0xe6, 0x22,
-	0xa5, 0xb0, 0x53, 0xdb, 0xed, 0x31, 0x17, 0x39, 0x6f, 0x72, 0x91, 0x7a, 0x9f, 0x6b, 0xb0, 0x7d,
-	0xf6, 0x76, 0xfa, 0xd6, 0xd0, 0xef, 0x22, 0x65, 0x1d, 0xcd, 0x18, 0x86, 0xf8, 0x16, 0xd0, 0x4a,
-	0x41, 0xec, 0x27, 0x3c, 0xcf, 0x83, 0x29, 0xc7, 0x14, 0x75, 0x59, 0xbf, 0x10, 0xbc, 0xd5, 0x7c,
-	0x55, 0x65, 0xaa, 0x77, 0x70, 0xcc, 0x54, 0x97, 0x69, 0xe2, 0x4d, 0xd3, 0xa9, 0xf7, 0x1b, 0xde,
-	0x07, 0xe8, 0x55, 0x83, 0xfa, 0x15, 0xef, 0xa5, 0xeb, 0xe0, 0xea, 0x04, 0xb0, 0xd5, 0xe1, 0x32,
-	0x47, 0x33, 0x86, 0xa1, 0xf7, 0xb9, 0x6e, 0xf7, 0x35, 0x77, 0xb1, 0x5c, 0x79, 0xd5, 0xd2, 0xaa,
-	0x9f, 0x59, 0x5a, 0x8d, 0x8b, 0x97, 0x96, 0x7d, 0x36, 0x34, 0x2b, 0xcf, 0x86, 0xc2, 0xf1, 0x56,
-	0xc5, 0xf1, 0x45, 0xc3, 0xdb, 0x8b, 0x86, 0xab, 0x8b, 0xd5, 0x76, 0x05, 0x79, 0x2e, 0xc6, 0x11,
-	0x8e, 0x1d, 0xf3, 0x14, 0xe9, 0xa3, 0x60, 0xbf, 0xe4, 0x2f, 0x05, 0xd8, 0x59, 0x0e, 0xf0, 0x21,
-	0x6c, 0xad, 0x6c, 0xce, 0xe4, 0x39, 0x74, 0x73, 0x9e, 0x1d, 0xf3, 0xcc, 0x57, 0x0f, 0x28, 0xf3,
-	0x37, 0xf8, 0x92, 0x97, 0xa0, 0xe1, 0x2f, 0x03, 0xc9, 0xd5, 0x37, 0x63, 0xfb, 0xec, 0x11, 0x79,
-	0xea, 0xa2, 0xed, 0x8b, 0xb4, 0x7e, 0xde, 0x8b, 0xd4, 0x5e, 0x60, 0xa3, 0x72, 0x81, 0xff, 0xad,
-	0xcb, 0x55, 0x5e, 0x91, 0xad, 0x0b, 0xbf, 0x22, 0xbd, 0x27, 0x00, 0xa5, 0x4d, 0xab, 0x26, 0x45,
-	0x2c, 0xa6, 0x51, 0x6a, 0x27, 0x05, 0x12, 0xde, 0x23, 0xab, 0xa3, 0xaa, 0x79, 0xd5, 0x50, 0xca,
-	0xe3, 0xf9, 0xd4, 0x66, 0xad, 0x5a, 0x7b, 0x3e, 0xce, 0xb1, 0xe2, 0xb6, 0xfe, 0x6f, 0x12, 0x5b,
-	0x87, 0xa0, 0x5f, 0xbd, 0x1d, 0xc6, 0x67, 0xc2, 0xa4, 0xfa, 0xbd, 0xa2, 0x21, 0xea, 0x5b, 0x24,
-	0x55, 0xdc, 0x62, 0x37, 0xf4, 0x3c, 0xe8, 0x18, 0x65, 0x72, 0x15, 0x3a, 0x53, 0xe1, 0x17, 0xfb,
-	0xbb, 0xac, 0x3d, 0x15, 0x4a, 0xe0, 0x85, 0xe0, 0x16, 0x8a, 0x68, 0xe5, 0x51, 0xf0, 0xd8, 0x40,
-	0x70, 0xad, 0xe6, 0x6f, 0x16, 0x7c, 0xc2, 0xd3, 0x7a, 0x4c, 0x2d, 0xd5, 0x13, 0x2b, 0x8c, 0x26,
-	0x13, 0x5f, 0x66, 0x9c, 0x9b, 0xb2, 0x58, 0x98, 0x1e, 0x2f, 0xa3, 0xc9, 0xe4, 0x30, 0xe3, 0x9c,
-	0x39, 0xa1, 0x59, 0x79, 0xcf, 0xd1, 0x55, 0x2b, 0x20, 0x0f, 0xa0, 0x39, 0x89, 0x62, 0x95, 0x6d,
-	0xa7, 0x7e, 0x55, 0x16, 0xf3, 0x3a, 0x8a, 0x39, 0x43, 0x94, 0x97, 0xe0, 0xf0, 0xae, 0x0a, 0x94,
-	0xa1, 0x66, 0x03, 0x34, 0x54, 0xad, 0x55, 0x58, 0x82, 0x30, 0xe4, 0xb6, 0x86, 0x35, 0x41, 0x28,
-	0x74, 0xcc, 0x87, 0xc4, 0x8e, 0x4d, 0x43, 0xaa, 0x69, 0x3d, 0x8a, 0xd2, 0x20, 0xd3, 0x75, 0xea,
-	0x30, 0x43, 0x79, 0x7f, 0xa9, 0x3f, 0xe6, 0xc2, 0xf7, 0x53, 0x6d, 0x32, 0xcb, 0xc4, 0x47, 0x3e,
-	0x96, 0xe6, 0x44, 0x4b, 0x92, 0x07, 0xfa, 0xed, 0x18, 0xc9, 0x9c, 0xd6, 0xd1, 0x99, 0x55, 0xf1,
-	0xb0, 0x10, 0x72, 0x47, 0x85, 0x78, 0x62, 0xff, 0x9f, 0x1b, 0x8b, 0x21, 0x9e, 0x30, 0x14, 0xaa,
-	0x47, 0xae, 0xfd, 0x5b, 0x21, 0x58, 0x7d, 0x3d, 0x5d, 0xd6, 0x35, 0x3c, 0xc6, 0x27, 0xb9, 0x37,
-	0x80, 0xb6, 0x56, 0xc1, 0xe8, 0xf0, 0x89, 0x7d, 0x1d, 0x65, 0x7c, 0x52, 0xc4, 0xb0, 0x5e, 0xc6,
-	0x70, 0xd4, 0xc6, 0x64, 0xff, 0xf6, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xdd, 0x4d, 0xc5, 0xae,
-	0x6d, 0x10, 0x00, 0x00,
+	// 2287 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x8e, 0x1c, 0xb7,
+	0xf1, 0xc7, 0x7c, 0x77, 0xd7, 0xcc, 0xce, 0x8e, 0x28, 0xcb, 0x6a, 0x6b, 0x6d, 0x79, 0xd4, 0xd2,
+	0xdf, 0xd6, 0x5f, 0x96, 0x77, 0x2d, 0xc5, 0x70, 0x84, 0x08, 0x41, 0x22, 0xaf, 0xe4, 0x60, 0x0d,
+	0x5b, 0x40, 0xa8, 0xd5, 0xb9, 0xd1, 0xdb, 0xcd, 0x99, 0x69, 0xbb, 0xa7, 0x39, 0x61, 0xb3, 0x57,
+	0x5e, 0x20, 0x27, 0x9f, 0xf2, 0x0c, 0x39, 0x24, 0x40, 0x80, 0x5c, 0xf3, 0x14, 0x39, 0xe6, 0x9a,
+	0x37, 0xc8, 0x35, 0x87, 0xbc, 0x41, 0xc0, 0x22, 0xd9, 0xdd, 0xd3, 0x33, 0xab, 0x5d, 0x29, 0x41,
+	0x6e, 0x64, 0xd5, 0xaf, 0xc8, 0x62, 0xb1, 0xbe, 0x48, 0x18, 0x2f, 0xc3, 0x24, 0x93, 0x19, 0x13,
+	0xfb, 0x2b, 0xc1, 0x25, 0x27, 0x03, 0x9c, 0xaf, 0x4e, 0x6e, 0x3c, 0x9e, 0x27, 0x72, 0x51, 0x9c,
+	0xec, 0x47, 0x7c, 0x79, 0x30, 0xe7, 0x69, 0x98, 0xcd, 0x0f, 0x10, 0x71, 0x52, 0xcc, 0x0e, 0x56,
+	0xf2, 0x6c, 0xc5, 0xf2, 0x03, 0x99, 0x2c, 0x59, 0x2e, 0xc3, 0xe5, 0xaa, 0x1a, 0xe9, 0x55, 0xfc,
+	0x3f, 0xb7, 0xc1, 0xf9, 0xb6, 0x90, 0xa1, 0x4c, 0x78, 0x46, 0x7e, 0x01, 0x23, 0xbd, 0x56, 0x90,
+	0xe4, 0x79, 0xc1, 0xbc, 0xd6, 0xb4, 0x75, 0x77, 0xf8, 0xf0, 0xfd, 0x7d, 0xb3, 0xd3, 0xfe, 0xaf,
+	0x90, 0x79, 0xa4, 0x78, 0x56, 0x86, 0x0e, 0xe7, 0x15, 0x91, 0x1c, 0x40, 0x5f, 0x4f, 0xbd, 0x0e,
+	0x8a, 0x5e, 0x6f, 0x88, 0x96, 0x52, 0x06, 0x46, 0x9e, 0xc3, 0x15, 0xb3, 0x63, 0x9c, 0xe4, 0x51,
+	0x91, 0xe7, 0x09, 0xcf, 0xbc, 0x1e, 0xca, 0xde, 0x6a, 0xc8, 0x3e, 0x2d, 0x01, 0xe5, 0x2a, 0x93,
+	0x79, 0x83, 0x43, 0x3e, 0x82, 0xce, 0x3c, 0x91, 0x5e, 0x1b, 0x57, 0x78, 0xa7, 0xbe, 0x42, 0x29,
+	0xa4, 0x00, 0xa8, 0x28, 0x13, 0x22, 0x91, 0x5e, 0xb7, 0xa9, 0x28, 0x92, 0x6b, 0x8a, 0xe2, 0xdc,
+	0xff, 0x53, 0x0b, 0xc6, 0xeb, 0x67, 0x20, 0xef, 0x40, 0x8f, 0xbf, 0xca, 0x98, 0x40, 0x33, 0xb9,
+	0x54, 0x4f, 0x08, 0x81, 0xae, 0x60, 0x2b, 0x8e, 0x2a, 0xb8, 0x14, 0xc7, 0xe4, 0x3e, 0xf4, 0xd3,
+	0xf0, 0x84, 0xa5, 0xb9, 0xd7, 0x99, 0x76, 0x9a, 0x8a, 0x2d, 0x8a, 0x93, 0x6f, 0x14, 0x93, 0x1a,
+	0x0c, 0x79, 0x04, 0xb0, 0x4c, 0x52, 0x96, 0x4b, 0x9e, 0xb1, 0xdc, 0xeb, 0xa2, 0x84, 0xd7, 0x34,
+	0xa4, 0x05, 0xd0, 0x1a, 0xd6, 0xff, 0xeb, 0x0e, 0x5c, 0xdd, 0x72, 0x47, 0x6f, 0xa0, 0xe9, 0xbb,
+	0xd0, 0xcf, 0x8a, 0xe5, 0x09, 0x13, 0x78, 0x81, 0x3d, 0x6a, 0x66, 0x64, 0x0f, 0xdc, 0x8c, 0xcb,
+	0x80, 0xfd, 0x90, 0xe4, 0xd2, 0xdb, 0x99, 0xb6, 0xee, 0x3a, 0xd4, 0xc9, 0xb8, 0x7c, 0xa6, 0xe6,
+	0x64, 0x0c, 0xed, 0x24, 0xf6, 0x46, 0xd3, 0xd6, 0xdd, 0x0e, 0x6d, 0x27, 0x31, 0xf9, 0x18, 0xba,
+	0x45, 0xce, 0x84, 0x31, 0xed, 0xd5, 0x86, 0xea, 0x2f, 0x73, 0x26, 0x28, 0x02, 0xc8, 0x03, 0x70,
+	0xc3, 0x3c, 0x4f, 0xe6, 0x19, 0x63, 0xb9, 0x07, 0xd3, 0xce, 0x79, 0xe8, 0x0a, 0x45, 0x3e, 0x81,
+	0x2b, 0x31, 0x4b, 0x99, 0x64, 0x71, 0x50, 0x89, 0x0e, 0xa7, 0x9d, 0xbb, 0x1d, 0x3a, 0x31, 0x8c,
+	0x27, 0x25, 0xf8, 0x73, 0x18, 0x44, 0x82, 0x85, 0x92, 0xc5, 0xc6, 0xa7, 0x6e, 0xec, 0xcf, 0x39,
+	0x9f, 0xa7, 0x6c, 0xdf, 0x06, 0xc8, 0xfe, 0xb1, 0x8d, 0x07, 0x6a, 0xa1, 0x4a, 0xaa, 0x58, 0xc5,
+	0x28, 0xd5, 0xbf, 0x58, 0xca, 0x40, 0x95, 0x8d, 0x65, 0x22, 0x53, 0xe6, 0xb9, 0xda, 0xc6, 0x38,
+	0x21, 0x5f, 0xc0, 0xf0, 0x84, 0xc7, 0x67, 0x41, 0xb4, 0x08, 0xb3, 0x39, 0xf3, 0x3e, 0xc4, 0xf5,
+	0xae, 0x95, 0x67, 0x7c, 0x21, 0x45, 0x92, 0xcd, 0x0f, 0x91, 0x49, 0x41, 0x21, 0xf5, 0x58, 0xdd,
+	0x8d, 0x9a, 0x79, 0x03, 0x7d, 0x37, 0x6a, 0x4c, 0x6e, 0xc1, 0x28, 0xe3, 0x41, 0x79, 0xdd, 0xde,
+	0x2e, 0x5e, 0xc3, 0x30, 0xe3, 0xa5, 0x33, 0x28, 0x48, 0xc9, 0x0f, 0x92, 0xd8, 0x9b, 0xe0, 0x9d,
+	0x0c, 0x4b, 0xda, 0x51, 0x4c, 0x6e, 0xc3, 0x4e, 0x05, 0xc9, 0x8a, 0xa5, 0x77, 0x05, 0x31, 0x95,
+	0xdc, 0xf3, 0x62, 0x49, 0x3e, 0x86, 0xdd, 0x0a, 0xa4, 0x8f, 0x45, 0x50, 0x93, 0x71, 0x49, 0x3e,
+	0xc6, 0xf3, 0x7d, 0x02, 0xfd, 0x28, 0xe5, 0x39, 0x8b, 0xbd, 0xab, 0x8d, 0xcb, 0xfe, 0x92, 0xf3,
+	0xd4, 0x1c, 0xcc, 0x40, 0x14, 0x38, 0xe5, 0xd1, 0xf7, 0x2c, 0xf6, 0xde, 0x7b, 0x0d, 0x58, 0x43,
+	0xd4, 0x51, 0x56, 0x45, 0x9a, 0x06, 0x82, 0xfd, 0xa6, 0x60, 0xb9, 0xf4, 0xde, 0xd7, 0xa7, 0x55,
+	0x34, 0xaa, 0x49, 0xe4, 0xa7, 0xe0, 0xea, 0x95, 0x83, 0x50, 0x7a, 0xd7, 0x2e, 0xbc, 0x2a, 0x47,
+	0x83, 0x9f, 0x48, 0xf2, 0x59, 0x29, 0x78, 0x72, 0xe6, 0xbd, 0x7b, 0xbe, 0x97, 0x1a, 0x89, 0x2f,
+	0xd1, 0xf6, 0x82, 0x2d, 0xf9, 0x29, 0x0b, 0x30, 0x48, 0xbd, 0xeb, 0xe8, 0x71, 0x43, 0x4d, 0xc3,
+	0xf0, 0x45, 0x67, 0x8e, 0x63, 0xc3, 0xf7, 0x5e, 0x13, 0xe7, 0x4e, 0x18, 0xc7, 0x5a, 0xe4, 0xe7,
+	0x30, 0x88, 0xf8, 0x72, 0xc9, 0x32, 0xe9, 0x39, 0x28, 0x70, 0x7b, 0x5b, 0xaa, 0x3d, 0xd4, 0x90,
+	0x32, 0x25, 0x59, 0x19, 0xf2, 0x0c, 0xc6, 0x66, 0x18, 0xe4, 0x32, 0x94, 0x45, 0xee, 0x8d, 0xf1,
+	0x2c, 0x37, 0xb7, 0xad, 0xf2, 0xe2, 0x2c, 0x8b, 0x5e, 0x20, 0x8a, 0xee, 0x18, 0x29, 0x3d, 0x25,
+	0x07, 0xd0, 0x63, 0xa7, 0x4a, 0x87, 0x1b, 0xa8, 0xc3, 0x7b, 0xdb, 0xa4, 0x9f, 0x29, 0x00, 0xd5,
+	0x38, 0xf2, 0x04, 0x46, 0xec, 0xb4, 0xb6, 0xeb, 0xde, 0xa5, 0x76, 0x1d, 0xa2, 0x8c, 0xd9, 0xf3,
+	0x53, 0xe8, 0x0b, 0x76, 0x9a, 0xb0, 0x57, 0xde, 0x07, 0xd3, 0xce, 0x5a, 0x48, 0x68, 0x61, 0x8a,
+	0x4c, 0x6a, 0x40, 0xe4, 0x10, 0x76, 0xf4, 0xc8, 0x6e, 0x79, 0xf3, 0x52, 0x5b, 0x8e, 0xb4, 0x90,
+	0xd9, 0xf3, 0x10, 0xc6, 0x66, 0x11, 0x6b, 0xf4, 0xe9, 0xb4, 0xb3, 0xa5, 0xbe, 0xe9, 0xbd, 0x8d,
+	0xd5, 0xe9, 0x8e, 0xa8, 0x4f, 0x09, 0x85, 0x6b, 0xeb, 0x8b, 0x58, 0x8d, 0x6e, 0x5d, 0x4a, 0xa3,
+	0xab, 0x6b, 0xab, 0x19, 0xc5, 0xee, 0xc0, 0x78, 0xc1, 0xc2, 0x18, 0x57, 0x4c, 0xa4, 0x8a, 0xdb,
+	0xff, 0xc3, 0x60, 0x1b, 0x29, 0xea, 0x21, 0x12, 0x8f, 0x62, 0xf2, 0x29, 0x90, 0xb0, 0x90, 0x0b,
+	0x2e, 0x54, 0xe2, 0xe3, 0x51, 0x82, 0xce, 0xe0, 0x7d, 0x84, 0xc8, 0x2b, 0x9a, 0xf3, 0xa4, 0x62,
+	0x90, 0xcf, 0xc1, 0x8d, 0x16, 0x2c, 0xfa, 0x3e, 0x10, 0x45, 0xe6, 0xf9, 0xd3, 0xce, 0x7a, 0x91,
+	0x43, 0xe5, 0x0e, 0x15, 0x9f, 0x16, 0x19, 0x75, 0x22, 0x33, 0x22, 0xbf, 0x84, 0x1d, 0xa3, 0x85,
+	0x39, 0xd6, 0x6d, 0x94, 0xdc, 0x6b, 0x4a, 0x22, 0xc6, 0x5a, 0x39, 0xaa, 0xcd, 0x94, 0x73, 0xe8,
+	0x7d, 0xcd, 0x02, 0x77, 0x2e, 0xe7, 0x1c, 0x28, 0xa3, 0x27, 0xfe, 0x4d, 0x80, 0x2a, 0x21, 0x90,
+	0x09, 0x74, 0x4e, 0xc3, 0x14, 0x4b, 0x97, 0x43, 0xd5, 0xd0, 0x9f, 0xc2, 0xa8, 0x9e, 0x38, 0xeb,
+	0x08, 0x57, 0x23, 0x1e, 0xc0, 0xb0, 0x16, 0x71, 0xa6, 0x40, 0xb5, 0xca, 0x02, 0x45, 0xa0, 0x9b,
+	0x85, 0x4b, 0x66, 0x2b, 0x9f, 0x1a, 0xfb, 0xbf, 0x85, 0xdd, 0x46, 0x69, 0xdd, 0x10, 0x2b, 0x53,
+	0x7c, 0xbb, 0x9e, 0xe2, 0xab, 0x14, 0xd8, 0xb9, 0x38, 0x05, 0x56, 0xf5, 0xb5, 0x8b, 0xcb, 0x9a,
+	0x99, 0xff, 0xc7, 0x1e, 0x4c, 0x9a, 0xe1, 0xb6, 0xb1, 0xff, 0x07, 0x00, 0x3a, 0xee, 0x54, 0x53,
+	0x67, 0x94, 0x70, 0x91, 0x72, 0x7c, 0xb6, 0x62, 0xe4, 0x3d, 0x70, 0xc2, 0x48, 0x72, 0x11, 0x24,
+	0x5a, 0x95, 0x0e, 0x1d, 0xe0, 0xfc, 0x28, 0xae, 0x17, 0xc2, 0xee, 0xe5, 0x0b, 0xe1, 0x3d, 0xe8,
+	0xe9, 0x6c, 0xd6, 0xdb, 0x6c, 0xa7, 0xca, 0x6c, 0xa6, 0x21, 0xe4, 0x0b, 0x70, 0xab, 0xca, 0xa4,
+	0xcb, 0xe6, 0xf9, 0x3d, 0x4b, 0x05, 0x25, 0x1f, 0xc2, 0xd0, 0xd6, 0x71, 0xa5, 0xf7, 0x00, 0xf5,
+	0x06, 0x4b, 0x3a, 0x8a, 0x6b, 0x00, 0x3c, 0x98, 0xb3, 0x06, 0x10, 0x18, 0x17, 0x7d, 0xed, 0x80,
+	0x58, 0x79, 0x37, 0x53, 0x89, 0xf6, 0x55, 0x6a, 0x40, 0x6a, 0x3d, 0xc1, 0xd4, 0x8d, 0x07, 0x33,
+	0xc1, 0x97, 0xde, 0x10, 0xad, 0x08, 0x9a, 0xf4, 0x95, 0xe0, 0x4b, 0xd5, 0xea, 0x18, 0x80, 0xe4,
+	0xd8, 0xd4, 0xb8, 0xd4, 0xd1, 0x84, 0x63, 0xae, 0xa5, 0x55, 0x04, 0x6b, 0x6d, 0x76, 0xb4, 0x36,
+	0x96, 0x74, 0x14, 0x93, 0x7d, 0x30, 0x21, 0x6e, 0x0b, 0x97, 0x06, 0x8e, 0x11, 0x78, 0x45, 0xb3,
+	0xa8, 0xe5, 0x1c, 0xc5, 0xe4, 0x11, 0xec, 0x48, 0x16, 0x2e, 0x03, 0xbb, 0x84, 0x37, 0x69, 0x38,
+	0x91, 0x3e, 0xc4, 0x31, 0x0b, 0x97, 0x74, 0xa4, 0x90, 0xd4, 0x00, 0xc9, 0x73, 0x98, 0xc4, 0x49,
+	0xbe, 0x4c, 0x72, 0x55, 0xc7, 0xb4, 0x38, 0xb6, 0x04, 0x9b, 0x55, 0xe4, 0xa9, 0x85, 0x69, 0x59,
+	0x9d, 0xcb, 0x77, 0xe3, 0x75, 0xaa, 0xf2, 0x2e, 0x2e, 0x17, 0x4c, 0x04, 0xdf, 0xe5, 0x3c, 0xf3,
+	0x60, 0xda, 0xba, 0x3b, 0xa2, 0x2e, 0x52, 0xbe, 0xce, 0x79, 0xe6, 0xff, 0xd8, 0x82, 0x1b, 0xe7,
+	0x2f, 0xa7, 0xad, 0x86, 0xe7, 0x2e, 0x5d, 0xd6, 0xd1, 0x84, 0xa3, 0x18, 0x9b, 0x36, 0x2d, 0x14,
+	0xa6, 0xc1, 0x92, 0xe5, 0x79, 0x38, 0x67, 0xe8, 0xa2, 0x2e, 0x9d, 0x94, 0x8c, 0x6f, 0x35, 0x5d,
+	0x45, 0x99, 0x4a, 0x1d, 0x0c, 0x3d, 0xd5, 0xa5, 0x7a, 0xf2, 0x75, 0xd7, 0x69, 0x4f, 0x3a, 0xfe,
+	0x4b, 0x18, 0xd5, 0x2f, 0xf5, 0x0d, 0x1a, 0xdb, 0x3d, 0x70, 0xab, 0xf4, 0xaa, 0xb7, 0x76, 0x22,
+	0x93, 0x5a, 0xfd, 0x1f, 0xdb, 0x76, 0x5d, 0x63, 0x8b, 0x66, 0xe4, 0xd5, 0x43, 0xab, 0x7d, 0x6e,
+	0x68, 0x75, 0x2e, 0x1f, 0x5a, 0xb6, 0xbf, 0xeb, 0xd6, 0xfa, 0xbb, 0xf2, 0xe0, 0xbd, 0xda, 0xc1,
+	0xd7, 0x15, 0xef, 0xaf, 0x2b, 0xae, 0x0c, 0xab, 0xf5, 0xaa, 0x97, 0x04, 0xdd, 0x33, 0x4e, 0x90,
+	0x51, 0xaf, 0x08, 0xeb, 0x17, 0xec, 0x34, 0x2f, 0xf8, 0x18, 0xae, 0x6d, 0xcd, 0xcd, 0xe4, 0x31,
+	0x0c, 0x73, 0x26, 0x4e, 0x99, 0x08, 0x54, 0xa7, 0xeb, 0xb5, 0x2e, 0x3c, 0x25, 0x68, 0xf8, 0xd3,
+	0x50, 0x32, 0xff, 0xef, 0xa5, 0xdb, 0x6c, 0xeb, 0x65, 0x36, 0x0c, 0x6d, 0x9f, 0x0e, 0xed, 0x8b,
+	0x9e, 0x0e, 0xd6, 0x80, 0x9d, 0x9a, 0x01, 0xdf, 0x2e, 0xcb, 0xd5, 0xda, 0xfd, 0xde, 0xa5, 0xdb,
+	0x7d, 0xff, 0x21, 0x40, 0xa5, 0xd3, 0xb6, 0x4a, 0x91, 0xf2, 0x79, 0x92, 0xd9, 0x4a, 0x81, 0x13,
+	0xff, 0x33, 0x80, 0x2a, 0x9a, 0xb7, 0x15, 0xa5, 0x3c, 0x2d, 0xe6, 0xd6, 0x6b, 0xd5, 0xd8, 0x0f,
+	0xb0, 0x8e, 0x95, 0xd6, 0xba, 0x63, 0x1c, 0x5b, 0x5f, 0xc1, 0xa4, 0x6e, 0x1d, 0xca, 0x56, 0xdc,
+	0xb8, 0xfa, 0xbd, 0x32, 0x21, 0x6a, 0x2b, 0x92, 0x3a, 0x6e, 0x3d, 0x1b, 0xfa, 0x3e, 0x0c, 0x8c,
+	0x30, 0xb9, 0x0e, 0x83, 0x39, 0x0f, 0xca, 0xf5, 0x5d, 0xda, 0x9f, 0x73, 0xc5, 0xf0, 0x63, 0x70,
+	0x4b, 0x41, 0xd4, 0x72, 0x11, 0x3e, 0x30, 0x10, 0x1c, 0xab, 0xfa, 0x2b, 0xc2, 0x57, 0xb8, 0xdb,
+	0x88, 0xaa, 0xa1, 0xea, 0x85, 0xe3, 0x64, 0x36, 0x0b, 0xa4, 0x60, 0xcc, 0xeb, 0x6c, 0x56, 0x8f,
+	0xa7, 0xc9, 0x6c, 0x76, 0x2c, 0x18, 0xa3, 0x4e, 0x6c, 0x46, 0xfe, 0x63, 0x18, 0xd6, 0x18, 0xe4,
+	0x3e, 0x74, 0x67, 0x49, 0xaa, 0xbc, 0x6d, 0xe3, 0xf9, 0x6b, 0x31, 0x5f, 0x25, 0x29, 0xa3, 0x88,
+	0xf2, 0x97, 0xb0, 0xdb, 0x60, 0x28, 0x45, 0xcd, 0x02, 0xa8, 0xa8, 0x1a, 0xab, 0x6b, 0x09, 0xe3,
+	0x98, 0xd9, 0x18, 0xd6, 0x13, 0xe2, 0xc1, 0xc0, 0xbc, 0x1c, 0x6d, 0xd9, 0x34, 0x53, 0x55, 0xad,
+	0x4f, 0x92, 0x2c, 0x14, 0x3a, 0x4e, 0x1d, 0x6a, 0x66, 0xfe, 0xdf, 0xd4, 0x67, 0xc0, 0xda, 0x3f,
+	0x81, 0x5a, 0x64, 0x25, 0xf8, 0x77, 0x2c, 0x92, 0x66, 0x47, 0x3b, 0x25, 0xf7, 0x75, 0x93, 0x9f,
+	0xc8, 0xdc, 0x6b, 0x4f, 0x3b, 0xe7, 0xdc, 0x87, 0x85, 0x90, 0xdb, 0xea, 0x8a, 0x67, 0xf6, 0xa3,
+	0x60, 0x77, 0xfd, 0x8a, 0x67, 0x14, 0x99, 0xea, 0x35, 0x62, 0x1f, 0xc1, 0x08, 0x56, 0x7f, 0x04,
+	0x2e, 0x1d, 0x1a, 0x1a, 0x55, 0x90, 0x7b, 0xd0, 0xcb, 0xb8, 0x64, 0xb9, 0xd7, 0x6b, 0xbe, 0x44,
+	0x50, 0xef, 0xe7, 0x8a, 0x47, 0x35, 0xc4, 0xdf, 0x87, 0xbe, 0x5e, 0x1e, 0x6f, 0x92, 0xcd, 0x6c,
+	0x27, 0x25, 0xd8, 0xac, 0xbc, 0xef, 0x76, 0x75, 0xdf, 0xfe, 0x3f, 0xda, 0x70, 0xb5, 0x9e, 0x2e,
+	0x6d, 0x6f, 0xdc, 0xf4, 0xe8, 0xb5, 0x9a, 0xd0, 0x6e, 0xd4, 0x04, 0x1b, 0xe9, 0x9d, 0xcb, 0x46,
+	0x7a, 0x3d, 0x55, 0x12, 0xe8, 0xae, 0x42, 0xb9, 0x30, 0x99, 0x12, 0xc7, 0x8a, 0x96, 0x26, 0xa6,
+	0xf9, 0xe8, 0x51, 0x1c, 0xa3, 0xf6, 0x49, 0xcc, 0xec, 0x33, 0x5a, 0x8d, 0xd7, 0x13, 0xaa, 0xd3,
+	0x48, 0xa8, 0x37, 0x61, 0x98, 0x64, 0x2a, 0x08, 0xd2, 0x33, 0x55, 0xfe, 0x5d, 0x54, 0xda, 0x4d,
+	0x32, 0xaa, 0x28, 0xc7, 0xbc, 0x9e, 0x62, 0xe0, 0xad, 0x52, 0xcc, 0xf0, 0xf2, 0x29, 0xe6, 0xf7,
+	0x6d, 0x18, 0xaf, 0x37, 0xea, 0xdb, 0x2c, 0x5c, 0x9d, 0xa5, 0xdd, 0x38, 0x8b, 0xed, 0x72, 0x3b,
+	0x55, 0x97, 0xab, 0x3c, 0xda, 0xf4, 0xe5, 0xda, 0x9c, 0x66, 0x46, 0x6e, 0x02, 0x44, 0x3c, 0x8b,
+	0xd2, 0xa2, 0xfc, 0x80, 0x73, 0x69, 0x8d, 0xa2, 0xfa, 0x9e, 0x98, 0xc9, 0x30, 0x49, 0xf3, 0xa0,
+	0x10, 0xa9, 0xa9, 0x43, 0x60, 0x48, 0x2f, 0x45, 0xaa, 0x8e, 0x98, 0xcb, 0x50, 0xa8, 0x23, 0x0e,
+	0x2e, 0x3e, 0xa2, 0x81, 0x92, 0x47, 0xa8, 0xff, 0x4a, 0x07, 0x9f, 0x73, 0xa1, 0x5c, 0x05, 0xf6,
+	0xff, 0xd0, 0x06, 0xb2, 0xf9, 0x16, 0x79, 0x33, 0x03, 0x79, 0x2a, 0x32, 0x33, 0xc9, 0x7e, 0x90,
+	0xc6, 0x46, 0x76, 0xba, 0xbd, 0x07, 0x21, 0x53, 0x65, 0x84, 0x3c, 0x12, 0xc9, 0x4a, 0x56, 0x56,
+	0xaa, 0x93, 0x54, 0x89, 0x95, 0xa1, 0x98, 0x33, 0x59, 0xb3, 0x92, 0xab, 0x29, 0xc6, 0x48, 0xd6,
+	0x7b, 0x06, 0x6f, 0xe5, 0x3d, 0xce, 0xe5, 0xbd, 0xe7, 0x2f, 0x5d, 0xf0, 0xce, 0xfb, 0x38, 0xfd,
+	0x6f, 0x7f, 0x08, 0x76, 0xb7, 0x7e, 0x08, 0xee, 0x6e, 0x54, 0xf5, 0xde, 0x45, 0xb1, 0x5e, 0x33,
+	0x50, 0xff, 0xad, 0x0c, 0x34, 0x78, 0x8b, 0x0f, 0x3b, 0xa7, 0xfe, 0x9a, 0xfb, 0x7f, 0x93, 0x6d,
+	0xdc, 0xd7, 0xfd, 0xd4, 0x21, 0x84, 0xdc, 0x00, 0x27, 0x0a, 0x25, 0x9b, 0x73, 0x71, 0xe6, 0x81,
+	0x71, 0x2d, 0x33, 0xaf, 0x3d, 0x0a, 0x87, 0x6f, 0xf2, 0x2f, 0x36, 0xba, 0xf8, 0x5f, 0x6c, 0x0f,
+	0xdc, 0x30, 0xcb, 0xd7, 0xde, 0x1f, 0x8e, 0x26, 0x1c, 0xc5, 0xe4, 0x67, 0xd5, 0x87, 0xd2, 0x18,
+	0xf3, 0xfe, 0xf4, 0xdc, 0x4f, 0x74, 0xfb, 0xbf, 0x61, 0x05, 0xfc, 0x7f, 0xb6, 0xe0, 0xfa, 0x39,
+	0xa0, 0x6d, 0xfd, 0x70, 0x99, 0x23, 0x4d, 0x3f, 0x2c, 0x4c, 0x86, 0xfc, 0x8f, 0xf2, 0xfa, 0xff,
+	0xf0, 0xc3, 0xd6, 0xff, 0x35, 0x0c, 0x6b, 0xb5, 0x50, 0x1d, 0x31, 0xd2, 0x7f, 0x08, 0x3d, 0xda,
+	0x8e, 0x52, 0xf2, 0x10, 0x1c, 0x63, 0x19, 0x5b, 0xb7, 0xdf, 0x6d, 0xd4, 0x50, 0x6b, 0xc1, 0x12,
+	0xe7, 0xff, 0xab, 0x0d, 0x3b, 0x6b, 0x3c, 0x75, 0xc8, 0xa2, 0x30, 0xa6, 0x73, 0x29, 0x8e, 0x55,
+	0x2e, 0x5d, 0x85, 0x42, 0xbd, 0xe3, 0x91, 0xa5, 0xa3, 0x0d, 0x34, 0xe9, 0x65, 0xa1, 0x93, 0xd6,
+	0x2a, 0x94, 0xd1, 0x22, 0xc8, 0x99, 0x34, 0x61, 0xe7, 0x20, 0xe1, 0x05, 0x93, 0x65, 0x5f, 0xd3,
+	0xad, 0xf5, 0x35, 0xb6, 0xf4, 0xf5, 0x6a, 0xa5, 0xef, 0x01, 0xf4, 0x04, 0xfe, 0x39, 0x6b, 0x93,
+	0xec, 0x9d, 0xa3, 0x3c, 0xfa, 0x96, 0x46, 0xae, 0x55, 0xcb, 0x5e, 0x55, 0x2d, 0xcd, 0xaf, 0x53,
+	0xf9, 0xf8, 0x76, 0x34, 0x41, 0x27, 0x50, 0xfb, 0x9a, 0xd3, 0xbf, 0xde, 0x76, 0x4a, 0xf6, 0xa1,
+	0x8b, 0x8f, 0x85, 0x8b, 0x8b, 0x24, 0xe2, 0x54, 0xfd, 0x29, 0x32, 0xc1, 0x72, 0x9e, 0x9e, 0x9a,
+	0x98, 0x71, 0x68, 0x8d, 0x52, 0xef, 0xd1, 0x46, 0xc8, 0xb4, 0x53, 0xff, 0x77, 0x2d, 0x20, 0x9b,
+	0x47, 0x52, 0x99, 0x18, 0x8b, 0x4c, 0x80, 0x86, 0xd1, 0xd7, 0xea, 0x22, 0xe5, 0x9b, 0x24, 0xab,
+	0xb1, 0xa3, 0x45, 0xa8, 0x5f, 0x1b, 0x96, 0x7d, 0xb8, 0x08, 0x85, 0xf2, 0x6f, 0x96, 0xc5, 0x5a,
+	0x56, 0x5f, 0xc0, 0x80, 0x65, 0x31, 0x4a, 0x1a, 0x16, 0xca, 0x75, 0x4b, 0x96, 0x92, 0x3a, 0xe9,
+	0xe3, 0xf1, 0x7e, 0xf2, 0xef, 0x01, 0x00, 0x1c, 0x3d, 0x1f, 0x98, 0xaa, 0x1b, 0x00, 0x00,
 }
@@ -4,11 +4,17 @@
 
 // The maintq command queries a maintnerd gRPC server.
 // This tool is mostly for debugging.
+//
+// The verify subcommand instead checks a local copy of the mutation
+// log against the log manifest signed by maintnerd.
 package main
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"flag"
 	"fmt"
@@ -19,6 +25,8 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"golang.org/x/build/maintner"
+	"golang.org/x/build/maintner/godata"
 	"golang.org/x/build/maintner/maintnerd/apipb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -36,29 +44,19 @@ var (
 func main() {
 	flag.Parse()
 
-	c := credentials.NewTLS(&tls.Config{
-		NextProtos:         []string{"h2"},
-		InsecureSkipVerify: strings.HasPrefix(*server, "localhost:"),
-	})
-	opts := []grpc.DialOption{
-		grpc.WithDisableRetry(),
-		grpc.WithBlock(),
-		grpc.WithTimeout(5 * time.Second),
-		grpc.WithTransportCredentials(c),
+	// verify reads the mutation log over HTTP, not the gRPC API.
+	if flag.Arg(0) != "verify" {
+		dial()
 	}
 
-	cc, err := grpc.Dial(*server, opts...)
-	if err != nil {
-		log.Fatalf("unable to grpc.Dial(%q) = %s", *server, err)
-	}
-	mc = apipb.NewMaintnerServiceClient(cc)
-
 	cmdFunc := map[string]func(args []string) error{
 		"has-ancestor":  callHasAncestor,
 		"get-ref":       callGetRef,
 		"try-work":      callTryWork,
 		"list-releases": callListReleases,
 		"get-dashboard": callGetDashboard,
+		"watch-changes": callWatchChanges,
+		"verify":        verifyLog,
 	}
 	log.SetFlags(0)
 	if flag.NArg() == 0 || cmdFunc[flag.Arg(0)] == nil {
@@ -74,6 +72,25 @@ func main() {
 	}
 }
 
+func dial() {
+	c := credentials.NewTLS(&tls.Config{
+		NextProtos:         []string{"h2"},
+		InsecureSkipVerify: strings.HasPrefix(*server, "localhost:"),
+	})
+	opts := []grpc.DialOption{
+		grpc.WithDisableRetry(),
+		grpc.WithBlock(),
+		grpc.WithTimeout(5 * time.Second),
+		grpc.WithTransportCredentials(c),
+	}
+
+	cc, err := grpc.Dial(*server, opts...)
+	if err != nil {
+		log.Fatalf("unable to grpc.Dial(%q) = %s", *server, err)
+	}
+	mc = apipb.NewMaintnerServiceClient(cc)
+}
+
 func callHasAncestor(args []string) error {
 	if len(args) != 2 {
 		return errors.New("Usage: maintq has-ancestor <commit> <ancestor>")
@@ -154,6 +171,94 @@ func callGetDashboard(args []string) error {
 	return printTextProto(res)
 }
 
+func callWatchChanges(args []string) error {
+	req := &apipb.WatchChangesRequest{}
+
+	fs := flag.NewFlagSet("watch-changes", flag.ExitOnError)
+	var repos, projects, kinds string
+	fs.StringVar(&repos, "repos", "", "comma-separated GitHub repos (\"golang/go\"); empty means all")
+	fs.StringVar(&projects, "projects", "", "comma-separated Gerrit projects (\"go.googlesource.com/go\"); empty means all")
+	fs.StringVar(&kinds, "kinds", "", "comma-separated change kinds (\"IssueLabeled,CLMerged\"); empty means all")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if repos != "" {
+		req.GithubRepos = strings.Split(repos, ",")
+	}
+	if projects != "" {
+		req.GerritProjects = strings.Split(projects, ",")
+	}
+	if kinds != "" {
+		req.Kinds = strings.Split(kinds, ",")
+	}
+
+	stream, err := mc.WatchChanges(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if err := printTextProto(e); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+}
+
+func verifyLog(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	logs := fs.String("logs", godata.Server, "maintner server's /logs URL")
+	dir := fs.String("dir", godata.Dir(), "local mutation log cache to verify")
+	keyFile := fs.String("key", "", "file containing the PEM-encoded Ed25519 public key the manifest is signed with; if empty, the signature isn't checked")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var pub ed25519.PublicKey
+	if *keyFile != "" {
+		slurp, err := os.ReadFile(*keyFile)
+		if err != nil {
+			return err
+		}
+		block, _ := pem.Decode(slurp)
+		if block == nil {
+			return fmt.Errorf("%s: not PEM-encoded", *keyFile)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("%s: %v", *keyFile, err)
+		}
+		var ok bool
+		if pub, ok = key.(ed25519.PublicKey); !ok {
+			return fmt.Errorf("%s: %T is not an Ed25519 key", *keyFile, key)
+		}
+	} else {
+		log.Printf("warning: no -key given; not checking the manifest's signature")
+	}
+
+	m, err := maintner.FetchLogManifest(ctx, *logs, pub)
+	if err != nil {
+		return err
+	}
+	n, err := maintner.VerifyLogCache(ctx, *dir, *logs, m)
+	if err != nil {
+		var ce *maintner.LogCorruptionError
+		if errors.As(err, &ce) && ce.File != "" {
+			return fmt.Errorf("%v\ndelete %s and later segments to re-download them", err, ce.File)
+		}
+		return err
+	}
+	fmt.Printf("verified %d of %d segments (manifest as of %v)\n", n, len(m.Segments), m.Time)
+	return nil
+}
+
 func printTextProto(m proto.Message) error {
 	tm := proto.TextMarshaler{Compact: false}
 	return tm.Marshal(os.Stdout, m)
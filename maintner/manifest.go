@@ -0,0 +1,299 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintner
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/build/maintner/maintpb"
+	"golang.org/x/build/maintner/reclog"
+)
+
+// A LogManifest is a signed list of a mutation log's segments, as
+// served by maintnerd at /logs/manifest.
+//
+// Each segment's Chain hash covers its own metadata and the Chain of
+// the segment before it, so the signature over the last Chain commits
+// to the whole log as of Time.
+type LogManifest struct {
+	Segments []ManifestSegment `json:"segments"`
+	Time     time.Time         `json:"time"`
+
+	// Signature is the Ed25519 signature of SignedMessage.
+	Signature []byte `json:"signature"`
+}
+
+// A ManifestSegment is a log segment in a LogManifest.
+type ManifestSegment struct {
+	LogSegmentJSON
+
+	// Chain is the lowercase hex SHA-256 of the previous segment's
+	// Chain (empty for segment 0) followed by this segment's
+	// "<number> <size> <sha224>\n".
+	Chain string `json:"chain"`
+}
+
+// errStopRecords stops a reclog.ForeachRecord walk once a bad record
+// has been found.
+var errStopRecords = errors.New("stop")
+
+// chainHash returns the Chain of seg following prev.
+func chainHash(prev string, seg LogSegmentJSON) string {
+	h := sha256.New()
+	io.WriteString(h, prev)
+	fmt.Fprintf(h, "%d %d %s\n", seg.Number, seg.Size, seg.SHA224)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewLogManifest returns a manifest of segs as of t, signed with key.
+func NewLogManifest(segs []LogSegmentJSON, t time.Time, key ed25519.PrivateKey) *LogManifest {
+	m := &LogManifest{Time: t.UTC().Truncate(time.Second)}
+	var prev string
+	for _, seg := range segs {
+		prev = chainHash(prev, seg)
+		m.Segments = append(m.Segments, ManifestSegment{LogSegmentJSON: seg, Chain: prev})
+	}
+	m.Signature = ed25519.Sign(key, m.SignedMessage())
+	return m
+}
+
+// SignedMessage returns the message that m's Signature signs.
+func (m *LogManifest) SignedMessage() []byte {
+	var last string
+	if n := len(m.Segments); n > 0 {
+		last = m.Segments[n-1].Chain
+	}
+	return []byte(fmt.Sprintf("maintner log manifest\n%d\n%s\n%s\n", len(m.Segments), m.Time.UTC().Format(time.RFC3339), last))
+}
+
+// Verify checks that m's segments are numbered in order, that their
+// chain hashes are consistent, and, if pub is non-nil, that m is
+// signed by pub.
+func (m *LogManifest) Verify(pub ed25519.PublicKey) error {
+	var prev string
+	for i, seg := range m.Segments {
+		if seg.Number != i {
+			return fmt.Errorf("manifest segment %d has number %d", i, seg.Number)
+		}
+		if want := chainHash(prev, seg.LogSegmentJSON); seg.Chain != want {
+			return fmt.Errorf("manifest segment %d has chain hash %s; want %s", i, seg.Chain, want)
+		}
+		prev = seg.Chain
+	}
+	if pub != nil && !ed25519.Verify(pub, m.SignedMessage(), m.Signature) {
+		return errors.New("manifest signature doesn't verify")
+	}
+	return nil
+}
+
+// FetchLogManifest fetches the manifest of the mutation log served at
+// server (a /logs URL, as passed to NewNetworkMutationSource) and
+// verifies it with pub. If pub is nil, the signature isn't checked.
+func FetchLogManifest(ctx context.Context, server string, pub ed25519.PublicKey) (*LogManifest, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", server+"/manifest", nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s/manifest: %v", server, res.Status)
+	}
+	m := new(LogManifest)
+	if err := json.NewDecoder(res.Body).Decode(m); err != nil {
+		return nil, fmt.Errorf("decoding %s/manifest: %v", server, err)
+	}
+	if err := m.Verify(pub); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// A LogCorruptionError reports where a local copy of the mutation log
+// diverges from a LogManifest.
+type LogCorruptionError struct {
+	Segment int
+	File    string // local file, or empty if missing
+
+	// Offset is the offset within the segment of the first bad
+	// record, or -1 if it couldn't be determined.
+	Offset int64
+
+	Reason string
+}
+
+func (e *LogCorruptionError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("segment %d (%s): %s", e.Segment, e.File, e.Reason)
+	}
+	return fmt.Sprintf("segment %d (%s): record at offset %d: %s", e.Segment, e.File, e.Offset, e.Reason)
+}
+
+// VerifyLogCache checks the mutation log cached in cacheDir by
+// NewNetworkMutationSource against m, which should already have been
+// verified. It reports the number of segments verified.
+//
+// Segments beyond the end of the local cache haven't been synced yet
+// and aren't an error. A segment that doesn't match m is reported as
+// a *LogCorruptionError identifying its first bad record. Structural
+// damage is found by reading the local copy; otherwise, if server is
+// non-empty, the segment is downloaded from server and compared with
+// the local copy record by record.
+func VerifyLogCache(ctx context.Context, cacheDir, server string, m *LogManifest) (int, error) {
+	for i, seg := range m.Segments {
+		file := localSegmentFile(cacheDir, seg.LogSegmentJSON)
+		if file == "" {
+			return i, nil
+		}
+		fi, err := os.Stat(file)
+		if err != nil {
+			return i, err
+		}
+		if fi.Size() < seg.Size {
+			// Still growing locally; we can't say more
+			// than that it's well formed so far.
+			if bad := firstBadRecord(file, fi.Size()); bad != nil {
+				bad.Segment = seg.Number
+				return i, bad
+			}
+			return i, nil
+		}
+		// A local growing segment may be ahead of the manifest,
+		// so only hash what the manifest covers.
+		if sum := (&netMutSource{}).filePrefixSum224(file, seg.Size); sum == seg.SHA224 {
+			continue
+		}
+		bad := firstBadRecord(file, seg.Size)
+		if bad == nil && server != "" {
+			bad, err = firstDifferingRecord(ctx, server, seg.LogSegmentJSON, file)
+			if err != nil {
+				return i, err
+			}
+		}
+		if bad == nil {
+			bad = &LogCorruptionError{File: file, Offset: -1, Reason: "SHA-224 doesn't match manifest"}
+		}
+		bad.Segment = seg.Number
+		return i, bad
+	}
+	return len(m.Segments), nil
+}
+
+// localSegmentFile returns the file in cacheDir holding seg, or the
+// empty string if seg hasn't been synced. If the cache has a frozen
+// copy of seg with a different hash, that's returned instead, since it
+// has diverged from the manifest.
+func localSegmentFile(cacheDir string, seg LogSegmentJSON) string {
+	for _, pattern := range []string{
+		fmt.Sprintf("%04d.%s.mutlog", seg.Number, seg.SHA224),
+		fmt.Sprintf("%04d.growing.mutlog", seg.Number),
+		fmt.Sprintf("%04d.*.mutlog", seg.Number),
+	} {
+		matches, _ := filepath.Glob(filepath.Join(cacheDir, pattern))
+		if len(matches) > 0 {
+			return matches[0]
+		}
+	}
+	return ""
+}
+
+// firstBadRecord returns the first record in the first size bytes of
+// file that's malformed or doesn't decode as a mutation, or nil if
+// there is none.
+func firstBadRecord(file string, size int64) *LogCorruptionError {
+	f, err := os.Open(file)
+	if err != nil {
+		return &LogCorruptionError{File: file, Offset: -1, Reason: err.Error()}
+	}
+	defer f.Close()
+	var last int64
+	var bad *LogCorruptionError
+	err = reclog.ForeachRecord(io.LimitReader(f, size), 0, func(off int64, hdr, rec []byte) error {
+		last = off + int64(len(hdr)+len(rec))
+		if err := proto.Unmarshal(rec, new(maintpb.Mutation)); err != nil {
+			bad = &LogCorruptionError{File: file, Offset: off, Reason: fmt.Sprintf("bad mutation: %v", err)}
+			return errStopRecords
+		}
+		return nil
+	})
+	if bad != nil {
+		return bad
+	}
+	if err != nil {
+		// The record following the last good one is bad.
+		return &LogCorruptionError{File: file, Offset: last, Reason: err.Error()}
+	}
+	return nil
+}
+
+// firstDifferingRecord downloads seg from server and returns the first
+// record of file that differs from it.
+func firstDifferingRecord(ctx context.Context, server string, seg LogSegmentJSON, file string) (*LogCorruptionError, error) {
+	base, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := url.Parse(seg.URL)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", base.ResolveReference(rel).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", seg.Size-1))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+		return nil, fmt.Errorf("fetching segment %d: %v", seg.Number, res.Status)
+	}
+	want, err := io.ReadAll(io.LimitReader(res.Body, seg.Size))
+	if err != nil {
+		return nil, err
+	}
+	if fmt.Sprintf("%x", sha256.Sum224(want)) != seg.SHA224 {
+		return nil, fmt.Errorf("server's copy of segment %d doesn't match the manifest", seg.Number)
+	}
+	local, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(local)) > seg.Size {
+		local = local[:seg.Size]
+	}
+	var bad *LogCorruptionError
+	err = reclog.ForeachRecord(bytes.NewReader(want), 0, func(off int64, hdr, rec []byte) error {
+		end := off + int64(len(hdr)+len(rec))
+		if end > int64(len(local)) || !bytes.Equal(local[off:end], want[off:end]) {
+			bad = &LogCorruptionError{File: file, Offset: off, Reason: "record differs from server's copy"}
+			return errStopRecords
+		}
+		return nil
+	})
+	if bad == nil && err != nil {
+		return nil, fmt.Errorf("server's copy of segment %d: %v", seg.Number, err)
+	}
+	return bad, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintner
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/build/maintner/maintpb"
+	"golang.org/x/build/maintner/reclog"
+)
+
+// testSegment returns a log segment of mutations with the given
+// titles, and the offsets of its records.
+func testSegment(t *testing.T, titles ...string) (data []byte, offs []int64) {
+	var buf bytes.Buffer
+	for _, title := range titles {
+		rec, err := proto.Marshal(&maintpb.Mutation{GithubIssue: &maintpb.GithubIssueMutation{Title: title}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		offs = append(offs, int64(buf.Len()))
+		if err := reclog.WriteRecord(&buf, int64(buf.Len()), rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes(), offs
+}
+
+func TestLogManifest(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segs := []LogSegmentJSON{
+		{Number: 0, Size: 10, SHA224: "aaaa"},
+		{Number: 1, Size: 20, SHA224: "bbbb"},
+	}
+	m := NewLogManifest(segs, time.Now(), priv)
+	if err := m.Verify(pub); err != nil {
+		t.Fatalf("Verify = %v", err)
+	}
+
+	// Changing any segment breaks the chain.
+	m.Segments[0].Size++
+	if err := m.Verify(nil); err == nil {
+		t.Errorf("Verify of modified segment succeeded")
+	}
+	m.Segments[0].Size--
+
+	// Recomputing the chain doesn't help without the key.
+	forged := NewLogManifest(append(segs, LogSegmentJSON{Number: 2, Size: 1, SHA224: "cccc"}), m.Time, priv)
+	forged.Signature = m.Signature
+	if err := forged.Verify(pub); err == nil {
+		t.Errorf("Verify of forged manifest succeeded")
+	}
+}
+
+func TestVerifyLogCache(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seg0, offs0 := testSegment(t, "one", "two", "three")
+	seg1, offs1 := testSegment(t, "four", "five")
+	sum := func(b []byte) string { return fmt.Sprintf("%x", sha256.Sum224(b)) }
+	m := NewLogManifest([]LogSegmentJSON{
+		{Number: 0, Size: int64(len(seg0)), SHA224: sum(seg0), URL: "/logs/0"},
+		{Number: 1, Size: int64(len(seg1)), SHA224: sum(seg1), URL: "/logs/1"},
+	}, time.Now(), priv)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logs/0":
+			w.Write(seg0)
+		case "/logs/1":
+			w.Write(seg1)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	setup := func(seg0, seg1 []byte) string {
+		t.Helper()
+		dir := t.TempDir()
+		write := func(name string, data []byte) {
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		write("0000."+m.Segments[0].SHA224+".mutlog", seg0)
+		if seg1 != nil {
+			write("0001.growing.mutlog", seg1)
+		}
+		return dir
+	}
+	check := func(dir, server string, wantN int, wantSeg int, wantOff int64) {
+		t.Helper()
+		n, err := VerifyLogCache(ctx, dir, server, m)
+		if n != wantN {
+			t.Errorf("verified %d segments; want %d", n, wantN)
+		}
+		if wantSeg < 0 {
+			if err != nil {
+				t.Errorf("VerifyLogCache = %v; want success", err)
+			}
+			return
+		}
+		var ce *LogCorruptionError
+		if !errors.As(err, &ce) {
+			t.Fatalf("VerifyLogCache = %v; want *LogCorruptionError", err)
+		}
+		if ce.Segment != wantSeg || ce.Offset != wantOff {
+			t.Errorf("corruption at segment %d, offset %d (%v); want segment %d, offset %d", ce.Segment, ce.Offset, err, wantSeg, wantOff)
+		}
+	}
+
+	// An intact cache, including one whose growing segment is
+	// ahead of the manifest.
+	check(setup(seg0, seg1), "", 2, -1, 0)
+	more, _ := testSegment(t, "four", "five", "six")
+	check(setup(seg0, more), "", 2, -1, 0)
+
+	// A cache that hasn't synced the last segment yet.
+	check(setup(seg0, nil), "", 1, -1, 0)
+	check(setup(seg0, seg1[:offs1[1]]), "", 1, -1, 0)
+
+	// A damaged record header is found from the local copy alone.
+	bad := bytes.Replace(seg1, []byte("REC@"+fmt.Sprintf("%x", offs1[1])), []byte("REX@"+fmt.Sprintf("%x", offs1[1])), 1)
+	check(setup(seg0, bad), "", 1, 1, offs1[1])
+
+	// A changed record that's still well formed needs the server's
+	// copy to pinpoint.
+	bad = []byte(strings.Replace(string(seg0), "two", "TWO", 1))
+	check(setup(bad, seg1), "", 0, 0, -1)
+	check(setup(bad, seg1), srv.URL+"/logs", 0, 0, offs0[1])
+}
@@ -0,0 +1,289 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A ChangeKind is the kind of change described by a ChangeEvent.
+type ChangeKind int
+
+const (
+	// IssueOpened is a GitHub issue or pull request seen for the first time.
+	IssueOpened ChangeKind = iota + 1
+	// IssueClosed is a GitHub issue or pull request being closed.
+	IssueClosed
+	// IssueReopened is a closed GitHub issue or pull request being reopened.
+	IssueReopened
+	// IssueLabeled is a label being added to a GitHub issue.
+	IssueLabeled
+	// IssueUnlabeled is a label being removed from a GitHub issue.
+	IssueUnlabeled
+	// IssueMilestoned is a GitHub issue's milestone changing.
+	IssueMilestoned
+	// IssueCommented is a new comment on a GitHub issue.
+	IssueCommented
+
+	// CLNewPatchSet is a new patch set of a Gerrit CL.
+	// A patch set with version 1 means the CL was created.
+	CLNewPatchSet
+	// CLVote is a label vote on a Gerrit CL.
+	CLVote
+	// CLMessage is a new message on a Gerrit CL.
+	CLMessage
+	// CLMerged is a Gerrit CL being merged.
+	CLMerged
+	// CLAbandoned is a Gerrit CL being abandoned.
+	CLAbandoned
+
+	// RefMoved is a non-change Gerrit ref (such as a branch or tag)
+	// being created or pointed at a new commit.
+	RefMoved
+)
+
+var changeKindNames = map[ChangeKind]string{
+	IssueOpened:     "IssueOpened",
+	IssueClosed:     "IssueClosed",
+	IssueReopened:   "IssueReopened",
+	IssueLabeled:    "IssueLabeled",
+	IssueUnlabeled:  "IssueUnlabeled",
+	IssueMilestoned: "IssueMilestoned",
+	IssueCommented:  "IssueCommented",
+	CLNewPatchSet:   "CLNewPatchSet",
+	CLVote:          "CLVote",
+	CLMessage:       "CLMessage",
+	CLMerged:        "CLMerged",
+	CLAbandoned:     "CLAbandoned",
+	RefMoved:        "RefMoved",
+}
+
+func (k ChangeKind) String() string {
+	if s, ok := changeKindNames[k]; ok {
+		return s
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// ParseChangeKind returns the ChangeKind with the given name,
+// as returned by ChangeKind.String.
+func ParseChangeKind(s string) (ChangeKind, bool) {
+	for k, name := range changeKindNames {
+		if strings.EqualFold(name, s) {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// IsGitHub reports whether k is a GitHub issue change.
+func (k ChangeKind) IsGitHub() bool { return k >= IssueOpened && k <= IssueCommented }
+
+// IsGerrit reports whether k is a Gerrit CL or ref change.
+func (k ChangeKind) IsGerrit() bool { return k >= CLNewPatchSet && k <= RefMoved }
+
+// A ChangeEvent describes a single change to an entity in the corpus,
+// observed while a mutation was applied.
+//
+// Only the fields relevant to Kind are set. GitHub events set
+// GitHubRepo and Issue; Gerrit CL events set GerritProject and CL;
+// RefMoved sets GerritProject, Ref and Hash.
+type ChangeEvent struct {
+	Kind ChangeKind
+
+	// Time is the time of the change, when known. Otherwise it is the
+	// time the mutation was applied to the corpus.
+	Time time.Time
+
+	GitHubRepo GitHubRepoID
+	Issue      int32 // GitHub issue or pull request number
+	Label      string
+	Milestone  string
+	CommentID  int64
+	// PullRequest reports whether the GitHub issue is a pull request.
+	PullRequest bool
+
+	// GerritProject is the Gerrit project, in the form
+	// "go.googlesource.com/go".
+	GerritProject string
+	CL            int32
+	PatchSet      int32
+	// Vote is the label vote value, such as +2, for CLVote events.
+	// The vote's label name is in the Label field.
+	Vote int8
+
+	Ref  string
+	Hash GitHash
+}
+
+func (e ChangeEvent) String() string {
+	switch {
+	case e.Kind.IsGitHub():
+		return fmt.Sprintf("%v %v#%d", e.Kind, e.GitHubRepo, e.Issue)
+	case e.Kind == RefMoved:
+		return fmt.Sprintf("%v %s %s => %v", e.Kind, e.GerritProject, e.Ref, e.Hash)
+	default:
+		return fmt.Sprintf("%v %s/%d", e.Kind, e.GerritProject, e.CL)
+	}
+}
+
+// watcher is a registered Watch callback.
+type watcher struct {
+	fn func(ChangeEvent)
+}
+
+// Watch registers fn to be called for each change applied to the corpus
+// after its initial load by Initialize. The returned func unregisters fn.
+//
+// Events are delivered in the order the corresponding mutations were
+// applied, from the goroutine that called Update or from the
+// goroutines syncing a leader-mode corpus. fn is called without the
+// corpus lock held, so it may use the corpus's read lock to inspect the
+// changed entity, which will reflect the state at or after the event.
+// A slow fn delays further Update calls, so callers that do expensive
+// work should hand events off to another goroutine.
+func (c *Corpus) Watch(fn func(ChangeEvent)) (stop func()) {
+	w := &watcher{fn: fn}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watchers = append(c.watchers, w)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i, v := range c.watchers {
+			if v == w {
+				c.watchers = append(c.watchers[:i:i], c.watchers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// watching reports whether change events should be recorded.
+//
+// c.mu must be held.
+func (c *Corpus) watching() bool {
+	return c.didInit && len(c.watchers) > 0
+}
+
+// noteChange records a change event to be delivered to watchers by
+// the next call to deliverChanges.
+//
+// c.mu must be held for writing.
+func (c *Corpus) noteChange(e ChangeEvent) {
+	if !c.watching() {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	c.pendingChanges = append(c.pendingChanges, e)
+}
+
+// deliverChanges calls the registered watchers with any pending change events.
+//
+// c.mu must not be held.
+func (c *Corpus) deliverChanges() {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	c.mu.Lock()
+	evs := c.pendingChanges
+	c.pendingChanges = nil
+	ws := c.watchers
+	c.mu.Unlock()
+
+	for _, e := range evs {
+		for _, w := range ws {
+			w.fn(e)
+		}
+	}
+}
+
+// watchState is a snapshot of the watched parts of a GerritCL,
+// taken before it is reprocessed.
+type watchState struct {
+	status string
+	metas  int
+}
+
+// gerritCLChanges records the change events for cl, which has just been
+// reprocessed by finishProcessingCL, compared to its prior state old.
+//
+// c.mu must be held for writing.
+func (gp *GerritProject) gerritCLChanges(cl *GerritCL, old watchState) {
+	c := gp.gerrit.c
+	if !c.watching() {
+		return
+	}
+	for _, m := range cl.Metas[min(old.metas, len(cl.Metas)):] {
+		if msg := gp.getGerritMessage(m.Commit); msg != nil {
+			c.noteChange(ChangeEvent{
+				Kind:          CLMessage,
+				Time:          msg.Date,
+				GerritProject: gp.proj,
+				CL:            cl.Number,
+				PatchSet:      msg.Version,
+			})
+		}
+		footer := m.Footer()
+		for {
+			v, rest, ok := lineValueOK(footer, "Label:")
+			if !ok {
+				break
+			}
+			footer = rest
+			label, value, whose := parseGerritLabelValue(v)
+			if whose != "" {
+				// Skip votes applied on behalf of another account,
+				// such as votes copied to a new patch set.
+				continue
+			}
+			// A removed vote is reported as a vote of 0.
+			label = strings.TrimPrefix(label, "-")
+			if label == "" {
+				continue
+			}
+			c.noteChange(ChangeEvent{
+				Kind:          CLVote,
+				Time:          m.Commit.CommitTime,
+				GerritProject: gp.proj,
+				CL:            cl.Number,
+				Label:         label,
+				Vote:          value,
+			})
+		}
+	}
+	if cl.Status != old.status && old.status != "" {
+		var kind ChangeKind
+		switch cl.Status {
+		case "merged":
+			kind = CLMerged
+		case "abandoned":
+			kind = CLAbandoned
+		}
+		if kind != 0 {
+			c.noteChange(ChangeEvent{
+				Kind:          kind,
+				Time:          cl.Meta.Commit.CommitTime,
+				GerritProject: gp.proj,
+				CL:            cl.Number,
+			})
+		}
+	}
+}
+
+// issueChange returns a ChangeEvent of the given kind for gi.
+func (gr *GitHubRepo) issueChange(kind ChangeKind, gi *GitHubIssue, t time.Time) ChangeEvent {
+	return ChangeEvent{
+		Kind:        kind,
+		Time:        t,
+		GitHubRepo:  gr.id,
+		Issue:       gi.Number,
+		PullRequest: gi.PullRequest,
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintner
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"golang.org/x/build/maintner/maintpb"
+)
+
+func TestWatchGitHubIssue(t *testing.T) {
+	c := singleIssueGitHubCorpus()
+	c.didInit = true
+	var got []string
+	stop := c.Watch(func(e ChangeEvent) {
+		got = append(got, e.String()+" "+e.Label+e.Milestone)
+	})
+
+	apply := func(m *maintpb.GithubIssueMutation) {
+		m.Owner, m.Repo = "golang", "go"
+		c.processMutationLocked(&maintpb.Mutation{GithubIssue: m})
+		c.deliverChanges()
+	}
+	apply(&maintpb.GithubIssueMutation{
+		Number:  4,
+		Title:   "new issue",
+		Created: tp1,
+		AddLabel: []*maintpb.GithubLabel{
+			{Id: 1, Name: "NeedsFix"},
+		},
+	})
+	apply(&maintpb.GithubIssueMutation{
+		Number: 3,
+		AddLabel: []*maintpb.GithubLabel{
+			{Id: 1, Name: "NeedsFix"},
+		},
+		MilestoneId:    7,
+		MilestoneTitle: "Go1.23",
+		Comment: []*maintpb.GithubIssueCommentMutation{
+			{Id: 100, Body: "hi", Created: tp2},
+		},
+	})
+	apply(&maintpb.GithubIssueMutation{
+		Number:      3,
+		RemoveLabel: []int64{1},
+		Closed:      &maintpb.BoolChange{Val: true},
+	})
+	// No-op changes produce no events.
+	apply(&maintpb.GithubIssueMutation{
+		Number: 3,
+		Closed: &maintpb.BoolChange{Val: true},
+		Comment: []*maintpb.GithubIssueCommentMutation{
+			{Id: 100, Body: "hi, edited"},
+		},
+	})
+
+	want := []string{
+		"IssueOpened golang/go#4 ",
+		"IssueLabeled golang/go#4 NeedsFix",
+		"IssueMilestoned golang/go#3 Go1.23",
+		"IssueLabeled golang/go#3 NeedsFix",
+		"IssueCommented golang/go#3 ",
+		"IssueClosed golang/go#3 ",
+		"IssueUnlabeled golang/go#3 NeedsFix",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events:\n got: %q\nwant: %q", got, want)
+	}
+
+	stop()
+	got = nil
+	apply(&maintpb.GithubIssueMutation{
+		Number: 3,
+		Closed: &maintpb.BoolChange{Val: false},
+	})
+	if len(got) != 0 {
+		t.Errorf("got events after stop: %q", got)
+	}
+}
+
+func TestWatchGerritCL(t *testing.T) {
+	c := new(Corpus)
+	c.initGerrit()
+	c.didInit = true
+	var got []string
+	c.Watch(func(e ChangeEvent) {
+		s := e.String()
+		switch e.Kind {
+		case CLNewPatchSet, CLMessage:
+			s += fmt.Sprintf(" ps%d", e.PatchSet)
+		case CLVote:
+			s += fmt.Sprintf(" %s%+d", e.Label, e.Vote)
+		}
+		got = append(got, s)
+	})
+
+	const proj = "go.googlesource.com/build"
+	hash := func(n int) string { return fmt.Sprintf("%040x", n) }
+	// commit adds commit number n with the given parent, if non-zero.
+	commit := func(n, parent int, msg string) {
+		raw := "tree " + hash(999) + "\n"
+		if parent != 0 {
+			raw += "parent " + hash(parent) + "\n"
+		}
+		raw += fmt.Sprintf("author Gopher <gopher@golang.org> %d +0000\n", 1700000000+n)
+		raw += fmt.Sprintf("committer Gerrit <gerrit@golang.org> %d +0000\n\n", 1700000000+n)
+		raw += msg
+		c.processMutationLocked(&maintpb.Mutation{Git: &maintpb.GitMutation{
+			Commit: &maintpb.GitCommit{Sha1: hash(n), Raw: []byte(raw)},
+		}})
+	}
+	refs := func(refs ...string) {
+		m := &maintpb.GerritMutation{Project: proj}
+		for i := 0; i < len(refs); i += 2 {
+			m.Refs = append(m.Refs, &maintpb.GitRef{Ref: refs[i], Sha1: refs[i+1]})
+		}
+		c.processMutationLocked(&maintpb.Mutation{Gerrit: m})
+		c.finishProcessing()
+		c.deliverChanges()
+	}
+
+	// CL 123 is created with its first patch set.
+	commit(1, 0, "build: fix things\n")
+	commit(11, 0, "Create change\n\nUploaded patch set 1.\n\nPatch-set: 1\n")
+	refs("refs/changes/23/123/1", hash(1), "refs/changes/23/123/meta", hash(11))
+	// A review with a vote.
+	commit(12, 11, "Update patch set 1\n\nPatch Set 1: Code-Review+2\n\nLooks good.\n\nPatch-set: 1\nLabel: Code-Review=+2\n")
+	refs("refs/changes/23/123/meta", hash(12))
+	// A second patch set.
+	commit(2, 0, "build: fix more things\n")
+	refs("refs/changes/23/123/2", hash(2))
+	// The CL is merged, and the branch moves to it.
+	commit(13, 12, "Update patch set 2\n\nChange has been successfully merged\n\nPatch-set: 2\nStatus: merged\n")
+	refs("refs/changes/23/123/meta", hash(13), "refs/heads/master", hash(2))
+	// Refs that don't move produce no events.
+	refs("refs/changes/23/123/2", hash(2), "refs/heads/master", hash(2))
+
+	want := []string{
+		"CLNewPatchSet " + proj + "/123 ps1",
+		"CLMessage " + proj + "/123 ps1",
+		"CLVote " + proj + "/123 Code-Review+2",
+		"CLNewPatchSet " + proj + "/123 ps2",
+		"RefMoved " + proj + " refs/heads/master => " + hash(2),
+		"CLMerged " + proj + "/123",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestWatchBeforeInit(t *testing.T) {
+	c := singleIssueGitHubCorpus()
+	var n int
+	c.Watch(func(ChangeEvent) { n++ })
+	c.processMutationLocked(&maintpb.Mutation{GithubIssue: &maintpb.GithubIssueMutation{
+		Owner: "golang", Repo: "go", Number: 4, Created: tp1,
+	}})
+	c.deliverChanges()
+	if n != 0 {
+		t.Errorf("got %d events during initial load; want 0", n)
+	}
+}
+
+func TestParseChangeKind(t *testing.T) {
+	for k := IssueOpened; k <= RefMoved; k++ {
+		got, ok := ParseChangeKind(k.String())
+		if !ok || got != k {
+			t.Errorf("ParseChangeKind(%q) = %v, %v; want %v, true", k.String(), got, ok, k)
+		}
+	}
+	if _, ok := ParseChangeKind("bogus"); ok {
+		t.Error("ParseChangeKind(bogus) succeeded")
+	}
+}
@@ -36,6 +36,11 @@ type Repo struct {
 	// build coordinator knows how to build.
 	CoordinatorCanBuild bool
 
+	// ExtraMirrors are git remotes that this repo is mirrored to
+	// from Gerrit, in addition to GitHub and Cloud Source Repositories.
+	// If not empty, GoGerritProject must be defined.
+	ExtraMirrors []Mirror
+
 	// GitHubRepo is the "org/repo" of where this repo exists on
 	// GitHub. If MirrorToGitHub is true, this is the
 	// destination.
@@ -47,6 +52,20 @@ type Repo struct {
 	WebsiteDesc string
 }
 
+// A Mirror is a git remote that a repo is mirrored to.
+type Mirror struct {
+	// Name is the name of the remote in gitmirror's copy of the repo,
+	// such as "gitlab". It must be unique among the repo's mirrors
+	// and must not be "origin", "github" or "csr".
+	Name string
+
+	// URL is the URL of the remote repository.
+	URL string
+
+	// PushOption, if non-empty, is passed to git push as --push-option.
+	PushOption string
+}
+
 // ByGerritProject maps from a Gerrit project name ("go", "net", etc)
 // to the Repo's information.
 var ByGerritProject = map[string]*Repo{ /* initialized below */ }
@@ -180,6 +199,19 @@ func add(r *Repo) {
 	if r.showOnDashboard && !r.CoordinatorCanBuild {
 		panic(fmt.Sprintf("project %+v is showOnDashboard but not marked buildable by coordinator", r))
 	}
+	if len(r.ExtraMirrors) > 0 && r.GoGerritProject == "" {
+		panic(fmt.Sprintf("project %+v has ExtraMirrors but no GoGerritProject", r))
+	}
+	seen := map[string]bool{"origin": true, "github": true, "csr": true}
+	for _, m := range r.ExtraMirrors {
+		if m.Name == "" || m.URL == "" {
+			panic(fmt.Sprintf("project %+v has a mirror without a name or URL", r))
+		}
+		if seen[m.Name] {
+			panic(fmt.Sprintf("project %+v has a mirror with reserved or duplicate name %q", r, m.Name))
+		}
+		seen[m.Name] = true
+	}
 
 	if p := r.GoGerritProject; p != "" {
 		if _, dup := ByGerritProject[p]; dup {